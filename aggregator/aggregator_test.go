@@ -0,0 +1,110 @@
+package aggregator
+
+import (
+	"math"
+	"testing"
+
+	goex "github.com/primitivelab/goexchange"
+	"github.com/primitivelab/goexchange/paper"
+)
+
+var btcUsdt = goex.NewSymbol("btc", "usdt")
+
+func getVenue(exchange string, fee float64, asks []goex.DepthRecord) *Venue {
+	spot := paper.NewSpot(nil, &paper.Config{
+		Exchange: exchange,
+		Balances: map[string]float64{"usdt": 10000, "btc": 10},
+		Fees:     &paper.FeeSchedule{Default: paper.Fee{Maker: fee, Taker: fee}},
+	})
+	spot.SetDepth(btcUsdt, &goex.Depth{
+		Bids: []goex.DepthRecord{{Price: 99, Amount: 1}},
+		Asks: asks,
+	})
+	return &Venue{API: spot, TakerFee: fee}
+}
+
+func getAggregator() *Aggregator {
+	return New(
+		getVenue(goex.EXCHANGE_BINANCE, 0.001, []goex.DepthRecord{{Price: 101, Amount: 1}, {Price: 103, Amount: 5}}),
+		getVenue(goex.EXCHANGE_HUOBI, 0, []goex.DepthRecord{{Price: 102, Amount: 2}}),
+	)
+}
+
+func TestAggregator_GetBook(t *testing.T) {
+	book, err := getAggregator().GetBook(btcUsdt, 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(book.Asks) != 3 || len(book.Bids) != 2 || len(book.Errors) != 0 {
+		t.Fatalf("unexpected book: %+v", book)
+	}
+	if book.BestAsk().Exchange != goex.EXCHANGE_BINANCE || math.Abs(book.BestAsk().EffectivePrice-101.101) > 1e-9 {
+		t.Fatalf("unexpected best ask: %+v", book.BestAsk())
+	}
+	if book.BestBid().Exchange != goex.EXCHANGE_HUOBI {
+		t.Fatalf("bid without fee must be best: %+v", book.BestBid())
+	}
+
+	routes, filled := book.Plan(goex.BUY, 3)
+	if filled != 3 || len(routes) != 2 || routes[0].Amount != 1 || routes[1].Exchange != goex.EXCHANGE_HUOBI || routes[1].Price != 102 {
+		t.Fatalf("unexpected routes: %+v %+v", routes[0], routes[1])
+	}
+	if _, filled = book.Plan(goex.BUY, 100); filled != 8 {
+		t.Fatalf("plan must be limited by book size: %v", filled)
+	}
+}
+
+func TestRouter_Execute(t *testing.T) {
+	aggregator := getAggregator()
+	execution, err := NewRouter(aggregator).Execute(btcUsdt, goex.BUY, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(execution.Children) != 2 || execution.FilledAmount != 4 {
+		t.Fatalf("unexpected execution: %+v", execution)
+	}
+	for _, child := range execution.Children {
+		if child.Err != nil || child.Status != goex.ORDER_STATUS_FILLED || child.OrderId == "" {
+			t.Fatalf("unexpected child order: %+v", child)
+		}
+	}
+	binance := execution.Children[0]
+	if binance.Exchange != goex.EXCHANGE_BINANCE || binance.Price != 103 || binance.Amount != 2 {
+		t.Fatalf("levels of one venue must be one child order: %+v", binance)
+	}
+	if execution.FilledCash != 101+2*102+103 {
+		t.Fatalf("unexpected filled cash: %v", execution.FilledCash)
+	}
+	balances := aggregator.Venue(goex.EXCHANGE_HUOBI).API.(*paper.Spot).GetBalances()
+	if balances["btc"].Free != 12 || balances["usdt"].Free != 10000-204 {
+		t.Fatalf("unexpected huobi balances: %v", balances)
+	}
+}
+
+func TestRouter_Venues(t *testing.T) {
+	main := getVenue(goex.EXCHANGE_BINANCE, 0, []goex.DepthRecord{{Price: 101, Amount: 1}})
+	sub := getVenue(goex.EXCHANGE_BINANCE, 0, []goex.DepthRecord{{Price: 102, Amount: 1.23456}, {Price: 102.0000001, Amount: 0.5}})
+	sub.TickSize, sub.LotSize = 0.01, 0.001
+	aggregator := New(main, sub)
+	if main.Id != goex.EXCHANGE_BINANCE || sub.Id != "binance-2" || aggregator.Venue("binance-2") != sub {
+		t.Fatalf("unexpected venue ids %s and %s", main.Id, sub.Id)
+	}
+
+	execution, err := NewRouter(aggregator).Execute(btcUsdt, goex.BUY, 2.5004)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(execution.Children) != 2 {
+		t.Fatalf("venues of one exchange must be routed apart: %+v", execution.Children)
+	}
+	child := execution.Children[1]
+	if child.Venue != "binance-2" || child.Err != nil || child.Price != 102.01 || child.Amount != 1.5 {
+		t.Fatalf("child order must be rounded to tick and lot size: %+v", child)
+	}
+	if balances := sub.API.(*paper.Spot).GetBalances(); math.Abs(balances["btc"].Free-11.5) > 1e-9 {
+		t.Fatalf("unexpected balances of second venue: %v", balances)
+	}
+	if roundToStep(0.30000000000000004, 0.1, false) != "0.3" || roundToStep(12.5, 1, true) != "13" || roundToStep(1.5, 0, false) != "1.5" {
+		t.Fatal("unexpected rounding")
+	}
+}
@@ -0,0 +1,192 @@
+package aggregator
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+	"sync"
+
+	goex "github.com/primitivelab/goexchange"
+)
+
+// Venue one exchange account of the aggregated book
+type Venue struct {
+	// unique name of venue, default the exchange name, New numbers further
+	// venues of one exchange like "binance-2"
+	Id  string
+	API goex.SpotAPI
+	// taker fee rate, eg: 0.001
+	TakerFee float64
+	// price tick and amount step of the symbol on venue, eg: 0.01 and 0.0001,
+	// child orders are rounded to them, 0 for no rounding
+	TickSize float64
+	LotSize  float64
+}
+
+// Name exchange name of venue
+func (venue *Venue) Name() string {
+	return venue.API.GetExchangeName()
+}
+
+// Level price level of one venue, EffectivePrice includes taker fee: higher
+// than Price for asks and lower for bids
+type Level struct {
+	Exchange       string
+	Venue          string
+	Price          float64
+	EffectivePrice float64
+	Amount         float64
+}
+
+// Book consolidated order book, bids effective price descending and asks
+// effective price ascending
+type Book struct {
+	Symbol goex.Symbol
+	Time   int64
+	Bids   []Level
+	Asks   []Level
+	// venues whose depth failed, by venue id
+	Errors map[string]error
+}
+
+// BestBid highest effective bid, zero level when empty
+func (book *Book) BestBid() Level {
+	if len(book.Bids) == 0 {
+		return Level{}
+	}
+	return book.Bids[0]
+}
+
+// BestAsk lowest effective ask, zero level when empty
+func (book *Book) BestAsk() Level {
+	if len(book.Asks) == 0 {
+		return Level{}
+	}
+	return book.Asks[0]
+}
+
+// Aggregator combines depth of several venues trading the same symbol
+type Aggregator struct {
+	venues []*Venue
+}
+
+// New new instance, venues without Id get the exchange name as Id
+func New(venues ...*Venue) *Aggregator {
+	used := map[string]int{}
+	for _, venue := range venues {
+		if venue.Id == "" {
+			venue.Id = venue.Name()
+		}
+		used[venue.Id]++
+		for used[venue.Id] > 1 {
+			venue.Id += "-" + strconv.Itoa(used[venue.Id])
+			used[venue.Id]++
+		}
+	}
+	return &Aggregator{venues: venues}
+}
+
+// Venues venues of aggregator
+func (aggregator *Aggregator) Venues() []*Venue {
+	return aggregator.venues
+}
+
+// Venue venue of id, nil when absent
+func (aggregator *Aggregator) Venue(id string) *Venue {
+	for _, venue := range aggregator.venues {
+		if venue.Id == id {
+			return venue
+		}
+	}
+	return nil
+}
+
+// GetBook fetch depth of all venues concurrently and merge it, failed venues
+// are left out and listed in Book.Errors, error only when every venue failed
+func (aggregator *Aggregator) GetBook(symbol goex.Symbol, size int) (*Book, error) {
+	depths := make([]*goex.Depth, len(aggregator.venues))
+	errs := make([]error, len(aggregator.venues))
+	var wait sync.WaitGroup
+	for i, venue := range aggregator.venues {
+		wait.Add(1)
+		go func(i int, venue *Venue) {
+			defer wait.Done()
+			depths[i], errs[i] = goex.ParseDepth(venue.API.GetDepth(symbol, size, nil))
+		}(i, venue)
+	}
+	wait.Wait()
+
+	book := &Book{Symbol: symbol, Time: goex.GetNowMillisecond(), Errors: map[string]error{}}
+	for i, venue := range aggregator.venues {
+		if errs[i] != nil {
+			book.Errors[venue.Id] = errs[i]
+			continue
+		}
+		for _, record := range depths[i].Bids {
+			book.Bids = append(book.Bids, Level{
+				Exchange:       venue.Name(),
+				Venue:          venue.Id,
+				Price:          record.Price,
+				EffectivePrice: record.Price * (1 - venue.TakerFee),
+				Amount:         record.Amount,
+			})
+		}
+		for _, record := range depths[i].Asks {
+			book.Asks = append(book.Asks, Level{
+				Exchange:       venue.Name(),
+				Venue:          venue.Id,
+				Price:          record.Price,
+				EffectivePrice: record.Price * (1 + venue.TakerFee),
+				Amount:         record.Amount,
+			})
+		}
+	}
+	if len(aggregator.venues) > 0 && len(book.Errors) == len(aggregator.venues) {
+		return nil, errors.New("depth of all venues failed")
+	}
+	sort.SliceStable(book.Bids, func(i, j int) bool { return book.Bids[i].EffectivePrice > book.Bids[j].EffectivePrice })
+	sort.SliceStable(book.Asks, func(i, j int) bool { return book.Asks[i].EffectivePrice < book.Asks[j].EffectivePrice })
+	return book, nil
+}
+
+// Route child order of a split order on one venue, Price is the worst level
+// price it takes and is used as limit price
+type Route struct {
+	Exchange string
+	Venue    string
+	Price    float64
+	Amount   float64
+	// traded value including fees
+	Cost float64
+}
+
+// Plan split amount over best effective levels, buy takes asks and sell takes
+// bids, routes are ordered by their first level and filled is the amount the
+// book can take
+func (book *Book) Plan(side goex.TradeSide, amount float64) (routes []*Route, filled float64) {
+	levels := book.Asks
+	if side == goex.SELL {
+		levels = book.Bids
+	}
+	byVenue := map[string]*Route{}
+	for _, level := range levels {
+		if amount-filled <= 1e-12 {
+			break
+		}
+		take := level.Amount
+		if take > amount-filled {
+			take = amount - filled
+		}
+		route, ok := byVenue[level.Venue]
+		if !ok {
+			route = &Route{Exchange: level.Exchange, Venue: level.Venue}
+			byVenue[level.Venue] = route
+			routes = append(routes, route)
+		}
+		route.Price = level.Price
+		route.Amount += take
+		route.Cost += take * level.EffectivePrice
+		filled += take
+	}
+	return routes, filled
+}
@@ -0,0 +1,177 @@
+package aggregator
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	goex "github.com/primitivelab/goexchange"
+)
+
+// ChildOrder routed order placed on one venue
+type ChildOrder struct {
+	Route
+	OrderId      string
+	FilledAmount float64
+	FilledCash   float64
+	Status       string
+	Err          error
+}
+
+// Execution result of a routed order
+type Execution struct {
+	Symbol       goex.Symbol
+	Side         goex.TradeSide
+	Amount       float64
+	FilledAmount float64
+	FilledCash   float64
+	Children     []*ChildOrder
+}
+
+// AvgPrice average filled price of all child orders, fees excluded
+func (execution *Execution) AvgPrice() float64 {
+	if execution.FilledAmount == 0 {
+		return 0
+	}
+	return execution.FilledCash / execution.FilledAmount
+}
+
+// Router smart order router, it splits a marketable order over the venues of
+// an aggregator by best effective price and places IOC limit child orders
+type Router struct {
+	aggregator *Aggregator
+	// depth levels requested from each venue, default 20
+	DepthSize int
+	// GetUserOrderInfo polls of a child order until it is closed, default 10
+	PollTimes    int
+	PollInterval time.Duration
+}
+
+// NewRouter new instance
+func NewRouter(aggregator *Aggregator) *Router {
+	return &Router{
+		aggregator:   aggregator,
+		DepthSize:    20,
+		PollTimes:    10,
+		PollInterval: 200 * time.Millisecond,
+	}
+}
+
+// Execute buy or sell amount across venues, child orders still open after
+// polling are canceled, so nothing keeps resting on a book. Child prices are
+// rounded to TickSize of the venue towards the taken levels and amounts down
+// to LotSize, children rounded to nothing are not placed
+func (router *Router) Execute(symbol goex.Symbol, side goex.TradeSide, amount float64) (*Execution, error) {
+	if amount <= 0 {
+		return nil, errors.New("amount must be positive")
+	}
+	book, err := router.aggregator.GetBook(symbol, router.DepthSize)
+	if err != nil {
+		return nil, err
+	}
+	routes, _ := book.Plan(side, amount)
+	if len(routes) == 0 {
+		return nil, errors.New("no liquidity to route")
+	}
+
+	execution := &Execution{Symbol: symbol, Side: side, Amount: amount}
+	var wait sync.WaitGroup
+	for _, route := range routes {
+		child := &ChildOrder{Route: *route, Status: goex.ORDER_STATUS_UNKNOWN}
+		execution.Children = append(execution.Children, child)
+		wait.Add(1)
+		go func(child *ChildOrder) {
+			defer wait.Done()
+			router.place(symbol, side, child)
+		}(child)
+	}
+	wait.Wait()
+
+	for _, child := range execution.Children {
+		execution.FilledAmount += child.FilledAmount
+		execution.FilledCash += child.FilledCash
+	}
+	return execution, nil
+}
+
+// place place child order and track it until closed
+func (router *Router) place(symbol goex.Symbol, side goex.TradeSide, child *ChildOrder) {
+	venue := router.aggregator.Venue(child.Venue)
+	if venue == nil {
+		child.Err = errors.New("venue does not exist")
+		return
+	}
+	price := roundToStep(child.Price, venue.TickSize, side == goex.BUY)
+	amount := roundToStep(child.Amount, venue.LotSize, false)
+	child.Price, child.Amount = goex.ToFloat(price), goex.ToFloat(amount)
+	if child.Amount <= 0 {
+		child.Err = errors.New("amount is below lot size of venue")
+		child.Status = goex.ORDER_STATUS_REJECTED
+		return
+	}
+	order, err := goex.ParseOrder(venue.API.PlaceOrder(&goex.PlaceOrder{
+		Symbol:      symbol,
+		Price:       price,
+		Amount:      amount,
+		Side:        side,
+		TradeType:   goex.LIMIT,
+		TimeInForce: goex.IOC,
+	}))
+	if err != nil {
+		child.Err = err
+		child.Status = goex.ORDER_STATUS_REJECTED
+		return
+	}
+	child.OrderId = order.OrderId
+	router.update(child, order)
+
+	for i := 0; child.Status == goex.ORDER_STATUS_NEW || child.Status == goex.ORDER_STATUS_PARTIALLY_FILLED; i++ {
+		if i >= router.PollTimes {
+			venue.API.CancelOrder(symbol, child.OrderId, "")
+		}
+		if i > router.PollTimes {
+			break
+		}
+		time.Sleep(router.PollInterval)
+		order, err = goex.ParseOrder(venue.API.GetUserOrderInfo(symbol, child.OrderId, ""))
+		if err != nil {
+			child.Err = err
+			continue
+		}
+		child.Err = nil
+		router.update(child, order)
+	}
+}
+
+// update copy fill state of order into child, place responses holding only
+// an order id leave the fill state unchanged
+func (router *Router) update(child *ChildOrder, order *goex.Order) {
+	child.Status = order.Status
+	if order.FilledAmount > 0 {
+		child.FilledAmount = order.FilledAmount
+		child.FilledCash = order.FilledCash
+		if child.FilledCash == 0 {
+			child.FilledCash = order.FilledAmount * child.Price
+		}
+	}
+}
+
+// roundToStep value rounded down, or up when up is set, to a multiple of step
+// and formatted with the decimals of step, step 0 leaves value as it is
+func roundToStep(value, step float64, up bool) string {
+	if step <= 0 {
+		return goex.FloatToString(value)
+	}
+	count := math.Floor(value/step + 1e-9)
+	if up {
+		count = math.Ceil(value/step - 1e-9)
+	}
+	decimals := 0
+	if text := goex.FloatToString(step); strings.Contains(text, ".") {
+		decimals = len(text) - strings.Index(text, ".") - 1
+	}
+	return strconv.FormatFloat(count*step, 'f', decimals, 64)
+}
@@ -0,0 +1,648 @@
+package algo
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	goex "github.com/primitivelab/goexchange"
+	"github.com/primitivelab/goexchange/builder"
+	"github.com/primitivelab/goexchange/clientid"
+)
+
+// task kinds
+const (
+	KIND_CONDITIONAL = "conditional"
+	KIND_TWAP        = "twap"
+	KIND_VWAP        = "vwap"
+	KIND_ICEBERG     = "iceberg"
+)
+
+// task status
+const (
+	STATUS_ACTIVE   = "active"
+	STATUS_DONE     = "done"
+	STATUS_CANCELED = "canceled"
+	STATUS_FAILED   = "failed"
+)
+
+// Config engine config
+type Config struct {
+	// poll interval of Run, default 1 second
+	Interval time.Duration
+	// tasks are saved to Store after every change and loaded by New, nil
+	// keeps them in memory only
+	Store Store
+	// current time, default time.Now
+	Clock func() time.Time
+	// client order ids of placed orders, saved with the task before the
+	// order is sent, default a generator of the exchange rule, orders keep
+	// their own ClientOrderId
+	ClientIds *clientid.Generator
+}
+
+// Task conditional order or execution algo run by the engine
+type Task struct {
+	Id     string `json:"id"`
+	Kind   string `json:"kind"`
+	Status string `json:"status"`
+	// trigger order of conditional tasks, placed as a limit or market order
+	// once the trigger fires
+	Order *goex.PlaceOrder `json:"order,omitempty"`
+	Algo  *Algo            `json:"algo,omitempty"`
+	// trailing stop is activated and Extreme is the best price since then
+	Activated bool    `json:"activated"`
+	Extreme   float64 `json:"extreme"`
+	// execution progress, Placed amount of Slice child orders and Filled
+	// amount of finished iceberg child orders
+	StartTime int64   `json:"startTime"`
+	Slice     int     `json:"slice"`
+	Placed    float64 `json:"placed"`
+	Filled    float64 `json:"filled"`
+	// ids of placed orders, OpenOrderId is the iceberg child being filled
+	OrderIds    []string `json:"orderIds"`
+	OpenOrderId string   `json:"openOrderId"`
+	// order being placed, it is saved before the order is sent and a task
+	// restored with it looks the order up by ClientOrderId before placing it
+	Pending    *goex.PlaceOrder `json:"pending,omitempty"`
+	Error      string           `json:"error"`
+	CreateTime int64            `json:"createTime"`
+	UpdateTime int64            `json:"updateTime"`
+}
+
+// IsActive task is waiting for its trigger or placing child orders
+func (task *Task) IsActive() bool {
+	return task.Status == STATUS_ACTIVE
+}
+
+// Engine client side conditional orders and execution algos over a SpotAPI,
+// for exchanges without native stop orders, prices come from Poll or from
+// OnPrice of a stream
+type Engine struct {
+	api      goex.SpotAPI
+	config   Config
+	mutex    sync.Mutex
+	tasks    map[string]*Task
+	inflight map[string]bool
+	sequence int64
+	handlers []func(task *Task)
+}
+
+// New new instance, tasks saved in config.Store are restored
+func New(api goex.SpotAPI, config *Config) (*Engine, error) {
+	engine := &Engine{api: api, tasks: map[string]*Task{}, inflight: map[string]bool{}}
+	if config != nil {
+		engine.config = *config
+	}
+	if engine.config.ClientIds == nil {
+		engine.config.ClientIds = clientIds(api.GetExchangeName())
+	}
+	if engine.config.Interval == 0 {
+		engine.config.Interval = time.Second
+	}
+	if engine.config.Clock == nil {
+		engine.config.Clock = time.Now
+	}
+	if engine.config.Store == nil {
+		return engine, nil
+	}
+	data, err := engine.config.Store.Load()
+	if err != nil || len(data) == 0 {
+		return engine, err
+	}
+	var tasks []*Task
+	if err := json.Unmarshal(data, &tasks); err != nil {
+		return nil, err
+	}
+	for _, task := range tasks {
+		engine.tasks[task.Id] = task
+	}
+	return engine, nil
+}
+
+// NewWithBuilder new instance of exchange built by apiBuilder
+func NewWithBuilder(apiBuilder *builder.APIBuilder, exchange string, config *Config) (*Engine, error) {
+	api := apiBuilder.Build(exchange)
+	if api == nil {
+		return nil, errors.New("exchange is not supported: " + exchange)
+	}
+	return New(api, config)
+}
+
+// OnTask add handler called with a copy of every task changed by the engine
+func (engine *Engine) OnTask(handler func(task *Task)) {
+	engine.mutex.Lock()
+	defer engine.mutex.Unlock()
+	engine.handlers = append(engine.handlers, handler)
+}
+
+// Submit add conditional order, order is a trigger order of goex.IsTriggerOrder
+// placed at Price or at market when price reaches StopPrice, or trailing stop
+// placed at market when price falls back CallbackRate from its best
+func (engine *Engine) Submit(order *goex.PlaceOrder) (*Task, error) {
+	if err := goex.ValidateOrder(order); err != nil {
+		return nil, err
+	}
+	if !goex.IsTriggerOrder(order.TradeType) {
+		return nil, errors.New("conditional order needs a trigger trade type, got " + order.TradeType)
+	}
+	if order.ClosePosition {
+		return nil, errors.New("conditional order needs amount, close position is not supported")
+	}
+	copied := *order
+	return engine.add(&Task{Kind: KIND_CONDITIONAL, Order: &copied})
+}
+
+// SubmitAlgo add execution algo, vwap without profile takes volumes of the
+// latest klines as profile
+func (engine *Engine) SubmitAlgo(algo *Algo) (*Task, error) {
+	copied := *algo
+	if copied.Kind == KIND_VWAP && len(copied.Profile) == 0 && copied.Slices > 0 {
+		profile, err := engine.volumeProfile(&copied)
+		if err != nil {
+			return nil, err
+		}
+		copied.Profile = profile
+	}
+	if err := validateAlgo(&copied); err != nil {
+		return nil, err
+	}
+	return engine.add(&Task{Kind: copied.Kind, Algo: &copied})
+}
+
+// Cancel stop active task, the open iceberg child order is canceled too, it
+// is canceled without holding the mutex and a child placed meanwhile is
+// canceled next or once its place request returns
+func (engine *Engine) Cancel(id string) error {
+	engine.mutex.Lock()
+	for {
+		task, ok := engine.tasks[id]
+		if !ok || !task.IsActive() {
+			engine.mutex.Unlock()
+			return errors.New("no active task " + id)
+		}
+		orderId := task.OpenOrderId
+		if orderId == "" {
+			task.Status = STATUS_CANCELED
+			return engine.finish([]*Task{task})
+		}
+		symbol := task.Algo.Symbol
+		engine.mutex.Unlock()
+
+		if _, err := goex.ParseResponse(engine.api.CancelOrder(symbol, orderId, "")); err != nil {
+			return err
+		}
+
+		engine.mutex.Lock()
+		if task.OpenOrderId == orderId {
+			task.OpenOrderId = ""
+		}
+	}
+}
+
+// Task copy of task by id
+func (engine *Engine) Task(id string) (Task, bool) {
+	engine.mutex.Lock()
+	defer engine.mutex.Unlock()
+	task, ok := engine.tasks[id]
+	if !ok {
+		return Task{}, false
+	}
+	return *task, true
+}
+
+// Tasks copies of all tasks sorted by create time
+func (engine *Engine) Tasks() []Task {
+	engine.mutex.Lock()
+	defer engine.mutex.Unlock()
+	tasks := make([]Task, 0, len(engine.tasks))
+	for _, task := range engine.sorted() {
+		tasks = append(tasks, *task)
+	}
+	return tasks
+}
+
+// Run poll every interval until stop is closed
+func (engine *Engine) Run(stop <-chan struct{}) {
+	ticker := time.NewTicker(engine.config.Interval)
+	defer ticker.Stop()
+	for {
+		engine.Poll()
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Poll check conditional orders against the last price of their symbols,
+// then place due child orders of execution algos, the error is of saving state.
+// orders are placed and queried without holding the mutex
+func (engine *Engine) Poll() error {
+	engine.mutex.Lock()
+	symbols := map[string]goex.Symbol{}
+	for _, task := range engine.tasks {
+		if task.IsActive() && task.Kind == KIND_CONDITIONAL {
+			symbols[symbolKey(task.Order.Symbol)] = task.Order.Symbol
+		}
+	}
+	engine.mutex.Unlock()
+
+	for _, symbol := range symbols {
+		ticker, err := goex.ParseTicker(engine.api.GetTicker(symbol))
+		if err != nil || ticker.Last <= 0 {
+			continue
+		}
+		if err := engine.OnPrice(symbol, ticker.Last); err != nil {
+			return err
+		}
+	}
+
+	changed := engine.pollIcebergs()
+	// a round places at most one child order of every task, so twap and vwap
+	// catch up on due slices in several rounds
+	for {
+		var sends []*send
+		for _, task := range engine.sorted() {
+			if !task.IsActive() || engine.inflight[task.Id] {
+				continue
+			}
+			if task.Pending != nil {
+				// restored while its order was placed
+				sends = append(sends, &send{task: task, lookup: true})
+			} else if task.Kind != KIND_CONDITIONAL {
+				if engine.step(task) {
+					changed = appendTask(changed, task)
+				}
+				if task.Pending == nil {
+					continue
+				}
+				sends = append(sends, &send{task: task})
+			} else {
+				continue
+			}
+			engine.inflight[task.Id] = true
+		}
+		if len(sends) == 0 {
+			break
+		}
+		if err := engine.sendAll(sends); err != nil {
+			engine.mutex.Unlock()
+			return err
+		}
+		for _, item := range sends {
+			changed = appendTask(changed, item.task)
+		}
+	}
+	return engine.finish(changed)
+}
+
+// OnPrice check conditional orders of symbol against price, call it with
+// trade or ticker prices of a stream, the error is of saving state
+func (engine *Engine) OnPrice(symbol goex.Symbol, price float64) error {
+	engine.mutex.Lock()
+	var changed []*Task
+	var sends []*send
+	for _, task := range engine.sorted() {
+		if !task.IsActive() || task.Kind != KIND_CONDITIONAL || symbolKey(task.Order.Symbol) != symbolKey(symbol) {
+			continue
+		}
+		// a pending order is placed now or was restored and is left to Poll
+		if engine.inflight[task.Id] || task.Pending != nil {
+			continue
+		}
+		fire, moved := trigger(task, price)
+		if fire {
+			engine.fire(task)
+			engine.inflight[task.Id] = true
+			sends = append(sends, &send{task: task})
+		}
+		if fire || moved {
+			changed = append(changed, task)
+		}
+	}
+	if len(sends) > 0 {
+		if err := engine.sendAll(sends); err != nil {
+			engine.mutex.Unlock()
+			return err
+		}
+	}
+	return engine.finish(changed)
+}
+
+// pollIcebergs query open iceberg child orders without holding the mutex,
+// the mutex is held on return with the changed tasks
+func (engine *Engine) pollIcebergs() []*Task {
+	engine.mutex.Lock()
+	queries := map[*Task]string{}
+	for _, task := range engine.tasks {
+		if task.IsActive() && task.Kind == KIND_ICEBERG && task.OpenOrderId != "" && !engine.inflight[task.Id] {
+			queries[task] = task.OpenOrderId
+			engine.inflight[task.Id] = true
+		}
+	}
+	engine.mutex.Unlock()
+
+	orders := map[*Task]*goex.Order{}
+	for task, orderId := range queries {
+		// keep waiting on errors, the order may not be queryable yet
+		order, err := goex.ParseOrder(engine.api.GetUserOrderInfo(task.Algo.Symbol, orderId, ""))
+		if err == nil && !order.IsOpen() && order.Status != goex.ORDER_STATUS_UNKNOWN {
+			orders[task] = order
+		}
+	}
+
+	engine.mutex.Lock()
+	var changed []*Task
+	for task, orderId := range queries {
+		delete(engine.inflight, task.Id)
+		order, ok := orders[task]
+		// the child may have been canceled meanwhile
+		if !ok || task.OpenOrderId != orderId {
+			continue
+		}
+		task.Filled += order.FilledAmount
+		task.OpenOrderId = ""
+		changed = append(changed, task)
+	}
+	return changed
+}
+
+// add save new task
+func (engine *Engine) add(task *Task) (*Task, error) {
+	engine.mutex.Lock()
+	engine.sequence++
+	now := engine.now()
+	task.Id = fmt.Sprintf("%d-%d", now, engine.sequence)
+	task.Status = STATUS_ACTIVE
+	task.CreateTime = now
+	task.UpdateTime = now
+	engine.tasks[task.Id] = task
+	changed := engine.changed([]*Task{task})
+	err := engine.save()
+	handlers := engine.handlers
+	engine.mutex.Unlock()
+	notify(handlers, changed)
+	return changed[0], err
+}
+
+// finish save changed tasks, unlock and notify handlers
+func (engine *Engine) finish(tasks []*Task) error {
+	if len(tasks) == 0 {
+		engine.mutex.Unlock()
+		return nil
+	}
+	changed := engine.changed(tasks)
+	err := engine.save()
+	handlers := engine.handlers
+	engine.mutex.Unlock()
+	notify(handlers, changed)
+	return err
+}
+
+// changed stamp update time of tasks and copy them for handlers
+func (engine *Engine) changed(tasks []*Task) []*Task {
+	copies := make([]*Task, 0, len(tasks))
+	for _, task := range tasks {
+		task.UpdateTime = engine.now()
+		copied := *task
+		copied.OrderIds = append([]string{}, task.OrderIds...)
+		copies = append(copies, &copied)
+	}
+	return copies
+}
+
+// send pending order of a task, lookup when the task was restored with it
+type send struct {
+	task   *Task
+	lookup bool
+	id     string
+	err    error
+}
+
+// sendAll save the tasks with their pending orders, place the orders without
+// holding the mutex and commit them, the mutex is held on entry and return.
+// nothing is placed when saving fails
+func (engine *Engine) sendAll(sends []*send) error {
+	if err := engine.save(); err != nil {
+		for _, item := range sends {
+			delete(engine.inflight, item.task.Id)
+			if !item.lookup {
+				item.task.Pending = nil
+			}
+		}
+		return err
+	}
+	orders := make([]goex.PlaceOrder, len(sends))
+	for i, item := range sends {
+		orders[i] = *item.task.Pending
+	}
+	engine.mutex.Unlock()
+	for i, item := range sends {
+		item.id, item.err = engine.send(&orders[i], item.lookup)
+	}
+	engine.mutex.Lock()
+
+	var cancels []*send
+	for _, item := range sends {
+		delete(engine.inflight, item.task.Id)
+		engine.placed(item.task, item.id, item.err)
+		if item.task.Status == STATUS_CANCELED && item.task.OpenOrderId != "" {
+			cancels = append(cancels, item)
+		}
+	}
+	if len(cancels) == 0 {
+		return nil
+	}
+	// iceberg canceled while its child was placed
+	engine.mutex.Unlock()
+	for _, item := range cancels {
+		if _, err := goex.ParseResponse(engine.api.CancelOrder(item.task.Algo.Symbol, item.id, "")); err != nil {
+			item.id = ""
+		}
+	}
+	engine.mutex.Lock()
+	for _, item := range cancels {
+		if item.id != "" && item.task.OpenOrderId == item.id {
+			item.task.OpenOrderId = ""
+		}
+	}
+	return nil
+}
+
+// send place order, a restored order is looked up by its client order id
+// first, placing it again with the same id is rejected by exchanges that
+// check duplicate ids
+func (engine *Engine) send(order *goex.PlaceOrder, lookup bool) (string, error) {
+	if lookup {
+		if order.ClientOrderId == "" {
+			return "", errors.New("order may have been placed before restart and has no client order id to look it up")
+		}
+		placed, err := goex.ParseOrder(engine.api.GetUserOrderInfo(order.Symbol, "", order.ClientOrderId))
+		if err == nil && placed.OrderId != "" {
+			return placed.OrderId, nil
+		}
+	}
+	placed, err := goex.ParseOrder(engine.api.PlaceOrder(order))
+	if err != nil {
+		return "", err
+	}
+	return placed.OrderId, nil
+}
+
+// placed commit the pending order of task, the order is recorded even when
+// the task was canceled while it was placed
+func (engine *Engine) placed(task *Task, id string, err error) {
+	order := task.Pending
+	task.Pending = nil
+	if err != nil {
+		if task.IsActive() {
+			task.Status = STATUS_FAILED
+			task.Error = err.Error()
+		}
+		return
+	}
+	task.OrderIds = append(task.OrderIds, id)
+	task.Placed += goex.ToFloat(order.Amount)
+	switch task.Kind {
+	case KIND_CONDITIONAL:
+		if task.IsActive() {
+			task.Status = STATUS_DONE
+		}
+	case KIND_ICEBERG:
+		task.Slice++
+		task.OpenOrderId = id
+	default:
+		task.Slice++
+		if task.IsActive() && task.Slice >= task.Algo.Slices {
+			task.Status = STATUS_DONE
+		}
+	}
+}
+
+// fire set the order of triggered conditional task pending
+func (engine *Engine) fire(task *Task) {
+	order := *task.Order
+	order.TradeType = goex.MARKET
+	if goex.IsLimitOrder(task.Order.TradeType) {
+		order.TradeType = goex.LIMIT
+	}
+	order.StopPrice = ""
+	order.CallbackRate = ""
+	task.Pending = engine.pending(&order)
+}
+
+// pending order with a client order id to look it up after restart
+func (engine *Engine) pending(order *goex.PlaceOrder) *goex.PlaceOrder {
+	if order.ClientOrderId == "" && engine.config.ClientIds != nil {
+		order.ClientOrderId = engine.config.ClientIds.Next()
+	}
+	return order
+}
+
+// save write all tasks to store
+func (engine *Engine) save() error {
+	if engine.config.Store == nil {
+		return nil
+	}
+	data, err := json.Marshal(engine.sorted())
+	if err != nil {
+		return err
+	}
+	return engine.config.Store.Save(data)
+}
+
+// sorted tasks by create time and id
+func (engine *Engine) sorted() []*Task {
+	tasks := make([]*Task, 0, len(engine.tasks))
+	for _, task := range engine.tasks {
+		tasks = append(tasks, task)
+	}
+	sort.Slice(tasks, func(i, j int) bool {
+		if tasks[i].CreateTime != tasks[j].CreateTime {
+			return tasks[i].CreateTime < tasks[j].CreateTime
+		}
+		return tasks[i].Id < tasks[j].Id
+	})
+	return tasks
+}
+
+func (engine *Engine) now() int64 {
+	return engine.config.Clock().UnixNano() / int64(time.Millisecond)
+}
+
+// trigger check conditional task against price, moved is true when trailing
+// state changed without firing
+func trigger(task *Task, price float64) (fire bool, moved bool) {
+	order := task.Order
+	if order.TradeType != goex.TRAILING_STOP {
+		if goex.TriggerAbove(order) {
+			return price >= goex.ToFloat(order.StopPrice), false
+		}
+		return price <= goex.ToFloat(order.StopPrice), false
+	}
+	if !task.Activated {
+		// a sell trails from above StopPrice and a buy from below it
+		activation := goex.ToFloat(order.StopPrice)
+		if activation > 0 && ((order.Side == goex.SELL && price < activation) || (order.Side == goex.BUY && price > activation)) {
+			return false, false
+		}
+		task.Activated = true
+		task.Extreme = price
+		return false, true
+	}
+	rate := goex.ToFloat(order.CallbackRate) / 100
+	if order.Side == goex.SELL {
+		if price > task.Extreme {
+			task.Extreme = price
+			return false, true
+		}
+		return price <= task.Extreme*(1-rate), false
+	}
+	if price < task.Extreme {
+		task.Extreme = price
+		return false, true
+	}
+	return price >= task.Extreme*(1+rate), false
+}
+
+// clientIds generator of the exchange rule, nil when the exchange takes no
+// client order ids
+func clientIds(exchange string) *clientid.Generator {
+	rule, ok := clientid.GetRule(exchange)
+	if !ok {
+		return nil
+	}
+	strategy := "algo"
+	if rule.Numeric {
+		strategy = "1"
+	}
+	generator, _ := clientid.New(rule, strategy)
+	return generator
+}
+
+// appendTask append task once
+func appendTask(tasks []*Task, task *Task) []*Task {
+	for _, item := range tasks {
+		if item == task {
+			return tasks
+		}
+	}
+	return append(tasks, task)
+}
+
+func notify(handlers []func(task *Task), tasks []*Task) {
+	for _, task := range tasks {
+		for _, handler := range handlers {
+			handler(task)
+		}
+	}
+}
+
+func symbolKey(symbol goex.Symbol) string {
+	return symbol.ToLower().String()
+}
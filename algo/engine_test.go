@@ -0,0 +1,352 @@
+package algo
+
+import (
+	"testing"
+	"time"
+
+	goex "github.com/primitivelab/goexchange"
+	"github.com/primitivelab/goexchange/paper"
+)
+
+var btcUsdt = goex.NewSymbol("btc", "usdt")
+
+type memoryStore struct {
+	data []byte
+}
+
+func (store *memoryStore) Load() ([]byte, error) {
+	return store.data, nil
+}
+
+func (store *memoryStore) Save(data []byte) error {
+	store.data = append([]byte{}, data...)
+	return nil
+}
+
+type testClock struct {
+	now time.Time
+}
+
+func (clock *testClock) Now() time.Time {
+	return clock.now
+}
+
+func getPaperInstance() *paper.Spot {
+	spot := paper.NewSpot(nil, &paper.Config{
+		Balances: map[string]float64{"usdt": 100000, "btc": 10},
+	})
+	setPrice(spot, 100)
+	return spot
+}
+
+func setPrice(spot *paper.Spot, price float64) {
+	spot.SetDepth(btcUsdt, &goex.Depth{
+		Bids: []goex.DepthRecord{{Price: price - 1, Amount: 10}},
+		Asks: []goex.DepthRecord{{Price: price + 1, Amount: 10}},
+	})
+}
+
+func TestEngine_StopOrder(t *testing.T) {
+	spot := getPaperInstance()
+	engine, _ := New(spot, nil)
+	var fired []*Task
+	engine.OnTask(func(task *Task) {
+		if task.Status == STATUS_DONE {
+			fired = append(fired, task)
+		}
+	})
+
+	_, err := engine.Submit(&goex.PlaceOrder{Symbol: btcUsdt, Side: goex.SELL, TradeType: goex.STOP_MARKET, Amount: "1", StopPrice: "95"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = engine.Submit(&goex.PlaceOrder{Symbol: btcUsdt, Side: goex.SELL, TradeType: goex.TAKE_PROFIT_LIMIT, Amount: "1", Price: "110", StopPrice: "110"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	engine.Poll()
+	if len(fired) != 0 {
+		t.Fatalf("fired at price 100: %+v", fired[0])
+	}
+	setPrice(spot, 94)
+	engine.Poll()
+	if len(fired) != 1 || fired[0].Order.TradeType != goex.STOP_MARKET || len(fired[0].OrderIds) != 1 {
+		t.Fatalf("unexpected fired tasks %+v", fired)
+	}
+	orders := spot.GetOrders()
+	if len(orders) != 1 || orders[0].Type != goex.MARKET || orders[0].FilledAmount != 1 {
+		t.Fatalf("unexpected orders %+v", orders)
+	}
+	engine.OnPrice(btcUsdt, 111)
+	orders = spot.GetOrders()
+	if len(fired) != 2 || len(orders) != 2 || orders[1].Type != goex.LIMIT || orders[1].Price != 110 {
+		t.Fatalf("unexpected orders %+v", orders)
+	}
+}
+
+func TestEngine_TrailingStop(t *testing.T) {
+	engine, _ := New(getPaperInstance(), nil)
+	task, err := engine.Submit(&goex.PlaceOrder{Symbol: btcUsdt, Side: goex.SELL, TradeType: goex.TRAILING_STOP, Amount: "1", CallbackRate: "2", StopPrice: "105"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, price := range []float64{100, 106, 110, 108} {
+		engine.OnPrice(btcUsdt, price)
+	}
+	state, _ := engine.Task(task.Id)
+	if !state.IsActive() || !state.Activated || state.Extreme != 110 {
+		t.Fatalf("unexpected trailing state %+v", state)
+	}
+	engine.OnPrice(btcUsdt, 107.5)
+	if state, _ = engine.Task(task.Id); state.Status != STATUS_DONE {
+		t.Fatalf("trailing stop did not fire %+v", state)
+	}
+}
+
+func TestEngine_Submit(t *testing.T) {
+	engine, _ := New(getPaperInstance(), nil)
+	invalid := []goex.PlaceOrder{
+		{Symbol: btcUsdt, TradeType: goex.LIMIT, Amount: "1", Price: "100"},
+		{Symbol: btcUsdt, TradeType: goex.STOP_MARKET, Amount: "1"},
+		{Symbol: btcUsdt, TradeType: goex.STOP_MARKET, StopPrice: "90", ClosePosition: true},
+	}
+	for _, order := range invalid {
+		if _, err := engine.Submit(&order); err == nil {
+			t.Errorf("%+v: expected error", order)
+		}
+	}
+	if _, err := engine.SubmitAlgo(&Algo{Kind: KIND_ICEBERG, Symbol: btcUsdt, Side: goex.BUY, Amount: 1}); err == nil {
+		t.Error("expected error of iceberg without price")
+	}
+}
+
+func TestEngine_Twap(t *testing.T) {
+	spot := getPaperInstance()
+	clock := &testClock{now: time.Unix(1600000000, 0)}
+	engine, _ := New(spot, &Config{Clock: clock.Now})
+	task, err := engine.SubmitAlgo(&Algo{Kind: KIND_TWAP, Symbol: btcUsdt, Side: goex.BUY, Amount: 1, Duration: 4 * time.Minute, Slices: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	engine.Poll()
+	if state, _ := engine.Task(task.Id); state.Slice != 1 || state.Placed != 0.25 {
+		t.Fatalf("unexpected first slice %+v", state)
+	}
+	clock.now = clock.now.Add(2 * time.Minute)
+	engine.Poll()
+	if state, _ := engine.Task(task.Id); state.Slice != 3 || state.Placed != 0.75 {
+		t.Fatalf("unexpected slices %+v", state)
+	}
+	clock.now = clock.now.Add(5 * time.Minute)
+	engine.Poll()
+	state, _ := engine.Task(task.Id)
+	if state.Status != STATUS_DONE || state.Placed != 1 || len(spot.GetOrders()) != 4 {
+		t.Fatalf("unexpected final state %+v", state)
+	}
+}
+
+func TestEngine_Vwap(t *testing.T) {
+	clock := &testClock{now: time.Unix(1600000000, 0)}
+	engine, _ := New(getPaperInstance(), &Config{Clock: clock.Now})
+	task, err := engine.SubmitAlgo(&Algo{Kind: KIND_VWAP, Symbol: btcUsdt, Side: goex.SELL, Amount: 2, AmountPrecision: 2, Duration: time.Hour, Slices: 3, Profile: []float64{1, 2, 3}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	engine.Poll()
+	if state, _ := engine.Task(task.Id); state.Placed != 0.33 {
+		t.Fatalf("unexpected first slice %+v", state)
+	}
+	clock.now = clock.now.Add(time.Hour)
+	engine.Poll()
+	if state, _ := engine.Task(task.Id); state.Status != STATUS_DONE || state.Placed != 2 {
+		t.Fatalf("unexpected final state %+v", state)
+	}
+}
+
+func TestEngine_Iceberg(t *testing.T) {
+	spot := getPaperInstance()
+	engine, _ := New(spot, nil)
+	task, err := engine.SubmitAlgo(&Algo{Kind: KIND_ICEBERG, Symbol: btcUsdt, Side: goex.BUY, Amount: 1, Price: 101, VisibleAmount: 0.4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 5; i++ {
+		engine.Poll()
+	}
+	state, _ := engine.Task(task.Id)
+	if state.Status != STATUS_DONE || state.Filled != 1 || len(state.OrderIds) != 3 {
+		t.Fatalf("unexpected iceberg state %+v", state)
+	}
+	orders := spot.GetOrders()
+	if len(orders) != 3 || orders[2].Amount != 0.2 {
+		t.Fatalf("unexpected orders %+v", orders)
+	}
+}
+
+// lockCheckSpot reports whether the engine is locked while orders are placed,
+// queried or canceled
+type lockCheckSpot struct {
+	*paper.Spot
+	engine *Engine
+	locked bool
+}
+
+func (api *lockCheckSpot) check() {
+	done := make(chan struct{})
+	go func() {
+		api.engine.Tasks()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		api.locked = true
+	}
+}
+
+func (api *lockCheckSpot) PlaceOrder(order *goex.PlaceOrder) interface{} {
+	api.check()
+	return api.Spot.PlaceOrder(order)
+}
+
+func (api *lockCheckSpot) GetUserOrderInfo(symbol goex.Symbol, orderId, clientOrderId string) interface{} {
+	api.check()
+	return api.Spot.GetUserOrderInfo(symbol, orderId, clientOrderId)
+}
+
+func (api *lockCheckSpot) CancelOrder(symbol goex.Symbol, orderId, clientOrderId string) interface{} {
+	api.check()
+	return api.Spot.CancelOrder(symbol, orderId, clientOrderId)
+}
+
+func TestEngine_PlaceUnlocked(t *testing.T) {
+	api := &lockCheckSpot{Spot: getPaperInstance()}
+	engine, _ := New(api, nil)
+	api.engine = engine
+	task, err := engine.SubmitAlgo(&Algo{Kind: KIND_ICEBERG, Symbol: btcUsdt, Side: goex.BUY, Amount: 1, Price: 101, VisibleAmount: 0.4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	stop, err := engine.Submit(&goex.PlaceOrder{Symbol: btcUsdt, Side: goex.SELL, TradeType: goex.STOP_MARKET, Amount: "1", StopPrice: "95"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 5; i++ {
+		engine.Poll()
+	}
+	engine.OnPrice(btcUsdt, 94)
+	if api.locked {
+		t.Fatal("orders were placed or queried while the engine was locked")
+	}
+	if state, _ := engine.Task(task.Id); state.Status != STATUS_DONE || state.Filled != 1 {
+		t.Fatalf("unexpected iceberg state %+v", state)
+	}
+	if state, _ := engine.Task(stop.Id); state.Status != STATUS_DONE || len(state.OrderIds) != 1 || state.Pending != nil {
+		t.Fatalf("unexpected stop state %+v", state)
+	}
+}
+
+func TestEngine_Cancel(t *testing.T) {
+	api := &lockCheckSpot{Spot: getPaperInstance()}
+	engine, _ := New(api, nil)
+	api.engine = engine
+	task, err := engine.SubmitAlgo(&Algo{Kind: KIND_ICEBERG, Symbol: btcUsdt, Side: goex.BUY, Amount: 1, Price: 95, VisibleAmount: 0.4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	engine.Poll()
+	if state, _ := engine.Task(task.Id); state.OpenOrderId == "" {
+		t.Fatalf("iceberg child should rest %+v", state)
+	}
+	if err := engine.Cancel(task.Id); err != nil {
+		t.Fatal(err)
+	}
+	if api.locked {
+		t.Fatal("child order was canceled while the engine was locked")
+	}
+	if state, _ := engine.Task(task.Id); state.Status != STATUS_CANCELED || state.OpenOrderId != "" {
+		t.Fatalf("unexpected canceled state %+v", state)
+	}
+	if err := engine.Cancel(task.Id); err == nil {
+		t.Fatal("expected error of canceling twice")
+	}
+}
+
+func TestEngine_Restore(t *testing.T) {
+	store := &memoryStore{}
+	engine, _ := New(getPaperInstance(), &Config{Store: store})
+	task, err := engine.Submit(&goex.PlaceOrder{Symbol: btcUsdt, Side: goex.SELL, TradeType: goex.TRAILING_STOP, Amount: "1", CallbackRate: "1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	engine.OnPrice(btcUsdt, 120)
+
+	restored, err := New(getPaperInstance(), &Config{Store: store})
+	if err != nil {
+		t.Fatal(err)
+	}
+	state, ok := restored.Task(task.Id)
+	if !ok || !state.Activated || state.Extreme != 120 || state.Order.CallbackRate != "1" {
+		t.Fatalf("unexpected restored task %+v", state)
+	}
+	if err := restored.Cancel(task.Id); err != nil {
+		t.Fatal(err)
+	}
+	if err := restored.Cancel(task.Id); err == nil {
+		t.Fatal("expected error of canceling a canceled task")
+	}
+}
+
+// crashSpot keeps the store as it was when the last order was sent
+type crashSpot struct {
+	*paper.Spot
+	store *memoryStore
+	saved []byte
+}
+
+func (api *crashSpot) PlaceOrder(order *goex.PlaceOrder) interface{} {
+	api.saved = append([]byte{}, api.store.data...)
+	return api.Spot.PlaceOrder(order)
+}
+
+func TestEngine_RestorePending(t *testing.T) {
+	store := &memoryStore{}
+	api := &crashSpot{Spot: getPaperInstance(), store: store}
+	engine, _ := New(api, &Config{Store: store})
+	task, err := engine.Submit(&goex.PlaceOrder{Symbol: btcUsdt, Side: goex.SELL, TradeType: goex.STOP_MARKET, Amount: "1", StopPrice: "95"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	engine.OnPrice(btcUsdt, 94)
+	placed := api.Spot.GetOrders()
+	if len(placed) != 1 || placed[0].ClientOrderId == "" {
+		t.Fatalf("unexpected placed orders %+v", placed)
+	}
+
+	// crashed after the order was placed, it is found by client order id
+	restored, err := New(api.Spot, &Config{Store: &memoryStore{data: api.saved}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state, _ := restored.Task(task.Id); state.Pending == nil || state.Pending.ClientOrderId != placed[0].ClientOrderId {
+		t.Fatalf("unexpected restored task %+v", state)
+	}
+	restored.OnPrice(btcUsdt, 94)
+	restored.Poll()
+	state, _ := restored.Task(task.Id)
+	if state.Status != STATUS_DONE || len(state.OrderIds) != 1 || state.OrderIds[0] != placed[0].OrderId {
+		t.Fatalf("unexpected resolved task %+v", state)
+	}
+	if orders := api.Spot.GetOrders(); len(orders) != 1 {
+		t.Fatalf("order was placed again %+v", orders)
+	}
+
+	// crashed before the order was sent, it is placed once
+	spot := getPaperInstance()
+	restored, _ = New(spot, &Config{Store: &memoryStore{data: api.saved}})
+	restored.Poll()
+	restored.Poll()
+	if state, _ := restored.Task(task.Id); state.Status != STATUS_DONE || len(spot.GetOrders()) != 1 {
+		t.Fatalf("unexpected placed state %+v", state)
+	}
+}
@@ -0,0 +1,187 @@
+package algo
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	goex "github.com/primitivelab/goexchange"
+)
+
+// amountEpsilon amounts below it are treated as zero
+const amountEpsilon = 1e-12
+
+// Algo execution algo splitting Amount into child orders, children are limit
+// orders at Price or market orders when Price is 0, twap places Slices equal
+// children over Duration, vwap weights them by Profile and iceberg shows
+// VisibleAmount at Price until Amount is filled
+type Algo struct {
+	Kind   string         `json:"kind"`
+	Symbol goex.Symbol    `json:"symbol"`
+	Side   goex.TradeSide `json:"side"`
+	// total amount in base coin
+	Amount float64 `json:"amount"`
+	Price  float64 `json:"price"`
+	// decimals of child amounts, the last child takes the rest, 0 for no rounding
+	AmountPrecision int `json:"amountPrecision"`
+	// twap and vwap run time and number of child orders
+	Duration time.Duration `json:"duration"`
+	Slices   int           `json:"slices"`
+	// vwap weight of every slice, default volumes of the latest Slices klines
+	// of KlinePeriod, default goex.KLINE_PERIOD_1MINUTE
+	Profile     []float64 `json:"profile"`
+	KlinePeriod int       `json:"klinePeriod"`
+	// iceberg amount of every child order
+	VisibleAmount float64 `json:"visibleAmount"`
+}
+
+// validateAlgo check the fields kind of algo requires
+func validateAlgo(algo *Algo) error {
+	if algo.Side != goex.BUY && algo.Side != goex.SELL {
+		return errors.New("side must be buy or sell")
+	}
+	if algo.Amount <= 0 || algo.Price < 0 {
+		return errors.New("amount must be positive and price not negative")
+	}
+	switch algo.Kind {
+	case KIND_TWAP, KIND_VWAP:
+		if algo.Duration <= 0 || algo.Slices <= 0 {
+			return errors.New("duration and slices must be positive")
+		}
+		if algo.Kind == KIND_TWAP {
+			return nil
+		}
+		if len(algo.Profile) != algo.Slices {
+			return errors.New("vwap profile needs a weight of every slice")
+		}
+		total := 0.0
+		for _, weight := range algo.Profile {
+			if weight < 0 {
+				return errors.New("vwap profile weights must not be negative")
+			}
+			total += weight
+		}
+		if total <= 0 {
+			return errors.New("vwap profile has no weight")
+		}
+	case KIND_ICEBERG:
+		if algo.Price <= 0 || algo.VisibleAmount <= 0 {
+			return errors.New("iceberg needs price and visible amount")
+		}
+	default:
+		return errors.New("unknown algo kind " + algo.Kind)
+	}
+	return nil
+}
+
+// volumeProfile volumes of the latest klines, one for every slice
+func (engine *Engine) volumeProfile(algo *Algo) ([]float64, error) {
+	period := algo.KlinePeriod
+	if period == 0 {
+		period = goex.KLINE_PERIOD_1MINUTE
+	}
+	klines, err := goex.ParseKline(engine.api.GetExchangeName(), engine.api.GetKline(algo.Symbol, period, algo.Slices, nil))
+	if err != nil {
+		return nil, err
+	}
+	if len(klines) < algo.Slices {
+		return nil, errors.New("not enough klines for vwap profile")
+	}
+	profile := make([]float64, 0, algo.Slices)
+	for _, kline := range klines[len(klines)-algo.Slices:] {
+		profile = append(profile, kline.Volume)
+	}
+	return profile, nil
+}
+
+// step set the next due child order of execution task pending, true when
+// task changed otherwise
+func (engine *Engine) step(task *Task) bool {
+	if task.StartTime == 0 {
+		task.StartTime = engine.now()
+	}
+	if task.Kind == KIND_ICEBERG {
+		return engine.stepIceberg(task)
+	}
+	algo := task.Algo
+	elapsed := engine.now() - task.StartTime
+	due := algo.Slices
+	if duration := algo.Duration.Milliseconds(); duration > 0 && elapsed < duration {
+		due = int(elapsed*int64(algo.Slices)/duration) + 1
+	}
+	if due > algo.Slices {
+		due = algo.Slices
+	}
+	changed := false
+	for task.Slice < due {
+		target := algo.Amount * algo.weight(task.Slice+1)
+		amount := roundAmount(target-task.Placed, algo.AmountPrecision)
+		if task.Slice+1 == algo.Slices {
+			amount = roundAmount(algo.Amount-task.Placed, algo.AmountPrecision)
+		}
+		if amount > amountEpsilon {
+			task.Pending = engine.pending(childOrder(algo, amount))
+			return changed
+		}
+		task.Slice++
+		changed = true
+	}
+	if task.Slice >= algo.Slices {
+		task.Status = STATUS_DONE
+	}
+	return changed
+}
+
+// stepIceberg set the next child order pending once the open child finished
+func (engine *Engine) stepIceberg(task *Task) bool {
+	algo := task.Algo
+	if task.OpenOrderId != "" {
+		return false
+	}
+	amount := roundAmount(math.Min(algo.VisibleAmount, algo.Amount-task.Filled), algo.AmountPrecision)
+	if task.Filled >= algo.Amount-amountEpsilon || amount <= amountEpsilon {
+		task.Status = STATUS_DONE
+		return true
+	}
+	task.Pending = engine.pending(childOrder(algo, amount))
+	return false
+}
+
+// weight share of Amount placed after the first slices
+func (algo *Algo) weight(slices int) float64 {
+	if algo.Kind != KIND_VWAP {
+		return float64(slices) / float64(algo.Slices)
+	}
+	total, done := 0.0, 0.0
+	for i, weight := range algo.Profile {
+		total += weight
+		if i < slices {
+			done += weight
+		}
+	}
+	return done / total
+}
+
+func childOrder(algo *Algo, amount float64) *goex.PlaceOrder {
+	order := &goex.PlaceOrder{
+		Symbol:    algo.Symbol,
+		Side:      algo.Side,
+		Amount:    goex.FloatToString(amount),
+		TradeType: goex.MARKET,
+	}
+	if algo.Price > 0 {
+		order.TradeType = goex.LIMIT
+		order.Price = goex.FloatToString(algo.Price)
+	}
+	return order
+}
+
+// roundAmount floor amount to precision decimals, precision 0 only rounds
+// off float noise of subtracting amounts
+func roundAmount(amount float64, precision int) float64 {
+	if precision <= 0 {
+		return math.Round(amount*1e10) / 1e10
+	}
+	pow := math.Pow(10, float64(precision))
+	return math.Floor(amount*pow+1e-9) / pow
+}
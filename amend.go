@@ -0,0 +1,137 @@
+package goexchange
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// AmendOrder new price or amount of a resting limit order, empty Price or
+// Amount keeps the old one, Amount is the new total amount including the
+// filled part
+type AmendOrder struct {
+	Symbol        Symbol
+	OrderId       string
+	ClientOrderId string
+	Side          TradeSide
+	Price         string
+	Amount        string
+	// 交割合约类型, 如 CONTRACT_QUARTER
+	ContractType string
+	// 合约开平方向 of the replacing order when the order is cancelled and placed again
+	Offset string
+	// client order id of the new order of exchanges that replace the order
+	NewClientOrderId string
+}
+
+// AmendAPI adapters amending orders with a native endpoint, which keeps
+// queue priority where the exchange allows it, data: order id
+type AmendAPI interface {
+	AmendOrder(order *AmendOrder) interface{}
+}
+
+// OrderAPI order methods shared by SpotAPI, MarginAPI and swap adapters,
+// which is all ReplaceOrder needs
+type OrderAPI interface {
+	PlaceOrder(order *PlaceOrder) interface{}
+	CancelOrder(symbol Symbol, orderId, clientOrderId string) interface{}
+	GetUserOrderInfo(symbol Symbol, orderId, clientOrderId string) interface{}
+}
+
+// replaceConfirmAttempts order info queries confirming the cancel of
+// ReplaceOrder before it gives up without placing
+var replaceConfirmAttempts = 3
+
+// replaceConfirmInterval wait between the queries
+var replaceConfirmInterval = 200 * time.Millisecond
+
+// ValidateAmendOrder check amend order has an order id and a new price or amount
+func ValidateAmendOrder(order *AmendOrder) error {
+	if order.OrderId == "" && order.ClientOrderId == "" {
+		return errors.New("order id or client order id is required")
+	}
+	if order.Price == "" && order.Amount == "" {
+		return errors.New("price or amount is required")
+	}
+	if order.Price != "" && ToFloat(order.Price) <= 0 {
+		return fmt.Errorf("price %q must be positive", order.Price)
+	}
+	if order.Amount != "" && ToFloat(order.Amount) <= 0 {
+		return fmt.Errorf("amount %q must be positive", order.Amount)
+	}
+	return nil
+}
+
+// ReplaceOrder amend order with the native endpoint of api when it is an
+// AmendAPI, otherwise cancel the order, confirm it is closed and place a limit
+// order of its unfilled amount at the new price, nothing is placed when the
+// cancel is not confirmed, data: order id of the replacing order
+func ReplaceOrder(api OrderAPI, order *AmendOrder) interface{} {
+	if err := ValidateAmendOrder(order); err != nil {
+		return InvalidOrder(err)
+	}
+	if amender, ok := api.(AmendAPI); ok {
+		return amender.AmendOrder(order)
+	}
+
+	result := api.GetUserOrderInfo(order.Symbol, order.OrderId, order.ClientOrderId)
+	current, err := ParseOrder(result)
+	if err != nil {
+		return result
+	}
+	if !current.IsOpen() {
+		return InvalidOrder(errors.New("order is not open, status " + current.Status))
+	}
+	result = api.CancelOrder(order.Symbol, order.OrderId, order.ClientOrderId)
+	if _, err := ParseResponse(result); err != nil {
+		return result
+	}
+	for attempt := 0; ; attempt++ {
+		current, err = ParseOrder(api.GetUserOrderInfo(order.Symbol, order.OrderId, order.ClientOrderId))
+		if err == nil && !current.IsOpen() && current.Status != ORDER_STATUS_UNKNOWN {
+			break
+		}
+		if attempt+1 >= replaceConfirmAttempts {
+			return replaceError(errors.New("cancel is not confirmed, order is not replaced"))
+		}
+		time.Sleep(replaceConfirmInterval)
+	}
+
+	amount := current.Amount
+	if order.Amount != "" {
+		amount = ToFloat(order.Amount)
+	}
+	amount -= current.FilledAmount
+	if amount <= 0 {
+		return replaceError(errors.New("order is filled, nothing is left to replace"))
+	}
+	replacing := &PlaceOrder{
+		Symbol:        order.Symbol,
+		ClientOrderId: order.NewClientOrderId,
+		Price:         order.Price,
+		Amount:        FloatToString(amount),
+		Side:          order.Side,
+		TradeType:     LIMIT,
+		ContractType:  order.ContractType,
+		Offset:        order.Offset,
+	}
+	if replacing.Price == "" {
+		replacing.Price = FloatToString(current.Price)
+	}
+	if replacing.Side != BUY && replacing.Side != SELL {
+		replacing.Side = current.Side
+	}
+	result = api.PlaceOrder(replacing)
+	placed, err := ParseOrder(result)
+	if err != nil {
+		return result
+	}
+	return ReturnAPIData(placed.OrderId)
+}
+
+// replaceError error response of ReplaceOrder
+func replaceError(err error) map[string]interface{} {
+	retData := ReturnAPIError(OrderReplaceError).(map[string]interface{})
+	retData["error"] = err.Error()
+	return retData
+}
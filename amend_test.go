@@ -0,0 +1,92 @@
+package goexchange
+
+import (
+	"testing"
+)
+
+type fakeOrderAPI struct {
+	status   string
+	placed   []*PlaceOrder
+	canceled int
+}
+
+func (api *fakeOrderAPI) PlaceOrder(order *PlaceOrder) interface{} {
+	api.placed = append(api.placed, order)
+	return ReturnAPIData(map[string]interface{}{"orderId": "2"})
+}
+
+func (api *fakeOrderAPI) CancelOrder(symbol Symbol, orderId, clientOrderId string) interface{} {
+	api.canceled++
+	return ReturnAPIData(map[string]interface{}{"orderId": orderId})
+}
+
+func (api *fakeOrderAPI) GetUserOrderInfo(symbol Symbol, orderId, clientOrderId string) interface{} {
+	status := api.status
+	if api.canceled == 0 {
+		status = "NEW"
+	}
+	return ReturnAPIData(map[string]interface{}{
+		"orderId": orderId, "side": "SELL", "price": "100", "origQty": "3", "executedQty": "1", "status": status,
+	})
+}
+
+type fakeAmendAPI struct {
+	fakeOrderAPI
+	amended []*AmendOrder
+}
+
+func (api *fakeAmendAPI) AmendOrder(order *AmendOrder) interface{} {
+	api.amended = append(api.amended, order)
+	return ReturnAPIData(order.OrderId)
+}
+
+func TestValidateAmendOrder(t *testing.T) {
+	tests := []struct {
+		order AmendOrder
+		valid bool
+	}{
+		{AmendOrder{OrderId: "1", Price: "100"}, true},
+		{AmendOrder{ClientOrderId: "c1", Amount: "2"}, true},
+		{AmendOrder{Price: "100"}, false},
+		{AmendOrder{OrderId: "1"}, false},
+		{AmendOrder{OrderId: "1", Price: "-1"}, false},
+	}
+	for _, test := range tests {
+		if err := ValidateAmendOrder(&test.order); (err == nil) != test.valid {
+			t.Errorf("%+v: expected valid %v, got %v", test.order, test.valid, err)
+		}
+	}
+}
+
+func TestReplaceOrder(t *testing.T) {
+	api := &fakeOrderAPI{status: "CANCELED"}
+	data, err := ParseResponse(ReplaceOrder(api, &AmendOrder{Symbol: NewSymbol("btc", "usdt"), OrderId: "1", Price: "101"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data != "2" || api.canceled != 1 || len(api.placed) != 1 {
+		t.Fatalf("unexpected replace %v %+v", data, api)
+	}
+	placed := api.placed[0]
+	if placed.Side != SELL || placed.Price != "101" || placed.Amount != "2" || placed.TradeType != LIMIT {
+		t.Fatalf("unexpected replacing order %+v", placed)
+	}
+
+	amender := &fakeAmendAPI{}
+	ReplaceOrder(amender, &AmendOrder{OrderId: "1", Amount: "5"})
+	if len(amender.amended) != 1 || amender.canceled != 0 {
+		t.Fatalf("native amend is not used %+v", amender)
+	}
+}
+
+func TestReplaceOrder_NotConfirmed(t *testing.T) {
+	interval := replaceConfirmInterval
+	replaceConfirmInterval = 0
+	defer func() { replaceConfirmInterval = interval }()
+
+	api := &fakeOrderAPI{status: "NEW"}
+	retData := ReplaceOrder(api, &AmendOrder{OrderId: "1", Price: "101"}).(map[string]interface{})
+	if retData["code"] != OrderReplaceError.Code || len(api.placed) != 0 {
+		t.Fatalf("order placed without confirmed cancel %v", retData)
+	}
+}
@@ -1,6 +1,9 @@
 package goexchange
 
-import "time"
+import (
+	"fmt"
+	"time"
+)
 
 type ApiStatusCode struct {
 	Code int
@@ -13,6 +16,13 @@ var (
 	ExchangeError           = ApiStatusCode{Code: 1003, Msg: "exchange api error"}
 	HttpRequestError        = ApiStatusCode{Code: 404, Msg: "http request error"}
 	MethodNotExistError     = ApiStatusCode{Code: 1004, Msg: "method is not exist"}
+	WithdrawDeniedError     = ApiStatusCode{Code: 1005, Msg: "withdraw is denied"}
+	TransferAccountError    = ApiStatusCode{Code: 1006, Msg: "transfer account is not supported"}
+	ContractNotFoundError   = ApiStatusCode{Code: 1007, Msg: "contract is not found"}
+	LeverageSettingError    = ApiStatusCode{Code: 1008, Msg: "leverage setting is invalid"}
+	OrderParamsError        = ApiStatusCode{Code: 1009, Msg: "order params are invalid"}
+	OrderReplaceError       = ApiStatusCode{Code: 1010, Msg: "order is not replaced"}
+	TimeInForceParamsError  = ApiStatusCode{Code: 1011, Msg: "time in force is not supported"}
 
 	// HTTP_ERR_CODE                = ApiError{Code: "HTTP_ERR_0001", Msg: "http request error"}
 	// EX_ERR_API_LIMIT             = ApiError{Code: "EX_ERR_1000", Msg: "api limited"}
@@ -43,3 +53,39 @@ func ReturnAPIError(code ApiStatusCode) interface{} {
 		"data":  nil}
 	return retData
 }
+
+// APIError error of api response map
+type APIError struct {
+	Code interface{}
+	Msg  string
+	Err  string
+}
+
+func (err *APIError) Error() string {
+	return fmt.Sprintf("code: %v, msg: %s, error: %s", err.Code, err.Msg, err.Err)
+}
+
+// ParseResponse split api response map into data and error
+func ParseResponse(result interface{}) (interface{}, error) {
+	retData, ok := result.(map[string]interface{})
+	if !ok {
+		return nil, &APIError{Code: JsonUnmarshalError.Code, Msg: JsonUnmarshalError.Msg, Err: "response is not a map"}
+	}
+	// code is float64 when the response map was stored as json and loaded back
+	if code, ok := retData["code"]; ok && code != 0 && code != float64(0) {
+		msg, _ := retData["msg"].(string)
+		errMsg, _ := retData["error"].(string)
+		return nil, &APIError{Code: code, Msg: msg, Err: errMsg}
+	}
+	return retData["data"], nil
+}
+
+// ReturnAPIData return data in api response map
+func ReturnAPIData(data interface{}) map[string]interface{} {
+	startTime := time.Now().UnixNano() / 1e6
+	return map[string]interface{}{
+		"code": 0,
+		"st":   startTime,
+		"et":   startTime,
+		"data": data}
+}
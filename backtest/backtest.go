@@ -0,0 +1,269 @@
+package backtest
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	goex "github.com/primitivelab/goexchange"
+	"github.com/primitivelab/goexchange/paper"
+)
+
+// Event one replayed market event, either Kline or Trade is set, Time is when
+// the strategy sees it: close time of kline or time of trade
+type Event struct {
+	Time   int64
+	Symbol goex.Symbol
+	Kline  *goex.Kline
+	Trade  *goex.Trade
+
+	period int64
+}
+
+// Strategy receives every event in time order and trades through api
+type Strategy interface {
+	OnEvent(api goex.SpotAPI, event *Event)
+}
+
+// StrategyFunc function as strategy
+type StrategyFunc func(api goex.SpotAPI, event *Event)
+
+// OnEvent call f
+func (f StrategyFunc) OnEvent(api goex.SpotAPI, event *Event) {
+	f(api, event)
+}
+
+// Config backtest config
+type Config struct {
+	// initial free balance by coin, eg: {"usdt": 10000}
+	Balances map[string]float64
+	Fees     *paper.FeeSchedule
+	// milliseconds between placing an order and it reaching the book
+	Latency int64
+	// half spread of the synthetic book around replayed prices, default 0.0005
+	Spread float64
+	// share of replayed volume our orders can fill against, default 0.1
+	Participation float64
+	// coin equity is valued in, default quote coin of the first symbol added
+	Quote string
+}
+
+// Backtester replays stored klines and trades through a paper spot exchange
+type Backtester struct {
+	config  Config
+	now     int64
+	spot    *paper.Spot
+	events  []*Event
+	symbols map[string]goex.Symbol
+	prices  map[string]float64
+	history map[string][]goex.Kline
+}
+
+// New new instance
+func New(config *Config) *Backtester {
+	backtester := &Backtester{
+		symbols: map[string]goex.Symbol{},
+		prices:  map[string]float64{},
+		history: map[string][]goex.Kline{},
+	}
+	if config != nil {
+		backtester.config = *config
+	}
+	if backtester.config.Spread == 0 {
+		backtester.config.Spread = 0.0005
+	}
+	if backtester.config.Participation == 0 {
+		backtester.config.Participation = 0.1
+	}
+	return backtester
+}
+
+// AddKlines add klines of symbol, period is one of goex.KLINE_PERIOD_*
+func (backtester *Backtester) AddKlines(symbol goex.Symbol, period int, klines []goex.Kline) error {
+	duration := goex.KlinePeriodMillisecond(period)
+	if duration == 0 {
+		return errors.New("unknown kline period")
+	}
+	backtester.addSymbol(symbol)
+	for i := range klines {
+		kline := klines[i]
+		backtester.events = append(backtester.events, &Event{
+			Time:   kline.Time + duration,
+			Symbol: symbol,
+			Kline:  &kline,
+			period: duration,
+		})
+	}
+	return nil
+}
+
+// AddTrades add market trades of symbol
+func (backtester *Backtester) AddTrades(symbol goex.Symbol, trades []goex.Trade) {
+	backtester.addSymbol(symbol)
+	for i := range trades {
+		trade := trades[i]
+		backtester.events = append(backtester.events, &Event{
+			Time:   trade.Time,
+			Symbol: symbol,
+			Trade:  &trade,
+		})
+	}
+}
+
+// Now current replay time in millisecond
+func (backtester *Backtester) Now() int64 {
+	return backtester.now
+}
+
+// Klines last size klines of symbol closed so far, oldest first, all when size is 0
+func (backtester *Backtester) Klines(symbol goex.Symbol, size int) []goex.Kline {
+	history := backtester.history[symbolKey(symbol)]
+	if size > 0 && len(history) > size {
+		history = history[len(history)-size:]
+	}
+	return append([]goex.Kline{}, history...)
+}
+
+// Spot simulated exchange of the last run
+func (backtester *Backtester) Spot() *paper.Spot {
+	return backtester.spot
+}
+
+// Run replay all events in time order through strategy and report the result
+func (backtester *Backtester) Run(strategy Strategy) *Report {
+	sort.SliceStable(backtester.events, func(i, j int) bool {
+		return backtester.events[i].Time < backtester.events[j].Time
+	})
+	backtester.now = 0
+	backtester.prices = map[string]float64{}
+	backtester.history = map[string][]goex.Kline{}
+	backtester.spot = paper.NewSpot(nil, &paper.Config{
+		Balances: backtester.config.Balances,
+		Fees:     backtester.config.Fees,
+		Latency:  backtester.config.Latency,
+		Clock:    backtester.Now,
+	})
+
+	report := &Report{Quote: backtester.config.Quote}
+	for _, event := range backtester.events {
+		backtester.replay(event)
+		backtester.now = event.Time
+		if event.Kline != nil {
+			key := symbolKey(event.Symbol)
+			backtester.history[key] = append(backtester.history[key], *event.Kline)
+		}
+		if len(report.Equity) == 0 {
+			report.Start = event.Time
+			report.InitialEquity = backtester.equity()
+		}
+		strategy.OnEvent(backtester.spot, event)
+		report.Equity = append(report.Equity, EquityPoint{Time: event.Time, Equity: backtester.equity()})
+	}
+	backtester.summarize(report)
+	return report
+}
+
+// replay feed market of event to the paper exchange, a kline is replayed as
+// four trades open, high/low, low/high, close spread across the bar
+func (backtester *Backtester) replay(event *Event) {
+	if event.Trade != nil {
+		backtester.trade(event.Symbol, event.Trade.Time, event.Trade.Price, event.Trade.Amount)
+		return
+	}
+
+	kline := event.Kline
+	path := []float64{kline.Open, kline.Low, kline.High, kline.Close}
+	if kline.Close < kline.Open {
+		path = []float64{kline.Open, kline.High, kline.Low, kline.Close}
+	}
+	for i, price := range path {
+		backtester.trade(event.Symbol, kline.Time+int64(i)*event.period/int64(len(path)), price, kline.Volume/float64(len(path)))
+	}
+}
+
+// trade replay one trade: orders reaching the book take from a synthetic book
+// around price and resting orders fill against the trade, sharing its amount
+func (backtester *Backtester) trade(symbol goex.Symbol, time int64, price, amount float64) {
+	if price <= 0 {
+		return
+	}
+	backtester.now = time
+	backtester.prices[symbolKey(symbol)] = price
+	amount *= backtester.config.Participation
+	if amount <= 0 {
+		return
+	}
+	spread := price * backtester.config.Spread
+	backtester.spot.ReplayTrade(symbol, &goex.Depth{
+		Symbol: symbol,
+		Time:   time,
+		Bids:   []goex.DepthRecord{{Price: price - spread, Amount: amount}},
+		Asks:   []goex.DepthRecord{{Price: price + spread, Amount: amount}},
+	}, price, amount)
+}
+
+func (backtester *Backtester) addSymbol(symbol goex.Symbol) {
+	backtester.symbols[symbolKey(symbol)] = symbol
+	if backtester.config.Quote == "" {
+		backtester.config.Quote = strings.ToLower(symbol.CoinTo)
+	}
+}
+
+// value price of coin in quote coin, 0 when no replayed symbol prices it
+func (backtester *Backtester) value(coin string) float64 {
+	coin = strings.ToLower(coin)
+	if coin == backtester.config.Quote {
+		return 1
+	}
+	for key, symbol := range backtester.symbols {
+		if strings.ToLower(symbol.CoinFrom) == coin && strings.ToLower(symbol.CoinTo) == backtester.config.Quote {
+			return backtester.prices[key]
+		}
+	}
+	return 0
+}
+
+// equity value of all balances in quote coin
+func (backtester *Backtester) equity() float64 {
+	equity := 0.0
+	for coin, balance := range backtester.spot.GetBalances() {
+		equity += (balance.Free + balance.Locked) * backtester.value(coin)
+	}
+	return equity
+}
+
+// LoadKlines load klines stored as json of a GetKline response or its data
+func LoadKlines(exchange, file string) ([]goex.Kline, error) {
+	data, err := loadJSON(file)
+	if err != nil {
+		return nil, err
+	}
+	return goex.ParseKline(exchange, data)
+}
+
+// LoadTrades load trades stored as json of a GetTrade response or its data
+func LoadTrades(file string) ([]goex.Trade, error) {
+	data, err := loadJSON(file)
+	if err != nil {
+		return nil, err
+	}
+	return goex.ParseTrade(data)
+}
+
+func loadJSON(file string) (interface{}, error) {
+	body, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var data interface{}
+	if err := json.Unmarshal(body, &data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func symbolKey(symbol goex.Symbol) string {
+	return symbol.ToLower().String()
+}
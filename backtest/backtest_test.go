@@ -0,0 +1,135 @@
+package backtest
+
+import (
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	goex "github.com/primitivelab/goexchange"
+	"github.com/primitivelab/goexchange/paper"
+)
+
+var btcUsdt = goex.NewSymbol("btc", "usdt")
+
+func getKlines() []goex.Kline {
+	closes := []float64{100, 110, 90, 120}
+	klines := []goex.Kline{}
+	for i, price := range closes {
+		klines = append(klines, goex.Kline{
+			Time:   int64(i) * 60000,
+			Open:   price,
+			High:   price,
+			Low:    price,
+			Close:  price,
+			Volume: 100,
+		})
+	}
+	return klines
+}
+
+func TestBacktester_BuyAndHold(t *testing.T) {
+	backtester := New(&Config{
+		Balances: map[string]float64{"usdt": 10000},
+		Fees:     &paper.FeeSchedule{Default: paper.Fee{Maker: 0.001, Taker: 0.001}},
+		Spread:   0.0001,
+	})
+	if err := backtester.AddKlines(btcUsdt, goex.KLINE_PERIOD_1MINUTE, getKlines()); err != nil {
+		t.Fatal(err)
+	}
+
+	report := backtester.Run(StrategyFunc(func(api goex.SpotAPI, event *Event) {
+		if event.Kline.Time == 0 {
+			api.PlaceMarketOrder(btcUsdt, "20", goex.BUY, "")
+		}
+	}))
+	if report.Start != 60000 || report.End != 240000 || report.InitialEquity != 10000 {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+	if report.Orders != 1 || report.PartiallyFilled != 1 || report.ExpiredOrders != 1 || report.Fills != 1 || report.TakerFills != 1 {
+		t.Fatalf("market order must fill partially against 10%% of bar volume: %+v", report)
+	}
+	if math.Abs(report.FillRatio-0.125) > 1e-9 {
+		t.Fatalf("unexpected fill ratio: %v", report.FillRatio)
+	}
+	balances := backtester.Spot().GetBalances()
+	if math.Abs(balances["btc"].Free-2.5*0.999) > 1e-9 {
+		t.Fatalf("unexpected balances: %v", balances)
+	}
+	if report.FinalEquity <= report.InitialEquity || report.PnL != report.FinalEquity-report.InitialEquity {
+		t.Fatalf("unexpected pnl: %+v", report)
+	}
+	if report.MaxDrawdown <= 0 || report.Turnover <= 250 || report.Fees <= 0 {
+		t.Fatalf("unexpected drawdown, turnover or fees: %+v", report)
+	}
+}
+
+func TestBacktester_LatencyAndLimitOrder(t *testing.T) {
+	backtester := New(&Config{
+		Balances: map[string]float64{"usdt": 10000},
+		Latency:  30000,
+	})
+	backtester.AddKlines(btcUsdt, goex.KLINE_PERIOD_1MINUTE, []goex.Kline{
+		{Time: 0, Open: 100, High: 100, Low: 100, Close: 100, Volume: 100},
+		{Time: 60000, Open: 100, High: 101, Low: 94, Close: 96, Volume: 100},
+	})
+	backtester.AddTrades(btcUsdt, []goex.Trade{{Time: 130000, Price: 95, Amount: 50}})
+
+	var orderID string
+	report := backtester.Run(StrategyFunc(func(api goex.SpotAPI, event *Event) {
+		if event.Kline != nil && event.Kline.Time == 0 {
+			data, _ := goex.ParseResponse(api.PlaceLimitOrder(btcUsdt, "95", "4", goex.BUY, "c1"))
+			orderID = data.(map[string]interface{})["orderId"].(string)
+		}
+	}))
+	if report.TakerFills != 1 || report.MakerFills != 1 {
+		t.Fatalf("limit order must take liquidity when it reaches the book and rest for the rest: %+v", report)
+	}
+	order := backtester.Spot().GetUserOrderInfo(btcUsdt, orderID, "")
+	data, _ := goex.ParseResponse(order)
+	if data.(map[string]interface{})["status"] != paper.ORDER_FILLED || goex.ToFloat(data.(map[string]interface{})["time"]) != 60000 {
+		t.Fatalf("unexpected order: %v", data)
+	}
+	if klines := backtester.Klines(btcUsdt, 1); len(klines) != 1 || klines[0].Time != 60000 {
+		t.Fatalf("unexpected kline history: %v", klines)
+	}
+}
+
+func TestBacktester_Participation(t *testing.T) {
+	backtester := New(&Config{Balances: map[string]float64{"usdt": 10000}})
+	backtester.AddTrades(btcUsdt, []goex.Trade{
+		{Time: 0, Price: 105, Amount: 10},
+		{Time: 1000, Price: 99, Amount: 100},
+		{Time: 2000, Price: 105, Amount: 10},
+	})
+
+	backtester.Run(StrategyFunc(func(api goex.SpotAPI, event *Event) {
+		if event.Trade.Time == 0 {
+			api.PlaceLimitOrder(btcUsdt, "100", "30", goex.BUY, "")
+		}
+		api.GetUserOpenTrustOrders(btcUsdt, 10, nil)
+	}))
+	balances := backtester.Spot().GetBalances()
+	if math.Abs(balances["btc"].Free-10) > 1e-9 {
+		t.Fatalf("a print must fill at most its participation once: %v", balances)
+	}
+}
+
+func TestLoadKlines(t *testing.T) {
+	dir, err := ioutil.TempDir("", "backtest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "klines.json")
+	ioutil.WriteFile(file, []byte(`{"code":0,"st":1,"et":1,"data":[[1600000000000,"1","2","1","2","5"]]}`), 0644)
+
+	klines, err := LoadKlines(goex.EXCHANGE_BINANCE, file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(klines) != 1 || klines[0].Close != 2 {
+		t.Fatalf("unexpected klines: %v", klines)
+	}
+}
@@ -0,0 +1,104 @@
+package backtest
+
+import (
+	"github.com/primitivelab/goexchange/paper"
+)
+
+// EquityPoint equity after an event
+type EquityPoint struct {
+	Time   int64
+	Equity float64
+}
+
+// Report backtest result, values are in Quote coin, fees at the last replayed prices
+type Report struct {
+	Start         int64
+	End           int64
+	Quote         string
+	InitialEquity float64
+	FinalEquity   float64
+	PnL           float64
+	// PnL / InitialEquity
+	Return float64
+	// largest drop from an equity peak, fraction of the peak
+	MaxDrawdown float64
+	// traded value
+	Turnover float64
+	Fees     float64
+
+	Orders          int
+	FilledOrders    int
+	PartiallyFilled int
+	CanceledOrders  int
+	ExpiredOrders   int
+	RejectedOrders  int
+	OpenOrders      int
+	Fills           int
+	MakerFills      int
+	TakerFills      int
+	// filled amount / ordered amount
+	FillRatio float64
+
+	Equity []EquityPoint
+}
+
+// summarize fill report from equity curve, orders and fills
+func (backtester *Backtester) summarize(report *Report) {
+	report.Quote = backtester.config.Quote
+	if len(report.Equity) == 0 {
+		return
+	}
+	report.End = report.Equity[len(report.Equity)-1].Time
+	report.FinalEquity = report.Equity[len(report.Equity)-1].Equity
+	report.PnL = report.FinalEquity - report.InitialEquity
+	if report.InitialEquity > 0 {
+		report.Return = report.PnL / report.InitialEquity
+	}
+
+	peak := report.InitialEquity
+	for _, point := range report.Equity {
+		if point.Equity > peak {
+			peak = point.Equity
+		}
+		if peak > 0 && (peak-point.Equity)/peak > report.MaxDrawdown {
+			report.MaxDrawdown = (peak - point.Equity) / peak
+		}
+	}
+
+	ordered, filled := 0.0, 0.0
+	for _, order := range backtester.spot.GetOrders() {
+		report.Orders++
+		ordered += order.Amount
+		filled += order.FilledAmount
+		switch order.Status {
+		case paper.ORDER_FILLED:
+			report.FilledOrders++
+		case paper.ORDER_CANCELED:
+			report.CanceledOrders++
+		case paper.ORDER_EXPIRED:
+			report.ExpiredOrders++
+		case paper.ORDER_REJECTED:
+			report.RejectedOrders++
+		default:
+			report.OpenOrders++
+		}
+		if order.FilledAmount > 0 && order.Status != paper.ORDER_FILLED {
+			report.PartiallyFilled++
+		}
+	}
+	if ordered > 0 {
+		report.FillRatio = filled / ordered
+	}
+
+	for _, fill := range backtester.spot.GetFills() {
+		report.Fills++
+		if fill.Maker {
+			report.MakerFills++
+		} else {
+			report.TakerFills++
+		}
+		symbol := backtester.symbols[fill.Symbol]
+		report.Turnover += fill.Price * fill.Amount * backtester.value(symbol.CoinTo)
+		report.Fees += fill.Fee * backtester.value(fill.FeeCoin)
+	}
+}
@@ -0,0 +1,113 @@
+package goexchange
+
+import (
+	"errors"
+	"sort"
+	"strings"
+)
+
+// Total free and frozen amount
+func (balance *AssetBalance) Total() float64 {
+	return balance.Free + balance.Frozen
+}
+
+// ParseBalance parse GetUserBalance response of any adapter into balances of
+// lower case coins sorted by coin, empty balances are left out. lists nested
+// like binance's {"balances": [...]} or huobi's {"list": [...]}, huobi's
+// trade and frozen records and objects keyed by coin like poloniex's are
+// supported
+func ParseBalance(result interface{}) ([]AssetBalance, error) {
+	data := result
+	if retData, ok := result.(map[string]interface{}); ok {
+		if _, isResponse := retData["code"]; isResponse {
+			var err error
+			data, err = ParseResponse(result)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	if typed, ok := data.([]AssetBalance); ok {
+		return mergeBalances(typed), nil
+	}
+	for level := 0; level < 3; level++ {
+		object, ok := data.(map[string]interface{})
+		if !ok {
+			break
+		}
+		nested, ok := firstValue(object, "balances", "list", "coin_list", "info", "data")
+		if !ok {
+			break
+		}
+		data = nested
+	}
+
+	balances := []AssetBalance{}
+	switch value := data.(type) {
+	case []interface{}:
+		for _, item := range value {
+			object, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			coin, ok := firstValue(object, "asset", "currency", "coin", "ccy", "margin_asset", "symbol", "name")
+			if !ok {
+				continue
+			}
+			balances = append(balances, parseBalanceObject(ToString(coin), object))
+		}
+	case map[string]interface{}:
+		for coin, item := range value {
+			if object, ok := item.(map[string]interface{}); ok {
+				balances = append(balances, parseBalanceObject(coin, object))
+			}
+		}
+	default:
+		return nil, errors.New("balance data is neither a list nor an object")
+	}
+	return mergeBalances(balances), nil
+}
+
+// parseBalanceObject parse balance of coin from one balance object
+func parseBalanceObject(coin string, object map[string]interface{}) AssetBalance {
+	balance := AssetBalance{Coin: coin}
+	// huobi lists a trade and a frozen record of every coin
+	if kind, ok := object["type"].(string); ok && (kind == "trade" || kind == "frozen") {
+		if kind == "frozen" {
+			balance.Frozen = ToFloat(object["balance"])
+		} else {
+			balance.Free = ToFloat(object["balance"])
+		}
+		return balance
+	}
+	if value, ok := firstValue(object, "free", "available", "availBal", "normal", "over", "availableBalance", "margin_available", "total_avail_balance", "amount"); ok {
+		balance.Free = ToFloat(value)
+	}
+	if value, ok := firstValue(object, "locked", "hold", "frozen", "frozenBal", "reserved", "onOrders", "lock", "freeze", "margin_frozen"); ok {
+		balance.Frozen = ToFloat(value)
+	} else if value, ok := firstValue(object, "balance", "walletBalance"); ok {
+		// contract wallets give the whole balance and the available part
+		balance.Frozen = ToFloat(value) - balance.Free
+	}
+	return balance
+}
+
+// mergeBalances sum balances of the same lower case coin and drop empty ones
+func mergeBalances(balances []AssetBalance) []AssetBalance {
+	index := map[string]int{}
+	merged := []AssetBalance{}
+	for _, balance := range balances {
+		coin := strings.ToLower(balance.Coin)
+		if coin == "" || (balance.Free == 0 && balance.Frozen == 0) {
+			continue
+		}
+		if _, ok := index[coin]; !ok {
+			index[coin] = len(merged)
+			merged = append(merged, AssetBalance{Coin: coin})
+		}
+		merged[index[coin]].Free += balance.Free
+		merged[index[coin]].Frozen += balance.Frozen
+	}
+	sort.Slice(merged, func(i, j int) bool { return merged[i].Coin < merged[j].Coin })
+	return merged
+}
@@ -0,0 +1,37 @@
+package goexchange
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseBalance(t *testing.T) {
+	bodies := []string{
+		`{"balances":[{"asset":"BTC","free":"1","locked":"0.5"},{"asset":"USDT","free":"100","locked":"0"},{"asset":"ETH","free":"0","locked":"0"}]}`,
+		`{"id":1,"type":"spot","state":"working","list":[{"currency":"btc","type":"trade","balance":"1"},{"currency":"btc","type":"frozen","balance":"0.5"},{"currency":"usdt","type":"trade","balance":"100"},{"currency":"eth","type":"trade","balance":"0"}]}`,
+		`[{"currency":"BTC","balance":"1.5","hold":"0.5","available":"1"},{"currency":"USDT","balance":"100","hold":"0","available":"100"}]`,
+		`{"BTC":{"available":"1","onOrders":"0.5","btcValue":"1.5"},"USDT":{"available":"100","onOrders":"0"}}`,
+		`{"total_asset":"1","coin_list":[{"coin":"btc","normal":"1","locked":"0.5"},{"coin":"usdt","normal":"100","locked":"0"}]}`,
+		`[{"asset":"BTC","balance":"1.5","availableBalance":"1"},{"asset":"USDT","balance":"100","availableBalance":"100"}]`,
+	}
+	for _, body := range bodies {
+		var data interface{}
+		json.Unmarshal([]byte(body), &data)
+		balances, err := ParseBalance(map[string]interface{}{"code": 0, "data": data})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(balances) != 2 || balances[0].Coin != "btc" || balances[0].Free != 1 || balances[0].Frozen != 0.5 ||
+			balances[1].Coin != "usdt" || balances[1].Total() != 100 {
+			t.Fatalf("unexpected balances of %s: %+v", body, balances)
+		}
+	}
+
+	balances, err := ParseBalance(ReturnAPIData([]AssetBalance{{Coin: "BTC", Free: 1}, {Coin: "btc", Frozen: 1}}))
+	if err != nil || len(balances) != 1 || balances[0].Total() != 2 {
+		t.Fatalf("unexpected typed balances %+v: %v", balances, err)
+	}
+	if _, err := ParseBalance(ReturnAPIError(ExchangeError)); err == nil {
+		t.Fatal("expected error of error response")
+	}
+}
@@ -2,6 +2,7 @@ package biki
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -129,6 +130,18 @@ func (spot *BikiSpot) GetUserBalance() interface{} {
 
 // PlaceOrder place order
 func (spot *BikiSpot) PlaceOrder(order *PlaceOrder) interface{} {
+	// market buys spend quote coin and market sells sell base coin
+	mode := MarketOrderByQuote
+	if order.Side == SELL {
+		mode = MarketOrderByBase
+	}
+	if err := CheckMarketMode(order, mode); err != nil {
+		return InvalidOrder(err)
+	}
+	// biki orders rest until they are filled or canceled
+	if err := CheckTimeInForce(order.TimeInForce); err != nil {
+		return InvalidOrder(err)
+	}
 	params := &url.Values{}
 	params.Set("symbol", spot.getSymbol(order.Symbol))
 	params.Set("volume", order.Amount)
@@ -178,12 +191,16 @@ func (spot *BikiSpot) PlaceMarketOrder(symbol Symbol, amount string, side TradeS
 	return result
 }
 
-// BatchPlaceLimitOrder batch place limit order
+// BatchPlaceLimitOrder batch place limit order, orders rest until they are
+// filled or canceled, so only GTC is accepted
 func (spot *BikiSpot) BatchPlaceLimitOrder(orders []LimitOrder) interface{} {
 	params := &url.Values{}
 	var trustOrders []map[string]interface{}
 	var symbol Symbol
 	for _, item := range orders {
+		if err := CheckTimeInForce(item.TimeInForce); err != nil {
+			return InvalidOrder(err)
+		}
 		param := map[string]interface{}{}
 		param["side"] = BIKI_BUY
 		if item.Side == SELL {
@@ -222,6 +239,53 @@ func (spot *BikiSpot) BatchCancelOrder(symbol Symbol, orderIds, clientOrderIds s
 	return result
 }
 
+// AmendOrder replace order with a new limit order in one mass_replaceV2
+// request, biki needs order id, side, price and amount, the new order takes
+// the unfilled part of amount, data: new order id
+func (spot *BikiSpot) AmendOrder(order *AmendOrder) interface{} {
+	if err := ValidateAmendOrder(order); err != nil {
+		return InvalidOrder(err)
+	}
+	if order.OrderId == "" || (order.Side != BUY && order.Side != SELL) || order.Price == "" || order.Amount == "" {
+		return InvalidOrder(errors.New("biki order replace needs order id, side, price and amount"))
+	}
+	result := spot.GetUserOrderInfo(order.Symbol, order.OrderId, "").(map[string]interface{})
+	if result["code"] != 0 {
+		return result
+	}
+	data, _ := result["data"].(map[string]interface{})
+	info, _ := data["order_info"].(map[string]interface{})
+	// status 0 init, 1 new and 3 partially filled are open
+	if status := ToString(info["status"]); status != "0" && status != "1" && status != "3" {
+		return InvalidOrder(errors.New("order is not open, status " + status))
+	}
+	amount := ToFloat(order.Amount) - ToFloat(info["deal_volume"])
+	if amount <= 0 {
+		return InvalidOrder(errors.New("order is filled up to amount, nothing is left to replace"))
+	}
+	param := map[string]interface{}{"side": BIKI_BUY, "type": "1", "volume": FloatToString(amount), "price": order.Price}
+	if order.Side == SELL {
+		param["side"] = BIKI_SELL
+	}
+	jsonBody, _ := json.Marshal([]map[string]interface{}{param})
+	params := &url.Values{}
+	params.Set("symbol", spot.getSymbol(order.Symbol))
+	params.Set("mass_cancel", fmt.Sprintf("[%s]", order.OrderId))
+	params.Set("mass_place", string(jsonBody))
+
+	result = spot.httpPost("/open/api/mass_replaceV2", params, true)
+	if result["code"] != 0 {
+		return result
+	}
+	data, _ = result["data"].(map[string]interface{})
+	if placed, ok := data["mass_place"].([]interface{}); ok && len(placed) == 1 {
+		if record, ok := placed[0].(map[string]interface{}); ok {
+			result["data"] = ToString(record["order_id"])
+		}
+	}
+	return result
+}
+
 // BatchCancelAllOrder batch cancel all orders
 func (spot *BikiSpot) BatchCancelAllOrder(symbol Symbol) interface{} {
 	params := &url.Values{}
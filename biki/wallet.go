@@ -0,0 +1,25 @@
+package biki
+
+import (
+	. "github.com/primitivelab/goexchange"
+)
+
+// GetDepositAddresses deposit address is not supported
+func (spot *BikiSpot) GetDepositAddresses(coin, chain string) interface{} {
+	return ReturnAPIError(MethodNotExistError)
+}
+
+// ApplyWithdraw withdraw is not supported
+func (spot *BikiSpot) ApplyWithdraw(request *WithdrawRequest) interface{} {
+	return ReturnAPIError(MethodNotExistError)
+}
+
+// GetDepositHistory deposit record is not supported
+func (spot *BikiSpot) GetDepositHistory(coin string, size int, options map[string]string) interface{} {
+	return ReturnAPIError(MethodNotExistError)
+}
+
+// GetWithdrawHistory withdraw record is not supported
+func (spot *BikiSpot) GetWithdrawHistory(coin string, size int, options map[string]string) interface{} {
+	return ReturnAPIError(MethodNotExistError)
+}
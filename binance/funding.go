@@ -0,0 +1,110 @@
+package binance
+
+import (
+	"net/url"
+	"sort"
+	"strconv"
+
+	goex "github.com/primitivelab/goexchange"
+)
+
+// GetFundingRate current funding of contract, data: goex.FundingInfo
+func (swap *SwapUsdt) GetFundingRate(symbol goex.Symbol) interface{} {
+	result := swap.GetPremiumIndex(symbol).(map[string]interface{})
+	if result["code"] != 0 {
+		return result
+	}
+	result["data"] = fundingInfo(result["data"], swap.getSymbol(symbol))
+	return result
+}
+
+// GetFundingRateHistory settled funding rates, options startTime and endTime,
+// data: []goex.FundingRate
+func (swap *SwapUsdt) GetFundingRateHistory(symbol goex.Symbol, size int, options map[string]string) interface{} {
+	params := fundingHistoryParams(swap.getSymbol(symbol), size, options)
+	result := swap.httpGet("/fapi/v1/fundingRate", params, false)
+	if result["code"] != 0 {
+		return result
+	}
+	result["data"] = fundingRates(result["data"])
+	return result
+}
+
+// GetFundingRate current funding of contract, data: goex.FundingInfo
+func (swap *SwapCoin) GetFundingRate(symbol goex.Symbol) interface{} {
+	result := swap.GetPremiumIndex(symbol).(map[string]interface{})
+	if result["code"] != 0 {
+		return result
+	}
+	result["data"] = fundingInfo(result["data"], swap.getSymbol(symbol))
+	return result
+}
+
+// GetFundingRateHistory settled funding rates, options startTime and endTime,
+// data: []goex.FundingRate
+func (swap *SwapCoin) GetFundingRateHistory(symbol goex.Symbol, size int, options map[string]string) interface{} {
+	params := fundingHistoryParams(swap.getSymbol(symbol), size, options)
+	result := swap.httpGet("/dapi/v1/fundingRate", params, false)
+	if result["code"] != 0 {
+		return result
+	}
+	result["data"] = fundingRates(result["data"])
+	return result
+}
+
+// fundingHistoryParams fundingRate params of symbol
+func fundingHistoryParams(symbol string, size int, options map[string]string) *url.Values {
+	params := &url.Values{}
+	params.Set("symbol", symbol)
+	if size != 0 {
+		params.Set("limit", strconv.Itoa(size))
+	}
+	for _, key := range []string{"startTime", "endTime"} {
+		if value, ok := options[key]; ok {
+			params.Set(key, value)
+		}
+	}
+	return params
+}
+
+// fundingInfo funding of premiumIndex, which is one object on fapi and a
+// list of the perpetual and delivery contracts of the pair on dapi
+func fundingInfo(data interface{}, symbol string) goex.FundingInfo {
+	list, ok := data.([]interface{})
+	if !ok {
+		list = []interface{}{data}
+	}
+	for _, item := range list {
+		record, ok := item.(map[string]interface{})
+		if !ok || record["symbol"] != symbol {
+			continue
+		}
+		return goex.FundingInfo{
+			Symbol:          symbol,
+			Rate:            goex.ToFloat(record["lastFundingRate"]),
+			NextFundingTime: goex.ParseTimestamp(record["nextFundingTime"]),
+			MarkPrice:       goex.ToFloat(record["markPrice"]),
+			IndexPrice:      goex.ToFloat(record["indexPrice"]),
+		}
+	}
+	return goex.FundingInfo{Symbol: symbol}
+}
+
+// fundingRates settled rates of fundingRate list, newest first
+func fundingRates(data interface{}) []goex.FundingRate {
+	list, _ := data.([]interface{})
+	rates := make([]goex.FundingRate, 0, len(list))
+	for _, item := range list {
+		record, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		rates = append(rates, goex.FundingRate{
+			Symbol: goex.ToString(record["symbol"]),
+			Rate:   goex.ToFloat(record["fundingRate"]),
+			Time:   goex.ParseTimestamp(record["fundingTime"]),
+		})
+	}
+	sort.SliceStable(rates, func(i, j int) bool { return rates[i].Time > rates[j].Time })
+	return rates
+}
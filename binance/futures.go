@@ -0,0 +1,324 @@
+package binance
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	goex "github.com/primitivelab/goexchange"
+)
+
+// futuresContractType binance contract type of delivery contracts, binance
+// has no weekly contracts
+var futuresContractType = map[string]string{
+	"CURRENT_QUARTER": goex.CONTRACT_QUARTER,
+	"NEXT_QUARTER":    goex.CONTRACT_NEXT_QUARTER,
+}
+
+// Futures binance coin margined delivery contract, it shares dapi with SwapCoin
+type Futures struct {
+	swap      *SwapCoin
+	contracts goex.FuturesContracts
+}
+
+// NewFutures new instance
+func NewFutures(client *http.Client, baseURL, apiKey, secretKey string) *Futures {
+	return &Futures{swap: NewSwapCoin(client, baseURL, apiKey, secretKey)}
+}
+
+// NewFuturesWithConfig new instance with config struct
+func NewFuturesWithConfig(config *goex.APIConfig) *Futures {
+	return &Futures{swap: NewSwapCoinWithConfig(config)}
+}
+
+// GetExchangeName get exchange name
+func (futures *Futures) GetExchangeName() string {
+	return goex.EXCHANGE_BINANCE
+}
+
+// GetContractList delivery contract list, data: []goex.FuturesContract
+func (futures *Futures) GetContractList() interface{} {
+	result := futures.swap.httpGet("/dapi/v1/exchangeInfo", &url.Values{}, false)
+	if result["code"] != 0 {
+		return result
+	}
+
+	data, _ := result["data"].(map[string]interface{})
+	list, _ := data["symbols"].([]interface{})
+	contracts := []goex.FuturesContract{}
+	for _, item := range list {
+		record, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		contractType, ok := futuresContractType[goex.ToString(record["contractType"])]
+		if !ok || record["contractStatus"] == "SETTLING" {
+			continue
+		}
+		contracts = append(contracts, goex.FuturesContract{
+			Symbol:       goex.NewSymbol(goex.ToString(record["baseAsset"]), goex.ToString(record["quoteAsset"])),
+			ContractType: contractType,
+			Code:         goex.ToString(record["symbol"]),
+			ContractSize: goex.ToFloat(record["contractSize"]),
+			ListTime:     goex.ParseTimestamp(record["onboardDate"]),
+			DeliveryTime: goex.ParseTimestamp(record["deliveryDate"]),
+		})
+	}
+	result["data"] = contracts
+	return result
+}
+
+// GetContract current contract of symbol and contract type, data: goex.FuturesContract
+func (futures *Futures) GetContract(symbol goex.Symbol, contractType string) interface{} {
+	contract, err := futures.resolve(symbol, contractType)
+	if err != nil {
+		return goex.ContractNotFound(err)
+	}
+	return goex.ReturnAPIData(contract)
+}
+
+// GetDepth exchange depth data
+func (futures *Futures) GetDepth(symbol goex.Symbol, contractType string, size int, options map[string]string) interface{} {
+	params, err := futures.params(symbol, contractType)
+	if err != nil {
+		return goex.ContractNotFound(err)
+	}
+	if size != 0 {
+		params.Set("limit", strconv.Itoa(size))
+	}
+	return futures.swap.httpGet("/dapi/v1/depth", params, false)
+}
+
+// GetTicker exchange ticker data
+func (futures *Futures) GetTicker(symbol goex.Symbol, contractType string) interface{} {
+	params, err := futures.params(symbol, contractType)
+	if err != nil {
+		return goex.ContractNotFound(err)
+	}
+	return futures.swap.httpGet("/dapi/v1/ticker/24hr", params, false)
+}
+
+// GetKline exchange kline data, options startTime and endTime
+func (futures *Futures) GetKline(symbol goex.Symbol, contractType string, period, size int, options map[string]string) interface{} {
+	params, err := futures.params(symbol, contractType)
+	if err != nil {
+		return goex.ContractNotFound(err)
+	}
+	periodStr, ok := klinePeriod[period]
+	if !ok {
+		periodStr = "1m"
+	}
+	params.Set("interval", periodStr)
+	futures.pageParams(params, size, options, "startTime", "endTime")
+	return futures.swap.httpGet("/dapi/v1/klines", params, false)
+}
+
+// GetTrade exchange trade order data
+func (futures *Futures) GetTrade(symbol goex.Symbol, contractType string, size int, options map[string]string) interface{} {
+	params, err := futures.params(symbol, contractType)
+	if err != nil {
+		return goex.ContractNotFound(err)
+	}
+	futures.pageParams(params, size, options)
+	return futures.swap.httpGet("/dapi/v1/trades", params, false)
+}
+
+// GetDeliveryPrices settled delivery prices of pair, data: []goex.DeliveryPrice
+func (futures *Futures) GetDeliveryPrices(symbol goex.Symbol, size int) interface{} {
+	params := &url.Values{}
+	params.Set("pair", symbol.ToUpper().ToSymbol(""))
+	result := futures.swap.httpGet("/futures/data/delivery-price", params, false)
+	if result["code"] != 0 {
+		return result
+	}
+
+	list, _ := result["data"].([]interface{})
+	prices := make([]goex.DeliveryPrice, 0, len(list))
+	for _, item := range list {
+		record, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		deliveryTime := goex.ParseTimestamp(record["deliveryTime"])
+		prices = append(prices, goex.DeliveryPrice{
+			Code:  symbol.ToUpper().ToSymbol("") + "_" + time.Unix(deliveryTime/1000, 0).UTC().Format("060102"),
+			Price: goex.ToFloat(record["deliveryPrice"]),
+			Time:  deliveryTime,
+		})
+		if size > 0 && len(prices) >= size {
+			break
+		}
+	}
+	result["data"] = prices
+	return result
+}
+
+// GetUserBalance user account balance
+func (futures *Futures) GetUserBalance() interface{} {
+	return futures.swap.httpGet("/dapi/v1/balance", &url.Values{}, true)
+}
+
+// GetUserPositions user position of contract, data: []goex.Position
+func (futures *Futures) GetUserPositions(symbol goex.Symbol, contractType string) interface{} {
+	contract, err := futures.resolve(symbol, contractType)
+	if err != nil {
+		return goex.ContractNotFound(err)
+	}
+	params := &url.Values{}
+	params.Set("pair", symbol.ToUpper().ToSymbol(""))
+	result := futures.swap.httpGet("/dapi/v1/positionRisk", params, true)
+	if result["code"] != 0 {
+		return result
+	}
+	result["data"] = parsePositions(result["data"], contract.Code)
+	return result
+}
+
+// PlaceOrder place order, amount is number of contracts, a close order is
+// sent reduce only, see futuresOrderParams for trigger orders
+func (futures *Futures) PlaceOrder(order *goex.PlaceOrder) interface{} {
+	if err := goex.ValidateOrder(order); err != nil {
+		return goex.InvalidOrder(err)
+	}
+	// quantity of coin margined contracts is number of contracts
+	if err := goex.CheckMarketMode(order); err != nil {
+		return goex.InvalidOrder(err)
+	}
+	if err := checkTimeInForce(order); err != nil {
+		return goex.InvalidOrder(err)
+	}
+	params, err := futures.params(order.Symbol, order.ContractType)
+	if err != nil {
+		return goex.ContractNotFound(err)
+	}
+	if order.ClientOrderId != "" {
+		params.Set("newClientOrderId", order.ClientOrderId)
+	}
+	params.Set("side", strings.ToUpper(order.Side.String()))
+	hedgeMode, result := futures.swap.isHedgeMode()
+	if result != nil {
+		return result
+	}
+	futuresOrderParams(params, order, hedgeMode)
+	return futures.swap.httpPost("/dapi/v1/order", params, true)
+}
+
+// AmendOrder modify price and amount of a resting limit order in place
+func (futures *Futures) AmendOrder(order *goex.AmendOrder) interface{} {
+	params, err := futures.params(order.Symbol, order.ContractType)
+	if err != nil {
+		return goex.ContractNotFound(err)
+	}
+	if err := amendParams(params, order); err != nil {
+		return goex.InvalidOrder(err)
+	}
+	return futures.swap.httpPut("/dapi/v1/order", params, true)
+}
+
+// CancelOrder cancel user trust order
+func (futures *Futures) CancelOrder(symbol goex.Symbol, contractType, orderID, clientOrderID string) interface{} {
+	params, err := futures.params(symbol, contractType)
+	if err != nil {
+		return goex.ContractNotFound(err)
+	}
+	futures.orderIDParams(params, orderID, clientOrderID)
+	return futures.swap.httpDelete("/dapi/v1/order", params, true)
+}
+
+// BatchCancelAllOrder cancel all open orders of contract
+func (futures *Futures) BatchCancelAllOrder(symbol goex.Symbol, contractType string) interface{} {
+	params, err := futures.params(symbol, contractType)
+	if err != nil {
+		return goex.ContractNotFound(err)
+	}
+	return futures.swap.httpDelete("/dapi/v1/allOpenOrders", params, true)
+}
+
+// GetUserOrderInfo user trust order info
+func (futures *Futures) GetUserOrderInfo(symbol goex.Symbol, contractType, orderID, clientOrderID string) interface{} {
+	params, err := futures.params(symbol, contractType)
+	if err != nil {
+		return goex.ContractNotFound(err)
+	}
+	futures.orderIDParams(params, orderID, clientOrderID)
+	return futures.swap.httpGet("/dapi/v1/order", params, true)
+}
+
+// GetUserOpenTrustOrders user open trust order list
+func (futures *Futures) GetUserOpenTrustOrders(symbol goex.Symbol, contractType string, size int, options map[string]string) interface{} {
+	params, err := futures.params(symbol, contractType)
+	if err != nil {
+		return goex.ContractNotFound(err)
+	}
+	return futures.swap.httpGet("/dapi/v1/openOrders", params, true)
+}
+
+// GetUserTrustOrders user trust order list, options startTime, endTime and orderId
+func (futures *Futures) GetUserTrustOrders(symbol goex.Symbol, contractType, status string, size int, options map[string]string) interface{} {
+	params, err := futures.params(symbol, contractType)
+	if err != nil {
+		return goex.ContractNotFound(err)
+	}
+	futures.pageParams(params, size, options, "startTime", "endTime", "orderId")
+	return futures.swap.httpGet("/dapi/v1/allOrders", params, true)
+}
+
+// GetUserTradeOrders user fill list, options startTime, endTime and fromId
+func (futures *Futures) GetUserTradeOrders(symbol goex.Symbol, contractType string, size int, options map[string]string) interface{} {
+	params, err := futures.params(symbol, contractType)
+	if err != nil {
+		return goex.ContractNotFound(err)
+	}
+	futures.pageParams(params, size, options, "startTime", "endTime", "fromId")
+	return futures.swap.httpGet("/dapi/v1/userTrades", params, true)
+}
+
+// HTTPRequest request url
+func (futures *Futures) HTTPRequest(requestURL, method string, options interface{}, signed bool) interface{} {
+	return futures.swap.HTTPRequest(requestURL, method, options, signed)
+}
+
+// resolve current contract of symbol and contract type
+func (futures *Futures) resolve(symbol goex.Symbol, contractType string) (goex.FuturesContract, error) {
+	return futures.contracts.Resolve(symbol, contractType, func() ([]goex.FuturesContract, error) {
+		data, err := goex.ParseResponse(futures.GetContractList())
+		if err != nil {
+			return nil, err
+		}
+		return data.([]goex.FuturesContract), nil
+	})
+}
+
+// params request params with symbol of resolved contract
+func (futures *Futures) params(symbol goex.Symbol, contractType string) (*url.Values, error) {
+	contract, err := futures.resolve(symbol, contractType)
+	if err != nil {
+		return nil, err
+	}
+	params := &url.Values{}
+	params.Set("symbol", contract.Code)
+	return params, nil
+}
+
+// pageParams set limit and options of keys
+func (futures *Futures) pageParams(params *url.Values, size int, options map[string]string, keys ...string) {
+	if size != 0 {
+		params.Set("limit", strconv.Itoa(size))
+	}
+	for _, key := range keys {
+		if value, ok := options[key]; ok {
+			params.Set(key, value)
+		}
+	}
+}
+
+// orderIDParams set orderId or origClientOrderId
+func (futures *Futures) orderIDParams(params *url.Values, orderID, clientOrderID string) {
+	if clientOrderID != "" {
+		params.Set("origClientOrderId", clientOrderID)
+	} else {
+		params.Set("orderId", orderID)
+	}
+}
@@ -0,0 +1,78 @@
+package binance
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	goex "github.com/primitivelab/goexchange"
+)
+
+func getFuturesInstance() goex.FuturesAPI {
+
+	client = &http.Client{}
+	config, err := goex.LoadConfig("binance")
+	if err != nil {
+		fmt.Println(err)
+	}
+	if config != nil {
+		apiKey = config["key"].(string)
+		secretKey = config["secret"].(string)
+	}
+
+	conf := goex.APIConfig{}
+	conf.ApiKey = apiKey
+	conf.ApiSecretKey = secretKey
+	conf.HttpClient = client
+	market := NewFuturesWithConfig(&conf)
+	return market
+}
+
+func TestFutures_GetContractList(t *testing.T) {
+	market := getFuturesInstance()
+
+	response := market.GetContractList()
+	b, _ := json.Marshal(response)
+	t.Log(string(b))
+}
+
+func TestFutures_GetContract(t *testing.T) {
+	market := getFuturesInstance()
+
+	response := market.GetContract(goex.NewSymbol("btc", "usd"), goex.CONTRACT_QUARTER)
+	b, _ := json.Marshal(response)
+	t.Log(string(b))
+}
+
+func TestFutures_GetDepth(t *testing.T) {
+	market := getFuturesInstance()
+
+	response := market.GetDepth(goex.NewSymbol("btc", "usd"), goex.CONTRACT_QUARTER, 10, nil)
+	b, _ := json.Marshal(response)
+	t.Log(string(b))
+}
+
+func TestFutures_GetDeliveryPrices(t *testing.T) {
+	market := getFuturesInstance()
+
+	response := market.GetDeliveryPrices(goex.NewSymbol("btc", "usd"), 10)
+	b, _ := json.Marshal(response)
+	t.Log(string(b))
+}
+
+func TestFutures_GetUserPositions(t *testing.T) {
+	market := getFuturesInstance()
+
+	response := market.GetUserPositions(goex.NewSymbol("btc", "usd"), goex.CONTRACT_QUARTER)
+	b, _ := json.Marshal(response)
+	t.Log(string(b))
+}
+
+func TestFutures_GetUserOpenTrustOrders(t *testing.T) {
+	market := getFuturesInstance()
+
+	response := market.GetUserOpenTrustOrders(goex.NewSymbol("btc", "usd"), goex.CONTRACT_QUARTER, 10, nil)
+	b, _ := json.Marshal(response)
+	t.Log(string(b))
+}
@@ -0,0 +1,280 @@
+package binance
+
+import (
+	"errors"
+	"net/url"
+	"strconv"
+	"sync/atomic"
+
+	goex "github.com/primitivelab/goexchange"
+)
+
+// GetLeverage leverage and margin type of contract and position mode of
+// account, data: goex.LeverageSetting
+func (swap *SwapUsdt) GetLeverage(symbol goex.Symbol) interface{} {
+	params := &url.Values{}
+	params.Set("symbol", swap.getSymbol(symbol))
+	result := swap.httpGet("/fapi/v2/positionRisk", params, true)
+	if result["code"] != 0 {
+		return result
+	}
+	mode := swap.httpGet("/fapi/v1/positionSide/dual", &url.Values{}, true)
+	if mode["code"] != 0 {
+		return mode
+	}
+	setting := leverageSetting(result["data"], mode["data"], swap.getSymbol(symbol))
+	storeHedgeMode(&swap.hedgeMode, setting.PositionMode)
+	result["data"] = setting
+	return result
+}
+
+// GetLeverageBrackets notional brackets of contract, data: []goex.LeverageBracket
+func (swap *SwapUsdt) GetLeverageBrackets(symbol goex.Symbol) interface{} {
+	params := &url.Values{}
+	params.Set("symbol", swap.getSymbol(symbol))
+	result := swap.httpGet("/fapi/v1/leverageBracket", params, true)
+	if result["code"] != 0 {
+		return result
+	}
+	result["data"] = leverageBrackets(result["data"], swap.getSymbol(symbol), "notionalCap")
+	return result
+}
+
+// SetLeverage set leverage of contract after checking it against the brackets
+func (swap *SwapUsdt) SetLeverage(symbol goex.Symbol, leverage int) interface{} {
+	result := swap.GetLeverageBrackets(symbol)
+	brackets, err := goex.ParseResponse(result)
+	if err != nil {
+		return result
+	}
+	if err := goex.CheckLeverage(leverage, brackets.([]goex.LeverageBracket)); err != nil {
+		return goex.InvalidLeverageSetting(err)
+	}
+	params := &url.Values{}
+	params.Set("symbol", swap.getSymbol(symbol))
+	params.Set("leverage", strconv.Itoa(leverage))
+	return swap.httpPost("/fapi/v1/leverage", params, true)
+}
+
+// SetMarginType set margin type of contract, goex.MARGIN_CROSSED or goex.MARGIN_ISOLATED
+func (swap *SwapUsdt) SetMarginType(symbol goex.Symbol, marginType string) interface{} {
+	params, err := marginTypeParams(marginType)
+	if err != nil {
+		return goex.InvalidLeverageSetting(err)
+	}
+	params.Set("symbol", swap.getSymbol(symbol))
+	return swap.httpPost("/fapi/v1/marginType", params, true)
+}
+
+// SetPositionMode set position mode of account, binance applies it to all
+// contracts, later orders send positionSide in hedge mode
+func (swap *SwapUsdt) SetPositionMode(symbol goex.Symbol, mode string) interface{} {
+	params, err := positionModeParams(mode)
+	if err != nil {
+		return goex.InvalidLeverageSetting(err)
+	}
+	result := swap.httpPost("/fapi/v1/positionSide/dual", params, true)
+	if result["code"] != 0 {
+		return result
+	}
+	storeHedgeMode(&swap.hedgeMode, mode)
+	return result
+}
+
+// isHedgeMode whether the account is in hedge position mode, it is read on
+// first use when it was not set
+func (swap *SwapUsdt) isHedgeMode() (bool, map[string]interface{}) {
+	return loadHedgeMode(&swap.hedgeMode, func() map[string]interface{} {
+		return swap.httpGet("/fapi/v1/positionSide/dual", &url.Values{}, true)
+	})
+}
+
+// GetLeverage leverage and margin type of contract and position mode of
+// account, data: goex.LeverageSetting
+func (swap *SwapCoin) GetLeverage(symbol goex.Symbol) interface{} {
+	params := &url.Values{}
+	params.Set("pair", symbol.ToUpper().ToSymbol(""))
+	result := swap.httpGet("/dapi/v1/positionRisk", params, true)
+	if result["code"] != 0 {
+		return result
+	}
+	mode := swap.httpGet("/dapi/v1/positionSide/dual", &url.Values{}, true)
+	if mode["code"] != 0 {
+		return mode
+	}
+	setting := leverageSetting(result["data"], mode["data"], swap.getSymbol(symbol))
+	storeHedgeMode(&swap.hedgeMode, setting.PositionMode)
+	result["data"] = setting
+	return result
+}
+
+// GetLeverageBrackets contract quantity brackets of contract, data: []goex.LeverageBracket
+func (swap *SwapCoin) GetLeverageBrackets(symbol goex.Symbol) interface{} {
+	params := &url.Values{}
+	params.Set("symbol", swap.getSymbol(symbol))
+	result := swap.httpGet("/dapi/v2/leverageBracket", params, true)
+	if result["code"] != 0 {
+		return result
+	}
+	result["data"] = leverageBrackets(result["data"], swap.getSymbol(symbol), "qtyCap")
+	return result
+}
+
+// SetLeverage set leverage of contract after checking it against the brackets
+func (swap *SwapCoin) SetLeverage(symbol goex.Symbol, leverage int) interface{} {
+	result := swap.GetLeverageBrackets(symbol)
+	brackets, err := goex.ParseResponse(result)
+	if err != nil {
+		return result
+	}
+	if err := goex.CheckLeverage(leverage, brackets.([]goex.LeverageBracket)); err != nil {
+		return goex.InvalidLeverageSetting(err)
+	}
+	params := &url.Values{}
+	params.Set("symbol", swap.getSymbol(symbol))
+	params.Set("leverage", strconv.Itoa(leverage))
+	return swap.httpPost("/dapi/v1/leverage", params, true)
+}
+
+// SetMarginType set margin type of contract, goex.MARGIN_CROSSED or goex.MARGIN_ISOLATED
+func (swap *SwapCoin) SetMarginType(symbol goex.Symbol, marginType string) interface{} {
+	params, err := marginTypeParams(marginType)
+	if err != nil {
+		return goex.InvalidLeverageSetting(err)
+	}
+	params.Set("symbol", swap.getSymbol(symbol))
+	return swap.httpPost("/dapi/v1/marginType", params, true)
+}
+
+// SetPositionMode set position mode of account, binance applies it to all
+// coin margined contracts, later orders send positionSide in hedge mode
+func (swap *SwapCoin) SetPositionMode(symbol goex.Symbol, mode string) interface{} {
+	params, err := positionModeParams(mode)
+	if err != nil {
+		return goex.InvalidLeverageSetting(err)
+	}
+	result := swap.httpPost("/dapi/v1/positionSide/dual", params, true)
+	if result["code"] != 0 {
+		return result
+	}
+	storeHedgeMode(&swap.hedgeMode, mode)
+	return result
+}
+
+// isHedgeMode whether the account is in hedge position mode, it is read on
+// first use when it was not set
+func (swap *SwapCoin) isHedgeMode() (bool, map[string]interface{}) {
+	return loadHedgeMode(&swap.hedgeMode, func() map[string]interface{} {
+		return swap.httpGet("/dapi/v1/positionSide/dual", &url.Values{}, true)
+	})
+}
+
+// leverageSetting setting of the first positionRisk entry of symbol and
+// dualSidePosition of mode
+func leverageSetting(positions, mode interface{}, symbol string) goex.LeverageSetting {
+	setting := goex.LeverageSetting{Symbol: symbol, PositionMode: goex.POSITION_MODE_ONE_WAY}
+	if object, ok := mode.(map[string]interface{}); ok && object["dualSidePosition"] == true {
+		setting.PositionMode = goex.POSITION_MODE_HEDGE
+	}
+	list, _ := positions.([]interface{})
+	for _, item := range list {
+		record, ok := item.(map[string]interface{})
+		if !ok || record["symbol"] != symbol {
+			continue
+		}
+		setting.Leverage = int(goex.ToFloat(record["leverage"]))
+		setting.ShortLeverage = setting.Leverage
+		setting.MarginType = goex.ParseMarginType(record["marginType"])
+		break
+	}
+	return setting
+}
+
+// leverageBrackets brackets of symbol, binance answers one object or a list
+// of objects, capKey is notionalCap or qtyCap
+func leverageBrackets(data interface{}, symbol, capKey string) []goex.LeverageBracket {
+	list, ok := data.([]interface{})
+	if !ok {
+		list = []interface{}{data}
+	}
+	brackets := []goex.LeverageBracket{}
+	for _, item := range list {
+		object, ok := item.(map[string]interface{})
+		if !ok || (object["symbol"] != nil && object["symbol"] != symbol) {
+			continue
+		}
+		records, _ := object["brackets"].([]interface{})
+		for index, item := range records {
+			record, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			bracket := goex.LeverageBracket{MaxLeverage: int(goex.ToFloat(record["initialLeverage"]))}
+			if index < len(records)-1 {
+				bracket.NotionalCap = goex.ToFloat(record[capKey])
+			}
+			brackets = append(brackets, bracket)
+		}
+	}
+	return brackets
+}
+
+// marginTypeParams marginType param of goex margin type
+func marginTypeParams(marginType string) (*url.Values, error) {
+	params := &url.Values{}
+	switch marginType {
+	case goex.MARGIN_CROSSED:
+		params.Set("marginType", "CROSSED")
+	case goex.MARGIN_ISOLATED:
+		params.Set("marginType", "ISOLATED")
+	default:
+		return nil, errors.New("unknown margin type " + marginType)
+	}
+	return params, nil
+}
+
+// positionModeParams dualSidePosition param of goex position mode
+func positionModeParams(mode string) (*url.Values, error) {
+	params := &url.Values{}
+	switch mode {
+	case goex.POSITION_MODE_HEDGE:
+		params.Set("dualSidePosition", "true")
+	case goex.POSITION_MODE_ONE_WAY:
+		params.Set("dualSidePosition", "false")
+	default:
+		return nil, errors.New("unknown position mode " + mode)
+	}
+	return params, nil
+}
+
+// values of hedgeMode, the position mode is unknown until it is set or read
+const (
+	hedgeModeUnknown int32 = iota
+	hedgeModeOff
+	hedgeModeOn
+)
+
+// storeHedgeMode remember position mode for orders
+func storeHedgeMode(hedgeMode *int32, mode string) {
+	value := hedgeModeOff
+	if mode == goex.POSITION_MODE_HEDGE {
+		value = hedgeModeOn
+	}
+	atomic.StoreInt32(hedgeMode, value)
+}
+
+// loadHedgeMode whether the account is in hedge position mode, getMode reads
+// positionSide/dual when the mode is unknown and its failed response is
+// returned
+func loadHedgeMode(hedgeMode *int32, getMode func() map[string]interface{}) (bool, map[string]interface{}) {
+	if value := atomic.LoadInt32(hedgeMode); value != hedgeModeUnknown {
+		return value == hedgeModeOn, nil
+	}
+	mode := getMode()
+	if mode["code"] != 0 {
+		return false, mode
+	}
+	positionMode := leverageSetting(nil, mode["data"], "").PositionMode
+	storeHedgeMode(hedgeMode, positionMode)
+	return positionMode == goex.POSITION_MODE_HEDGE, nil
+}
@@ -0,0 +1,296 @@
+package binance
+
+import (
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	goex "github.com/primitivelab/goexchange"
+)
+
+// Margin binance cross or isolated margin account, it signs sapi requests
+// with the keys of Spot
+type Margin struct {
+	spot       *Spot
+	marginType string
+}
+
+// NewMargin new instance, marginType is goex.MARGIN_CROSSED or goex.MARGIN_ISOLATED
+func NewMargin(client *http.Client, baseURL, apiKey, secretKey, marginType string) *Margin {
+	return &Margin{spot: New(client, baseURL, apiKey, secretKey), marginType: marginType}
+}
+
+// NewMarginWithConfig new instance with config struct
+func NewMarginWithConfig(config *goex.APIConfig, marginType string) *Margin {
+	return &Margin{spot: NewWithConfig(config), marginType: marginType}
+}
+
+// GetExchangeName get exchange name
+func (margin *Margin) GetExchangeName() string {
+	return goex.EXCHANGE_BINANCE
+}
+
+// GetMarginType margin type of the account, default crossed
+func (margin *Margin) GetMarginType() string {
+	if margin.marginType == goex.MARGIN_ISOLATED {
+		return goex.MARGIN_ISOLATED
+	}
+	return goex.MARGIN_CROSSED
+}
+
+// GetUserBalance margin account balance, data: []goex.MarginBalance
+func (margin *Margin) GetUserBalance() interface{} {
+	path := "/sapi/v1/margin/account"
+	if margin.isIsolated() {
+		path = "/sapi/v1/margin/isolated/account"
+	}
+	result := margin.spot.httpGet(path, &url.Values{}, true)
+	if result["code"] != 0 {
+		return result
+	}
+	result["data"] = marginBalances(result["data"])
+	return result
+}
+
+// Borrow borrow coin, data: loan id
+func (margin *Margin) Borrow(symbol goex.Symbol, coin, amount string) interface{} {
+	return margin.loan("/sapi/v1/margin/loan", symbol, coin, amount)
+}
+
+// Repay repay coin, data: repay id
+func (margin *Margin) Repay(symbol goex.Symbol, coin, amount string) interface{} {
+	return margin.loan("/sapi/v1/margin/repay", symbol, coin, amount)
+}
+
+// GetInterestHistory interest records, options startTime, endTime and
+// current page, data: []goex.MarginInterest
+func (margin *Margin) GetInterestHistory(symbol goex.Symbol, coin string, size int, options map[string]string) interface{} {
+	params := &url.Values{}
+	if coin != "" {
+		params.Set("asset", strings.ToUpper(coin))
+	}
+	if margin.isIsolated() {
+		params.Set("isolatedSymbol", margin.spot.getSymbol(symbol))
+	}
+	if size != 0 {
+		params.Set("size", strconv.Itoa(size))
+	}
+	for _, key := range []string{"startTime", "endTime", "current"} {
+		if value, ok := options[key]; ok {
+			params.Set(key, value)
+		}
+	}
+	result := margin.spot.httpGet("/sapi/v1/margin/interestHistory", params, true)
+	if result["code"] != 0 {
+		return result
+	}
+	result["data"] = marginInterests(result["data"])
+	return result
+}
+
+// PlaceOrder place margin order, stop and take-profit orders are triggered at StopPrice
+func (margin *Margin) PlaceOrder(order *goex.PlaceOrder) interface{} {
+	params, err := margin.spot.orderParams(order)
+	if err != nil {
+		return goex.InvalidOrder(err)
+	}
+	margin.isolatedParams(params)
+	return margin.spot.httpPost("/sapi/v1/margin/order", params, true)
+}
+
+// PlaceOcoOrder place one-cancels-the-other margin pair, data: order list
+func (margin *Margin) PlaceOcoOrder(order *goex.OcoOrder) interface{} {
+	if err := goex.ValidateOcoOrder(order); err != nil {
+		return goex.InvalidOrder(err)
+	}
+	params := margin.spot.ocoParams(order)
+	margin.isolatedParams(params)
+	return margin.spot.httpPost("/sapi/v1/margin/order/oco", params, true)
+}
+
+// PlaceLimitOrder place limit margin order
+func (margin *Margin) PlaceLimitOrder(symbol goex.Symbol, price string, amount string, side goex.TradeSide, ClientOrderID string) interface{} {
+	return margin.PlaceOrder(&goex.PlaceOrder{
+		Symbol:        symbol,
+		ClientOrderId: ClientOrderID,
+		Price:         price,
+		Amount:        amount,
+		Side:          side,
+		TradeType:     goex.LIMIT,
+	})
+}
+
+// PlaceMarketOrder place market margin order
+func (margin *Margin) PlaceMarketOrder(symbol goex.Symbol, amount string, side goex.TradeSide, ClientOrderID string) interface{} {
+	return margin.PlaceOrder(&goex.PlaceOrder{
+		Symbol:        symbol,
+		ClientOrderId: ClientOrderID,
+		Amount:        amount,
+		Side:          side,
+		TradeType:     goex.MARKET,
+	})
+}
+
+// BatchPlaceLimitOrder batch place limit margin order
+func (margin *Margin) BatchPlaceLimitOrder(orders []goex.LimitOrder) interface{} {
+	return goex.ReturnAPIError(goex.MethodNotExistError)
+}
+
+// CancelOrder cancel margin order
+func (margin *Margin) CancelOrder(symbol goex.Symbol, orderID, clientOrderID string) interface{} {
+	params := margin.params(symbol)
+	if clientOrderID != "" {
+		params.Set("origClientOrderId", clientOrderID)
+	} else {
+		params.Set("orderId", orderID)
+	}
+	return margin.spot.httpDelete("/sapi/v1/margin/order", params, true)
+}
+
+// BatchCancelOrder batch cancel margin order
+func (margin *Margin) BatchCancelOrder(symbol goex.Symbol, orderIds, clientOrderIds string) interface{} {
+	return goex.ReturnAPIError(goex.MethodNotExistError)
+}
+
+// GetUserOpenTrustOrders user open margin order list
+func (margin *Margin) GetUserOpenTrustOrders(symbol goex.Symbol, size int, options map[string]string) interface{} {
+	return margin.spot.httpGet("/sapi/v1/margin/openOrders", margin.params(symbol), true)
+}
+
+// GetUserOrderInfo user margin order info
+func (margin *Margin) GetUserOrderInfo(symbol goex.Symbol, orderID, clientOrderID string) interface{} {
+	params := margin.params(symbol)
+	if clientOrderID != "" {
+		params.Set("origClientOrderId", clientOrderID)
+	} else {
+		params.Set("orderId", orderID)
+	}
+	return margin.spot.httpGet("/sapi/v1/margin/order", params, true)
+}
+
+// GetUserTradeOrders user margin trade list, options startTime, endTime and fromId
+func (margin *Margin) GetUserTradeOrders(symbol goex.Symbol, size int, options map[string]string) interface{} {
+	params := margin.pageParams(symbol, size, options, "fromId")
+	return margin.spot.httpGet("/sapi/v1/margin/myTrades", params, true)
+}
+
+// GetUserTrustOrders user margin order list, options startTime, endTime and orderId
+func (margin *Margin) GetUserTrustOrders(symbol goex.Symbol, status string, size int, options map[string]string) interface{} {
+	params := margin.pageParams(symbol, size, options, "orderId")
+	return margin.spot.httpGet("/sapi/v1/margin/allOrders", params, true)
+}
+
+// loan borrow or repay coin
+func (margin *Margin) loan(path string, symbol goex.Symbol, coin, amount string) interface{} {
+	params := &url.Values{}
+	params.Set("asset", strings.ToUpper(coin))
+	params.Set("amount", amount)
+	if margin.isIsolated() {
+		params.Set("symbol", margin.spot.getSymbol(symbol))
+	}
+	margin.isolatedParams(params)
+	result := margin.spot.httpPost(path, params, true)
+	if result["code"] != 0 {
+		return result
+	}
+	data, _ := result["data"].(map[string]interface{})
+	result["data"] = goex.ToString(data["tranId"])
+	return result
+}
+
+// params symbol params of margin order apis
+func (margin *Margin) params(symbol goex.Symbol) *url.Values {
+	params := &url.Values{}
+	params.Set("symbol", margin.spot.getSymbol(symbol))
+	margin.isolatedParams(params)
+	return params
+}
+
+// pageParams symbol params with limit, startTime, endTime and keys of options
+func (margin *Margin) pageParams(symbol goex.Symbol, size int, options map[string]string, keys ...string) *url.Values {
+	params := margin.params(symbol)
+	if size != 0 {
+		params.Set("limit", strconv.Itoa(size))
+	}
+	for _, key := range append([]string{"startTime", "endTime"}, keys...) {
+		if value, ok := options[key]; ok {
+			params.Set(key, value)
+		}
+	}
+	return params
+}
+
+// isolatedParams mark request of the isolated margin account
+func (margin *Margin) isolatedParams(params *url.Values) {
+	if margin.isIsolated() {
+		params.Set("isIsolated", "TRUE")
+	}
+}
+
+func (margin *Margin) isIsolated() bool {
+	return margin.GetMarginType() == goex.MARGIN_ISOLATED
+}
+
+// marginBalances balances of margin account, userAssets of the cross account
+// or assets with base and quote asset of every isolated symbol
+func marginBalances(data interface{}) []goex.MarginBalance {
+	body, _ := data.(map[string]interface{})
+	balances := []goex.MarginBalance{}
+	if list, ok := body["userAssets"].([]interface{}); ok {
+		for _, item := range list {
+			if record, ok := item.(map[string]interface{}); ok {
+				balances = append(balances, marginBalance(record, ""))
+			}
+		}
+		return balances
+	}
+	list, _ := body["assets"].([]interface{})
+	for _, item := range list {
+		record, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		symbol := goex.ToString(record["symbol"])
+		for _, key := range []string{"baseAsset", "quoteAsset"} {
+			if asset, ok := record[key].(map[string]interface{}); ok {
+				balances = append(balances, marginBalance(asset, symbol))
+			}
+		}
+	}
+	return balances
+}
+
+func marginBalance(record map[string]interface{}, symbol string) goex.MarginBalance {
+	return goex.MarginBalance{
+		Symbol:   symbol,
+		Coin:     goex.ToString(record["asset"]),
+		Free:     goex.ToFloat(record["free"]),
+		Frozen:   goex.ToFloat(record["locked"]),
+		Borrowed: goex.ToFloat(record["borrowed"]),
+		Interest: goex.ToFloat(record["interest"]),
+	}
+}
+
+// marginInterests interest rows of interestHistory, newest first
+func marginInterests(data interface{}) []goex.MarginInterest {
+	body, _ := data.(map[string]interface{})
+	list, _ := body["rows"].([]interface{})
+	interests := make([]goex.MarginInterest, 0, len(list))
+	for _, item := range list {
+		record, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		interests = append(interests, goex.MarginInterest{
+			Symbol:   goex.ToString(record["isolatedSymbol"]),
+			Coin:     goex.ToString(record["asset"]),
+			Interest: goex.ToFloat(record["interest"]),
+			Rate:     goex.ToFloat(record["interestRate"]),
+			Time:     goex.ParseTimestamp(record["interestAccuredTime"]),
+		})
+	}
+	sort.SliceStable(interests, func(i, j int) bool { return interests[i].Time > interests[j].Time })
+	return interests
+}
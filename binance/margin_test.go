@@ -0,0 +1,79 @@
+package binance
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	goex "github.com/primitivelab/goexchange"
+)
+
+func getMarginInstance() goex.MarginAPI {
+
+	client = &http.Client{}
+	config, err := goex.LoadConfig("binance")
+	if err != nil {
+		fmt.Println(err)
+	}
+	if config != nil {
+		apiKey = config["key"].(string)
+		secretKey = config["secret"].(string)
+	}
+
+	conf := goex.APIConfig{}
+	conf.ApiKey = apiKey
+	conf.ApiSecretKey = secretKey
+	conf.HttpClient = client
+	market := NewMarginWithConfig(&conf, goex.MARGIN_CROSSED)
+	return market
+}
+
+func TestMargin_GetUserBalance(t *testing.T) {
+	market := getMarginInstance()
+
+	response := market.GetUserBalance()
+	b, _ := json.Marshal(response)
+	t.Log(string(b))
+}
+
+func TestMargin_GetInterestHistory(t *testing.T) {
+	market := getMarginInstance()
+
+	response := market.GetInterestHistory(goex.NewSymbol("btc", "usdt"), "usdt", 10, nil)
+	b, _ := json.Marshal(response)
+	t.Log(string(b))
+}
+
+func TestMargin_GetUserOpenTrustOrders(t *testing.T) {
+	market := getMarginInstance()
+
+	response := market.GetUserOpenTrustOrders(goex.NewSymbol("btc", "usdt"), 10, nil)
+	b, _ := json.Marshal(response)
+	t.Log(string(b))
+}
+
+func TestMarginBalances(t *testing.T) {
+	var _ goex.MarginAPI = &Margin{}
+
+	var data interface{}
+	json.Unmarshal([]byte(`{"userAssets":[{"asset":"BTC","free":"0.5","locked":"0.1","borrowed":"0.2","interest":"0.001","netAsset":"0.399"}]}`), &data)
+	balances := marginBalances(data)
+	if len(balances) != 1 || balances[0].Coin != "BTC" || balances[0].Symbol != "" || balances[0].Borrowed != 0.2 || balances[0].NetAsset() < 0.3989 || balances[0].NetAsset() > 0.3991 {
+		t.Fatalf("unexpected cross balances %+v", balances)
+	}
+
+	json.Unmarshal([]byte(`{"assets":[{"symbol":"BTCUSDT","baseAsset":{"asset":"BTC","free":"1","locked":"0","borrowed":"0","interest":"0"},
+		"quoteAsset":{"asset":"USDT","free":"100","locked":"0","borrowed":"50","interest":"0.1"}}]}`), &data)
+	balances = marginBalances(data)
+	if len(balances) != 2 || balances[1].Symbol != "BTCUSDT" || balances[1].Coin != "USDT" || balances[1].Interest != 0.1 {
+		t.Fatalf("unexpected isolated balances %+v", balances)
+	}
+
+	json.Unmarshal([]byte(`{"rows":[{"asset":"USDT","interest":"0.01","interestRate":"0.0002","interestAccuredTime":1620000000000,"isolatedSymbol":"BTCUSDT"},
+		{"asset":"USDT","interest":"0.02","interestRate":"0.0002","interestAccuredTime":1620003600000,"isolatedSymbol":"BTCUSDT"}],"total":2}`), &data)
+	interests := marginInterests(data)
+	if len(interests) != 2 || interests[0].Interest != 0.02 || interests[0].Symbol != "BTCUSDT" || interests[1].Time != 1620000000000 {
+		t.Fatalf("unexpected interests %+v", interests)
+	}
+}
@@ -2,6 +2,7 @@ package binance
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -201,32 +202,104 @@ func (spot *Spot) GetUserCommissionRate(symbol goex.Symbol) interface{} {
 	return result
 }
 
-// PlaceOrder place order
+// spotOrderType binance order type of trade types, spot has no trailing stop
+var spotOrderType = map[string]string{
+	goex.LIMIT:              "LIMIT",
+	goex.STOP_LIMIT:         "STOP_LOSS_LIMIT",
+	goex.STOP_MARKET:        "STOP_LOSS",
+	goex.TAKE_PROFIT_LIMIT:  "TAKE_PROFIT_LIMIT",
+	goex.TAKE_PROFIT_MARKET: "TAKE_PROFIT",
+}
+
+// PlaceOrder place order, stop and take-profit orders are triggered at StopPrice
 func (spot *Spot) PlaceOrder(order *goex.PlaceOrder) interface{} {
+	params, err := spot.orderParams(order)
+	if err != nil {
+		return goex.InvalidOrder(err)
+	}
+	result := spot.httpPost("/api/v3/order", params, true)
+	return result
+}
+
+// PlaceOcoOrder place one-cancels-the-other pair, data: order list
+func (spot *Spot) PlaceOcoOrder(order *goex.OcoOrder) interface{} {
+	if err := goex.ValidateOcoOrder(order); err != nil {
+		return goex.InvalidOrder(err)
+	}
+	return spot.httpPost("/api/v3/order/oco", spot.ocoParams(order), true)
+}
+
+// orderParams validated order params shared by spot and margin orders
+func (spot *Spot) orderParams(order *goex.PlaceOrder) (*url.Values, error) {
+	if err := goex.ValidateOrder(order); err != nil {
+		return nil, err
+	}
+	orderType, ok := spotOrderType[order.TradeType]
+	if order.TradeType == "" || order.TradeType == goex.MARKET {
+		orderType, ok = "MARKET", true
+	}
+	if !ok {
+		return nil, errors.New("binance spot has no " + order.TradeType + " orders")
+	}
+	if order.ReduceOnly || order.ClosePosition {
+		return nil, errors.New("reduce only and close position are for contract orders")
+	}
+	// market orders take quantity or quoteOrderQty, triggered ones quantity only
+	if err := goex.CheckMarketMode(order, goex.MarketOrderByBase, goex.MarketOrderByQuote); err != nil {
+		return nil, err
+	}
+	if err := checkTimeInForce(order); err != nil {
+		return nil, err
+	}
 	params := &url.Values{}
 	params.Set("symbol", spot.getSymbol(order.Symbol))
 	if order.ClientOrderId != "" {
 		params.Set("newClientOrderId", order.ClientOrderId)
 	}
 	params.Set("side", strings.ToUpper(order.Side.String()))
-	params.Set("quantity", order.Amount)
-	if order.TradeType == goex.LIMIT {
+	if orderType == "MARKET" && order.MarketMode == goex.MarketOrderByQuote {
+		params.Set("quoteOrderQty", order.Amount)
+	} else {
+		params.Set("quantity", order.Amount)
+	}
+	params.Set("type", orderType)
+	if goex.IsTriggerOrder(order.TradeType) {
+		params.Set("stopPrice", order.StopPrice)
+	}
+	if goex.IsLimitOrder(order.TradeType) {
 		params.Set("price", order.Price)
-		params.Set("type", strings.ToUpper(goex.LIMIT))
 		switch order.TimeInForce {
 		case goex.IOC:
 			params.Set("timeInForce", "IOC")
 		case goex.FOK:
 			params.Set("timeInForce", "FOK")
+		case goex.POC, goex.GTX:
+			// post only orders are LIMIT_MAKER orders, which take no timeInForce
+			if order.TradeType != goex.LIMIT {
+				return nil, &goex.TimeInForceError{TimeInForce: order.TimeInForce, Reason: "binance stop orders can not be post only"}
+			}
+			params.Set("type", "LIMIT_MAKER")
 		default:
 			params.Set("timeInForce", "GTC")
 		}
-	} else {
-		params.Set("type", strings.ToUpper(goex.MARKET))
 	}
+	return params, nil
+}
 
-	result := spot.httpPost("/api/v3/order", params, true)
-	return result
+// ocoParams params of oco order, the stop-limit leg is good till canceled
+func (spot *Spot) ocoParams(order *goex.OcoOrder) *url.Values {
+	params := &url.Values{}
+	params.Set("symbol", spot.getSymbol(order.Symbol))
+	if order.ClientOrderId != "" {
+		params.Set("listClientOrderId", order.ClientOrderId)
+	}
+	params.Set("side", strings.ToUpper(order.Side.String()))
+	params.Set("quantity", order.Amount)
+	params.Set("price", order.Price)
+	params.Set("stopPrice", order.StopPrice)
+	params.Set("stopLimitPrice", order.StopLimitPrice)
+	params.Set("stopLimitTimeInForce", "GTC")
+	return params
 }
 
 // PlaceLimitOrder place limit order
@@ -209,3 +209,115 @@ func TestBinanceSpot_BatchCancelAllOrder(t *testing.T) {
 	b, _ := json.Marshal(response)
 	t.Log(string(b))
 }
+
+func TestBinanceSpot_GetDepositAddresses(t *testing.T) {
+	market := getInstance()
+
+	response := market.GetDepositAddresses("usdt", "")
+	b, _ := json.Marshal(response)
+	t.Log(string(b))
+}
+
+func TestBinanceSpot_GetDepositHistory(t *testing.T) {
+	market := getInstance()
+
+	response := market.GetDepositHistory("usdt", 10, nil)
+	b, _ := json.Marshal(response)
+	t.Log(string(b))
+}
+
+func TestBinanceSpot_GetWithdrawHistory(t *testing.T) {
+	market := getInstance()
+
+	response := market.GetWithdrawHistory("usdt", 10, nil)
+	b, _ := json.Marshal(response)
+	t.Log(string(b))
+}
+
+func TestBinanceSpot_TransferAsset(t *testing.T) {
+	market := getInstance()
+
+	response := market.TransferAsset(&goex.TransferRequest{Coin: "usdt", From: goex.ACCOUNT_SPOT, To: goex.ACCOUNT_SWAP_USDT, Amount: "1"})
+	b, _ := json.Marshal(response)
+	t.Log(string(b))
+}
+
+func TestBinanceSpot_GetTransferHistory(t *testing.T) {
+	market := getInstance()
+
+	response := market.GetTransferHistory("usdt", goex.ACCOUNT_SPOT, goex.ACCOUNT_SWAP_USDT, 10, nil)
+	b, _ := json.Marshal(response)
+	t.Log(string(b))
+}
+
+func TestBinanceSpot_GetSubAccounts(t *testing.T) {
+	market := getInstance()
+
+	response := market.GetSubAccounts()
+	b, _ := json.Marshal(response)
+	t.Log(string(b))
+}
+
+func TestBinanceSpot_GetSubAccountBalances(t *testing.T) {
+	market := getInstance()
+
+	response := market.GetSubAccountBalances("")
+	b, _ := json.Marshal(response)
+	t.Log(string(b))
+}
+
+func TestSpotOrderParams(t *testing.T) {
+	spot := &Spot{}
+	params, err := spot.orderParams(&goex.PlaceOrder{
+		Symbol:    goex.NewSymbol("btc", "usdt"),
+		Side:      goex.SELL,
+		TradeType: goex.STOP_LIMIT,
+		Amount:    "1",
+		Price:     "89",
+		StopPrice: "90",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "price=89&quantity=1&side=SELL&stopPrice=90&symbol=BTCUSDT&timeInForce=GTC&type=STOP_LOSS_LIMIT"
+	if params.Encode() != expected {
+		t.Fatalf("expected %s, got %s", expected, params.Encode())
+	}
+	params, err = spot.orderParams(&goex.PlaceOrder{Symbol: goex.NewSymbol("btc", "usdt"), Side: goex.BUY, Amount: "100", MarketMode: goex.MarketOrderByQuote})
+	if err != nil || params.Get("quoteOrderQty") != "100" || params.Get("quantity") != "" {
+		t.Fatalf("unexpected market by quote params %s: %v", params.Encode(), err)
+	}
+	params, err = spot.orderParams(&goex.PlaceOrder{Symbol: goex.NewSymbol("btc", "usdt"), Side: goex.BUY, TradeType: goex.LIMIT, Amount: "1", Price: "100", TimeInForce: goex.POC})
+	if err != nil || params.Get("type") != "LIMIT_MAKER" || params.Get("timeInForce") != "" {
+		t.Fatalf("unexpected post only params %s: %v", params.Encode(), err)
+	}
+	invalid := []goex.PlaceOrder{
+		{TradeType: goex.STOP_LIMIT, Amount: "1", Price: "89", StopPrice: "90", TimeInForce: goex.GTX},
+		{TradeType: goex.TRAILING_STOP, Amount: "1", CallbackRate: "1"},
+		{TradeType: goex.MARKET, Amount: "1", ReduceOnly: true},
+		{TradeType: goex.TAKE_PROFIT_LIMIT, Amount: "1", Price: "110"},
+		{TradeType: goex.STOP_MARKET, Amount: "100", StopPrice: "90", MarketMode: goex.MarketOrderByQuote},
+		{TradeType: goex.MARKET, Amount: "1", TimeInForce: goex.POC},
+		{TradeType: goex.STOP_MARKET, Amount: "1", StopPrice: "90", TimeInForce: goex.FOK},
+	}
+	for _, order := range invalid {
+		if _, err := spot.orderParams(&order); err == nil {
+			t.Errorf("%+v: expected error", order)
+		}
+	}
+}
+
+func TestSpot_PlaceOcoOrder(t *testing.T) {
+	market := getInstance()
+
+	response := market.PlaceOcoOrder(&goex.OcoOrder{
+		Symbol:         goex.NewSymbol("btc", "usdt"),
+		Side:           goex.SELL,
+		Amount:         "0.001",
+		Price:          "80000",
+		StopPrice:      "20000",
+		StopLimitPrice: "19900",
+	})
+	b, _ := json.Marshal(response)
+	t.Log(string(b))
+}
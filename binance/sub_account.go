@@ -0,0 +1,108 @@
+package binance
+
+import (
+	"net/url"
+	"strings"
+
+	goex "github.com/primitivelab/goexchange"
+)
+
+// GetSubAccounts sub-account list of master account, id is sub-account email
+func (spot *Spot) GetSubAccounts() interface{} {
+	params := &url.Values{}
+	params.Set("limit", "200")
+	result := spot.httpGet("/sapi/v1/sub-account/list", params, true)
+	if result["code"] != 0 {
+		return result
+	}
+
+	data, _ := result["data"].(map[string]interface{})
+	list, _ := data["subAccounts"].([]interface{})
+	accounts := make([]goex.SubAccount, 0, len(list))
+	for _, item := range list {
+		record, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		accounts = append(accounts, goex.SubAccount{
+			Id:      goex.ToString(record["email"]),
+			Name:    goex.ToString(record["email"]),
+			Enabled: record["isFreeze"] != true,
+			Time:    goex.ParseTimestamp(record["createTime"]),
+		})
+	}
+	result["data"] = accounts
+	return result
+}
+
+// GetSubAccountBalances spot balances of sub-account email
+func (spot *Spot) GetSubAccountBalances(subAccount string) interface{} {
+	params := &url.Values{}
+	params.Set("email", subAccount)
+	result := spot.httpGet("/sapi/v3/sub-account/assets", params, true)
+	if result["code"] != 0 {
+		return result
+	}
+
+	data, _ := result["data"].(map[string]interface{})
+	list, _ := data["balances"].([]interface{})
+	balances := make([]goex.AssetBalance, 0, len(list))
+	for _, item := range list {
+		record, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		balances = append(balances, goex.AssetBalance{
+			Coin:   goex.ToString(record["asset"]),
+			Free:   goex.ToFloat(record["free"]),
+			Frozen: goex.ToFloat(record["locked"]),
+		})
+	}
+	result["data"] = balances
+	return result
+}
+
+// SubAccountTransfer transfer between spot wallets of master and sub-accounts,
+// options fromAccountType and toAccountType select other wallets
+func (spot *Spot) SubAccountTransfer(request *goex.SubTransferRequest) interface{} {
+	if request.From == request.To {
+		return goex.ReturnAPIError(goex.TransferAccountError)
+	}
+	params := &url.Values{}
+	if request.From != "" {
+		params.Set("fromEmail", request.From)
+	}
+	if request.To != "" {
+		params.Set("toEmail", request.To)
+	}
+	params.Set("fromAccountType", "SPOT")
+	params.Set("toAccountType", "SPOT")
+	params.Set("asset", strings.ToUpper(request.Coin))
+	params.Set("amount", request.Amount)
+	for key, value := range request.Options {
+		params.Set(key, value)
+	}
+
+	result := spot.httpPost("/sapi/v1/sub-account/universalTransfer", params, true)
+	if result["code"] != 0 {
+		return result
+	}
+	data, _ := result["data"].(map[string]interface{})
+	result["data"] = goex.ToString(data["tranId"])
+	return result
+}
+
+// CreateSubAccountAPIKey binance only lets broker accounts manage sub-account api keys
+func (spot *Spot) CreateSubAccountAPIKey(request *goex.SubAPIKeyRequest) interface{} {
+	return goex.ReturnAPIError(goex.MethodNotExistError)
+}
+
+// GetSubAccountAPIKeys binance only lets broker accounts manage sub-account api keys
+func (spot *Spot) GetSubAccountAPIKeys(subAccount string) interface{} {
+	return goex.ReturnAPIError(goex.MethodNotExistError)
+}
+
+// DeleteSubAccountAPIKey binance only lets broker accounts manage sub-account api keys
+func (spot *Spot) DeleteSubAccountAPIKey(subAccount, apiKey string) interface{} {
+	return goex.ReturnAPIError(goex.MethodNotExistError)
+}
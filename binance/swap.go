@@ -1,6 +1,12 @@
 package binance
 
-import "github.com/primitivelab/goexchange"
+import (
+	"errors"
+	"net/url"
+	"strings"
+
+	"github.com/primitivelab/goexchange"
+)
 
 // Swap swap api interface
 type Swap interface {
@@ -24,3 +30,142 @@ type Swap interface {
 	// Get exchange http request
 	HTTPRequest(requestURL, method string, options interface{}, signed bool) interface{}
 }
+
+// parsePositions typed positions of positionRisk list, entries of other
+// symbols than symbol and empty positions are skipped, symbol "" keeps all
+func parsePositions(data interface{}, symbol string) []goexchange.Position {
+	list, _ := data.([]interface{})
+	positions := []goexchange.Position{}
+	for _, item := range list {
+		record, ok := item.(map[string]interface{})
+		if !ok || (symbol != "" && record["symbol"] != symbol) {
+			continue
+		}
+		amount := goexchange.ToFloat(record["positionAmt"])
+		if amount == 0 {
+			continue
+		}
+		position := goexchange.Position{
+			Symbol:           goexchange.ToString(record["symbol"]),
+			Side:             goexchange.POSITION_LONG,
+			Amount:           amount,
+			EntryPrice:       goexchange.ToFloat(record["entryPrice"]),
+			MarkPrice:        goexchange.ToFloat(record["markPrice"]),
+			UnrealizedPnl:    goexchange.ToFloat(record["unRealizedProfit"]),
+			Leverage:         goexchange.ToFloat(record["leverage"]),
+			MarginType:       goexchange.ParseMarginType(record["marginType"]),
+			LiquidationPrice: goexchange.ToFloat(record["liquidationPrice"]),
+		}
+		if amount < 0 || record["positionSide"] == "SHORT" {
+			position.Side = goexchange.POSITION_SHORT
+		}
+		if amount < 0 {
+			position.Amount = -amount
+		}
+		positions = append(positions, position)
+	}
+	return positions
+}
+
+// futuresOrderType binance contract order type of trade types
+var futuresOrderType = map[string]string{
+	goexchange.LIMIT:              "LIMIT",
+	goexchange.STOP_LIMIT:         "STOP",
+	goexchange.STOP_MARKET:        "STOP_MARKET",
+	goexchange.TAKE_PROFIT_LIMIT:  "TAKE_PROFIT",
+	goexchange.TAKE_PROFIT_MARKET: "TAKE_PROFIT_MARKET",
+	goexchange.TRAILING_STOP:      "TRAILING_STOP_MARKET",
+}
+
+// checkTimeInForce orders other than limit orders take the book when they are
+// placed or triggered, so they can be IOC but not post only or FOK
+func checkTimeInForce(order *goexchange.PlaceOrder) error {
+	if goexchange.IsLimitOrder(order.TradeType) {
+		return nil
+	}
+	return goexchange.CheckTimeInForce(order.TimeInForce, goexchange.IOC)
+}
+
+// futuresOrderParams quantity, type and position params of a validated
+// contract order, reduce only orders close the position, StopPrice is the
+// activation price of trailing stops and close position orders close the
+// whole position without quantity
+func futuresOrderParams(params *url.Values, order *goexchange.PlaceOrder, hedge bool) {
+	orderType, ok := futuresOrderType[order.TradeType]
+	if !ok {
+		orderType = "MARKET"
+	}
+	params.Set("type", orderType)
+	position := *order
+	if order.ReduceOnly || order.ClosePosition {
+		position.Offset = goexchange.OFFSET_CLOSE
+	}
+	positionParams(params, &position, hedge)
+	if order.ClosePosition {
+		params.Del("reduceOnly")
+		params.Set("closePosition", "true")
+	} else {
+		params.Set("quantity", order.Amount)
+	}
+	if order.TradeType == goexchange.TRAILING_STOP {
+		params.Set("callbackRate", order.CallbackRate)
+		if order.StopPrice != "" {
+			params.Set("activationPrice", order.StopPrice)
+		}
+	} else if goexchange.IsTriggerOrder(order.TradeType) {
+		params.Set("stopPrice", order.StopPrice)
+	}
+	if goexchange.IsLimitOrder(order.TradeType) {
+		params.Set("price", order.Price)
+		switch order.TimeInForce {
+		case goexchange.IOC:
+			params.Set("timeInForce", "IOC")
+		case goexchange.FOK:
+			params.Set("timeInForce", "FOK")
+		case goexchange.POC, goexchange.GTX:
+			params.Set("timeInForce", "GTX")
+		default:
+			params.Set("timeInForce", "GTC")
+		}
+	}
+}
+
+// positionParams set positionSide of hedge mode orders, which are closed by
+// the opposite side of the position, or reduceOnly of one-way close orders
+func positionParams(params *url.Values, order *goexchange.PlaceOrder, hedge bool) {
+	if !hedge {
+		if order.Offset == goexchange.OFFSET_CLOSE {
+			params.Set("reduceOnly", "true")
+		}
+		return
+	}
+	long := order.Side == goexchange.BUY
+	if order.Offset == goexchange.OFFSET_CLOSE {
+		long = !long
+	}
+	if long {
+		params.Set("positionSide", "LONG")
+	} else {
+		params.Set("positionSide", "SHORT")
+	}
+}
+
+// amendParams params of modifying a limit contract order, binance needs
+// side, price and quantity of the modified order
+func amendParams(params *url.Values, order *goexchange.AmendOrder) error {
+	if err := goexchange.ValidateAmendOrder(order); err != nil {
+		return err
+	}
+	if (order.Side != goexchange.BUY && order.Side != goexchange.SELL) || order.Price == "" || order.Amount == "" {
+		return errors.New("binance order modify needs side, price and amount")
+	}
+	if order.ClientOrderId != "" {
+		params.Set("origClientOrderId", order.ClientOrderId)
+	} else {
+		params.Set("orderId", order.OrderId)
+	}
+	params.Set("side", strings.ToUpper(order.Side.String()))
+	params.Set("quantity", order.Amount)
+	params.Set("price", order.Price)
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
 	"testing"
 
 	goex "github.com/primitivelab/goexchange"
@@ -208,3 +209,203 @@ func TestSwap_GetTrade(t *testing.T) {
 // 	b, _ := json.Marshal(response)
 // 	t.Log(string(b))
 // }
+
+func TestParsePositions(t *testing.T) {
+	var data interface{}
+	json.Unmarshal([]byte(`[
+		{"symbol":"BTCUSDT","positionAmt":"-0.5","entryPrice":"30000","markPrice":"29000","unRealizedProfit":"500","leverage":"10","marginType":"isolated","positionSide":"BOTH","liquidationPrice":"33000"},
+		{"symbol":"BTCUSDT","positionAmt":"0","positionSide":"BOTH"},
+		{"symbol":"ETHUSDT","positionAmt":"1","marginType":"cross","positionSide":"BOTH"}
+	]`), &data)
+
+	positions := parsePositions(data, "BTCUSDT")
+	if len(positions) != 1 {
+		t.Fatalf("expected 1 position, got %+v", positions)
+	}
+	position := positions[0]
+	if position.Side != goex.POSITION_SHORT || position.Amount != 0.5 || position.EntryPrice != 30000 || position.MarkPrice != 29000 ||
+		position.UnrealizedPnl != 500 || position.Leverage != 10 || position.MarginType != goex.MARGIN_ISOLATED || position.LiquidationPrice != 33000 {
+		t.Fatalf("unexpected position %+v", position)
+	}
+	if positions = parsePositions(data, ""); len(positions) != 2 || positions[1].Side != goex.POSITION_LONG || positions[1].MarginType != goex.MARGIN_CROSSED {
+		t.Fatalf("unexpected positions %+v", positions)
+	}
+}
+
+func TestLeverageBrackets(t *testing.T) {
+	var _ goex.LeverageAPI = &SwapUsdt{}
+	var _ goex.LeverageAPI = &SwapCoin{}
+
+	var data interface{}
+	json.Unmarshal([]byte(`[{"symbol":"BTCUSDT","brackets":[
+		{"bracket":1,"initialLeverage":125,"notionalCap":50000},
+		{"bracket":2,"initialLeverage":100,"notionalCap":250000},
+		{"bracket":3,"initialLeverage":1,"notionalCap":9223372036854775807}
+	]}]`), &data)
+	brackets := leverageBrackets(data, "BTCUSDT", "notionalCap")
+	if len(brackets) != 3 || brackets[0].MaxLeverage != 125 || brackets[1].NotionalCap != 250000 || brackets[2].NotionalCap != 0 {
+		t.Fatalf("unexpected brackets %+v", brackets)
+	}
+	if len(leverageBrackets(data, "ETHUSDT", "notionalCap")) != 0 {
+		t.Fatal("brackets of other symbols should be skipped")
+	}
+
+	var positions, mode interface{}
+	json.Unmarshal([]byte(`[{"symbol":"BTCUSDT","leverage":"20","marginType":"cross","positionSide":"LONG"}]`), &positions)
+	json.Unmarshal([]byte(`{"dualSidePosition":true}`), &mode)
+	setting := leverageSetting(positions, mode, "BTCUSDT")
+	if setting.Leverage != 20 || setting.MarginType != goex.MARGIN_CROSSED || setting.PositionMode != goex.POSITION_MODE_HEDGE {
+		t.Fatalf("unexpected setting %+v", setting)
+	}
+}
+
+func TestLoadHedgeMode(t *testing.T) {
+	var hedgeMode int32
+	reads := 0
+	getMode := func() map[string]interface{} {
+		reads++
+		var mode interface{}
+		json.Unmarshal([]byte(`{"dualSidePosition":true}`), &mode)
+		return goex.ReturnAPIData(mode)
+	}
+	for i := 0; i < 2; i++ {
+		if hedge, result := loadHedgeMode(&hedgeMode, getMode); !hedge || result != nil {
+			t.Fatalf("unexpected hedge mode %v, %v", hedge, result)
+		}
+	}
+	if reads != 1 {
+		t.Fatalf("position mode should be read once, read %d times", reads)
+	}
+
+	storeHedgeMode(&hedgeMode, goex.POSITION_MODE_ONE_WAY)
+	if hedge, _ := loadHedgeMode(&hedgeMode, getMode); hedge || reads != 1 {
+		t.Fatal("stored position mode should be used")
+	}
+
+	hedgeMode = hedgeModeUnknown
+	failed := func() map[string]interface{} {
+		return goex.ReturnAPIError(goex.ExchangeError).(map[string]interface{})
+	}
+	if _, result := loadHedgeMode(&hedgeMode, failed); result == nil || hedgeMode != hedgeModeUnknown {
+		t.Fatalf("failed read should be returned, got %v", result)
+	}
+}
+
+func TestPositionParams(t *testing.T) {
+	tests := []struct {
+		order        goex.PlaceOrder
+		hedge        bool
+		positionSide string
+		reduceOnly   string
+	}{
+		{goex.PlaceOrder{Side: goex.BUY}, false, "", ""},
+		{goex.PlaceOrder{Side: goex.SELL, Offset: goex.OFFSET_CLOSE}, false, "", "true"},
+		{goex.PlaceOrder{Side: goex.BUY}, true, "LONG", ""},
+		{goex.PlaceOrder{Side: goex.SELL}, true, "SHORT", ""},
+		{goex.PlaceOrder{Side: goex.SELL, Offset: goex.OFFSET_CLOSE}, true, "LONG", ""},
+		{goex.PlaceOrder{Side: goex.BUY, Offset: goex.OFFSET_CLOSE}, true, "SHORT", ""},
+	}
+	for _, test := range tests {
+		params := &url.Values{}
+		positionParams(params, &test.order, test.hedge)
+		if params.Get("positionSide") != test.positionSide || params.Get("reduceOnly") != test.reduceOnly {
+			t.Errorf("%+v hedge %v: unexpected params %v", test.order, test.hedge, params.Encode())
+		}
+	}
+}
+
+func TestSwap_GetFundingRate(t *testing.T) {
+	market := getSwapInstance().(*SwapUsdt)
+
+	response := market.GetFundingRate(goex.NewSymbol(CoinFrom, CoinTo))
+	b, _ := json.Marshal(response)
+	t.Log(string(b))
+}
+
+func TestSwap_GetFundingRateHistory(t *testing.T) {
+	market := getSwapInstance().(*SwapUsdt)
+
+	response := market.GetFundingRateHistory(goex.NewSymbol(CoinFrom, CoinTo), 10, nil)
+	b, _ := json.Marshal(response)
+	t.Log(string(b))
+}
+
+func TestFundingRates(t *testing.T) {
+	var _ goex.FundingAPI = &SwapUsdt{}
+	var _ goex.FundingAPI = &SwapCoin{}
+
+	var data interface{}
+	json.Unmarshal([]byte(`[{"symbol":"BTCUSD_PERP","markPrice":"31000.1","indexPrice":"31001.2","lastFundingRate":"0.0001","nextFundingTime":1620000000000},
+		{"symbol":"BTCUSD_210625","markPrice":"32000","lastFundingRate":""}]`), &data)
+	info := fundingInfo(data, "BTCUSD_PERP")
+	if info.Rate != 0.0001 || info.NextFundingTime != 1620000000000 || info.MarkPrice != 31000.1 || info.IndexPrice != 31001.2 {
+		t.Fatalf("unexpected funding info %+v", info)
+	}
+
+	json.Unmarshal([]byte(`[{"symbol":"BTCUSDT","fundingRate":"0.0001","fundingTime":1619971200000},
+		{"symbol":"BTCUSDT","fundingRate":"-0.0002","fundingTime":1620000000000}]`), &data)
+	rates := fundingRates(data)
+	if len(rates) != 2 || rates[0].Rate != -0.0002 || rates[0].Time != 1620000000000 || rates[1].Symbol != "BTCUSDT" {
+		t.Fatalf("unexpected funding rates %+v", rates)
+	}
+}
+
+func TestFuturesOrderParams(t *testing.T) {
+	tests := []struct {
+		order    goex.PlaceOrder
+		expected string
+	}{
+		{
+			goex.PlaceOrder{Side: goex.SELL, TradeType: goex.STOP_MARKET, Amount: "1", StopPrice: "90", ReduceOnly: true},
+			"quantity=1&reduceOnly=true&stopPrice=90&type=STOP_MARKET",
+		},
+		{
+			goex.PlaceOrder{Side: goex.SELL, TradeType: goex.TAKE_PROFIT_MARKET, StopPrice: "120", ClosePosition: true},
+			"closePosition=true&stopPrice=120&type=TAKE_PROFIT_MARKET",
+		},
+		{
+			goex.PlaceOrder{Side: goex.BUY, TradeType: goex.STOP_LIMIT, Amount: "1", Price: "101", StopPrice: "100"},
+			"price=101&quantity=1&stopPrice=100&timeInForce=GTC&type=STOP",
+		},
+		{
+			goex.PlaceOrder{Side: goex.SELL, TradeType: goex.TRAILING_STOP, Amount: "1", CallbackRate: "1", StopPrice: "110"},
+			"activationPrice=110&callbackRate=1&quantity=1&type=TRAILING_STOP_MARKET",
+		},
+	}
+	for _, test := range tests {
+		params := &url.Values{}
+		futuresOrderParams(params, &test.order, false)
+		if params.Encode() != test.expected {
+			t.Errorf("%+v: expected %s, got %s", test.order, test.expected, params.Encode())
+		}
+	}
+}
+
+func TestSwap_PlaceOrderTimeInForce(t *testing.T) {
+	orders := []*goex.PlaceOrder{
+		{Symbol: goex.NewSymbol("btc", "usdt"), Side: goex.BUY, TradeType: goex.MARKET, Amount: "1", TimeInForce: goex.POC},
+		{Symbol: goex.NewSymbol("btc", "usdt"), Side: goex.SELL, TradeType: goex.TRAILING_STOP, Amount: "1", CallbackRate: "1", TimeInForce: goex.GTX},
+	}
+	for _, order := range orders {
+		for _, result := range []interface{}{(&SwapUsdt{}).PlaceOrder(order), (&SwapCoin{}).PlaceOrder(order)} {
+			if result.(map[string]interface{})["code"] != goex.TimeInForceParamsError.Code {
+				t.Fatalf("%+v: expected time in force error, got %v", order, result)
+			}
+		}
+	}
+}
+
+func TestAmendParams(t *testing.T) {
+	var _ goex.AmendAPI = &SwapUsdt{}
+	var _ goex.AmendAPI = &SwapCoin{}
+	var _ goex.AmendAPI = &Futures{}
+
+	params := &url.Values{}
+	err := amendParams(params, &goex.AmendOrder{OrderId: "1", Side: goex.BUY, Price: "100", Amount: "2"})
+	if err != nil || params.Encode() != "orderId=1&price=100&quantity=2&side=BUY" {
+		t.Fatalf("unexpected params %s: %v", params.Encode(), err)
+	}
+	if err := amendParams(&url.Values{}, &goex.AmendOrder{OrderId: "1", Price: "100"}); err == nil {
+		t.Fatal("expected error of amend without side and amount")
+	}
+}
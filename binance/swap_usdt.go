@@ -17,6 +17,11 @@ type SwapUsdt struct {
 	baseURL    string
 	accessKey  string
 	secretKey  string
+	// spot endpoint of universal transfers, set by SetSpotEndpoint
+	spotURL string
+	// position mode of the account for orders, set by SetPositionMode and
+	// GetLeverage or read on first order
+	hedgeMode int32
 }
 
 // NewSwapUsdt new instance
@@ -196,43 +201,53 @@ func (swap *SwapUsdt) GetUserAssets() interface{} {
 	return result
 }
 
-// GetUserPositions user open position
+// GetUserPositions user open positions, all contracts when symbol is empty,
+// data: []goex.Position
 func (swap *SwapUsdt) GetUserPositions(symbol goex.Symbol) interface{} {
 	params := &url.Values{}
-	result := swap.httpGet("/fapi/v2/account", params, true)
+	contract := ""
+	if symbol.CoinFrom != "" {
+		params.Set("symbol", swap.getSymbol(symbol))
+		contract = swap.getSymbol(symbol)
+	}
+	result := swap.httpGet("/fapi/v2/positionRisk", params, true)
 	if result["code"] != 0 {
 		return result
 	}
+	result["data"] = parsePositions(result["data"], contract)
 	return result
 }
 
-// PlaceOrder place order
+// ClosePosition close positions of contract at market when price is empty,
+// or with limit orders at price
+func (swap *SwapUsdt) ClosePosition(symbol goex.Symbol, price string) interface{} {
+	return goex.ClosePositions(swap.GetUserPositions(symbol), symbol, price, swap.PlaceOrder)
+}
+
+// PlaceOrder place order, see futuresOrderParams for trigger orders
 func (swap *SwapUsdt) PlaceOrder(order *goex.PlaceOrder) interface{} {
+	if err := goex.ValidateOrder(order); err != nil {
+		return goex.InvalidOrder(err)
+	}
+	// quantity of usdt margined contracts is in base coin
+	if err := goex.CheckMarketMode(order, goex.MarketOrderByBase); err != nil {
+		return goex.InvalidOrder(err)
+	}
+	if err := checkTimeInForce(order); err != nil {
+		return goex.InvalidOrder(err)
+	}
 	params := &url.Values{}
 	params.Set("symbol", swap.getSymbol(order.Symbol))
 	if order.ClientOrderId != "" {
 		params.Set("newClientOrderId", order.ClientOrderId)
 	}
 	params.Set("side", strings.ToUpper(order.Side.String()))
-	params.Set("quantity", order.Amount)
-	if order.TradeType == goex.LIMIT {
-		params.Set("price", order.Price)
-		params.Set("type", strings.ToUpper(goex.LIMIT))
-		switch order.TimeInForce {
-		case goex.IOC:
-			params.Set("timeInForce", "IOC")
-		case goex.FOK:
-			params.Set("timeInForce", "FOK")
-		case goex.GTX:
-			params.Set("timeInForce", "GTX")
-		default:
-			params.Set("timeInForce", "GTC")
-		}
-	} else {
-		params.Set("type", strings.ToUpper(goex.MARKET))
+	hedgeMode, result := swap.isHedgeMode()
+	if result != nil {
+		return result
 	}
-
-	result := swap.httpPost("/fapi/v1/order", params, true)
+	futuresOrderParams(params, order, hedgeMode)
+	result = swap.httpPost("/fapi/v1/order", params, true)
 	return result
 }
 
@@ -279,7 +294,6 @@ func (swap *SwapUsdt) BatchPlaceLimitOrder(orders []goex.LimitOrder) interface{}
 		param["symbol"] = swap.getSymbol(item.Symbol)
 		param["price"] = item.Price
 		param["quantity"] = item.Amount
-		param["timeInForce"] = item.TimeInForce
 		param["type"] = strings.ToUpper(goex.LIMIT)
 		param["side"] = strings.ToUpper(item.Side.String())
 		if item.ClientOrderId != "" {
@@ -290,7 +304,7 @@ func (swap *SwapUsdt) BatchPlaceLimitOrder(orders []goex.LimitOrder) interface{}
 			param["timeInForce"] = "IOC"
 		case goex.FOK:
 			param["timeInForce"] = "FOK"
-		case goex.GTX:
+		case goex.POC, goex.GTX:
 			param["timeInForce"] = "GTX"
 		default:
 			param["timeInForce"] = "GTC"
@@ -304,6 +318,16 @@ func (swap *SwapUsdt) BatchPlaceLimitOrder(orders []goex.LimitOrder) interface{}
 	return result
 }
 
+// AmendOrder modify price and amount of a resting limit order in place
+func (swap *SwapUsdt) AmendOrder(order *goex.AmendOrder) interface{} {
+	params := &url.Values{}
+	params.Set("symbol", swap.getSymbol(order.Symbol))
+	if err := amendParams(params, order); err != nil {
+		return goex.InvalidOrder(err)
+	}
+	return swap.httpPut("/fapi/v1/order", params, true)
+}
+
 // CancelOrder cancel user trust order
 func (swap *SwapUsdt) CancelOrder(symbol goex.Symbol, orderID, clientOrderID string) interface{} {
 	params := &url.Values{}
@@ -503,6 +527,17 @@ func (swap *SwapUsdt) httpPost(url string, params *url.Values, signed bool) map[
 	return swap.handlerResponse(&responseMap)
 }
 
+// httpPut Put request method
+func (swap *SwapUsdt) httpPut(url string, params *url.Values, signed bool) map[string]interface{} {
+	var responseMap goex.HttpClientResponse
+	headers := map[string]string{}
+	headers["X-MBX-APIKEY"] = swap.accessKey
+	swap.sign(params)
+	requestURL := swap.baseURL + url
+	responseMap = goex.HttpPutWithHeader(swap.httpClient, requestURL, params.Encode(), headers)
+	return swap.handlerResponse(&responseMap)
+}
+
 // httpGet Delete request method
 func (swap *SwapUsdt) httpDelete(url string, params *url.Values, signed bool) map[string]interface{} {
 	var responseMap goex.HttpClientResponse
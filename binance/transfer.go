@@ -0,0 +1,151 @@
+package binance
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+
+	goex "github.com/primitivelab/goexchange"
+)
+
+// transferAccounts binance universal transfer wallet names, delivery
+// contracts share the coin margined wallet
+var transferAccounts = map[string]string{
+	goex.ACCOUNT_SPOT:      "MAIN",
+	goex.ACCOUNT_MARGIN:    "MARGIN",
+	goex.ACCOUNT_SWAP_USDT: "UMFUTURE",
+	goex.ACCOUNT_SWAP_COIN: "CMFUTURE",
+	goex.ACCOUNT_FUTURES:   "CMFUTURE",
+}
+
+// transferStatus binance universal transfer status
+var transferStatus = map[string]string{
+	"PENDING":   goex.TRANSFER_STATUS_PENDING,
+	"CONFIRMED": goex.TRANSFER_STATUS_SUCCESS,
+	"FAILED":    goex.TRANSFER_STATUS_FAILED,
+}
+
+// TransferAsset universal transfer between user wallets, data: transfer id
+func (spot *Spot) TransferAsset(request *goex.TransferRequest) interface{} {
+	transferType, ok := transferType(request.From, request.To)
+	if !ok {
+		return goex.ReturnAPIError(goex.TransferAccountError)
+	}
+	params := &url.Values{}
+	params.Set("type", transferType)
+	params.Set("asset", strings.ToUpper(request.Coin))
+	params.Set("amount", request.Amount)
+	for key, value := range request.Options {
+		params.Set(key, value)
+	}
+
+	result := spot.httpPost("/sapi/v1/asset/transfer", params, true)
+	if result["code"] != 0 {
+		return result
+	}
+	data, _ := result["data"].(map[string]interface{})
+	result["data"] = goex.ToString(data["tranId"])
+	return result
+}
+
+// GetTransferHistory universal transfer record list, from and to are required
+// by binance, options startTime, endTime and current page
+func (spot *Spot) GetTransferHistory(coin, from, to string, size int, options map[string]string) interface{} {
+	transferType, ok := transferType(from, to)
+	if !ok {
+		return goex.ReturnAPIError(goex.TransferAccountError)
+	}
+	params := &url.Values{}
+	params.Set("type", transferType)
+	if size != 0 {
+		params.Set("size", strconv.Itoa(size))
+	}
+	for _, key := range []string{"startTime", "endTime", "current"} {
+		if value, ok := options[key]; ok {
+			params.Set(key, value)
+		}
+	}
+
+	result := spot.httpGet("/sapi/v1/asset/transfer", params, true)
+	if result["code"] != 0 {
+		return result
+	}
+
+	data, _ := result["data"].(map[string]interface{})
+	list, _ := data["rows"].([]interface{})
+	transfers := make([]goex.AccountTransfer, 0, len(list))
+	for _, item := range list {
+		record, ok := item.(map[string]interface{})
+		if !ok || (coin != "" && !strings.EqualFold(goex.ToString(record["asset"]), coin)) {
+			continue
+		}
+		transfers = append(transfers, goex.AccountTransfer{
+			Id:     goex.ToString(record["tranId"]),
+			Coin:   goex.ToString(record["asset"]),
+			From:   from,
+			To:     to,
+			Amount: goex.ToFloat(record["amount"]),
+			Status: transferStatus[goex.ToString(record["status"])],
+			Time:   goex.ParseTimestamp(record["timestamp"]),
+		})
+	}
+	result["data"] = transfers
+	return result
+}
+
+// TransferIn move coin from spot wallet to usdt margined futures wallet
+func (swap *SwapUsdt) TransferIn(coin, amount string) interface{} {
+	return swap.transfer(coin, amount, goex.ACCOUNT_SPOT, goex.ACCOUNT_SWAP_USDT)
+}
+
+// TransferOut move coin from usdt margined futures wallet to spot wallet
+func (swap *SwapUsdt) TransferOut(coin, amount string) interface{} {
+	return swap.transfer(coin, amount, goex.ACCOUNT_SWAP_USDT, goex.ACCOUNT_SPOT)
+}
+
+// SetSpotEndpoint spot endpoint TransferIn and TransferOut are sent to,
+// default https://api.binance.com
+func (swap *SwapUsdt) SetSpotEndpoint(endpoint string) {
+	swap.spotURL = endpoint
+}
+
+// transfer universal transfer is a spot api, so it is sent to the spot endpoint
+func (swap *SwapUsdt) transfer(coin, amount, from, to string) interface{} {
+	spot := New(swap.httpClient, swap.spotURL, swap.accessKey, swap.secretKey)
+	return spot.TransferAsset(&goex.TransferRequest{Coin: coin, From: from, To: to, Amount: amount})
+}
+
+// TransferIn move coin from spot wallet to coin margined futures wallet
+func (swap *SwapCoin) TransferIn(coin, amount string) interface{} {
+	return swap.transfer(coin, amount, goex.ACCOUNT_SPOT, goex.ACCOUNT_SWAP_COIN)
+}
+
+// TransferOut move coin from coin margined futures wallet to spot wallet
+func (swap *SwapCoin) TransferOut(coin, amount string) interface{} {
+	return swap.transfer(coin, amount, goex.ACCOUNT_SWAP_COIN, goex.ACCOUNT_SPOT)
+}
+
+// SetSpotEndpoint spot endpoint TransferIn and TransferOut are sent to,
+// default https://api.binance.com
+func (swap *SwapCoin) SetSpotEndpoint(endpoint string) {
+	swap.spotURL = endpoint
+}
+
+// transfer universal transfer is a spot api, so it is sent to the spot endpoint
+func (swap *SwapCoin) transfer(coin, amount, from, to string) interface{} {
+	spot := New(swap.httpClient, swap.spotURL, swap.accessKey, swap.secretKey)
+	return spot.TransferAsset(&goex.TransferRequest{Coin: coin, From: from, To: to, Amount: amount})
+}
+
+// transferType binance universal transfer type like MAIN_UMFUTURE
+func transferType(from, to string) (string, bool) {
+	fromAccount, ok := transferAccounts[from]
+	if !ok {
+		return "", false
+	}
+	toAccount, ok := transferAccounts[to]
+	if !ok || fromAccount == toAccount {
+		return "", false
+	}
+	return fromAccount + "_" + toAccount, true
+}
@@ -0,0 +1,122 @@
+package binance
+
+import (
+	"net/url"
+
+	goex "github.com/primitivelab/goexchange"
+)
+
+// depositStatus binance deposit status
+var depositStatus = map[string]string{
+	"0": goex.TRANSFER_STATUS_PENDING,
+	"6": goex.TRANSFER_STATUS_SUCCESS,
+	"1": goex.TRANSFER_STATUS_SUCCESS,
+}
+
+// withdrawStatus binance withdraw status
+var withdrawStatus = map[string]string{
+	"0": goex.TRANSFER_STATUS_PENDING,
+	"1": goex.TRANSFER_STATUS_CANCELED,
+	"2": goex.TRANSFER_STATUS_PENDING,
+	"3": goex.TRANSFER_STATUS_FAILED,
+	"4": goex.TRANSFER_STATUS_PENDING,
+	"5": goex.TRANSFER_STATUS_FAILED,
+	"6": goex.TRANSFER_STATUS_SUCCESS,
+}
+
+// GetDepositAddresses deposit address of coin, chain is binance network, empty for the default one
+func (spot *Spot) GetDepositAddresses(coin, chain string) interface{} {
+	options := map[string]string{}
+	if chain != "" {
+		options["network"] = chain
+	}
+	result := spot.GetUserDepositAddress(coin, options).(map[string]interface{})
+	if result["code"] != 0 {
+		return result
+	}
+
+	data, _ := result["data"].(map[string]interface{})
+	address := goex.DepositAddress{Coin: coin, Chain: chain}
+	address.Address = goex.ToString(data["address"])
+	address.Tag = goex.ToString(data["tag"])
+	result["data"] = []goex.DepositAddress{address}
+	return result
+}
+
+// ApplyWithdraw user withdraw, binance charges a fixed fee so request fee is not used
+func (spot *Spot) ApplyWithdraw(request *goex.WithdrawRequest) interface{} {
+	params := &url.Values{}
+	// typed fields are set last so that options can not override them
+	for key, value := range request.Options {
+		params.Set(key, value)
+	}
+	params.Set("coin", request.Coin)
+	params.Set("address", request.Address)
+	params.Set("amount", request.Amount)
+	if request.Tag != "" {
+		params.Set("addressTag", request.Tag)
+	}
+	if request.Chain != "" {
+		params.Set("network", request.Chain)
+	}
+	if request.ClientId != "" {
+		params.Set("withdrawOrderId", request.ClientId)
+	}
+	result := spot.httpPost("/sapi/v1/capital/withdraw/apply", params, true)
+	if result["code"] != 0 {
+		return result
+	}
+	data, _ := result["data"].(map[string]interface{})
+	result["data"] = data["id"]
+	return result
+}
+
+// GetDepositHistory user deposit record list, data: []goex.Transfer
+func (spot *Spot) GetDepositHistory(coin string, size int, options map[string]string) interface{} {
+	result := spot.GetUserDepositRecords(coin, size, options).(map[string]interface{})
+	if result["code"] != 0 {
+		return result
+	}
+	result["data"] = spot.parseTransfers(result["data"], goex.TRANSFER_DEPOSIT, depositStatus)
+	return result
+}
+
+// GetWithdrawHistory user withdraw record list, data: []goex.Transfer
+func (spot *Spot) GetWithdrawHistory(coin string, size int, options map[string]string) interface{} {
+	result := spot.GetUserWithdrawRecords(coin, size, options).(map[string]interface{})
+	if result["code"] != 0 {
+		return result
+	}
+	result["data"] = spot.parseTransfers(result["data"], goex.TRANSFER_WITHDRAW, withdrawStatus)
+	return result
+}
+
+func (spot *Spot) parseTransfers(data interface{}, transferType string, status map[string]string) []goex.Transfer {
+	list, _ := data.([]interface{})
+	transfers := make([]goex.Transfer, 0, len(list))
+	for _, item := range list {
+		record, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		transfer := goex.Transfer{
+			Type:   transferType,
+			Amount: goex.ToFloat(record["amount"]),
+			Fee:    goex.ToFloat(record["transactionFee"]),
+			Status: status[goex.ToString(record["status"])],
+		}
+		transfer.Id = goex.ToString(record["id"])
+		transfer.TxId = goex.ToString(record["txId"])
+		transfer.Coin = goex.ToString(record["coin"])
+		transfer.Chain = goex.ToString(record["network"])
+		transfer.Address = goex.ToString(record["address"])
+		transfer.Tag = goex.ToString(record["addressTag"])
+		if insertTime, ok := record["insertTime"]; ok {
+			transfer.Time = goex.ParseTimestamp(insertTime)
+		} else {
+			transfer.Time = goex.ParseTimestamp(record["applyTime"])
+		}
+		transfers = append(transfers, transfer)
+	}
+	return transfers
+}
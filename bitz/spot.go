@@ -156,9 +156,32 @@ func (spot *BitzSpot) GetUserBalance() interface{} {
 	return result
 }
 
-// 批量下单
+// 下单, 只支持限价单和市价单, bitz 限价单挂单到成交或撤单, 市价单立即成交
 func (spot *BitzSpot) PlaceOrder(order *PlaceOrder) interface{} {
-	return nil
+	if err := ValidateOrder(order); err != nil {
+		return InvalidOrder(err)
+	}
+	// market buys take total in quote coin and market sells in base coin
+	mode := MarketOrderByBase
+	if order.Side == BUY {
+		mode = MarketOrderByQuote
+	}
+	if err := CheckMarketMode(order, mode); err != nil {
+		return InvalidOrder(err)
+	}
+	switch {
+	case order.TradeType == LIMIT:
+		if err := CheckTimeInForce(order.TimeInForce); err != nil {
+			return InvalidOrder(err)
+		}
+		return spot.PlaceLimitOrder(order.Symbol, order.Price, order.Amount, order.Side, order.ClientOrderId)
+	case IsMarketOrder(order):
+		if err := CheckTimeInForce(order.TimeInForce, IOC); err != nil {
+			return InvalidOrder(err)
+		}
+		return spot.PlaceMarketOrder(order.Symbol, order.Amount, order.Side, order.ClientOrderId)
+	}
+	return ReturnAPIError(MethodNotExistError)
 }
 
 // 下限价单
@@ -201,6 +224,10 @@ func (spot *BitzSpot) BatchPlaceLimitOrder(orders []LimitOrder) interface{} {
 	var trustOrders []map[string]interface{}
 	tradePwd := Md5Signer(spot.passphrase)
 	for _, item := range orders {
+		// bitz orders rest until they are filled or canceled
+		if err := CheckTimeInForce(item.TimeInForce); err != nil {
+			return InvalidOrder(err)
+		}
 		param := map[string]interface{}{}
 		param["coins"] = item.Symbol.String()
 		param["price"] = item.Price
@@ -157,3 +157,29 @@ func TestBitzSpot_BatchCancelOrder(t *testing.T) {
 	b, _ := json.Marshal(response)
 	t.Log(string(b))
 }
+
+func TestPlaceOrderUnsupported(t *testing.T) {
+	market := New(client, baseUrl, apiKey, secretKey, passphrase)
+	symbol := NewSymbol("eos", "usdt")
+	orders := []*PlaceOrder{
+		{Symbol: symbol, Side: BUY, TradeType: LIMIT, Price: "1", Amount: "1", TimeInForce: POC},
+		{Symbol: symbol, Side: BUY, TradeType: MARKET, Amount: "1", TimeInForce: FOK},
+	}
+	for _, order := range orders {
+		if result := market.PlaceOrder(order); result.(map[string]interface{})["code"] != TimeInForceParamsError.Code {
+			t.Fatalf("%+v: expected time in force error, got %v", order, result)
+		}
+	}
+	result := market.PlaceOrder(&PlaceOrder{Symbol: symbol, Side: SELL, TradeType: STOP_MARKET, Amount: "1", StopPrice: "1"})
+	if result.(map[string]interface{})["code"] != MethodNotExistError.Code {
+		t.Fatalf("expected unsupported stop order, got %v", result)
+	}
+}
+
+func TestPlaceOrderMarketMode(t *testing.T) {
+	market := New(client, baseUrl, apiKey, secretKey, passphrase)
+	result := market.PlaceOrder(&PlaceOrder{Symbol: NewSymbol("eos", "usdt"), Side: SELL, TradeType: MARKET, Amount: "10", MarketMode: MarketOrderByQuote})
+	if result.(map[string]interface{})["code"] != OrderParamsError.Code {
+		t.Fatalf("expected market mode error, got %v", result)
+	}
+}
@@ -0,0 +1,25 @@
+package bitz
+
+import (
+	. "github.com/primitivelab/goexchange"
+)
+
+// GetDepositAddresses deposit address is not supported
+func (spot *BitzSpot) GetDepositAddresses(coin, chain string) interface{} {
+	return ReturnAPIError(MethodNotExistError)
+}
+
+// ApplyWithdraw withdraw is not supported
+func (spot *BitzSpot) ApplyWithdraw(request *WithdrawRequest) interface{} {
+	return ReturnAPIError(MethodNotExistError)
+}
+
+// GetDepositHistory deposit record is not supported
+func (spot *BitzSpot) GetDepositHistory(coin string, size int, options map[string]string) interface{} {
+	return ReturnAPIError(MethodNotExistError)
+}
+
+// GetWithdrawHistory withdraw record is not supported
+func (spot *BitzSpot) GetWithdrawHistory(coin string, size int, options map[string]string) interface{} {
+	return ReturnAPIError(MethodNotExistError)
+}
@@ -17,6 +17,7 @@ import (
 	"github.com/primitivelab/goexchange/huobi"
 	"github.com/primitivelab/goexchange/mxc"
 	"github.com/primitivelab/goexchange/okex"
+	"github.com/primitivelab/goexchange/paper"
 	"github.com/primitivelab/goexchange/poloniex"
 )
 
@@ -29,6 +30,16 @@ type APIBuilder struct {
 	accountId        string
 	passphrase       string
 	endPoint         string
+	// sub-account credentials by name
+	accounts map[string]Credential
+}
+
+// Credential api key of one account, AccountId is needed by huobi
+type Credential struct {
+	ApiKey        string
+	ApiSecretKey  string
+	ApiPassphrase string
+	AccountId     string
 }
 
 type HttpClientConfig struct {
@@ -122,8 +133,10 @@ func (builder *APIBuilder) HttpProxy(proxyUrl string) (_builder *APIBuilder) {
 		return builder
 	}
 	builder.HttpClientConfig.Proxy = proxy
-	transport := builder.client.Transport.(*http.Transport)
-	transport.Proxy = http.ProxyURL(proxy)
+	transport := builder.transport()
+	if transport != nil {
+		transport.Proxy = http.ProxyURL(proxy)
+	}
 	return builder
 }
 
@@ -131,7 +144,7 @@ func (builder *APIBuilder) HttpTimeout(timeout time.Duration) (_builder *APIBuil
 	builder.HttpClientConfig.HttpTimeout = timeout
 	builder.httpTimeout = timeout
 	builder.client.Timeout = timeout
-	transport := builder.client.Transport.(*http.Transport)
+	transport := builder.transport()
 	if transport != nil {
 		// transport.ResponseHeaderTimeout = timeout
 		// transport.TLSHandshakeTimeout = timeout
@@ -143,6 +156,23 @@ func (builder *APIBuilder) HttpTimeout(timeout time.Duration) (_builder *APIBuil
 	return builder
 }
 
+// HttpRecorder send requests of built apis through recorder, to record golden
+// files from the exchange or replay them
+func (builder *APIBuilder) HttpRecorder(recorder *HTTPRecorder) (_builder *APIBuilder) {
+	builder.client = recorder.WrapClient(builder.client)
+	return builder
+}
+
+// transport underlying http transport of client, nil if it is a custom one
+func (builder *APIBuilder) transport() *http.Transport {
+	roundTripper := builder.client.Transport
+	if recorder, ok := roundTripper.(*HTTPRecorder); ok {
+		roundTripper = recorder.Transport
+	}
+	transport, _ := roundTripper.(*http.Transport)
+	return transport
+}
+
 func (builder *APIBuilder) APIKey(key string) (_builder *APIBuilder) {
 	builder.apiKey = key
 	return builder
@@ -168,14 +198,32 @@ func (builder *APIBuilder) Endpoint(endpoint string) (_builer *APIBuilder) {
 	return builder
 }
 
+// SubAccount register credential of sub-account name, apis of it are built by As(name)
+func (builder *APIBuilder) SubAccount(name string, credential Credential) (_builder *APIBuilder) {
+	if builder.accounts == nil {
+		builder.accounts = map[string]Credential{}
+	}
+	builder.accounts[name] = credential
+	return builder
+}
+
+// As copy of builder acting on behalf of sub-account name, it shares the
+// http client and registered sub-accounts. nil when name is not registered
+func (builder *APIBuilder) As(name string) (_builder *APIBuilder) {
+	credential, ok := builder.accounts[name]
+	if !ok {
+		return nil
+	}
+	account := *builder
+	account.apiKey = credential.ApiKey
+	account.secretKey = credential.ApiSecretKey
+	account.passphrase = credential.ApiPassphrase
+	account.accountId = credential.AccountId
+	return &account
+}
+
 func (builder *APIBuilder) Build(exName string) (api SpotAPI) {
-	config := APIConfig{}
-	config.HttpClient = builder.client
-	config.ApiKey = builder.apiKey
-	config.ApiSecretKey = builder.secretKey
-	config.ApiPassphrase = builder.passphrase
-	config.Endpoint = builder.endPoint
-	config.AccountId = builder.accountId
+	config := builder.config()
 
 	switch exName {
 	case EXCHANGE_BINANCE:
@@ -201,3 +249,89 @@ func (builder *APIBuilder) Build(exName string) (api SpotAPI) {
 	}
 	return api
 }
+
+// config api config of builder settings
+func (builder *APIBuilder) config() APIConfig {
+	config := APIConfig{}
+	config.HttpClient = builder.client
+	config.ApiKey = builder.apiKey
+	config.ApiSecretKey = builder.secretKey
+	config.ApiPassphrase = builder.passphrase
+	config.Endpoint = builder.endPoint
+	config.AccountId = builder.accountId
+	return config
+}
+
+// BuildPaper build simulated exchange, orders are matched in memory against
+// depth of exName, strategies can run on it unchanged in dry-run mode
+func (builder *APIBuilder) BuildPaper(exName string, config *paper.Config) (api SpotAPI) {
+	source := builder.Build(exName)
+	if source == nil {
+		return nil
+	}
+	return paper.NewSpot(source, config)
+}
+
+// BuildWallet build wallet api of exName, nil when the adapter has none
+func (builder *APIBuilder) BuildWallet(exName string) (api WalletAPI) {
+	wallet, _ := builder.Build(exName).(WalletAPI)
+	return wallet
+}
+
+// BuildTransfer build internal transfer api of exName, nil when the adapter has none
+func (builder *APIBuilder) BuildTransfer(exName string) (api TransferAPI) {
+	transfer, _ := builder.Build(exName).(TransferAPI)
+	return transfer
+}
+
+// BuildSubAccount build sub-account api of exName, nil when the adapter has none
+func (builder *APIBuilder) BuildSubAccount(exName string) (api SubAccountAPI) {
+	subAccount, _ := builder.Build(exName).(SubAccountAPI)
+	return subAccount
+}
+
+// BuildFutures build delivery contract api of exName, nil when the adapter has none
+func (builder *APIBuilder) BuildFutures(exName string) (api FuturesAPI) {
+	config := builder.config()
+	switch exName {
+	case EXCHANGE_BINANCE:
+		api = binance.NewFuturesWithConfig(&config)
+	case EXCHANGE_HUOBI:
+		api = huobi.NewFuturesWithConfig(&config)
+	case EXCHANGE_OKEX:
+		api = okex.NewFuturesWithConfig(&config)
+	}
+	return api
+}
+
+// BuildFunding build funding rate api of the usdt margined swaps of exName,
+// nil when the adapter has none
+func (builder *APIBuilder) BuildFunding(exName string) (api FundingAPI) {
+	config := builder.config()
+	switch exName {
+	case EXCHANGE_BINANCE:
+		api = binance.NewSwapUsdtWithConfig(&config)
+	case EXCHANGE_HUOBI:
+		api = huobi.NewSwapUsdtWithConfig(&config)
+	case EXCHANGE_OKEX:
+		api = okex.NewSwapWithConfig(&config)
+	}
+	return api
+}
+
+// BuildMargin build margin api of exName trading the marginType account,
+// nil when the adapter has none
+func (builder *APIBuilder) BuildMargin(exName, marginType string) (api MarginAPI) {
+	config := builder.config()
+	switch exName {
+	case EXCHANGE_BINANCE:
+		api = binance.NewMarginWithConfig(&config, marginType)
+	case EXCHANGE_HUOBI:
+		api = huobi.NewMarginWithConfig(&config, marginType)
+	case EXCHANGE_OKEX:
+		if marginType == MARGIN_ISOLATED {
+			api = okex.NewMarginWithConfig(&config)
+		}
+	}
+	return api
+}
@@ -21,3 +21,85 @@ func TestGetUserBalance(t *testing.T) {
 	api := DefaultAPIBuilder.Build("okex")
 	t.Log(api.GetUserBalance())
 }
+
+func TestBuildWallet(t *testing.T) {
+	for _, exName := range []string{"binance", "huobi", "okex", "gate", "bitz", "mxc", "hoo", "poloniex", "biki"} {
+		if DefaultAPIBuilder.BuildWallet(exName) == nil {
+			t.Fatalf("%s has no wallet api", exName)
+		}
+	}
+}
+
+func TestBuildTransfer(t *testing.T) {
+	for _, exName := range []string{"binance", "huobi"} {
+		if DefaultAPIBuilder.BuildTransfer(exName) == nil {
+			t.Fatalf("%s has no transfer api", exName)
+		}
+	}
+	if DefaultAPIBuilder.BuildTransfer("gate") != nil {
+		t.Fatal("gate should have no transfer api")
+	}
+}
+
+func TestBuildSubAccount(t *testing.T) {
+	for _, exName := range []string{"binance", "huobi", "okex"} {
+		if DefaultAPIBuilder.BuildSubAccount(exName) == nil {
+			t.Fatalf("%s has no sub-account api", exName)
+		}
+	}
+}
+
+func TestAPIBuilder_As(t *testing.T) {
+	builder := NewAPIBuilder().APIKey("master").AccountId("1")
+	builder.SubAccount("sub1", Credential{ApiKey: "key1", ApiSecretKey: "secret1", AccountId: "2"})
+
+	if builder.As("sub2") != nil {
+		t.Fatal("unregistered sub-account should be nil")
+	}
+	sub := builder.As("sub1")
+	if sub.apiKey != "key1" || sub.secretKey != "secret1" || sub.accountId != "2" {
+		t.Fatalf("unexpected sub-account credential %s %s %s", sub.apiKey, sub.secretKey, sub.accountId)
+	}
+	if builder.apiKey != "master" || builder.accountId != "1" {
+		t.Fatal("master credential should not change")
+	}
+	if sub.GetHttpClient() != builder.GetHttpClient() || sub.Build("huobi") == nil {
+		t.Fatal("sub-account builder should share http client")
+	}
+}
+
+func TestBuildFutures(t *testing.T) {
+	for _, exName := range []string{"binance", "huobi", "okex"} {
+		api := DefaultAPIBuilder.BuildFutures(exName)
+		if api == nil || api.GetExchangeName() != exName {
+			t.Fatalf("%s has no futures api", exName)
+		}
+	}
+	if DefaultAPIBuilder.BuildFutures("gate") != nil {
+		t.Fatal("gate should have no futures api")
+	}
+}
+
+func TestBuildFunding(t *testing.T) {
+	for _, exName := range []string{"binance", "huobi", "okex"} {
+		api := DefaultAPIBuilder.BuildFunding(exName)
+		if api == nil || api.GetExchangeName() != exName {
+			t.Fatalf("%s has no funding api", exName)
+		}
+	}
+	if DefaultAPIBuilder.BuildFunding("gate") != nil {
+		t.Fatal("gate should have no funding api")
+	}
+}
+
+func TestBuildMargin(t *testing.T) {
+	for _, exName := range []string{"binance", "huobi", "okex"} {
+		api := DefaultAPIBuilder.BuildMargin(exName, goexchange.MARGIN_ISOLATED)
+		if api == nil || api.GetExchangeName() != exName || api.GetMarginType() != goexchange.MARGIN_ISOLATED {
+			t.Fatalf("%s has no isolated margin api", exName)
+		}
+	}
+	if DefaultAPIBuilder.BuildMargin("okex", goexchange.MARGIN_CROSSED) != nil {
+		t.Fatal("okex should have no cross margin api")
+	}
+}
@@ -0,0 +1,107 @@
+package clientid
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// numericEpoch start of the seconds in numeric ids, 9 digits of seconds last
+// until 2051
+var numericEpoch = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC).Unix()
+
+// Generator unique client order ids of a rule, every id starts with the
+// strategy tag so that orders and fills can be routed back to the strategy.
+// ids are prefix, strategy, base36 milliseconds, 2 random chars of the
+// generator and a 3 chars sequence, numeric ids are 1, strategy in 3 digits,
+// 9 digits of seconds and a 5 digits sequence
+type Generator struct {
+	rule     Rule
+	strategy string
+	instance string
+	mutex    sync.Mutex
+	sequence int64
+	now      func() time.Time
+}
+
+// New new instance, numeric rules take a strategy of up to 3 digits and the
+// others a strategy of letters and digits short enough for the max length
+func New(rule Rule, strategy string) (*Generator, error) {
+	random := rand.New(rand.NewSource(time.Now().UnixNano()))
+	generator := &Generator{rule: rule, strategy: strategy, now: time.Now}
+	if rule.Numeric {
+		if len(strategy) > 3 || strings.TrimLeft(strategy, "0123456789") != "" {
+			return nil, fmt.Errorf("strategy %q of numeric ids must be up to 3 digits", strategy)
+		}
+		generator.strategy = leftPad(strategy, 3)
+		generator.sequence = random.Int63n(100000)
+	} else {
+		for i := 0; i < len(strategy); i++ {
+			if !isLetter(strategy[i]) && !isDigit(strategy[i]) {
+				return nil, fmt.Errorf("strategy %q must be letters and digits", strategy)
+			}
+		}
+		generator.instance = leftPad(strconv.FormatInt(random.Int63n(36*36), 36), 2)
+		generator.sequence = random.Int63n(36 * 36 * 36)
+	}
+	if err := rule.Validate(generator.format(generator.now(), 0)); err != nil {
+		return nil, errors.New("strategy does not fit client order id rule: " + err.Error())
+	}
+	return generator, nil
+}
+
+// NewForExchange new instance of the rule of exchange
+func NewForExchange(exchange, strategy string) (*Generator, error) {
+	rule, ok := GetRule(exchange)
+	if !ok {
+		return nil, errors.New("exchange takes no client order ids: " + exchange)
+	}
+	return New(rule, strategy)
+}
+
+// Next new client order id
+func (generator *Generator) Next() string {
+	generator.mutex.Lock()
+	defer generator.mutex.Unlock()
+	generator.sequence++
+	return generator.format(generator.now(), generator.sequence)
+}
+
+// Strategy strategy tag of the ids
+func (generator *Generator) Strategy() string {
+	return generator.strategy
+}
+
+// Match id was made by a generator of the same rule and strategy
+func (generator *Generator) Match(id string) bool {
+	if generator.rule.Numeric {
+		return len(id) == 18 && strings.HasPrefix(id, "1"+generator.strategy)
+	}
+	head := generator.rule.Prefix + generator.strategy
+	return strings.HasPrefix(id, head) && len(id) == len(head)+8+5
+}
+
+func (generator *Generator) format(now time.Time, sequence int64) string {
+	if generator.rule.Numeric {
+		seconds := (now.Unix() - numericEpoch) % 1e9
+		return fmt.Sprintf("1%s%09d%05d", generator.strategy, seconds, sequence%100000)
+	}
+	millis := strconv.FormatInt(now.UnixNano()/1e6, 36)
+	if len(millis) > 8 {
+		millis = millis[len(millis)-8:]
+	}
+	return generator.rule.Prefix + generator.strategy + leftPad(millis, 8) +
+		generator.instance + leftPad(strconv.FormatInt(sequence%(36*36*36), 36), 3)
+}
+
+// leftPad pad text with zeros to length
+func leftPad(text string, length int) string {
+	if len(text) >= length {
+		return text
+	}
+	return strings.Repeat("0", length-len(text)) + text
+}
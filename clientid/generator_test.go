@@ -0,0 +1,93 @@
+package clientid
+
+import (
+	"testing"
+	"time"
+
+	goex "github.com/primitivelab/goexchange"
+)
+
+func TestRule_Validate(t *testing.T) {
+	okex, _ := GetRule(goex.EXCHANGE_OKEX)
+	gate, _ := GetRule(goex.EXCHANGE_GATE)
+	tests := []struct {
+		rule  Rule
+		id    string
+		valid bool
+	}{
+		{okex, "grid1abc", true},
+		{okex, "1grid", false},
+		{okex, "grid-1", false},
+		{okex, "a234567890123456789012345678901234", false},
+		{gate, "t-grid_1", true},
+		{gate, "grid_1", false},
+		{HuobiContractRule, "123456789012345678", true},
+		{HuobiContractRule, "0123", false},
+		{HuobiContractRule, "1234567890123456789", false},
+		{HuobiContractRule, "12a", false},
+	}
+	for _, test := range tests {
+		if err := test.rule.Validate(test.id); (err == nil) != test.valid {
+			t.Errorf("%+v %s: expected valid %v, got %v", test.rule, test.id, test.valid, err)
+		}
+	}
+	if _, ok := GetRule(goex.EXCHANGE_BIKI); ok {
+		t.Error("biki takes no client order ids")
+	}
+}
+
+func TestGenerator_Next(t *testing.T) {
+	for _, exchange := range []string{goex.EXCHANGE_BINANCE, goex.EXCHANGE_HUOBI, goex.EXCHANGE_OKEX, goex.EXCHANGE_GATE, goex.EXCHANGE_POLONIEX} {
+		strategy := "grid"
+		if exchange == goex.EXCHANGE_POLONIEX {
+			strategy = "7"
+		}
+		generator, err := NewForExchange(exchange, strategy)
+		if err != nil {
+			t.Fatal(exchange, err)
+		}
+		rule, _ := GetRule(exchange)
+		seen := map[string]bool{}
+		for i := 0; i < 1000; i++ {
+			id := generator.Next()
+			if err := rule.Validate(id); err != nil {
+				t.Fatal(exchange, err)
+			}
+			if seen[id] || !generator.Match(id) {
+				t.Fatalf("%s: id %s is duplicated or not matched", exchange, id)
+			}
+			seen[id] = true
+		}
+	}
+	generator, _ := New(HuobiContractRule, "12")
+	generator.now = func() time.Time { return time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC) }
+	if id := generator.Next(); len(id) != 18 || id[:13] != "1012031622400" {
+		t.Fatalf("unexpected numeric id %s", id)
+	}
+	other, _ := NewForExchange(goex.EXCHANGE_BINANCE, "dca")
+	if other.Match(generator.Next()) {
+		t.Error("id of another strategy is matched")
+	}
+}
+
+func TestNew(t *testing.T) {
+	okex, _ := GetRule(goex.EXCHANGE_OKEX)
+	invalid := []struct {
+		rule     Rule
+		strategy string
+	}{
+		{okex, "1grid"},
+		{okex, "grid_1"},
+		{okex, "averyveryverylongstrategy"},
+		{HuobiContractRule, "1234"},
+		{HuobiContractRule, "g"},
+	}
+	for _, test := range invalid {
+		if _, err := New(test.rule, test.strategy); err == nil {
+			t.Errorf("%+v %s: expected error", test.rule, test.strategy)
+		}
+	}
+	if _, err := NewForExchange(goex.EXCHANGE_HOO, ""); err == nil {
+		t.Error("expected error of exchange without client order ids")
+	}
+}
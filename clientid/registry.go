@@ -0,0 +1,254 @@
+package clientid
+
+import (
+	"encoding/json"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	goex "github.com/primitivelab/goexchange"
+)
+
+// record status
+const (
+	// registered, the exchange answer is not known yet
+	STATUS_PENDING = "pending"
+	// the exchange has the order, OrderId is set
+	STATUS_LANDED = "landed"
+	// the exchange or the adapter rejected the place request
+	STATUS_REJECTED = "rejected"
+	// the exchange does not know the order after Timeout
+	STATUS_MISSING = "missing"
+)
+
+// Config registry config
+type Config struct {
+	// pending orders are queried by Resolve only after Timeout, as exchanges
+	// may not list an order right after placing it, default 10 seconds
+	Timeout time.Duration
+	// records are saved to Store after every change and loaded by
+	// NewRegistry, nil keeps them in memory only
+	Store Store
+	// current time, default time.Now
+	Clock func() time.Time
+}
+
+// Record client order id and the exchange order id it maps to
+type Record struct {
+	ClientOrderId string      `json:"clientOrderId"`
+	OrderId       string      `json:"orderId"`
+	Symbol        goex.Symbol `json:"symbol"`
+	Status        string      `json:"status"`
+	Error         string      `json:"error"`
+	CreateTime    int64       `json:"createTime"`
+	UpdateTime    int64       `json:"updateTime"`
+}
+
+// Registry client order ids of placed orders, every id is placed at most
+// once and orders whose place request timed out are looked up by client id
+type Registry struct {
+	config  Config
+	mutex   sync.Mutex
+	records map[string]*Record
+}
+
+// NewRegistry new instance, records saved in config.Store are restored
+func NewRegistry(config *Config) (*Registry, error) {
+	registry := &Registry{records: map[string]*Record{}}
+	if config != nil {
+		registry.config = *config
+	}
+	if registry.config.Timeout == 0 {
+		registry.config.Timeout = 10 * time.Second
+	}
+	if registry.config.Clock == nil {
+		registry.config.Clock = time.Now
+	}
+	if registry.config.Store == nil {
+		return registry, nil
+	}
+	data, err := registry.config.Store.Load()
+	if err != nil || len(data) == 0 {
+		return registry, err
+	}
+	var records []*Record
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, err
+	}
+	for _, record := range records {
+		registry.records[record.ClientOrderId] = record
+	}
+	return registry, nil
+}
+
+// Register add pending client order id before its order is placed, error
+// when the id is registered already
+func (registry *Registry) Register(clientOrderId string, symbol goex.Symbol) error {
+	if clientOrderId == "" {
+		return errors.New("client order id is empty")
+	}
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+	if _, ok := registry.records[clientOrderId]; ok {
+		return errors.New("client order id is registered already: " + clientOrderId)
+	}
+	now := registry.now()
+	registry.records[clientOrderId] = &Record{
+		ClientOrderId: clientOrderId,
+		Symbol:        symbol,
+		Status:        STATUS_PENDING,
+		CreateTime:    now,
+		UpdateTime:    now,
+	}
+	return registry.save()
+}
+
+// Placed record PlaceOrder result of registered client order id, transport
+// errors keep the record pending as the order may have landed
+func (registry *Registry) Placed(clientOrderId string, result interface{}) (Record, error) {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+	record, ok := registry.records[clientOrderId]
+	if !ok {
+		return Record{}, errors.New("client order id is not registered: " + clientOrderId)
+	}
+	order, err := goex.ParseOrder(result)
+	switch {
+	case err == nil:
+		record.Status = STATUS_LANDED
+		record.OrderId = order.OrderId
+		record.Error = ""
+	case isTransportError(err):
+		record.Error = err.Error()
+	default:
+		record.Status = STATUS_REJECTED
+		record.Error = err.Error()
+	}
+	record.UpdateTime = registry.now()
+	return *record, registry.save()
+}
+
+// Place register client order id of order and place it, order without a
+// client order id or with a registered one is not placed
+func (registry *Registry) Place(api goex.OrderAPI, order *goex.PlaceOrder) interface{} {
+	if err := registry.Register(order.ClientOrderId, order.Symbol); err != nil {
+		return goex.InvalidOrder(err)
+	}
+	result := api.PlaceOrder(order)
+	registry.Placed(order.ClientOrderId, result)
+	return result
+}
+
+// Resolve answer whether the order of client order id landed, pending and
+// missing records older than Timeout are looked up on the exchange by client
+// order id, error when the lookup itself fails
+func (registry *Registry) Resolve(api goex.OrderAPI, clientOrderId string) (Record, error) {
+	registry.mutex.Lock()
+	record, ok := registry.records[clientOrderId]
+	if !ok {
+		registry.mutex.Unlock()
+		return Record{}, errors.New("client order id is not registered: " + clientOrderId)
+	}
+	waiting := registry.now()-record.CreateTime < registry.config.Timeout.Milliseconds()
+	if record.Status == STATUS_LANDED || record.Status == STATUS_REJECTED || waiting {
+		defer registry.mutex.Unlock()
+		return *record, nil
+	}
+	symbol := record.Symbol
+	registry.mutex.Unlock()
+
+	order, err := goex.ParseOrder(api.GetUserOrderInfo(symbol, "", clientOrderId))
+	if err != nil && isTransportError(err) {
+		return registry.Record(clientOrderId)
+	}
+
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+	if err == nil {
+		record.Status = STATUS_LANDED
+		record.OrderId = order.OrderId
+		record.Error = ""
+	} else {
+		record.Status = STATUS_MISSING
+		record.Error = err.Error()
+	}
+	record.UpdateTime = registry.now()
+	return *record, registry.save()
+}
+
+// Record copy of record by client order id, error when it is not registered
+func (registry *Registry) Record(clientOrderId string) (Record, error) {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+	record, ok := registry.records[clientOrderId]
+	if !ok {
+		return Record{}, errors.New("client order id is not registered: " + clientOrderId)
+	}
+	return *record, nil
+}
+
+// Records copies of records of status sorted by create time, all records
+// when status is empty
+func (registry *Registry) Records(status string) []Record {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+	records := make([]Record, 0, len(registry.records))
+	for _, record := range registry.sorted() {
+		if status == "" || record.Status == status {
+			records = append(records, *record)
+		}
+	}
+	return records
+}
+
+// Remove forget client order id, its order is no longer tracked
+func (registry *Registry) Remove(clientOrderId string) error {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+	delete(registry.records, clientOrderId)
+	return registry.save()
+}
+
+// save write all records to store
+func (registry *Registry) save() error {
+	if registry.config.Store == nil {
+		return nil
+	}
+	data, err := json.Marshal(registry.sorted())
+	if err != nil {
+		return err
+	}
+	return registry.config.Store.Save(data)
+}
+
+// sorted records by create time and client order id
+func (registry *Registry) sorted() []*Record {
+	records := make([]*Record, 0, len(registry.records))
+	for _, record := range registry.records {
+		records = append(records, record)
+	}
+	sort.Slice(records, func(i, j int) bool {
+		if records[i].CreateTime != records[j].CreateTime {
+			return records[i].CreateTime < records[j].CreateTime
+		}
+		return records[i].ClientOrderId < records[j].ClientOrderId
+	})
+	return records
+}
+
+func (registry *Registry) now() int64 {
+	return registry.config.Clock().UnixNano() / int64(time.Millisecond)
+}
+
+// isTransportError the request may have reached the exchange, but its answer
+// was lost
+func isTransportError(err error) bool {
+	var apiErr *goex.APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	code := goex.ToFloat(apiErr.Code)
+	return code == float64(goex.HttpClientInternalError.Code) || code == float64(goex.HttpRequestError.Code) ||
+		code == float64(goex.JsonUnmarshalError.Code)
+}
@@ -0,0 +1,117 @@
+package clientid
+
+import (
+	"testing"
+	"time"
+
+	goex "github.com/primitivelab/goexchange"
+	"github.com/primitivelab/goexchange/paper"
+)
+
+var btcUsdt = goex.NewSymbol("btc", "usdt")
+
+type memoryStore struct {
+	data []byte
+}
+
+func (store *memoryStore) Load() ([]byte, error) {
+	return store.data, nil
+}
+
+func (store *memoryStore) Save(data []byte) error {
+	store.data = append([]byte{}, data...)
+	return nil
+}
+
+type testClock struct {
+	now time.Time
+}
+
+func (clock *testClock) Now() time.Time {
+	return clock.now
+}
+
+// lostSpot paper spot whose place answers are lost on the way back
+type lostSpot struct {
+	*paper.Spot
+}
+
+func (spot *lostSpot) PlaceOrder(order *goex.PlaceOrder) interface{} {
+	spot.Spot.PlaceOrder(order)
+	return goex.ReturnAPIError(goex.HttpClientInternalError)
+}
+
+func getPaperInstance() *paper.Spot {
+	spot := paper.NewSpot(nil, &paper.Config{
+		Balances: map[string]float64{"usdt": 100000, "btc": 10},
+	})
+	spot.SetDepth(btcUsdt, &goex.Depth{
+		Bids: []goex.DepthRecord{{Price: 99, Amount: 10}},
+		Asks: []goex.DepthRecord{{Price: 101, Amount: 10}},
+	})
+	return spot
+}
+
+func limitOrder(clientOrderId string) *goex.PlaceOrder {
+	return &goex.PlaceOrder{Symbol: btcUsdt, ClientOrderId: clientOrderId, Side: goex.BUY, TradeType: goex.LIMIT, Price: "90", Amount: "1"}
+}
+
+func TestRegistry_Place(t *testing.T) {
+	var _ goex.OrderAPI = &lostSpot{}
+	spot := getPaperInstance()
+	registry, _ := NewRegistry(nil)
+
+	if _, err := goex.ParseOrder(registry.Place(spot, limitOrder("a1"))); err != nil {
+		t.Fatal(err)
+	}
+	record, _ := registry.Record("a1")
+	if record.Status != STATUS_LANDED || record.OrderId == "" {
+		t.Fatalf("unexpected record %+v", record)
+	}
+	if _, err := goex.ParseOrder(registry.Place(spot, limitOrder("a1"))); err == nil {
+		t.Fatal("expected error of placing a registered client order id")
+	}
+	if len(spot.GetOrders()) != 1 {
+		t.Fatalf("unexpected orders %+v", spot.GetOrders())
+	}
+	registry.Place(spot, &goex.PlaceOrder{Symbol: btcUsdt, ClientOrderId: "a2", Side: goex.BUY, TradeType: goex.LIMIT, Amount: "1"})
+	if record, _ := registry.Record("a2"); record.Status != STATUS_REJECTED || record.Error == "" {
+		t.Fatalf("unexpected record %+v", record)
+	}
+}
+
+func TestRegistry_Resolve(t *testing.T) {
+	spot := getPaperInstance()
+	clock := &testClock{now: time.Unix(1600000000, 0)}
+	store := &memoryStore{}
+	registry, _ := NewRegistry(&Config{Timeout: 5 * time.Second, Store: store, Clock: clock.Now})
+
+	registry.Place(&lostSpot{spot}, limitOrder("b1"))
+	registry.Register("b2", btcUsdt)
+	if records := registry.Records(STATUS_PENDING); len(records) != 2 {
+		t.Fatalf("unexpected pending records %+v", records)
+	}
+	if record, err := registry.Resolve(spot, "b1"); err != nil || record.Status != STATUS_PENDING {
+		t.Fatalf("resolved before timeout %+v: %v", record, err)
+	}
+
+	clock.now = clock.now.Add(6 * time.Second)
+	restored, err := NewRegistry(&Config{Timeout: 5 * time.Second, Store: store, Clock: clock.Now})
+	if err != nil {
+		t.Fatal(err)
+	}
+	record, err := restored.Resolve(spot, "b1")
+	if err != nil || record.Status != STATUS_LANDED || record.OrderId != spot.GetOrders()[0].OrderId {
+		t.Fatalf("unexpected landed record %+v: %v", record, err)
+	}
+	if record, err = restored.Resolve(spot, "b2"); err != nil || record.Status != STATUS_MISSING {
+		t.Fatalf("unexpected missing record %+v: %v", record, err)
+	}
+	if _, err := restored.Resolve(spot, "b3"); err == nil {
+		t.Fatal("expected error of unknown client order id")
+	}
+	restored.Remove("b2")
+	if records := restored.Records(""); len(records) != 1 {
+		t.Fatalf("unexpected records %+v", records)
+	}
+}
@@ -0,0 +1,86 @@
+package clientid
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	goex "github.com/primitivelab/goexchange"
+)
+
+// Rule client order id rule of an exchange
+type Rule struct {
+	// max length including Prefix
+	MaxLength int
+	// every id starts with Prefix, like t- of gate
+	Prefix string
+	// ids are decimal integers below 10^18, which fit int64
+	Numeric bool
+	// first char after Prefix is a letter
+	LetterFirst bool
+	// chars allowed besides letters and digits
+	Symbols string
+}
+
+// rules of exchanges taking client order ids, biki, hoo and bitz take none
+var rules = map[string]Rule{
+	goex.EXCHANGE_BINANCE:  {MaxLength: 36, Symbols: ".:/_-"},
+	goex.EXCHANGE_HUOBI:    {MaxLength: 64, Symbols: "_-"},
+	goex.EXCHANGE_OKEX:     {MaxLength: 32, LetterFirst: true},
+	goex.EXCHANGE_GATE:     {MaxLength: 30, Prefix: "t-", Symbols: "_-."},
+	goex.EXCHANGE_HITBTC:   {MaxLength: 32, Symbols: "_-"},
+	goex.EXCHANGE_MCX:      {MaxLength: 32, Symbols: "_-"},
+	goex.EXCHANGE_POLONIEX: {Numeric: true},
+	goex.EXCHANGE_PAPER:    {MaxLength: 36, Symbols: "_-"},
+}
+
+// HuobiContractRule huobi futures and swaps take integer client order ids
+var HuobiContractRule = Rule{Numeric: true}
+
+// GetRule client order id rule of exchange, false when exchange takes no
+// client order ids
+func GetRule(exchange string) (Rule, bool) {
+	rule, ok := rules[exchange]
+	return rule, ok
+}
+
+// Validate check id follows rule
+func (rule Rule) Validate(id string) error {
+	if id == "" {
+		return errors.New("client order id is empty")
+	}
+	if rule.Numeric {
+		if len(id) > 18 || strings.TrimLeft(id, "0123456789") != "" || id[0] == '0' {
+			return fmt.Errorf("client order id %q is not a positive integer below 10^18", id)
+		}
+		return nil
+	}
+	if rule.MaxLength > 0 && len(id) > rule.MaxLength {
+		return fmt.Errorf("client order id %q is longer than %d", id, rule.MaxLength)
+	}
+	if !strings.HasPrefix(id, rule.Prefix) {
+		return fmt.Errorf("client order id %q does not start with %s", id, rule.Prefix)
+	}
+	body := id[len(rule.Prefix):]
+	if body == "" {
+		return fmt.Errorf("client order id %q has nothing after prefix", id)
+	}
+	if rule.LetterFirst && !isLetter(body[0]) {
+		return fmt.Errorf("client order id %q does not start with a letter", id)
+	}
+	for i := 0; i < len(body); i++ {
+		char := body[i]
+		if !isLetter(char) && !isDigit(char) && strings.IndexByte(rule.Symbols, char) < 0 {
+			return fmt.Errorf("client order id %q has invalid char %q", id, char)
+		}
+	}
+	return nil
+}
+
+func isLetter(char byte) bool {
+	return char >= 'a' && char <= 'z' || char >= 'A' && char <= 'Z'
+}
+
+func isDigit(char byte) bool {
+	return char >= '0' && char <= '9'
+}
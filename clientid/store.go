@@ -0,0 +1,35 @@
+package clientid
+
+import (
+	"io/ioutil"
+	"os"
+)
+
+// Store persists records of the registry across restarts
+type Store interface {
+	// saved data, nil when nothing is saved yet
+	Load() ([]byte, error)
+	Save(data []byte) error
+}
+
+// FileStore store of a json file path, saved through a temp file and rename
+// so that a crash never leaves a partial file
+type FileStore string
+
+// Load read file, nil when it does not exist
+func (file FileStore) Load() ([]byte, error) {
+	data, err := ioutil.ReadFile(string(file))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	return data, err
+}
+
+// Save replace file with data
+func (file FileStore) Save(data []byte) error {
+	temp := string(file) + ".tmp"
+	if err := ioutil.WriteFile(temp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(temp, string(file))
+}
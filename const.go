@@ -39,6 +39,20 @@ const (
 const (
 	LIMIT  string = "limit"
 	MARKET string = "market"
+	// 止损, 买单在价格涨到 StopPrice 时触发, 卖单在价格跌到 StopPrice 时触发
+	STOP_LIMIT  string = "stop_limit"
+	STOP_MARKET string = "stop_market"
+	// 止盈, 买单在价格跌到 StopPrice 时触发, 卖单在价格涨到 StopPrice 时触发
+	TAKE_PROFIT_LIMIT  string = "take_profit_limit"
+	TAKE_PROFIT_MARKET string = "take_profit_market"
+	// 跟踪止损, 价格从极值回调 CallbackRate 时以市价成交, StopPrice 为可选的激活价
+	TRAILING_STOP string = "trailing_stop"
+)
+
+// 合约开平方向, 为空时由交易所默认处理
+const (
+	OFFSET_OPEN  string = "open"
+	OFFSET_CLOSE string = "close"
 )
 
 // k线周期
@@ -78,4 +92,5 @@ const (
 	EXCHANGE_POLONIEX = "poloniex"
 	EXCHANGE_BIKI     = "biki"
 	EXCHANGE_HITBTC   = "hitbtc"
+	EXCHANGE_PAPER    = "paper"
 )
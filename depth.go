@@ -0,0 +1,141 @@
+package goexchange
+
+import (
+	"errors"
+	"sort"
+)
+
+// DepthRecord depth price level
+type DepthRecord struct {
+	Price  float64
+	Amount float64
+}
+
+// Depth typed order book, bids price descending and asks price ascending
+type Depth struct {
+	Exchange string
+	Symbol   Symbol
+	Time     int64
+	Bids     []DepthRecord
+	Asks     []DepthRecord
+}
+
+// BestBid highest bid level, zero record when empty
+func (depth *Depth) BestBid() DepthRecord {
+	if len(depth.Bids) == 0 {
+		return DepthRecord{}
+	}
+	return depth.Bids[0]
+}
+
+// BestAsk lowest ask level, zero record when empty
+func (depth *Depth) BestAsk() DepthRecord {
+	if len(depth.Asks) == 0 {
+		return DepthRecord{}
+	}
+	return depth.Asks[0]
+}
+
+// MidPrice middle of best bid and best ask, 0 when a side is empty
+func (depth *Depth) MidPrice() float64 {
+	if len(depth.Bids) == 0 || len(depth.Asks) == 0 {
+		return 0
+	}
+	return (depth.Bids[0].Price + depth.Asks[0].Price) / 2
+}
+
+// Sort order bids price descending and asks price ascending
+func (depth *Depth) Sort() {
+	sort.SliceStable(depth.Bids, func(i, j int) bool { return depth.Bids[i].Price > depth.Bids[j].Price })
+	sort.SliceStable(depth.Asks, func(i, j int) bool { return depth.Asks[i].Price < depth.Asks[j].Price })
+}
+
+// ParseDepth parse GetDepth response of any adapter into typed depth, it looks
+// for the bids/asks (or bid/ask) object in response data, levels can be
+// [price, amount, ...] arrays or {price, quantity|size|amount} objects
+func ParseDepth(result interface{}) (*Depth, error) {
+	data := result
+	if retData, ok := result.(map[string]interface{}); ok {
+		if _, isResponse := retData["code"]; isResponse {
+			var err error
+			data, err = ParseResponse(result)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	book := findDepthBook(data, 0)
+	if book == nil {
+		return nil, errors.New("depth data has no bids and asks")
+	}
+
+	depth := &Depth{}
+	depth.Bids = parseDepthRecords(book, "bids", "bid")
+	depth.Asks = parseDepthRecords(book, "asks", "ask")
+	for _, key := range []string{"ts", "timestamp", "time"} {
+		if ts, ok := book[key]; ok {
+			depth.Time = int64(ToFloat(ts))
+			break
+		}
+	}
+	depth.Sort()
+	return depth, nil
+}
+
+// findDepthBook find object which has bids/asks keys, nested in data/tick at most 3 levels
+func findDepthBook(data interface{}, level int) map[string]interface{} {
+	object, ok := data.(map[string]interface{})
+	if !ok || level > 3 {
+		return nil
+	}
+	for _, key := range []string{"bids", "asks", "bid", "ask"} {
+		if _, ok := object[key]; ok {
+			return object
+		}
+	}
+	for _, key := range []string{"data", "tick", "result"} {
+		if book := findDepthBook(object[key], level+1); book != nil {
+			return book
+		}
+	}
+	return nil
+}
+
+// parseDepthRecords parse price levels of one side
+func parseDepthRecords(book map[string]interface{}, keys ...string) []DepthRecord {
+	var levels []interface{}
+	for _, key := range keys {
+		if list, ok := book[key].([]interface{}); ok {
+			levels = list
+			break
+		}
+	}
+
+	records := make([]DepthRecord, 0, len(levels))
+	for _, level := range levels {
+		var record DepthRecord
+		switch item := level.(type) {
+		case []interface{}:
+			if len(item) < 2 {
+				continue
+			}
+			record.Price = ToFloat(item[0])
+			record.Amount = ToFloat(item[1])
+		case map[string]interface{}:
+			record.Price = ToFloat(item["price"])
+			for _, key := range []string{"quantity", "size", "amount", "volume", "qty"} {
+				if amount, ok := item[key]; ok {
+					record.Amount = ToFloat(amount)
+					break
+				}
+			}
+		default:
+			continue
+		}
+		if record.Price > 0 && record.Amount > 0 {
+			records = append(records, record)
+		}
+	}
+	return records
+}
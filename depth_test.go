@@ -0,0 +1,30 @@
+package goexchange
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseDepth(t *testing.T) {
+	bodies := []string{
+		`{"lastUpdateId":1,"bids":[["99","1"],["100","2"]],"asks":[["101","3"]]}`,
+		`{"tick":{"bids":[[100,2],[99,1]],"asks":[[101,3]],"ts":1}}`,
+		`{"data":{"bids":[{"price":"100","quantity":"2"}],"asks":[{"price":"101","quantity":"3"}]}}`,
+		`{"bid":[{"price":"100","size":"2"}],"ask":[{"price":"101","size":"3"}]}`,
+	}
+	for _, body := range bodies {
+		var data interface{}
+		json.Unmarshal([]byte(body), &data)
+		depth, err := ParseDepth(map[string]interface{}{"code": 0, "data": data})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if depth.BestBid().Price != 100 || depth.BestAsk().Amount != 3 || depth.MidPrice() != 100.5 {
+			t.Fatalf("unexpected depth of %s: %v", body, depth)
+		}
+	}
+
+	if _, err := ParseDepth(ReturnAPIError(ExchangeError)); err == nil {
+		t.Fatal("expect error of failed response")
+	}
+}
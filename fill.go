@@ -0,0 +1,179 @@
+package goexchange
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"strings"
+)
+
+// Fill typed user fill, Fee is what was paid in lower case FeeCoin, fees
+// deducted in exchange coins like bnb, ht or gt keep that coin
+type Fill struct {
+	Id            string
+	OrderId       string
+	ClientOrderId string
+	Symbol        string
+	Side          TradeSide
+	Price         float64
+	Amount        float64
+	Fee           float64
+	FeeCoin       string
+	Maker         bool
+	Time          int64
+}
+
+// CommissionRate maker and taker fee rates, eg: 0.001, negative rates are rebates
+type CommissionRate struct {
+	Maker float64
+	Taker float64
+}
+
+// Fee expected fee in quote coin of trading amount at price
+func (rate *CommissionRate) Fee(price, amount float64, maker bool) float64 {
+	if maker {
+		return price * amount * rate.Maker
+	}
+	return price * amount * rate.Taker
+}
+
+// ParseFills parse GetUserTradeOrders response into typed fills sorted by time
+func ParseFills(result interface{}) ([]Fill, error) {
+	data := result
+	if retData, ok := result.(map[string]interface{}); ok {
+		if _, isResponse := retData["code"]; isResponse {
+			var err error
+			data, err = ParseResponse(result)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	for level := 0; level < 3; level++ {
+		object, ok := data.(map[string]interface{})
+		if !ok {
+			break
+		}
+		nested, ok := firstValue(object, "data", "trades", "fills", "list", "result")
+		if !ok {
+			break
+		}
+		data = nested
+	}
+	if data == nil {
+		return []Fill{}, nil
+	}
+	list, ok := data.([]interface{})
+	if !ok {
+		return nil, errors.New("fill data is not a list")
+	}
+	fills := make([]Fill, 0, len(list))
+	for _, item := range list {
+		if object, ok := item.(map[string]interface{}); ok {
+			fills = append(fills, parseFillObject(object))
+		}
+	}
+	sort.SliceStable(fills, func(i, j int) bool { return fills[i].Time < fills[j].Time })
+	return fills, nil
+}
+
+// parseFillObject parse one fill object
+func parseFillObject(object map[string]interface{}) Fill {
+	fill := Fill{}
+	if value, ok := firstValue(object, "tradeId", "trade_id", "trade-id", "id"); ok {
+		fill.Id = ToString(value)
+	}
+	if value, ok := firstValue(object, "orderId", "order_id", "order-id"); ok {
+		fill.OrderId = ToString(value)
+	}
+	if value, ok := firstValue(object, "clientOrderId", "client_oid", "client-order-id", "text"); ok {
+		fill.ClientOrderId = ToString(value)
+	}
+	if value, ok := firstValue(object, "symbol", "instrument_id", "currency_pair", "contract_code"); ok {
+		fill.Symbol = ToString(value)
+	}
+	if value, ok := firstValue(object, "price", "trade_price"); ok {
+		fill.Price = ToFloat(value)
+	}
+	if value, ok := firstValue(object, "qty", "filled-amount", "size", "amount", "quantity", "trade_volume"); ok {
+		fill.Amount = ToFloat(value)
+	}
+	if value, ok := firstValue(object, "time", "created-at", "create_time_ms", "create_time", "timestamp", "created_at"); ok {
+		fill.Time = ParseTimestamp(value)
+	}
+
+	// fees are negative on okex, rebates are not told apart
+	if value, ok := firstValue(object, "commission", "filled-fees", "fee", "trade_fee"); ok {
+		fill.Fee = math.Abs(ToFloat(value))
+	}
+	if value, ok := firstValue(object, "commissionAsset", "fee-currency", "fee_currency", "fee_asset", "currency"); ok {
+		fill.FeeCoin = strings.ToLower(ToString(value))
+	}
+	if fill.Fee == 0 {
+		// huobi fees deducted in ht or points and gate fees in gt
+		if points := ToFloat(object["filled-points"]); points > 0 {
+			fill.Fee = points
+			fill.FeeCoin = strings.ToLower(ToString(object["fee-deduct-currency"]))
+		} else if gt := ToFloat(object["gt_fee"]); gt > 0 {
+			fill.Fee = gt
+			fill.FeeCoin = "gt"
+		}
+	}
+
+	if maker, ok := object["isMaker"].(bool); ok {
+		fill.Maker = maker
+	} else if value, ok := firstValue(object, "role", "exec_type", "liquidity"); ok {
+		role := strings.ToLower(ToString(value))
+		fill.Maker = role == "maker" || role == "m"
+	}
+	if buyer, ok := object["isBuyer"].(bool); ok {
+		fill.Side = SELL
+		if buyer {
+			fill.Side = BUY
+		}
+	} else if value, ok := firstValue(object, "side", "direction", "type"); ok {
+		side := strings.ToLower(ToString(value))
+		if strings.HasPrefix(side, "buy") {
+			fill.Side = BUY
+		} else if strings.HasPrefix(side, "sell") {
+			fill.Side = SELL
+		}
+	}
+	return fill
+}
+
+// ParseCommissionRate parse GetUserCommissionRate response into maker and
+// taker rates, the first symbol of a list is taken
+func ParseCommissionRate(result interface{}) (*CommissionRate, error) {
+	data := result
+	if retData, ok := result.(map[string]interface{}); ok {
+		if _, isResponse := retData["code"]; isResponse {
+			var err error
+			data, err = ParseResponse(result)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	var object map[string]interface{}
+	for level := 0; level < 3; level++ {
+		if list, ok := data.([]interface{}); ok && len(list) > 0 {
+			data = list[0]
+		}
+		var ok bool
+		if object, ok = data.(map[string]interface{}); !ok {
+			return nil, errors.New("commission rate data is not an object")
+		}
+		nested, ok := firstValue(object, "tradeFee", "data", "result")
+		if !ok {
+			break
+		}
+		data = nested
+	}
+	maker, makerOk := firstValue(object, "actualMakerRate", "makerCommissionRate", "makerFeeRate", "maker", "provideLiquidityRate", "open_maker_fee")
+	taker, takerOk := firstValue(object, "actualTakerRate", "takerCommissionRate", "takerFeeRate", "taker", "takeLiquidityRate", "open_taker_fee")
+	if !makerOk && !takerOk {
+		return nil, errors.New("commission rate data has no maker or taker rate")
+	}
+	return &CommissionRate{Maker: ToFloat(maker), Taker: ToFloat(taker)}, nil
+}
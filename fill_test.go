@@ -0,0 +1,55 @@
+package goexchange
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseFills(t *testing.T) {
+	bodies := []string{
+		`[{"symbol":"BNBBTC","id":28,"orderId":100,"price":"100","qty":"2","commission":"0.01","commissionAsset":"BNB","time":1600000000000,"isBuyer":true,"isMaker":true}]`,
+		`[{"symbol":"btcusdt","id":5,"trade-id":28,"order-id":100,"type":"buy-limit","price":"100","filled-amount":"2","filled-fees":"0","fee-currency":"btc","filled-points":"0.01","fee-deduct-currency":"ht","role":"maker","created-at":1600000000000}]`,
+		`{"data":[{"trade_id":"28","order_id":"100","instrument_id":"BTC-USDT","price":"100","size":"2","fee":"-0.01","currency":"BNB","side":"buy","exec_type":"M","timestamp":"2020-09-13T12:26:40.000Z"}]}`,
+		`[{"id":"28","order_id":"100","currency_pair":"BTC_USDT","side":"buy","role":"maker","amount":"2","price":"100","fee":"0","fee_currency":"USDT","gt_fee":"0.01","create_time_ms":"1600000000000.000"}]`,
+	}
+	feeCoins := []string{"bnb", "ht", "bnb", "gt"}
+	for i, body := range bodies {
+		var data interface{}
+		json.Unmarshal([]byte(body), &data)
+		fills, err := ParseFills(map[string]interface{}{"code": 0, "data": data})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(fills) != 1 {
+			t.Fatalf("unexpected fills of %s: %+v", body, fills)
+		}
+		fill := fills[0]
+		if fill.Id != "28" || fill.OrderId != "100" || fill.Side != BUY || fill.Price != 100 || fill.Amount != 2 ||
+			fill.Fee != 0.01 || fill.FeeCoin != feeCoins[i] || !fill.Maker || fill.Time != 1600000000000 {
+			t.Fatalf("unexpected fill of %s: %+v", body, fill)
+		}
+	}
+}
+
+func TestParseCommissionRate(t *testing.T) {
+	bodies := []string{
+		`{"tradeFee":[{"symbol":"BTCUSDT","maker":0.001,"taker":0.002}],"success":true}`,
+		`[{"symbol":"btcusdt","makerFeeRate":"0.002","takerFeeRate":"0.002","actualMakerRate":"0.001","actualTakerRate":"0.002"}]`,
+		`{"symbol":"BTCUSDT","makerCommissionRate":"0.001","takerCommissionRate":"0.002"}`,
+		`{"takeLiquidityRate":"0.002","provideLiquidityRate":"0.001"}`,
+	}
+	for _, body := range bodies {
+		var data interface{}
+		json.Unmarshal([]byte(body), &data)
+		rate, err := ParseCommissionRate(map[string]interface{}{"code": 0, "data": data})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rate.Maker != 0.001 || rate.Taker != 0.002 {
+			t.Fatalf("unexpected rate of %s: %+v", body, rate)
+		}
+	}
+	if fee := (&CommissionRate{Maker: 0.001, Taker: 0.002}).Fee(100, 2, false); fee != 0.4 {
+		t.Fatalf("unexpected fee %v", fee)
+	}
+}
@@ -0,0 +1,52 @@
+package goexchange
+
+import "time"
+
+// FundingInterval funding period of binance, huobi and okex perpetual swaps
+const FundingInterval = 8 * time.Hour
+
+// FundingInfo current funding of a perpetual contract
+type FundingInfo struct {
+	Symbol string
+	// rate settled at NextFundingTime
+	Rate float64
+	// estimated rate of the period after NextFundingTime, 0 when the exchange has none
+	PredictedRate   float64
+	NextFundingTime int64
+	// 0 when the exchange answers them by other apis
+	MarkPrice  float64
+	IndexPrice float64
+}
+
+// FundingRate settled funding rate
+type FundingRate struct {
+	Symbol string
+	Rate   float64
+	Time   int64
+}
+
+// FundingAPI funding rate api of perpetual swap adapters
+type FundingAPI interface {
+
+	// exchange name
+	GetExchangeName() string
+	// 当前资金费率, data: FundingInfo
+	GetFundingRate(symbol Symbol) interface{}
+	// 历史资金费率, 时间倒序, data: []FundingRate
+	GetFundingRateHistory(symbol Symbol, size int, options map[string]string) interface{}
+}
+
+// FundingSchedule count funding times from nextFundingTime on, FundingInterval apart
+func FundingSchedule(nextFundingTime int64, count int) []int64 {
+	times := make([]int64, 0, count)
+	interval := int64(FundingInterval / time.Millisecond)
+	for i := 0; i < count; i++ {
+		times = append(times, nextFundingTime+int64(i)*interval)
+	}
+	return times
+}
+
+// AnnualizedFundingRate yearly rate of a rate paid every FundingInterval
+func AnnualizedFundingRate(rate float64) float64 {
+	return rate * float64(365*24*time.Hour/FundingInterval)
+}
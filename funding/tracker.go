@@ -0,0 +1,225 @@
+package funding
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	goex "github.com/primitivelab/goexchange"
+	"github.com/primitivelab/goexchange/builder"
+)
+
+// Config tracker config
+type Config struct {
+	Symbols []goex.Symbol
+	// poll interval of Run, default 1 minute
+	Interval time.Duration
+	// min Spread of an opportunity, eg: 0.0005
+	MinSpread float64
+}
+
+// Rate current funding of symbol on one exchange
+type Rate struct {
+	Exchange string
+	Symbol   goex.Symbol
+	Info     goex.FundingInfo
+	Time     int64
+	Err      error
+}
+
+// Comparison funding of symbol on the exchanges paying the lowest and the
+// highest rate, longs pay shorts when the rate is positive, so the carry is
+// long on LongExchange and short on ShortExchange
+type Comparison struct {
+	Symbol        goex.Symbol
+	LongExchange  string
+	ShortExchange string
+	LongRate      float64
+	ShortRate     float64
+	// ShortRate - LongRate per funding period
+	Spread     float64
+	Annualized float64
+	// earliest next funding time of both exchanges
+	NextFundingTime int64
+	Opportunity     bool
+	Time            int64
+}
+
+// Tracker polls funding rates of symbols across exchanges and compares them
+type Tracker struct {
+	config   Config
+	apis     []goex.FundingAPI
+	mutex    sync.Mutex
+	rates    map[string]map[string]*Rate
+	handlers []func(comparison *Comparison)
+}
+
+// New new instance
+func New(config *Config, apis ...goex.FundingAPI) *Tracker {
+	tracker := &Tracker{apis: apis, rates: map[string]map[string]*Rate{}}
+	if config != nil {
+		tracker.config = *config
+	}
+	if tracker.config.Interval == 0 {
+		tracker.config.Interval = time.Minute
+	}
+	return tracker
+}
+
+// NewWithBuilder new instance of exchanges built by apiBuilder
+func NewWithBuilder(apiBuilder *builder.APIBuilder, config *Config, exchanges ...string) (*Tracker, error) {
+	apis := make([]goex.FundingAPI, 0, len(exchanges))
+	for _, exchange := range exchanges {
+		api := apiBuilder.BuildFunding(exchange)
+		if api == nil {
+			return nil, errors.New("exchange has no funding api: " + exchange)
+		}
+		apis = append(apis, api)
+	}
+	return New(config, apis...), nil
+}
+
+// OnComparison add handler called with every comparison of Poll
+func (tracker *Tracker) OnComparison(handler func(comparison *Comparison)) {
+	tracker.mutex.Lock()
+	defer tracker.mutex.Unlock()
+	tracker.handlers = append(tracker.handlers, handler)
+}
+
+// Rates latest rates of symbol by exchange name
+func (tracker *Tracker) Rates(symbol goex.Symbol) map[string]Rate {
+	tracker.mutex.Lock()
+	defer tracker.mutex.Unlock()
+	rates := map[string]Rate{}
+	for exchange, rate := range tracker.rates[symbolKey(symbol)] {
+		rates[exchange] = *rate
+	}
+	return rates
+}
+
+// History settled funding rates of symbol by exchange name, newest first,
+// exchanges answering an error are left out
+func (tracker *Tracker) History(symbol goex.Symbol, size int) map[string][]goex.FundingRate {
+	history := map[string][]goex.FundingRate{}
+	for _, api := range tracker.apis {
+		data, err := goex.ParseResponse(api.GetFundingRateHistory(symbol, size, nil))
+		if rates, ok := data.([]goex.FundingRate); err == nil && ok {
+			history[api.GetExchangeName()] = rates
+		}
+	}
+	return history
+}
+
+// Run poll every interval until stop is closed
+func (tracker *Tracker) Run(stop <-chan struct{}) {
+	ticker := time.NewTicker(tracker.config.Interval)
+	defer ticker.Stop()
+	for {
+		tracker.Poll()
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Poll fetch rates of all symbols and exchanges concurrently once, then emit
+// a comparison for every symbol quoted by at least two exchanges
+func (tracker *Tracker) Poll() []*Comparison {
+	rates := make([]*Rate, 0, len(tracker.config.Symbols)*len(tracker.apis))
+	var wait sync.WaitGroup
+	for _, symbol := range tracker.config.Symbols {
+		for _, api := range tracker.apis {
+			rate := &Rate{Exchange: api.GetExchangeName(), Symbol: symbol}
+			rates = append(rates, rate)
+			wait.Add(1)
+			go func(api goex.FundingAPI, rate *Rate) {
+				defer wait.Done()
+				fetch(api, rate)
+			}(api, rate)
+		}
+	}
+	wait.Wait()
+
+	tracker.mutex.Lock()
+	for _, rate := range rates {
+		key := symbolKey(rate.Symbol)
+		if tracker.rates[key] == nil {
+			tracker.rates[key] = map[string]*Rate{}
+		}
+		tracker.rates[key][rate.Exchange] = rate
+	}
+	handlers := append([]func(comparison *Comparison){}, tracker.handlers...)
+	tracker.mutex.Unlock()
+
+	comparisons := []*Comparison{}
+	for _, symbol := range tracker.config.Symbols {
+		if comparison := tracker.compare(symbol, rates); comparison != nil {
+			comparisons = append(comparisons, comparison)
+		}
+	}
+	for _, comparison := range comparisons {
+		for _, handler := range handlers {
+			handler(comparison)
+		}
+	}
+	return comparisons
+}
+
+// fetch current funding of rate from api
+func fetch(api goex.FundingAPI, rate *Rate) {
+	rate.Time = goex.GetNowMillisecond()
+	data, err := goex.ParseResponse(api.GetFundingRate(rate.Symbol))
+	if err != nil {
+		rate.Err = err
+		return
+	}
+	info, ok := data.(goex.FundingInfo)
+	if !ok {
+		rate.Err = errors.New("unexpected funding rate data")
+		return
+	}
+	rate.Info = info
+}
+
+// compare lowest and highest rate of symbol, the spread is 0 when all rates
+// are equal and it is nil when less than two exchanges answered
+func (tracker *Tracker) compare(symbol goex.Symbol, rates []*Rate) *Comparison {
+	var low, high *Rate
+	for _, rate := range rates {
+		if rate.Err != nil || symbolKey(rate.Symbol) != symbolKey(symbol) {
+			continue
+		}
+		if low == nil || rate.Info.Rate < low.Info.Rate {
+			low = rate
+		}
+		// ties go to the last rate so that equal rates are two exchanges
+		if high == nil || rate.Info.Rate >= high.Info.Rate {
+			high = rate
+		}
+	}
+	if low == nil || low == high {
+		return nil
+	}
+	comparison := &Comparison{
+		Symbol:          symbol,
+		LongExchange:    low.Exchange,
+		ShortExchange:   high.Exchange,
+		LongRate:        low.Info.Rate,
+		ShortRate:       high.Info.Rate,
+		Spread:          high.Info.Rate - low.Info.Rate,
+		NextFundingTime: low.Info.NextFundingTime,
+		Time:            goex.GetNowMillisecond(),
+	}
+	if high.Info.NextFundingTime != 0 && (comparison.NextFundingTime == 0 || high.Info.NextFundingTime < comparison.NextFundingTime) {
+		comparison.NextFundingTime = high.Info.NextFundingTime
+	}
+	comparison.Annualized = goex.AnnualizedFundingRate(comparison.Spread)
+	comparison.Opportunity = comparison.Spread > 0 && comparison.Spread >= tracker.config.MinSpread
+	return comparison
+}
+
+func symbolKey(symbol goex.Symbol) string {
+	return symbol.ToLower().String()
+}
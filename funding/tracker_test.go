@@ -0,0 +1,81 @@
+package funding
+
+import (
+	"errors"
+	"testing"
+
+	goex "github.com/primitivelab/goexchange"
+)
+
+var btcUsdt = goex.NewSymbol("btc", "usdt")
+
+type venue struct {
+	exchange string
+	info     goex.FundingInfo
+	err      error
+}
+
+func (api *venue) GetExchangeName() string {
+	return api.exchange
+}
+
+func (api *venue) GetFundingRate(symbol goex.Symbol) interface{} {
+	if api.err != nil {
+		return goex.ReturnAPIError(goex.ExchangeError)
+	}
+	return goex.ReturnAPIData(api.info)
+}
+
+func (api *venue) GetFundingRateHistory(symbol goex.Symbol, size int, options map[string]string) interface{} {
+	return goex.ReturnAPIData([]goex.FundingRate{{Symbol: symbol.String(), Rate: api.info.Rate}})
+}
+
+func TestTracker_Poll(t *testing.T) {
+	tracker := New(&Config{Symbols: []goex.Symbol{btcUsdt}, MinSpread: 0.0002},
+		&venue{exchange: goex.EXCHANGE_BINANCE, info: goex.FundingInfo{Rate: 0.0001, NextFundingTime: 2000}},
+		&venue{exchange: goex.EXCHANGE_HUOBI, info: goex.FundingInfo{Rate: 0.0004, NextFundingTime: 1000}},
+		&venue{exchange: goex.EXCHANGE_OKEX, info: goex.FundingInfo{Rate: -0.0001}, err: errors.New("down")},
+	)
+	handled := 0
+	tracker.OnComparison(func(comparison *Comparison) { handled++ })
+
+	comparisons := tracker.Poll()
+	if len(comparisons) != 1 || handled != 1 {
+		t.Fatalf("expect one comparison: %d %d", len(comparisons), handled)
+	}
+	comparison := comparisons[0]
+	if comparison.LongExchange != goex.EXCHANGE_BINANCE || comparison.ShortExchange != goex.EXCHANGE_HUOBI ||
+		comparison.Spread < 0.000299 || comparison.Spread > 0.000301 || comparison.NextFundingTime != 1000 || !comparison.Opportunity {
+		t.Fatalf("unexpected comparison %+v", comparison)
+	}
+	rates := tracker.Rates(btcUsdt)
+	if len(rates) != 3 || rates[goex.EXCHANGE_OKEX].Err == nil {
+		t.Fatalf("unexpected rates %+v", rates)
+	}
+	if history := tracker.History(btcUsdt, 10); len(history) != 3 {
+		t.Fatalf("unexpected history %+v", history)
+	}
+}
+
+func TestTracker_SingleExchange(t *testing.T) {
+	tracker := New(&Config{Symbols: []goex.Symbol{btcUsdt}},
+		&venue{exchange: goex.EXCHANGE_BINANCE, info: goex.FundingInfo{Rate: 0.0001}},
+	)
+	if comparisons := tracker.Poll(); len(comparisons) != 0 {
+		t.Fatalf("one exchange has nothing to compare: %+v", comparisons)
+	}
+}
+
+func TestTracker_EqualRates(t *testing.T) {
+	tracker := New(&Config{Symbols: []goex.Symbol{btcUsdt}},
+		&venue{exchange: goex.EXCHANGE_BINANCE, info: goex.FundingInfo{Rate: 0.0001}},
+		&venue{exchange: goex.EXCHANGE_HUOBI, info: goex.FundingInfo{Rate: 0.0001}},
+	)
+	comparisons := tracker.Poll()
+	if len(comparisons) != 1 {
+		t.Fatalf("equal rates should be compared: %+v", comparisons)
+	}
+	if comparison := comparisons[0]; comparison.LongExchange == comparison.ShortExchange || comparison.Spread != 0 || comparison.Opportunity {
+		t.Fatalf("unexpected comparison %+v", comparison)
+	}
+}
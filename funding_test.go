@@ -0,0 +1,13 @@
+package goexchange
+
+import "testing"
+
+func TestFundingSchedule(t *testing.T) {
+	times := FundingSchedule(1620000000000, 3)
+	if len(times) != 3 || times[0] != 1620000000000 || times[2] != 1620000000000+16*3600*1000 {
+		t.Fatalf("unexpected schedule %v", times)
+	}
+	if rate := AnnualizedFundingRate(0.0001); rate < 0.10949 || rate > 0.10951 {
+		t.Fatalf("unexpected annualized rate %v", rate)
+	}
+}
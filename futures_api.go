@@ -0,0 +1,138 @@
+package goexchange
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 交割合约类型
+const (
+	CONTRACT_THIS_WEEK    = "this_week"
+	CONTRACT_NEXT_WEEK    = "next_week"
+	CONTRACT_QUARTER      = "quarter"
+	CONTRACT_NEXT_QUARTER = "next_quarter"
+)
+
+// FuturesContract delivery contract, Code is the exchange contract id like
+// BTCUSD_210625, BTC210625 or BTC-USD-210625
+type FuturesContract struct {
+	Symbol       Symbol
+	ContractType string
+	Code         string
+	// face value of one contract
+	ContractSize float64
+	ListTime     int64
+	DeliveryTime int64
+}
+
+// DeliveryPrice settled delivery price of contract
+type DeliveryPrice struct {
+	Code  string
+	Price float64
+	Time  int64
+}
+
+// FuturesAPI delivery contract api interface, contracts are addressed by
+// symbol and contract type, which resolve to the current contract code
+type FuturesAPI interface {
+
+	// exchange name
+	GetExchangeName() string
+	// 合约列表, data: []FuturesContract
+	GetContractList() interface{}
+	// 当前合约, data: FuturesContract
+	GetContract(symbol Symbol, contractType string) interface{}
+	// 深度
+	GetDepth(symbol Symbol, contractType string, size int, options map[string]string) interface{}
+	// ticker
+	GetTicker(symbol Symbol, contractType string) interface{}
+	// k线
+	GetKline(symbol Symbol, contractType string, period, size int, options map[string]string) interface{}
+	// 成交记录
+	GetTrade(symbol Symbol, contractType string, size int, options map[string]string) interface{}
+	// 历史交割价格, data: []DeliveryPrice
+	GetDeliveryPrices(symbol Symbol, size int) interface{}
+
+	// 账户资产
+	GetUserBalance() interface{}
+	// 持仓
+	GetUserPositions(symbol Symbol, contractType string) interface{}
+	// 下单, 合约由 order.Symbol 和 order.ContractType 确定
+	PlaceOrder(order *PlaceOrder) interface{}
+	// 撤单
+	CancelOrder(symbol Symbol, contractType, orderID, clientOrderID string) interface{}
+	// 撤销全部订单
+	BatchCancelAllOrder(symbol Symbol, contractType string) interface{}
+	// 订单详情
+	GetUserOrderInfo(symbol Symbol, contractType, orderID, clientOrderID string) interface{}
+	// 当前委托
+	GetUserOpenTrustOrders(symbol Symbol, contractType string, size int, options map[string]string) interface{}
+	// 历史委托
+	GetUserTrustOrders(symbol Symbol, contractType, status string, size int, options map[string]string) interface{}
+	// 成交明细
+	GetUserTradeOrders(symbol Symbol, contractType string, size int, options map[string]string) interface{}
+	// Get exchange http request
+	HTTPRequest(requestURL, method string, options interface{}, signed bool) interface{}
+}
+
+// FuturesContracts contract list cache of an adapter, it is reloaded after
+// the earliest delivery so contract types roll over to the next contracts
+type FuturesContracts struct {
+	mutex     sync.Mutex
+	contracts []FuturesContract
+	expire    int64
+}
+
+// Resolve contract of symbol and contract type, load is called when the
+// cache is empty or a cached contract has been delivered
+func (cache *FuturesContracts) Resolve(symbol Symbol, contractType string, load func() ([]FuturesContract, error)) (FuturesContract, error) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	now := time.Now().UnixNano() / int64(time.Millisecond)
+	if cache.contracts == nil || now >= cache.expire {
+		contracts, err := load()
+		if err != nil {
+			return FuturesContract{}, err
+		}
+		cache.set(contracts)
+	}
+	if contract, ok := FindFuturesContract(cache.contracts, symbol, contractType); ok {
+		return contract, nil
+	}
+	return FuturesContract{}, errors.New("no " + contractType + " contract of " + symbol.String())
+}
+
+// set replace cached contracts
+func (cache *FuturesContracts) set(contracts []FuturesContract) {
+	cache.contracts = contracts
+	cache.expire = 0
+	for _, contract := range contracts {
+		if contract.DeliveryTime > 0 && (cache.expire == 0 || contract.DeliveryTime < cache.expire) {
+			cache.expire = contract.DeliveryTime
+		}
+	}
+	if cache.expire == 0 {
+		cache.expire = time.Now().Add(time.Hour).UnixNano() / int64(time.Millisecond)
+	}
+}
+
+// FindFuturesContract contract of symbol and contract type in contracts
+func FindFuturesContract(contracts []FuturesContract, symbol Symbol, contractType string) (FuturesContract, bool) {
+	for _, contract := range contracts {
+		if contract.ContractType == contractType &&
+			strings.EqualFold(contract.Symbol.CoinFrom, symbol.CoinFrom) &&
+			strings.EqualFold(contract.Symbol.CoinTo, symbol.CoinTo) {
+			return contract, true
+		}
+	}
+	return FuturesContract{}, false
+}
+
+// ContractNotFound response of contract that can not be resolved
+func ContractNotFound(err error) map[string]interface{} {
+	retData := ReturnAPIError(ContractNotFoundError).(map[string]interface{})
+	retData["error"] = err.Error()
+	return retData
+}
@@ -0,0 +1,64 @@
+package goexchange
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestFindFuturesContract(t *testing.T) {
+	contracts := []FuturesContract{
+		{Symbol: NewSymbol("BTC", "USD"), ContractType: CONTRACT_THIS_WEEK, Code: "BTC210618"},
+		{Symbol: NewSymbol("BTC", "USD"), ContractType: CONTRACT_QUARTER, Code: "BTC210625"},
+	}
+	contract, ok := FindFuturesContract(contracts, NewSymbol("btc", "usd"), CONTRACT_QUARTER)
+	if !ok || contract.Code != "BTC210625" {
+		t.Fatalf("unexpected contract %+v", contract)
+	}
+	if _, ok := FindFuturesContract(contracts, NewSymbol("btc", "usd"), CONTRACT_NEXT_QUARTER); ok {
+		t.Fatal("next quarter should not be found")
+	}
+}
+
+func TestFuturesContracts_Resolve(t *testing.T) {
+	now := time.Now().UnixNano() / int64(time.Millisecond)
+	loads := 0
+	delivery := now + 3600000
+	load := func() ([]FuturesContract, error) {
+		loads++
+		return []FuturesContract{
+			{Symbol: NewSymbol("BTC", "USD"), ContractType: CONTRACT_QUARTER, Code: "BTC210625", DeliveryTime: delivery},
+		}, nil
+	}
+
+	cache := &FuturesContracts{}
+	for i := 0; i < 2; i++ {
+		contract, err := cache.Resolve(NewSymbol("btc", "usd"), CONTRACT_QUARTER, load)
+		if err != nil || contract.Code != "BTC210625" {
+			t.Fatalf("unexpected contract %+v %v", contract, err)
+		}
+	}
+	if loads != 1 {
+		t.Fatalf("contracts should be loaded once, got %d", loads)
+	}
+
+	if _, err := cache.Resolve(NewSymbol("eth", "usd"), CONTRACT_QUARTER, load); err == nil {
+		t.Fatal("eth contract should not be found")
+	}
+
+	// delivered contracts are reloaded
+	delivery = now - 1
+	cache = &FuturesContracts{}
+	cache.Resolve(NewSymbol("btc", "usd"), CONTRACT_QUARTER, load)
+	cache.Resolve(NewSymbol("btc", "usd"), CONTRACT_QUARTER, load)
+	if loads != 3 {
+		t.Fatalf("delivered contracts should be reloaded, got %d loads", loads)
+	}
+
+	_, err := (&FuturesContracts{}).Resolve(NewSymbol("btc", "usd"), CONTRACT_QUARTER, func() ([]FuturesContract, error) {
+		return nil, errors.New("network")
+	})
+	if err == nil || err.Error() != "network" {
+		t.Fatalf("load error should be returned, got %v", err)
+	}
+}
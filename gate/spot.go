@@ -162,22 +162,22 @@ func (spot *GateSpot) GetUserBalance() interface{} {
 
 // PlaceOrder place order
 func (spot *GateSpot) PlaceOrder(order *PlaceOrder) interface{} {
+	// orders are placed as limit orders, market modes can not be honored
+	if err := CheckMarketMode(order); err != nil {
+		return InvalidOrder(err)
+	}
+	timeInForce, err := gateTimeInForce(order.TimeInForce)
+	if err != nil {
+		return InvalidOrder(err)
+	}
 	params := &url.Values{}
 	params.Set("currency_pair", spot.getSymbol(order.Symbol))
 	params.Set("amount", order.Amount)
-	params.Set("amount", order.Price)
+	params.Set("price", order.Price)
 	params.Set("type", "limit")
 	params.Set("account", "spot")
 	params.Set("side", order.Side.String())
-
-	switch order.TimeInForce {
-	case IOC:
-		params.Set("time_in_force", "ioc")
-	case GTC:
-		params.Set("time_in_force", "gtc")
-	case POC:
-		params.Set("time_in_force", "poc")
-	}
+	params.Set("time_in_force", timeInForce)
 	if order.ClientOrderId != "" {
 		params.Set("text", order.ClientOrderId)
 	}
@@ -186,6 +186,20 @@ func (spot *GateSpot) PlaceOrder(order *PlaceOrder) interface{} {
 	return result
 }
 
+// gateTimeInForce gate time_in_force of timeInForce, gate has no fok orders
+func gateTimeInForce(timeInForce TimeInForce) (string, error) {
+	switch timeInForce {
+	case GTC:
+		return "gtc", nil
+	case IOC:
+		return "ioc", nil
+	case POC, GTX:
+		return "poc", nil
+	default:
+		return "", &TimeInForceError{TimeInForce: timeInForce}
+	}
+}
+
 // PlaceLimitOrder place limit order
 func (spot *GateSpot) PlaceLimitOrder(symbol Symbol, price string, amount string, side TradeSide, ClientOrderID string) interface{} {
 	params := &url.Values{}
@@ -222,14 +236,11 @@ func (spot *GateSpot) BatchPlaceLimitOrder(orders []LimitOrder) interface{} {
 		} else {
 			param["text"] = "t-" + GetNowMillisecondStr() + strconv.FormatInt(int64(index), 10)
 		}
-		switch item.TimeInForce {
-		case IOC:
-			param["time_in_force"] = "ioc"
-		case GTC:
-			param["time_in_force"] = "gtc"
-		case POC:
-			param["time_in_force"] = "poc"
+		timeInForce, err := gateTimeInForce(item.TimeInForce)
+		if err != nil {
+			return InvalidOrder(err)
 		}
+		param["time_in_force"] = timeInForce
 		params = append(params, param)
 	}
 	return spot.httpPostBatch(spot.getURL("batch_orders"), params, true)
@@ -180,3 +180,27 @@ func TestGateSpot_BatchCancelOrder(t *testing.T) {
 	b, _ := json.Marshal(response)
 	t.Log(string(b))
 }
+
+func TestGateSpot_GetDepositAddresses(t *testing.T) {
+	market := getInstance()
+
+	response := market.GetDepositAddresses("usdt", "")
+	b, _ := json.Marshal(response)
+	t.Log(string(b))
+}
+
+func TestGateSpot_GetDepositHistory(t *testing.T) {
+	market := getInstance()
+
+	response := market.GetDepositHistory("usdt", 10, nil)
+	b, _ := json.Marshal(response)
+	t.Log(string(b))
+}
+
+func TestGateSpot_GetWithdrawHistory(t *testing.T) {
+	market := getInstance()
+
+	response := market.GetWithdrawHistory("usdt", 10, nil)
+	b, _ := json.Marshal(response)
+	t.Log(string(b))
+}
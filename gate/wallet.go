@@ -0,0 +1,143 @@
+package gate
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+
+	. "github.com/primitivelab/goexchange"
+)
+
+// transferStatus gate deposit and withdraw status
+var transferStatus = map[string]string{
+	"DONE":      TRANSFER_STATUS_SUCCESS,
+	"CANCEL":    TRANSFER_STATUS_CANCELED,
+	"FAIL":      TRANSFER_STATUS_FAILED,
+	"INVALID":   TRANSFER_STATUS_FAILED,
+	"REQUEST":   TRANSFER_STATUS_PENDING,
+	"MANUAL":    TRANSFER_STATUS_PENDING,
+	"BCODE":     TRANSFER_STATUS_PENDING,
+	"EXTPEND":   TRANSFER_STATUS_PENDING,
+	"VERIFY":    TRANSFER_STATUS_PENDING,
+	"PROCES":    TRANSFER_STATUS_PENDING,
+	"PEND":      TRANSFER_STATUS_PENDING,
+	"DMOVE":     TRANSFER_STATUS_PENDING,
+	"SPLITPEND": TRANSFER_STATUS_PENDING,
+}
+
+// GetDepositAddresses deposit addresses of coin, all chains when chain is empty
+func (spot *GateSpot) GetDepositAddresses(coin, chain string) interface{} {
+	params := &url.Values{}
+	params.Set("currency", strings.ToUpper(coin))
+	result := spot.httpGet("/api/v4/wallet/deposit_address", params, true)
+	if result["code"] != 0 {
+		return result
+	}
+
+	data, _ := result["data"].(map[string]interface{})
+	list, _ := data["multichain_addresses"].([]interface{})
+	addresses := []DepositAddress{}
+	for _, item := range list {
+		record, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		address := DepositAddress{
+			Coin:    ToString(data["currency"]),
+			Chain:   ToString(record["chain"]),
+			Address: ToString(record["address"]),
+			Tag:     ToString(record["payment_id"]),
+		}
+		if chain == "" || address.Chain == chain {
+			addresses = append(addresses, address)
+		}
+	}
+	result["data"] = addresses
+	return result
+}
+
+// ApplyWithdraw user withdraw, gate charges a fixed fee so request fee is not used
+func (spot *GateSpot) ApplyWithdraw(request *WithdrawRequest) interface{} {
+	params := &url.Values{}
+	// typed fields are set last so that options can not override them
+	for key, value := range request.Options {
+		params.Set(key, value)
+	}
+	params.Set("currency", strings.ToUpper(request.Coin))
+	params.Set("address", request.Address)
+	params.Set("amount", request.Amount)
+	if request.Tag != "" {
+		params.Set("memo", request.Tag)
+	}
+	if request.Chain != "" {
+		params.Set("chain", request.Chain)
+	}
+	if request.ClientId != "" {
+		params.Set("withdraw_order_id", request.ClientId)
+	}
+	result := spot.httpPost("/api/v4/withdrawals", params, true)
+	if result["code"] != 0 {
+		return result
+	}
+	data, _ := result["data"].(map[string]interface{})
+	result["data"] = ToString(data["id"])
+	return result
+}
+
+// GetDepositHistory user deposit record list, data: []Transfer
+func (spot *GateSpot) GetDepositHistory(coin string, size int, options map[string]string) interface{} {
+	return spot.transferHistory("/api/v4/wallet/deposits", coin, size, options, TRANSFER_DEPOSIT)
+}
+
+// GetWithdrawHistory user withdraw record list, data: []Transfer
+func (spot *GateSpot) GetWithdrawHistory(coin string, size int, options map[string]string) interface{} {
+	return spot.transferHistory("/api/v4/wallet/withdrawals", coin, size, options, TRANSFER_WITHDRAW)
+}
+
+func (spot *GateSpot) transferHistory(path, coin string, size int, options map[string]string, transferType string) interface{} {
+	params := &url.Values{}
+	if coin != "" {
+		params.Set("currency", strings.ToUpper(coin))
+	}
+	if size != 0 {
+		params.Set("limit", strconv.Itoa(size))
+	}
+	if from, ok := options["from"]; ok {
+		params.Set("from", from)
+	}
+	if to, ok := options["to"]; ok {
+		params.Set("to", to)
+	}
+	if offset, ok := options["offset"]; ok {
+		params.Set("offset", offset)
+	}
+
+	result := spot.httpGet(path, params, true)
+	if result["code"] != 0 {
+		return result
+	}
+
+	list, _ := result["data"].([]interface{})
+	transfers := make([]Transfer, 0, len(list))
+	for _, item := range list {
+		record, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		transfers = append(transfers, Transfer{
+			Id:      ToString(record["id"]),
+			TxId:    ToString(record["txid"]),
+			Type:    transferType,
+			Coin:    ToString(record["currency"]),
+			Chain:   ToString(record["chain"]),
+			Address: ToString(record["address"]),
+			Tag:     ToString(record["memo"]),
+			Amount:  ToFloat(record["amount"]),
+			Fee:     ToFloat(record["fee"]),
+			Status:  transferStatus[ToString(record["status"])],
+			Time:    ParseTimestamp(record["timestamp"]),
+		})
+	}
+	result["data"] = transfers
+	return result
+}
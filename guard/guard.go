@@ -0,0 +1,313 @@
+package guard
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	goex "github.com/primitivelab/goexchange"
+)
+
+// Address allowed withdraw destination, empty Chain matches any chain while
+// Tag must equal the memo of the request, so an address without Tag only
+// allows withdrawals without memo
+type Address struct {
+	Address string
+	Chain   string
+	Tag     string
+}
+
+// Policy withdraw policy of one coin
+type Policy struct {
+	Addresses []Address
+	// max amount withdrawn per UTC day, 0 for no cap
+	DailyLimit float64
+	// max amount of one withdraw, 0 for no cap
+	MaxAmount float64
+}
+
+// Config guard config, coins without policy can not be withdrawn
+type Config struct {
+	// policy by lower case coin
+	Policies map[string]Policy
+	// check and audit withdrawals without sending them
+	DryRun bool
+	// called after policy checks pass without holding the guard, withdraw is
+	// denied when it returns false
+	Approve func(request *goex.WithdrawRequest, decision *Decision) bool
+	// audit log, one json Record per line, withdrawals are denied when it fails
+	Audit io.Writer
+	// current time, default time.Now
+	Clock func() time.Time
+}
+
+// Decision result of checking a withdraw request
+type Decision struct {
+	Allowed bool
+	Reason  string
+	// amount left of today's cap after the request, -1 when uncapped
+	Remaining float64
+}
+
+// Record audit log line of one withdraw attempt
+type Record struct {
+	Time     int64   `json:"time"`
+	Exchange string  `json:"exchange"`
+	Coin     string  `json:"coin"`
+	Chain    string  `json:"chain"`
+	Address  string  `json:"address"`
+	Tag      string  `json:"tag"`
+	Amount   float64 `json:"amount"`
+	DryRun   bool    `json:"dryRun"`
+	// false when written before sending the withdraw, true for its result
+	Sent       bool   `json:"sent"`
+	Allowed    bool   `json:"allowed"`
+	Reason     string `json:"reason"`
+	WithdrawId string `json:"withdrawId"`
+	Error      string `json:"error"`
+}
+
+// Guard WalletAPI wrapper enforcing withdraw policies, give strategies the
+// guard instead of the adapter so every withdraw goes through it
+type Guard struct {
+	api    goex.WalletAPI
+	name   string
+	config Config
+	mutex  sync.Mutex
+	// withdrawn amount by day and coin
+	used map[string]float64
+}
+
+// New new instance, exchange is written to audit records
+func New(api goex.WalletAPI, exchange string, config *Config) *Guard {
+	guard := &Guard{api: api, name: exchange, used: map[string]float64{}}
+	if config != nil {
+		guard.config = *config
+	}
+	policies := map[string]Policy{}
+	for coin, policy := range guard.config.Policies {
+		policy.Addresses = append([]Address{}, policy.Addresses...)
+		policies[strings.ToLower(coin)] = policy
+	}
+	guard.config.Policies = policies
+	if guard.config.Clock == nil {
+		guard.config.Clock = time.Now
+	}
+	return guard
+}
+
+// LoadAudit restore today's withdrawn amounts from an audit log, so that
+// restarting the process does not reset the daily caps
+func (guard *Guard) LoadAudit(reader io.Reader) error {
+	guard.mutex.Lock()
+	defer guard.mutex.Unlock()
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		var record Record
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return err
+		}
+		if !record.Allowed || record.DryRun {
+			continue
+		}
+		day := time.Unix(0, record.Time*int64(time.Millisecond)).UTC().Format("2006-01-02")
+		if !record.Sent {
+			guard.used[day+"/"+strings.ToLower(record.Coin)] += record.Amount
+		} else if record.Error != "" {
+			guard.used[day+"/"+strings.ToLower(record.Coin)] -= record.Amount
+		}
+	}
+	return scanner.Err()
+}
+
+// Preview check request against policies without withdrawing or auditing it
+func (guard *Guard) Preview(request *goex.WithdrawRequest) *Decision {
+	guard.mutex.Lock()
+	defer guard.mutex.Unlock()
+	return guard.check(request)
+}
+
+// GetDepositAddresses deposit addresses of wrapped api
+func (guard *Guard) GetDepositAddresses(coin, chain string) interface{} {
+	return guard.api.GetDepositAddresses(coin, chain)
+}
+
+// ApplyWithdraw withdraw when policies and approval allow it, data of dry-run is the decision
+func (guard *Guard) ApplyWithdraw(request *goex.WithdrawRequest) interface{} {
+	guard.mutex.Lock()
+	decision := guard.check(request)
+	guard.mutex.Unlock()
+	if decision.Allowed && guard.config.Approve != nil && !guard.config.Approve(request, decision) {
+		decision.Allowed = false
+		decision.Reason = "withdraw is not approved"
+	}
+
+	guard.mutex.Lock()
+	if decision.Allowed {
+		// other withdrawals may have used the cap while waiting for approval
+		decision = guard.check(request)
+	}
+	record := guard.record(request, decision)
+	if !decision.Allowed || guard.config.DryRun {
+		err := guard.audit(record)
+		guard.mutex.Unlock()
+		if !decision.Allowed {
+			return denied(decision.Reason)
+		}
+		if err != nil {
+			return denied("audit log failed: " + err.Error())
+		}
+		return goex.ReturnAPIData(decision)
+	}
+	if err := guard.audit(record); err != nil {
+		guard.mutex.Unlock()
+		return denied("audit log failed: " + err.Error())
+	}
+	key := guard.key(request.Coin)
+	guard.used[key] += record.Amount
+	guard.mutex.Unlock()
+
+	result := guard.api.ApplyWithdraw(request)
+	retData, _ := result.(map[string]interface{})
+	id, err := goex.ParseResponse(result)
+	guard.mutex.Lock()
+	defer guard.mutex.Unlock()
+	if err != nil {
+		// the exchange refused it, so it does not count towards the cap
+		guard.used[key] -= record.Amount
+		record.Error = err.Error()
+	}
+	record.Sent = true
+	record.WithdrawId = goex.ToString(id)
+	guard.audit(record)
+	if retData == nil {
+		return result
+	}
+	return retData
+}
+
+// GetDepositHistory deposit records of wrapped api
+func (guard *Guard) GetDepositHistory(coin string, size int, options map[string]string) interface{} {
+	return guard.api.GetDepositHistory(coin, size, options)
+}
+
+// GetWithdrawHistory withdraw records of wrapped api
+func (guard *Guard) GetWithdrawHistory(coin string, size int, options map[string]string) interface{} {
+	return guard.api.GetWithdrawHistory(coin, size, options)
+}
+
+// check request against allowlist and caps
+func (guard *Guard) check(request *goex.WithdrawRequest) *Decision {
+	decision := &Decision{Remaining: -1}
+	policy, ok := guard.config.Policies[strings.ToLower(request.Coin)]
+	if !ok {
+		decision.Reason = "coin has no withdraw policy"
+		return decision
+	}
+	for key := range request.Options {
+		if protectedOption(key) {
+			decision.Reason = "option " + key + " can not be set on a guarded withdraw"
+			return decision
+		}
+	}
+	amount := goex.ToFloat(request.Amount)
+	if amount <= 0 {
+		decision.Reason = "amount must be positive"
+		return decision
+	}
+	if !allowed(policy.Addresses, request) {
+		decision.Reason = "address is not in allowlist"
+		return decision
+	}
+	if policy.MaxAmount > 0 && amount > policy.MaxAmount {
+		decision.Reason = "amount is over max amount of one withdraw"
+		return decision
+	}
+	if policy.DailyLimit > 0 {
+		decision.Remaining = policy.DailyLimit - guard.used[guard.key(request.Coin)] - amount
+		if decision.Remaining < 0 {
+			decision.Reason = "amount is over daily limit"
+			return decision
+		}
+	}
+	decision.Allowed = true
+	return decision
+}
+
+func (guard *Guard) record(request *goex.WithdrawRequest, decision *Decision) *Record {
+	return &Record{
+		Time:     guard.config.Clock().UnixNano() / int64(time.Millisecond),
+		Exchange: guard.name,
+		Coin:     strings.ToLower(request.Coin),
+		Chain:    request.Chain,
+		Address:  request.Address,
+		Tag:      request.Tag,
+		Amount:   goex.ToFloat(request.Amount),
+		DryRun:   guard.config.DryRun,
+		Allowed:  decision.Allowed,
+		Reason:   decision.Reason,
+	}
+}
+
+// audit write record, fails when no audit log is configured
+func (guard *Guard) audit(record *Record) error {
+	if guard.config.Audit == nil {
+		return errors.New("audit log is not configured")
+	}
+	line, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	_, err = guard.config.Audit.Write(append(line, '\n'))
+	return err
+}
+
+// key daily usage key of coin
+func (guard *Guard) key(coin string) string {
+	return guard.config.Clock().UTC().Format("2006-01-02") + "/" + strings.ToLower(coin)
+}
+
+func allowed(addresses []Address, request *goex.WithdrawRequest) bool {
+	for _, address := range addresses {
+		if address.Address != request.Address {
+			continue
+		}
+		if address.Chain != "" && !strings.EqualFold(address.Chain, request.Chain) {
+			continue
+		}
+		if address.Tag != request.Tag {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+// protectedOption whether option key may change the destination, memo,
+// chain, coin or amount of a withdraw, eg: address, to_address, addr-tag, network
+func protectedOption(key string) bool {
+	key = strings.ToLower(key)
+	for _, word := range protectedOptionWords {
+		if strings.Contains(key, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// protectedOptionWords parts of withdraw option keys of the adapters that
+// carry the typed fields of WithdrawRequest
+var protectedOptionWords = []string{
+	"addr", "destination", "tag", "memo", "payment", "chain", "network",
+	"withdrawas", "amount", "qty", "quantity", "coin", "currency", "asset",
+}
+
+func denied(reason string) map[string]interface{} {
+	retData := goex.ReturnAPIError(goex.WithdrawDeniedError).(map[string]interface{})
+	retData["error"] = reason
+	return retData
+}
@@ -0,0 +1,246 @@
+package guard
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	goex "github.com/primitivelab/goexchange"
+)
+
+type fakeWallet struct {
+	withdraws []*goex.WithdrawRequest
+	fail      bool
+}
+
+func (wallet *fakeWallet) GetDepositAddresses(coin, chain string) interface{} {
+	return goex.ReturnAPIData([]goex.DepositAddress{})
+}
+
+func (wallet *fakeWallet) ApplyWithdraw(request *goex.WithdrawRequest) interface{} {
+	if wallet.fail {
+		return goex.ReturnAPIError(goex.HttpRequestError)
+	}
+	wallet.withdraws = append(wallet.withdraws, request)
+	return goex.ReturnAPIData("w1")
+}
+
+func (wallet *fakeWallet) GetDepositHistory(coin string, size int, options map[string]string) interface{} {
+	return goex.ReturnAPIData([]goex.Transfer{})
+}
+
+func (wallet *fakeWallet) GetWithdrawHistory(coin string, size int, options map[string]string) interface{} {
+	return goex.ReturnAPIData([]goex.Transfer{})
+}
+
+type failWriter struct{}
+
+func (failWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+func testConfig(audit *bytes.Buffer) *Config {
+	return &Config{
+		Policies: map[string]Policy{
+			"USDT": {
+				Addresses:  []Address{{Address: "TAddr", Chain: "TRC20"}},
+				DailyLimit: 100,
+				MaxAmount:  60,
+			},
+		},
+		Audit: audit,
+		Clock: func() time.Time { return time.Date(2021, 5, 1, 12, 0, 0, 0, time.UTC) },
+	}
+}
+
+func request(coin, address, amount string) *goex.WithdrawRequest {
+	return &goex.WithdrawRequest{Coin: coin, Chain: "trc20", Address: address, Amount: amount}
+}
+
+func TestGuard_ApplyWithdraw(t *testing.T) {
+	wallet := &fakeWallet{}
+	audit := &bytes.Buffer{}
+	guard := New(wallet, "binance", testConfig(audit))
+
+	tests := []struct {
+		request *goex.WithdrawRequest
+		allowed bool
+	}{
+		{request("btc", "TAddr", "1"), false},
+		{request("usdt", "Other", "1"), false},
+		{request("usdt", "TAddr", "0"), false},
+		{request("usdt", "TAddr", "70"), false},
+		{request("usdt", "TAddr", "60"), true},
+		{request("usdt", "TAddr", "50"), false},
+		{request("usdt", "TAddr", "40"), true},
+	}
+	for _, test := range tests {
+		id, err := goex.ParseResponse(guard.ApplyWithdraw(test.request))
+		if test.allowed && (err != nil || id != "w1") {
+			t.Errorf("%v should be allowed, got %v %v", test.request, id, err)
+		}
+		if !test.allowed && err == nil {
+			t.Errorf("%v should be denied", test.request)
+		}
+	}
+	if len(wallet.withdraws) != 2 {
+		t.Errorf("expected 2 withdraws, got %d", len(wallet.withdraws))
+	}
+	// 5 denials plus a pre-send and a result record for each withdraw
+	if lines := strings.Count(audit.String(), "\n"); lines != 9 {
+		t.Errorf("expected 9 audit records, got %d", lines)
+	}
+}
+
+func TestGuard_DryRun(t *testing.T) {
+	wallet := &fakeWallet{}
+	audit := &bytes.Buffer{}
+	config := testConfig(audit)
+	config.DryRun = true
+	guard := New(wallet, "binance", config)
+
+	data, err := goex.ParseResponse(guard.ApplyWithdraw(request("usdt", "TAddr", "60")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decision := data.(*Decision); !decision.Allowed || decision.Remaining != 40 {
+		t.Errorf("unexpected decision %+v", decision)
+	}
+	if len(wallet.withdraws) != 0 {
+		t.Error("dry-run should not withdraw")
+	}
+	if !strings.Contains(audit.String(), `"dryRun":true`) {
+		t.Errorf("dry-run should be audited, got %s", audit.String())
+	}
+}
+
+func TestGuard_Approve(t *testing.T) {
+	wallet := &fakeWallet{}
+	config := testConfig(&bytes.Buffer{})
+	config.Approve = func(request *goex.WithdrawRequest, decision *Decision) bool {
+		return goex.ToFloat(request.Amount) < 10
+	}
+	guard := New(wallet, "binance", config)
+
+	if _, err := goex.ParseResponse(guard.ApplyWithdraw(request("usdt", "TAddr", "20"))); err == nil {
+		t.Error("unapproved withdraw should be denied")
+	}
+	if _, err := goex.ParseResponse(guard.ApplyWithdraw(request("usdt", "TAddr", "5"))); err != nil {
+		t.Error(err)
+	}
+	if len(wallet.withdraws) != 1 {
+		t.Errorf("expected 1 withdraw, got %d", len(wallet.withdraws))
+	}
+}
+
+func TestGuard_AuditFailure(t *testing.T) {
+	wallet := &fakeWallet{}
+	config := testConfig(nil)
+	config.Audit = failWriter{}
+	guard := New(wallet, "binance", config)
+
+	if _, err := goex.ParseResponse(guard.ApplyWithdraw(request("usdt", "TAddr", "5"))); err == nil {
+		t.Error("withdraw should be denied when audit fails")
+	}
+	config.Audit = nil
+	guard = New(wallet, "binance", config)
+	if _, err := goex.ParseResponse(guard.ApplyWithdraw(request("usdt", "TAddr", "5"))); err == nil {
+		t.Error("withdraw should be denied without audit log")
+	}
+	if len(wallet.withdraws) != 0 {
+		t.Errorf("expected no withdraw, got %d", len(wallet.withdraws))
+	}
+}
+
+func TestGuard_LoadAudit(t *testing.T) {
+	wallet := &fakeWallet{}
+	audit := &bytes.Buffer{}
+	guard := New(wallet, "binance", testConfig(audit))
+	guard.ApplyWithdraw(request("usdt", "TAddr", "60"))
+	wallet.fail = true
+	guard.ApplyWithdraw(request("usdt", "TAddr", "30"))
+
+	restarted := New(&fakeWallet{}, "binance", testConfig(&bytes.Buffer{}))
+	if err := restarted.LoadAudit(bytes.NewReader(audit.Bytes())); err != nil {
+		t.Fatal(err)
+	}
+	// the failed withdraw is released, so only 60 is used
+	if decision := restarted.Preview(request("usdt", "TAddr", "40")); !decision.Allowed || decision.Remaining != 0 {
+		t.Errorf("unexpected decision %+v", decision)
+	}
+	if decision := restarted.Preview(request("usdt", "TAddr", "41")); decision.Allowed {
+		t.Errorf("unexpected decision %+v", decision)
+	}
+}
+
+func TestGuard_Options(t *testing.T) {
+	wallet := &fakeWallet{}
+	guard := New(wallet, "binance", testConfig(&bytes.Buffer{}))
+
+	for _, key := range []string{"address", "to_address", "addressTag", "addr-tag", "memo", "network", "amount", "currencyToWithdrawAs"} {
+		withdraw := request("usdt", "TAddr", "5")
+		withdraw.Options = map[string]string{key: "Other"}
+		if _, err := goex.ParseResponse(guard.ApplyWithdraw(withdraw)); err == nil {
+			t.Errorf("option %s should be denied", key)
+		}
+	}
+	withdraw := request("usdt", "TAddr", "5")
+	withdraw.Options = map[string]string{"name": "cold wallet"}
+	if _, err := goex.ParseResponse(guard.ApplyWithdraw(withdraw)); err != nil {
+		t.Error(err)
+	}
+	if len(wallet.withdraws) != 1 {
+		t.Errorf("expected 1 withdraw, got %d", len(wallet.withdraws))
+	}
+}
+
+func TestGuard_Tag(t *testing.T) {
+	config := testConfig(&bytes.Buffer{})
+	config.Policies["xrp"] = Policy{Addresses: []Address{{Address: "rAddr", Tag: "123"}, {Address: "rOwn"}}}
+	guard := New(&fakeWallet{}, "binance", config)
+
+	tests := []struct {
+		address string
+		tag     string
+		allowed bool
+	}{
+		{"rAddr", "123", true},
+		{"rAddr", "456", false},
+		{"rAddr", "", false},
+		{"rOwn", "", true},
+		{"rOwn", "123", false},
+	}
+	for _, test := range tests {
+		withdraw := request("xrp", test.address, "1")
+		withdraw.Tag = test.tag
+		if decision := guard.Preview(withdraw); decision.Allowed != test.allowed {
+			t.Errorf("%s:%s unexpected decision %+v", test.address, test.tag, decision)
+		}
+	}
+}
+
+func TestGuard_ApproveUnlocked(t *testing.T) {
+	config := testConfig(&bytes.Buffer{})
+	var guard *Guard
+	config.Approve = func(request *goex.WithdrawRequest, decision *Decision) bool {
+		// approvers may look at the guard without deadlocking it
+		return guard.Preview(request).Allowed
+	}
+	guard = New(&fakeWallet{}, "binance", config)
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := goex.ParseResponse(guard.ApplyWithdraw(request("usdt", "TAddr", "5")))
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Error(err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("approve deadlocked the guard")
+	}
+}
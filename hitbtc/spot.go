@@ -203,12 +203,27 @@ func (spot *Spot) GetUserCommissionRate(symbol goex.Symbol) interface{} {
 
 // PlaceOrder place order
 func (spot *Spot) PlaceOrder(order *goex.PlaceOrder) interface{} {
+	// quantity of market orders is in base coin
+	if err := goex.CheckMarketMode(order, goex.MarketOrderByBase); err != nil {
+		return goex.InvalidOrder(err)
+	}
 	params := &url.Values{}
 	params.Set("symbol", spot.getSymbol(order.Symbol))
 	params.Set("price", order.Price)
 	params.Set("quantity", order.Amount)
 	params.Set("side", order.Side.String())
 	params.Set("type", order.TradeType)
+	switch order.TimeInForce {
+	case goex.IOC:
+		params.Set("timeInForce", "IOC")
+	case goex.FOK:
+		params.Set("timeInForce", "FOK")
+	case goex.POC, goex.GTX:
+		params.Set("timeInForce", "GTC")
+		params.Set("postOnly", "true")
+	default:
+		params.Set("timeInForce", "GTC")
+	}
 	if order.ClientOrderId != "" {
 		params.Set("clientOrderId", order.ClientOrderId)
 	}
@@ -215,3 +215,27 @@ func TestHitbtcSpot_BatchCancelAllOrder(t *testing.T) {
 	b, _ := json.Marshal(response)
 	t.Log(string(b))
 }
+
+func TestHitbtcSpot_GetDepositAddresses(t *testing.T) {
+	market := getInstance()
+
+	response := market.GetDepositAddresses("usdt", "")
+	b, _ := json.Marshal(response)
+	t.Log(string(b))
+}
+
+func TestHitbtcSpot_GetDepositHistory(t *testing.T) {
+	market := getInstance()
+
+	response := market.GetDepositHistory("usdt", 10, nil)
+	b, _ := json.Marshal(response)
+	t.Log(string(b))
+}
+
+func TestHitbtcSpot_GetWithdrawHistory(t *testing.T) {
+	market := getInstance()
+
+	response := market.GetWithdrawHistory("usdt", 10, nil)
+	b, _ := json.Marshal(response)
+	t.Log(string(b))
+}
@@ -0,0 +1,101 @@
+package hitbtc
+
+import (
+	"net/url"
+
+	goex "github.com/primitivelab/goexchange"
+)
+
+// transferStatus hitbtc transaction status
+var transferStatus = map[string]string{
+	"created": goex.TRANSFER_STATUS_PENDING,
+	"pending": goex.TRANSFER_STATUS_PENDING,
+	"failed":  goex.TRANSFER_STATUS_FAILED,
+	"success": goex.TRANSFER_STATUS_SUCCESS,
+}
+
+// GetDepositAddresses deposit address of coin, hitbtc has one currency code per chain so chain is not used
+func (spot *Spot) GetDepositAddresses(coin, chain string) interface{} {
+	result := spot.GetUserDepositAddress(coin, nil).(map[string]interface{})
+	if result["code"] != 0 {
+		return result
+	}
+
+	data, _ := result["data"].(map[string]interface{})
+	result["data"] = []goex.DepositAddress{{
+		Coin:    coin,
+		Address: goex.ToString(data["address"]),
+		Tag:     goex.ToString(data["paymentId"]),
+	}}
+	return result
+}
+
+// ApplyWithdraw user withdraw, request fee is sent as networkFee
+func (spot *Spot) ApplyWithdraw(request *goex.WithdrawRequest) interface{} {
+	params := &url.Values{}
+	// typed fields are set last so that options can not override them
+	for key, value := range request.Options {
+		params.Set(key, value)
+	}
+	params.Set("address", request.Address)
+	params.Set("amount", request.Amount)
+	params.Set("currency", request.Coin)
+	if request.Tag != "" {
+		params.Set("paymentId", request.Tag)
+	}
+	if request.Fee != "" {
+		params.Set("networkFee", request.Fee)
+	}
+	result := spot.httpPost("/api/2/account/crypto/withdraw", params, true)
+	if result["code"] != 0 {
+		return result
+	}
+	data, _ := result["data"].(map[string]interface{})
+	result["data"] = goex.ToString(data["id"])
+	return result
+}
+
+// GetDepositHistory user deposit record list, data: []goex.Transfer
+func (spot *Spot) GetDepositHistory(coin string, size int, options map[string]string) interface{} {
+	result := spot.GetUserDepositRecords(coin, size, options).(map[string]interface{})
+	if result["code"] != 0 {
+		return result
+	}
+	result["data"] = spot.parseTransfers(result["data"], "payin", goex.TRANSFER_DEPOSIT)
+	return result
+}
+
+// GetWithdrawHistory user withdraw record list, data: []goex.Transfer
+func (spot *Spot) GetWithdrawHistory(coin string, size int, options map[string]string) interface{} {
+	result := spot.GetUserWithdrawRecords(coin, size, options).(map[string]interface{})
+	if result["code"] != 0 {
+		return result
+	}
+	result["data"] = spot.parseTransfers(result["data"], "payout", goex.TRANSFER_WITHDRAW)
+	return result
+}
+
+// parseTransfers transactions of hitbtc type, other account transactions are skipped
+func (spot *Spot) parseTransfers(data interface{}, hitbtcType, transferType string) []goex.Transfer {
+	list, _ := data.([]interface{})
+	transfers := make([]goex.Transfer, 0, len(list))
+	for _, item := range list {
+		record, ok := item.(map[string]interface{})
+		if !ok || record["type"] != hitbtcType {
+			continue
+		}
+		transfers = append(transfers, goex.Transfer{
+			Id:      goex.ToString(record["id"]),
+			TxId:    goex.ToString(record["hash"]),
+			Type:    transferType,
+			Coin:    goex.ToString(record["currency"]),
+			Address: goex.ToString(record["address"]),
+			Tag:     goex.ToString(record["paymentId"]),
+			Amount:  goex.ToFloat(record["amount"]),
+			Fee:     goex.ToFloat(record["fee"]),
+			Status:  transferStatus[goex.ToString(record["status"])],
+			Time:    goex.ParseTimestamp(record["createdAt"]),
+		})
+	}
+	return transfers
+}
@@ -124,6 +124,14 @@ func (spot *HooSpot) GetUserBalance() interface{} {
 
 // 批量下单
 func (spot *HooSpot) PlaceOrder(order *PlaceOrder) interface{} {
+	// orders are placed as limit orders, market modes can not be honored
+	if err := CheckMarketMode(order); err != nil {
+		return InvalidOrder(err)
+	}
+	// hoo orders rest until they are filled or canceled
+	if err := CheckTimeInForce(order.TimeInForce); err != nil {
+		return InvalidOrder(err)
+	}
 	params := &url.Values{}
 
 	params.Set("symbol", spot.getSymbol(order.Symbol))
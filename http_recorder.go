@@ -0,0 +1,372 @@
+package goexchange
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// RecorderMode http recorder working mode
+type RecorderMode int
+
+const (
+	// HTTP_RECORD send requests to exchange and save request/response pairs
+	HTTP_RECORD RecorderMode = 1
+	// HTTP_REPLAY answer requests from saved golden files only
+	HTTP_REPLAY RecorderMode = 2
+)
+
+// scrubbed replace value of secrets in golden files
+const scrubbed = "***"
+
+var (
+	// recorderSecretHeaders api key, passphrase and signature headers of all adapters
+	recorderSecretHeaders = []string{
+		"X-MBX-APIKEY",
+		"OK-ACCESS-KEY", "OK-ACCESS-SIGN", "OK-ACCESS-PASSPHRASE", "OK-ACCESS-TIMESTAMP",
+		"KEY", "SIGN", "Timestamp",
+		"Authorization",
+	}
+
+	// recorderSecretParams api key and signature params of all adapters
+	recorderSecretParams = []string{
+		"signature", "Signature", "AccessKeyId",
+		"sign", "api_key", "apiKey", "client_id",
+	}
+
+	// recorderVolatileParams params change on every request, ignored when matching
+	recorderVolatileParams = []string{
+		"timestamp", "Timestamp", "recvWindow", "SignatureMethod", "SignatureVersion",
+		"timeStamp", "nonce", "req_time", "time", "ts",
+	}
+)
+
+// HTTPRecorder http.RoundTripper which records exchange request/response pairs
+// to golden files, or replays them from those files
+type HTTPRecorder struct {
+	Mode      RecorderMode
+	Dir       string
+	Transport http.RoundTripper
+
+	// extra header and param names scrubbed besides the built-in ones
+	SecretHeaders []string
+	SecretParams  []string
+
+	mutex sync.Mutex
+}
+
+// HTTPFixture golden file content
+type HTTPFixture struct {
+	Request  HTTPFixtureRequest  `json:"request"`
+	Response HTTPFixtureResponse `json:"response"`
+}
+
+// HTTPFixtureRequest recorded request, secrets scrubbed
+type HTTPFixtureRequest struct {
+	Method  string            `json:"method"`
+	URL     string            `json:"url"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    string            `json:"body,omitempty"`
+}
+
+// HTTPFixtureResponse recorded response
+type HTTPFixtureResponse struct {
+	Status      int             `json:"status"`
+	ContentType string          `json:"content_type,omitempty"`
+	Body        json.RawMessage `json:"body,omitempty"`
+	BodyText    string          `json:"body_text,omitempty"`
+}
+
+// NewHTTPRecorder new recorder instance, transport is used in record mode only,
+// http.DefaultTransport when nil
+func NewHTTPRecorder(mode RecorderMode, dir string, transport http.RoundTripper) *HTTPRecorder {
+	return &HTTPRecorder{Mode: mode, Dir: dir, Transport: transport}
+}
+
+// WrapClient return a copy of client which sends requests through the recorder,
+// client's transport is used as the real transport if recorder has none
+func (recorder *HTTPRecorder) WrapClient(client *http.Client) *http.Client {
+	if client == nil {
+		client = NewHTTPClient()
+	}
+	wrapped := *client
+	if recorder.Transport == nil {
+		recorder.Transport = client.Transport
+	}
+	wrapped.Transport = recorder
+	return &wrapped
+}
+
+// RoundTrip implement http.RoundTripper
+func (recorder *HTTPRecorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		data, err := ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		body = data
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+
+	file := recorder.fixturePath(req, body)
+	if recorder.Mode == HTTP_REPLAY {
+		return recorder.replay(req, file)
+	}
+	return recorder.record(req, body, file)
+}
+
+// record send request to exchange and save the scrubbed pair
+func (recorder *HTTPRecorder) record(req *http.Request, body []byte, file string) (*http.Response, error) {
+	transport := recorder.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(respBody))
+
+	fixture := HTTPFixture{
+		Request: HTTPFixtureRequest{
+			Method:  req.Method,
+			URL:     recorder.scrubURL(req.URL),
+			Headers: recorder.scrubHeaders(req.Header),
+			Body:    recorder.scrubBody(body),
+		},
+		Response: HTTPFixtureResponse{
+			Status:      resp.StatusCode,
+			ContentType: resp.Header.Get("Content-Type"),
+		},
+	}
+	if json.Valid(respBody) {
+		fixture.Response.Body = respBody
+	} else {
+		fixture.Response.BodyText = string(respBody)
+	}
+
+	data, err := json.MarshalIndent(fixture, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	recorder.mutex.Lock()
+	defer recorder.mutex.Unlock()
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return nil, err
+	}
+	if err := ioutil.WriteFile(file, append(data, '\n'), 0644); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// replay answer request from golden file
+func (recorder *HTTPRecorder) replay(req *http.Request, file string) (*http.Response, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("http recorder: no fixture for %s %s", req.Method, recorder.scrubURL(req.URL))
+	}
+	var fixture HTTPFixture
+	if err := json.Unmarshal(data, &fixture); err != nil {
+		return nil, fmt.Errorf("http recorder: bad fixture %s: %s", file, err.Error())
+	}
+
+	respBody := []byte(fixture.Response.BodyText)
+	if len(fixture.Response.Body) > 0 {
+		respBody = fixture.Response.Body
+	}
+	header := http.Header{}
+	if fixture.Response.ContentType != "" {
+		header.Set("Content-Type", fixture.Response.ContentType)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", fixture.Response.Status, http.StatusText(fixture.Response.Status)),
+		StatusCode:    fixture.Response.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          ioutil.NopCloser(bytes.NewReader(respBody)),
+		ContentLength: int64(len(respBody)),
+		Request:       req,
+	}, nil
+}
+
+// fixturePath golden file of request: dir/host/method_path_hash.json, hash is
+// taken over method, path, query and body without secrets and volatile params
+func (recorder *HTTPRecorder) fixturePath(req *http.Request, body []byte) string {
+	var sb strings.Builder
+	sb.WriteString(req.Method)
+	sb.WriteString(" ")
+	sb.WriteString(req.URL.Host)
+	sb.WriteString(req.URL.Path)
+	sb.WriteString("?")
+	sb.WriteString(recorder.matchValues(req.URL.Query()))
+	sb.WriteString("\n")
+	sb.WriteString(recorder.matchBody(body))
+	hash := fmt.Sprintf("%x", sha1.Sum([]byte(sb.String())))
+
+	path := strings.Trim(req.URL.Path, "/")
+	path = strings.NewReplacer("/", "_", ".", "_", ":", "_").Replace(path)
+	if len(path) > 80 {
+		path = path[:80]
+	}
+	name := fmt.Sprintf("%s_%s_%s.json", strings.ToLower(req.Method), path, hash[:12])
+	return filepath.Join(recorder.Dir, req.URL.Host, name)
+}
+
+// matchValues encode values sorted by key without ignored params
+func (recorder *HTTPRecorder) matchValues(values url.Values) string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		if recorder.isSecretParam(key) || recorder.isVolatileParam(key) {
+			continue
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var sb strings.Builder
+	for _, key := range keys {
+		for _, val := range values[key] {
+			sb.WriteString(key)
+			sb.WriteString("=")
+			sb.WriteString(val)
+			sb.WriteString("&")
+		}
+	}
+	return sb.String()
+}
+
+// matchBody body used in fixture hash, json objects and forms are stripped of
+// ignored params
+func (recorder *HTTPRecorder) matchBody(body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+	var object map[string]interface{}
+	if json.Unmarshal(body, &object) == nil {
+		for key := range object {
+			if recorder.isSecretParam(key) || recorder.isVolatileParam(key) {
+				delete(object, key)
+			}
+		}
+		data, _ := json.Marshal(object)
+		return string(data)
+	}
+	if json.Valid(body) {
+		return string(body)
+	}
+	if values, err := url.ParseQuery(string(body)); err == nil {
+		return recorder.matchValues(values)
+	}
+	return string(body)
+}
+
+// scrubURL url string with secret query params replaced
+func (recorder *HTTPRecorder) scrubURL(reqURL *url.URL) string {
+	scrubURL := *reqURL
+	if scrubURL.RawQuery != "" {
+		values := scrubURL.Query()
+		recorder.scrubValues(values)
+		scrubURL.RawQuery = values.Encode()
+	}
+	return scrubURL.String()
+}
+
+// scrubHeaders request headers with secrets replaced
+func (recorder *HTTPRecorder) scrubHeaders(header http.Header) map[string]string {
+	headers := map[string]string{}
+	for key := range header {
+		if strings.EqualFold(key, "User-Agent") {
+			continue
+		}
+		if recorder.isSecretHeader(key) {
+			headers[key] = scrubbed
+		} else {
+			headers[key] = header.Get(key)
+		}
+	}
+	return headers
+}
+
+// scrubBody request body with secret params replaced
+func (recorder *HTTPRecorder) scrubBody(body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+	var object map[string]interface{}
+	if json.Unmarshal(body, &object) == nil {
+		for key := range object {
+			if recorder.isSecretParam(key) {
+				object[key] = scrubbed
+			}
+		}
+		data, _ := json.Marshal(object)
+		return string(data)
+	}
+	if json.Valid(body) {
+		return string(body)
+	}
+	if values, err := url.ParseQuery(string(body)); err == nil {
+		recorder.scrubValues(values)
+		return values.Encode()
+	}
+	return string(body)
+}
+
+// scrubValues replace secret values in place
+func (recorder *HTTPRecorder) scrubValues(values url.Values) {
+	for key := range values {
+		if recorder.isSecretParam(key) {
+			values.Set(key, scrubbed)
+		}
+	}
+}
+
+func (recorder *HTTPRecorder) isSecretHeader(key string) bool {
+	return containsFold(recorderSecretHeaders, key) || containsFold(recorder.SecretHeaders, key)
+}
+
+func (recorder *HTTPRecorder) isSecretParam(key string) bool {
+	return contains(recorderSecretParams, key) || contains(recorder.SecretParams, key)
+}
+
+func (recorder *HTTPRecorder) isVolatileParam(key string) bool {
+	return contains(recorderVolatileParams, key)
+}
+
+func contains(list []string, key string) bool {
+	for _, item := range list {
+		if item == key {
+			return true
+		}
+	}
+	return false
+}
+
+func containsFold(list []string, key string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, key) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,55 @@
+package goexchange
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestHTTPRecorder_RecordReplay(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"symbol":"BTCUSDT","bids":[["1","2"]]}`))
+	}))
+	dir, err := ioutil.TempDir("", "recorder")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	recorder := NewHTTPRecorder(HTTP_RECORD, dir, http.DefaultTransport)
+	client := recorder.WrapClient(&http.Client{})
+	headers := map[string]string{"X-MBX-APIKEY": "my-api-key"}
+	resp := HttpGetWithHeader(client, server.URL+"/api/v3/depth?symbol=BTCUSDT&timestamp=1&signature=abc", headers)
+	if resp.Code != 0 {
+		t.Fatal(resp.Error)
+	}
+	server.Close()
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*", "*.json"))
+	if len(files) != 1 {
+		t.Fatalf("expect 1 fixture, got %d", len(files))
+	}
+	data, _ := ioutil.ReadFile(files[0])
+	if strings.Contains(string(data), "my-api-key") || strings.Contains(string(data), "abc") {
+		t.Fatalf("secret is not scrubbed: %s", string(data))
+	}
+	t.Log(string(data))
+
+	recorder = NewHTTPRecorder(HTTP_REPLAY, dir, nil)
+	client = recorder.WrapClient(&http.Client{})
+	resp = HttpGetWithHeader(client, server.URL+"/api/v3/depth?symbol=BTCUSDT&timestamp=2&signature=def", headers)
+	if resp.Code != 0 {
+		t.Fatal(resp.Error)
+	}
+	if !strings.Contains(string(resp.Data), "BTCUSDT") {
+		t.Fatalf("unexpected replay body: %s", string(resp.Data))
+	}
+
+	resp = HttpGetWithHeader(client, server.URL+"/api/v3/depth?symbol=ETHUSDT", headers)
+	if resp.Code == 0 {
+		t.Fatal("expect missing fixture error")
+	}
+}
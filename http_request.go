@@ -14,6 +14,7 @@ const (
 	HTTP_GET    string = "GET"
 	HTTP_POST   string = "POST"
 	HTTP_DELETE string = "DELETE"
+	HTTP_PUT    string = "PUT"
 )
 
 // NewHttpRequest http request
@@ -96,3 +97,8 @@ func HttpDeleteWithHeader(client *http.Client, reqURL string, headers map[string
 	headers["Content-Type"] = "application/json; charset=UTF-8"
 	return NewHttpRequest(client, "DELETE", reqURL, "", headers)
 }
+
+func HttpPutWithHeader(client *http.Client, reqURL string, postData string, headers map[string]string) HttpClientResponse {
+	headers["Content-Type"] = "application/x-www-form-urlencoded"
+	return NewHttpRequest(client, "PUT", reqURL, postData, headers)
+}
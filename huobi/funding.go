@@ -0,0 +1,102 @@
+package huobi
+
+import (
+	"net/url"
+	"sort"
+	"strconv"
+
+	goex "github.com/primitivelab/goexchange"
+)
+
+// GetFundingRate current and estimated funding of contract, data: goex.FundingInfo
+func (swap *SwapUsdt) GetFundingRate(symbol goex.Symbol) interface{} {
+	params := &url.Values{}
+	params.Set("contract_code", swap.getSymbol(symbol))
+	result := swap.httpGet("/linear-swap-api/v1/swap_funding_rate", params, false)
+	if result["code"] != 0 {
+		return result
+	}
+	result["data"] = fundingInfo(result["data"])
+	return result
+}
+
+// GetFundingRateHistory settled funding rates, options page_index, data: []goex.FundingRate
+func (swap *SwapUsdt) GetFundingRateHistory(symbol goex.Symbol, size int, options map[string]string) interface{} {
+	params := fundingHistoryParams(swap.getSymbol(symbol), size, options)
+	result := swap.httpGet("/linear-swap-api/v1/swap_historical_funding_rate", params, false)
+	if result["code"] != 0 {
+		return result
+	}
+	result["data"] = fundingRates(result["data"])
+	return result
+}
+
+// GetFundingRate current and estimated funding of contract, data: goex.FundingInfo
+func (swap *SwapCoin) GetFundingRate(symbol goex.Symbol) interface{} {
+	params := &url.Values{}
+	params.Set("contract_code", swap.getSymbol(symbol))
+	result := swap.httpGet("/swap-api/v1/swap_funding_rate", params, false)
+	if result["code"] != 0 {
+		return result
+	}
+	result["data"] = fundingInfo(result["data"])
+	return result
+}
+
+// GetFundingRateHistory settled funding rates, options page_index, data: []goex.FundingRate
+func (swap *SwapCoin) GetFundingRateHistory(symbol goex.Symbol, size int, options map[string]string) interface{} {
+	params := fundingHistoryParams(swap.getSymbol(symbol), size, options)
+	result := swap.httpGet("/swap-api/v1/swap_historical_funding_rate", params, false)
+	if result["code"] != 0 {
+		return result
+	}
+	result["data"] = fundingRates(result["data"])
+	return result
+}
+
+// fundingHistoryParams historical funding params of contract code
+func fundingHistoryParams(contractCode string, size int, options map[string]string) *url.Values {
+	params := &url.Values{}
+	params.Set("contract_code", contractCode)
+	if size != 0 {
+		params.Set("page_size", strconv.Itoa(size))
+	}
+	if pageIndex, ok := options["page_index"]; ok {
+		params.Set("page_index", pageIndex)
+	}
+	return params
+}
+
+// fundingInfo funding of swap_funding_rate body, funding_rate is settled at
+// funding_time and estimated_rate at next_funding_time
+func fundingInfo(data interface{}) goex.FundingInfo {
+	body, _ := data.(map[string]interface{})
+	record, _ := body["data"].(map[string]interface{})
+	return goex.FundingInfo{
+		Symbol:          goex.ToString(record["contract_code"]),
+		Rate:            goex.ToFloat(record["funding_rate"]),
+		PredictedRate:   goex.ToFloat(record["estimated_rate"]),
+		NextFundingTime: goex.ParseTimestamp(record["funding_time"]),
+	}
+}
+
+// fundingRates realized rates of swap_historical_funding_rate body, newest first
+func fundingRates(data interface{}) []goex.FundingRate {
+	body, _ := data.(map[string]interface{})
+	page, _ := body["data"].(map[string]interface{})
+	list, _ := page["data"].([]interface{})
+	rates := make([]goex.FundingRate, 0, len(list))
+	for _, item := range list {
+		record, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		rates = append(rates, goex.FundingRate{
+			Symbol: goex.ToString(record["contract_code"]),
+			Rate:   goex.ToFloat(record["realized_rate"]),
+			Time:   goex.ParseTimestamp(record["funding_time"]),
+		})
+	}
+	sort.SliceStable(rates, func(i, j int) bool { return rates[i].Time > rates[j].Time })
+	return rates
+}
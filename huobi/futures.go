@@ -0,0 +1,412 @@
+package huobi
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	goex "github.com/primitivelab/goexchange"
+)
+
+// futuresContractAlias huobi market symbol suffix of contract type
+var futuresContractAlias = map[string]string{
+	goex.CONTRACT_THIS_WEEK:    "CW",
+	goex.CONTRACT_NEXT_WEEK:    "NW",
+	goex.CONTRACT_QUARTER:      "CQ",
+	goex.CONTRACT_NEXT_QUARTER: "NQ",
+}
+
+// Futures huobi coin margined delivery contract, it shares hbdm with SwapCoin
+type Futures struct {
+	swap      *SwapCoin
+	contracts goex.FuturesContracts
+	mutex     sync.Mutex
+	// lever rate by upper case coin, set by SetLeverRate or read on first order
+	leverRates map[string]int
+}
+
+// NewFutures new instance
+func NewFutures(client *http.Client, baseURL, apiKey, secretKey, accountID string) *Futures {
+	return &Futures{swap: NewSwapCoin(client, baseURL, apiKey, secretKey, accountID)}
+}
+
+// NewFuturesWithConfig new instance with config struct
+func NewFuturesWithConfig(config *goex.APIConfig) *Futures {
+	return &Futures{swap: NewSwapCoinWithConfig(config)}
+}
+
+// GetExchangeName get exchange name
+func (futures *Futures) GetExchangeName() string {
+	return goex.EXCHANGE_HUOBI
+}
+
+// GetContractList delivery contract list, data: []goex.FuturesContract
+func (futures *Futures) GetContractList() interface{} {
+	result := futures.swap.httpGet("/api/v1/contract_contract_info", &url.Values{}, false)
+	if result["code"] != 0 {
+		return result
+	}
+
+	data, _ := result["data"].(map[string]interface{})
+	list, _ := data["data"].([]interface{})
+	contracts := make([]goex.FuturesContract, 0, len(list))
+	for _, item := range list {
+		record, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		contracts = append(contracts, goex.FuturesContract{
+			Symbol:       goex.NewSymbol(goex.ToString(record["symbol"]), "USD"),
+			ContractType: goex.ToString(record["contract_type"]),
+			Code:         goex.ToString(record["contract_code"]),
+			ContractSize: goex.ToFloat(record["contract_size"]),
+			ListTime:     futuresDate(record["create_date"]),
+			DeliveryTime: goex.ParseTimestamp(record["delivery_time"]),
+		})
+	}
+	result["data"] = contracts
+	return result
+}
+
+// GetContract current contract of symbol and contract type, data: goex.FuturesContract
+func (futures *Futures) GetContract(symbol goex.Symbol, contractType string) interface{} {
+	contract, err := futures.resolve(symbol, contractType)
+	if err != nil {
+		return goex.ContractNotFound(err)
+	}
+	return goex.ReturnAPIData(contract)
+}
+
+// GetDepth exchange depth data, options type default step0
+func (futures *Futures) GetDepth(symbol goex.Symbol, contractType string, size int, options map[string]string) interface{} {
+	params, ok := futures.marketParams(symbol, contractType)
+	if !ok {
+		return goex.ReturnAPIError(goex.ContractNotFoundError)
+	}
+	params.Set("type", "step0")
+	if depthType, ok := options["type"]; ok {
+		params.Set("type", depthType)
+	}
+	return futures.market("/market/depth", params, "tick")
+}
+
+// GetTicker exchange ticker data
+func (futures *Futures) GetTicker(symbol goex.Symbol, contractType string) interface{} {
+	params, ok := futures.marketParams(symbol, contractType)
+	if !ok {
+		return goex.ReturnAPIError(goex.ContractNotFoundError)
+	}
+	return futures.market("/market/detail/merged", params, "tick")
+}
+
+// GetKline exchange kline data, options from and to
+func (futures *Futures) GetKline(symbol goex.Symbol, contractType string, period, size int, options map[string]string) interface{} {
+	params, ok := futures.marketParams(symbol, contractType)
+	if !ok {
+		return goex.ReturnAPIError(goex.ContractNotFoundError)
+	}
+	periodStr, ok := klinePeriod[period]
+	if !ok {
+		periodStr = "1min"
+	}
+	params.Set("period", periodStr)
+	if size != 0 {
+		params.Set("size", strconv.Itoa(size))
+	}
+	for _, key := range []string{"from", "to"} {
+		if value, ok := options[key]; ok {
+			params.Set(key, value)
+		}
+	}
+	return futures.market("/market/history/kline", params, "data")
+}
+
+// GetTrade exchange trade order data
+func (futures *Futures) GetTrade(symbol goex.Symbol, contractType string, size int, options map[string]string) interface{} {
+	params, ok := futures.marketParams(symbol, contractType)
+	if !ok {
+		return goex.ReturnAPIError(goex.ContractNotFoundError)
+	}
+	if size != 0 {
+		params.Set("size", strconv.Itoa(size))
+	}
+	return futures.market("/market/history/trade", params, "data")
+}
+
+// GetDeliveryPrices settled delivery prices of coin, data: []goex.DeliveryPrice
+func (futures *Futures) GetDeliveryPrices(symbol goex.Symbol, size int) interface{} {
+	params := &url.Values{}
+	params.Set("symbol", strings.ToUpper(symbol.CoinFrom))
+	params.Set("page_size", "50")
+	result := futures.swap.httpGet("/api/v1/contract_settlement_records", params, false)
+	if result["code"] != 0 {
+		return result
+	}
+
+	data, _ := result["data"].(map[string]interface{})
+	data, _ = data["data"].(map[string]interface{})
+	records, _ := data["settlement_record"].([]interface{})
+	prices := []goex.DeliveryPrice{}
+	for _, item := range records {
+		record, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		list, _ := record["list"].([]interface{})
+		for _, item := range list {
+			contract, ok := item.(map[string]interface{})
+			if !ok || contract["settlement_type"] != "delivery" {
+				continue
+			}
+			prices = append(prices, goex.DeliveryPrice{
+				Code:  goex.ToString(contract["contract_code"]),
+				Price: goex.ToFloat(contract["settlement_price"]),
+				Time:  goex.ParseTimestamp(record["settlement_time"]),
+			})
+		}
+		if size > 0 && len(prices) >= size {
+			prices = prices[:size]
+			break
+		}
+	}
+	result["data"] = prices
+	return result
+}
+
+// GetUserBalance user account balance
+func (futures *Futures) GetUserBalance() interface{} {
+	return futures.swap.httpPostData("/api/v1/contract_account_info", map[string]interface{}{})
+}
+
+// GetUserPositions user position of contract, data: []goex.Position
+func (futures *Futures) GetUserPositions(symbol goex.Symbol, contractType string) interface{} {
+	contract, err := futures.resolve(symbol, contractType)
+	if err != nil {
+		return goex.ContractNotFound(err)
+	}
+	params := map[string]interface{}{"symbol": strings.ToUpper(symbol.CoinFrom)}
+	result := futures.swap.httpPostData("/api/v1/contract_position_info", params)
+	if result["code"] != 0 {
+		return result
+	}
+	accounts := futures.swap.httpPostData("/api/v1/contract_account_info", params)
+	if accounts["code"] != 0 {
+		return accounts
+	}
+	positions := futures.filter(result["data"], contract.Code)
+	result["data"] = parseSwapPositions(positions, accounts["data"], "symbol")
+	return result
+}
+
+// SetLeverRate switch lever rate of coin, later orders of it are sent with the
+// rate, orders of coins without it are sent with the rate of the account
+func (futures *Futures) SetLeverRate(symbol goex.Symbol, leverRate int) interface{} {
+	coin := strings.ToUpper(symbol.CoinFrom)
+	params := map[string]interface{}{"symbol": coin, "lever_rate": leverRate}
+	result := futures.swap.httpPostData("/api/v1/contract_switch_lever_rate", params)
+	if result["code"] != 0 {
+		return result
+	}
+	futures.setLeverRate(coin, leverRate)
+	return result
+}
+
+// PlaceOrder place order, amount is number of contracts, offset is open when
+// order has none, trigger orders are sent to trigger_order or track_order
+func (futures *Futures) PlaceOrder(order *goex.PlaceOrder) interface{} {
+	order, err := swapOrder(order)
+	if err != nil {
+		return goex.InvalidOrder(err)
+	}
+	contract, err := futures.resolve(order.Symbol, order.ContractType)
+	if err != nil {
+		return goex.ContractNotFound(err)
+	}
+	leverRate, result := futures.leverRate(strings.ToUpper(order.Symbol.CoinFrom))
+	if result != nil {
+		return result
+	}
+	if goex.IsTriggerOrder(order.TradeType) {
+		params, kind := swapTriggerParams(contract.Code, order, leverRate)
+		return futures.swap.httpPostData("/api/v1/contract_"+kind, params)
+	}
+	params := swapOrderParams(contract.Code, order, leverRate)
+	return futures.swap.httpPostData("/api/v1/contract_order", params)
+}
+
+// CancelOrder cancel user trust order
+func (futures *Futures) CancelOrder(symbol goex.Symbol, contractType, orderID, clientOrderID string) interface{} {
+	params := map[string]interface{}{"symbol": strings.ToUpper(symbol.CoinFrom)}
+	swapOrderIDParams(params, orderID, clientOrderID)
+	return futures.swap.httpPostData("/api/v1/contract_cancel", params)
+}
+
+// BatchCancelAllOrder cancel all open orders of contract
+func (futures *Futures) BatchCancelAllOrder(symbol goex.Symbol, contractType string) interface{} {
+	params, err := futures.params(symbol, contractType)
+	if err != nil {
+		return goex.ContractNotFound(err)
+	}
+	return futures.swap.httpPostData("/api/v1/contract_cancelall", params)
+}
+
+// GetUserOrderInfo user trust order info, status is one of ORDER_STATUS_*
+func (futures *Futures) GetUserOrderInfo(symbol goex.Symbol, contractType, orderID, clientOrderID string) interface{} {
+	params := map[string]interface{}{"symbol": strings.ToUpper(symbol.CoinFrom)}
+	swapOrderIDParams(params, orderID, clientOrderID)
+	return contractOrders(futures.swap.httpPostData("/api/v1/contract_order_info", params))
+}
+
+// GetUserOpenTrustOrders user open trust order list of contract, status is one
+// of ORDER_STATUS_*, options page_index
+func (futures *Futures) GetUserOpenTrustOrders(symbol goex.Symbol, contractType string, size int, options map[string]string) interface{} {
+	contract, err := futures.resolve(symbol, contractType)
+	if err != nil {
+		return goex.ContractNotFound(err)
+	}
+	params := map[string]interface{}{"symbol": strings.ToUpper(symbol.CoinFrom)}
+	swapHistoryParams(params, size, options)
+	result := contractOrders(futures.swap.httpPostData("/api/v1/contract_openorders", params))
+	if result["code"] != 0 {
+		return result
+	}
+	data, _ := result["data"].(map[string]interface{})
+	result["data"] = futures.filter(data["orders"], contract.Code)
+	return result
+}
+
+// GetUserTrustOrders user history trust order list, status is huobi status
+// list like "3,4", empty for all, statuses of the orders are ORDER_STATUS_*,
+// options create_date days default 7 and page_index
+func (futures *Futures) GetUserTrustOrders(symbol goex.Symbol, contractType, status string, size int, options map[string]string) interface{} {
+	params, err := futures.params(symbol, contractType)
+	if err != nil {
+		return goex.ContractNotFound(err)
+	}
+	params["trade_type"] = 0
+	params["type"] = 1
+	params["status"] = "0"
+	params["create_date"] = 7
+	if status != "" {
+		params["status"] = status
+	}
+	swapHistoryParams(params, size, options)
+	return contractOrders(futures.swap.httpPostData("/api/v1/contract_hisorders", params))
+}
+
+// GetUserTradeOrders user fill list, options create_date days default 7 and page_index
+func (futures *Futures) GetUserTradeOrders(symbol goex.Symbol, contractType string, size int, options map[string]string) interface{} {
+	params, err := futures.params(symbol, contractType)
+	if err != nil {
+		return goex.ContractNotFound(err)
+	}
+	params["trade_type"] = 0
+	params["create_date"] = 7
+	swapHistoryParams(params, size, options)
+	return futures.swap.httpPostData("/api/v1/contract_matchresults", params)
+}
+
+// HTTPRequest request url
+func (futures *Futures) HTTPRequest(requestURL, method string, options interface{}, signed bool) interface{} {
+	return futures.swap.HTTPRequest(requestURL, method, options, signed)
+}
+
+// leverRate lever rate of upper case coin, it is read from the account of
+// coin when it was not set, the failed response is returned when it is unknown
+func (futures *Futures) leverRate(coin string) (int, map[string]interface{}) {
+	futures.mutex.Lock()
+	leverRate, ok := futures.leverRates[coin]
+	futures.mutex.Unlock()
+	if ok {
+		return leverRate, nil
+	}
+	params := map[string]interface{}{"symbol": coin}
+	result := futures.swap.httpPostData("/api/v1/contract_account_info", params)
+	if result["code"] != 0 {
+		return 0, result
+	}
+	leverRate, err := accountLeverRate(result["data"], "symbol", coin)
+	if err != nil {
+		return 0, goex.InvalidOrder(err)
+	}
+	futures.setLeverRate(coin, leverRate)
+	return leverRate, nil
+}
+
+// setLeverRate remember lever rate of upper case coin
+func (futures *Futures) setLeverRate(coin string, leverRate int) {
+	futures.mutex.Lock()
+	defer futures.mutex.Unlock()
+	if futures.leverRates == nil {
+		futures.leverRates = map[string]int{}
+	}
+	futures.leverRates[coin] = leverRate
+}
+
+// resolve current contract of symbol and contract type
+func (futures *Futures) resolve(symbol goex.Symbol, contractType string) (goex.FuturesContract, error) {
+	return futures.contracts.Resolve(symbol, contractType, func() ([]goex.FuturesContract, error) {
+		data, err := goex.ParseResponse(futures.GetContractList())
+		if err != nil {
+			return nil, err
+		}
+		return data.([]goex.FuturesContract), nil
+	})
+}
+
+// params trade params with symbol and contract code of resolved contract
+func (futures *Futures) params(symbol goex.Symbol, contractType string) (map[string]interface{}, error) {
+	contract, err := futures.resolve(symbol, contractType)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"symbol":        strings.ToUpper(symbol.CoinFrom),
+		"contract_code": contract.Code,
+	}, nil
+}
+
+// marketParams market api symbol like BTC_CQ, which needs no contract list
+func (futures *Futures) marketParams(symbol goex.Symbol, contractType string) (*url.Values, bool) {
+	alias, ok := futuresContractAlias[contractType]
+	if !ok {
+		return nil, false
+	}
+	params := &url.Values{}
+	params.Set("symbol", strings.ToUpper(symbol.CoinFrom)+"_"+alias)
+	return params, true
+}
+
+// market public get, data of result is key of huobi response
+func (futures *Futures) market(path string, params *url.Values, key string) interface{} {
+	result := futures.swap.httpGet(path, params, false)
+	if result["code"] != 0 {
+		return result
+	}
+	result["data"] = result["data"].(map[string]interface{})[key]
+	return result
+}
+
+// filter records of contract code
+func (futures *Futures) filter(data interface{}, contractCode string) []interface{} {
+	list, _ := data.([]interface{})
+	records := []interface{}{}
+	for _, item := range list {
+		if record, ok := item.(map[string]interface{}); ok && record["contract_code"] == contractCode {
+			records = append(records, record)
+		}
+	}
+	return records
+}
+
+// futuresDate millisecond timestamp of huobi date like 20210312
+func futuresDate(value interface{}) int64 {
+	date, err := time.Parse("20060102", goex.ToString(value))
+	if err != nil {
+		return 0
+	}
+	return date.UnixNano() / int64(time.Millisecond)
+}
@@ -0,0 +1,210 @@
+package huobi
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	goex "github.com/primitivelab/goexchange"
+)
+
+// GetLeverage lever rate and position mode of contract, data: goex.LeverageSetting
+func (swap *SwapUsdt) GetLeverage(symbol goex.Symbol) interface{} {
+	contractCode := swap.getSymbol(symbol)
+	params := map[string]interface{}{"margin_account": contractCode}
+	result := swap.httpPostData("/linear-swap-api/v1/swap_account_info", params)
+	if result["code"] != 0 {
+		return result
+	}
+	setting := leverageSetting(result["data"], contractCode)
+	swap.setOneWay(contractCode, setting.PositionMode == goex.POSITION_MODE_ONE_WAY)
+	result["data"] = setting
+	return result
+}
+
+// GetLeverageBrackets one bracket of the max available lever rate, data: []goex.LeverageBracket
+func (swap *SwapUsdt) GetLeverageBrackets(symbol goex.Symbol) interface{} {
+	return leverageBrackets(swap.availableLevels(symbol))
+}
+
+// SetLeverage set lever rate of contract, huobi accepts available lever rates only
+func (swap *SwapUsdt) SetLeverage(symbol goex.Symbol, leverage int) interface{} {
+	if result, ok := checkLevel(swap.availableLevels(symbol), leverage); !ok {
+		return result
+	}
+	return swap.SetLeverRate(symbol, leverage)
+}
+
+// SetMarginType the adapter trades isolated margin accounts, so crossed is rejected
+func (swap *SwapUsdt) SetMarginType(symbol goex.Symbol, marginType string) interface{} {
+	return isolatedMarginType(marginType)
+}
+
+// SetPositionMode switch position mode of the isolated margin account of
+// contract, later one-way orders are sent with offset both
+func (swap *SwapUsdt) SetPositionMode(symbol goex.Symbol, mode string) interface{} {
+	positionMode, ok := map[string]string{
+		goex.POSITION_MODE_ONE_WAY: "single_side",
+		goex.POSITION_MODE_HEDGE:   "dual_side",
+	}[mode]
+	if !ok {
+		return goex.InvalidLeverageSetting(errors.New("unknown position mode " + mode))
+	}
+	contractCode := swap.getSymbol(symbol)
+	params := map[string]interface{}{
+		"margin_account": contractCode,
+		"position_mode":  positionMode,
+	}
+	result := swap.httpPostData("/linear-swap-api/v1/swap_switch_position_mode", params)
+	if result["code"] != 0 {
+		return result
+	}
+	swap.setOneWay(contractCode, mode == goex.POSITION_MODE_ONE_WAY)
+	return result
+}
+
+// availableLevels available lever rates of contract, data: []int
+func (swap *SwapUsdt) availableLevels(symbol goex.Symbol) map[string]interface{} {
+	params := map[string]interface{}{"contract_code": swap.getSymbol(symbol)}
+	result := swap.httpPostData("/linear-swap-api/v1/swap_available_level_rate", params)
+	if result["code"] != 0 {
+		return result
+	}
+	result["data"] = parseLevels(result["data"])
+	return result
+}
+
+// GetLeverage lever rate of contract, coin margined swaps are always in
+// hedge position mode, data: goex.LeverageSetting
+func (swap *SwapCoin) GetLeverage(symbol goex.Symbol) interface{} {
+	contractCode := swap.getSymbol(symbol)
+	params := map[string]interface{}{"contract_code": contractCode}
+	result := swap.httpPostData("/swap-api/v1/swap_account_info", params)
+	if result["code"] != 0 {
+		return result
+	}
+	result["data"] = leverageSetting(result["data"], contractCode)
+	return result
+}
+
+// GetLeverageBrackets one bracket of the max available lever rate, data: []goex.LeverageBracket
+func (swap *SwapCoin) GetLeverageBrackets(symbol goex.Symbol) interface{} {
+	return leverageBrackets(swap.availableLevels(symbol))
+}
+
+// SetLeverage set lever rate of contract, huobi accepts available lever rates only
+func (swap *SwapCoin) SetLeverage(symbol goex.Symbol, leverage int) interface{} {
+	if result, ok := checkLevel(swap.availableLevels(symbol), leverage); !ok {
+		return result
+	}
+	return swap.SetLeverRate(symbol, leverage)
+}
+
+// SetMarginType coin margined swaps have isolated margin only
+func (swap *SwapCoin) SetMarginType(symbol goex.Symbol, marginType string) interface{} {
+	return isolatedMarginType(marginType)
+}
+
+// SetPositionMode coin margined swaps have hedge position mode only
+func (swap *SwapCoin) SetPositionMode(symbol goex.Symbol, mode string) interface{} {
+	if mode != goex.POSITION_MODE_HEDGE {
+		return goex.InvalidLeverageSetting(errors.New("huobi coin margined swaps support hedge position mode only"))
+	}
+	return goex.ReturnAPIData(nil)
+}
+
+// availableLevels available lever rates of contract, data: []int
+func (swap *SwapCoin) availableLevels(symbol goex.Symbol) map[string]interface{} {
+	params := map[string]interface{}{"contract_code": swap.getSymbol(symbol)}
+	result := swap.httpPostData("/swap-api/v1/swap_available_level_rate", params)
+	if result["code"] != 0 {
+		return result
+	}
+	result["data"] = parseLevels(result["data"])
+	return result
+}
+
+// leverageSetting setting of the account of contract code, accounts without
+// position_mode are in hedge mode
+func leverageSetting(data interface{}, contractCode string) goex.LeverageSetting {
+	setting := goex.LeverageSetting{
+		Symbol:       contractCode,
+		MarginType:   goex.MARGIN_ISOLATED,
+		PositionMode: goex.POSITION_MODE_HEDGE,
+	}
+	list, _ := data.([]interface{})
+	for _, item := range list {
+		record, ok := item.(map[string]interface{})
+		if !ok || record["contract_code"] != contractCode {
+			continue
+		}
+		setting.Leverage = int(goex.ToFloat(record["lever_rate"]))
+		setting.ShortLeverage = setting.Leverage
+		if record["position_mode"] == "single_side" {
+			setting.PositionMode = goex.POSITION_MODE_ONE_WAY
+		}
+		break
+	}
+	return setting
+}
+
+// parseLevels lever rates of available_level_rate like "1,2,3,5"
+func parseLevels(data interface{}) []int {
+	levels := []int{}
+	list, _ := data.([]interface{})
+	for _, item := range list {
+		record, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for _, text := range strings.Split(goex.ToString(record["available_level_rate"]), ",") {
+			if level, err := strconv.Atoi(strings.TrimSpace(text)); err == nil {
+				levels = append(levels, level)
+			}
+		}
+	}
+	return levels
+}
+
+// leverageBrackets bracket of the max level of an availableLevels response
+func leverageBrackets(result map[string]interface{}) map[string]interface{} {
+	if result["code"] != 0 {
+		return result
+	}
+	maxLevel := 0
+	for _, level := range result["data"].([]int) {
+		if level > maxLevel {
+			maxLevel = level
+		}
+	}
+	result["data"] = []goex.LeverageBracket{{MaxLeverage: maxLevel}}
+	return result
+}
+
+// checkLevel false with the response to return when lever rate is not
+// in an availableLevels response
+func checkLevel(result map[string]interface{}, leverage int) (map[string]interface{}, bool) {
+	if result["code"] != 0 {
+		return result, false
+	}
+	levels := result["data"].([]int)
+	for _, level := range levels {
+		if level == leverage {
+			return result, true
+		}
+	}
+	available := make([]string, 0, len(levels))
+	for _, level := range levels {
+		available = append(available, strconv.Itoa(level))
+	}
+	err := errors.New("lever rate " + strconv.Itoa(leverage) + " is not one of " + strings.Join(available, ","))
+	return goex.InvalidLeverageSetting(err), false
+}
+
+// isolatedMarginType response of setting margin type on isolated margin accounts
+func isolatedMarginType(marginType string) interface{} {
+	if marginType != goex.MARGIN_ISOLATED {
+		return goex.InvalidLeverageSetting(errors.New("huobi swap adapters trade isolated margin only"))
+	}
+	return goex.ReturnAPIData(nil)
+}
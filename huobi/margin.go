@@ -0,0 +1,373 @@
+package huobi
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	goex "github.com/primitivelab/goexchange"
+)
+
+// Margin huobi cross or isolated margin account, orders are spot orders of
+// the margin account id
+type Margin struct {
+	spot       *Spot
+	marginType string
+	mutex      sync.Mutex
+	// account id by "super-margin" or isolated symbol like btcusdt
+	accounts map[string]string
+}
+
+// NewMargin new instance, marginType is goex.MARGIN_CROSSED or goex.MARGIN_ISOLATED
+func NewMargin(client *http.Client, baseURL, apiKey, secretKey, marginType string) *Margin {
+	return &Margin{spot: New(client, baseURL, apiKey, secretKey, ""), marginType: marginType}
+}
+
+// NewMarginWithConfig new instance with config struct
+func NewMarginWithConfig(config *goex.APIConfig, marginType string) *Margin {
+	return &Margin{spot: NewWithConfig(config), marginType: marginType}
+}
+
+// GetExchangeName get exchange name
+func (margin *Margin) GetExchangeName() string {
+	return goex.EXCHANGE_HUOBI
+}
+
+// GetMarginType margin type of the account, default crossed
+func (margin *Margin) GetMarginType() string {
+	if margin.marginType == goex.MARGIN_ISOLATED {
+		return goex.MARGIN_ISOLATED
+	}
+	return goex.MARGIN_CROSSED
+}
+
+// GetUserBalance margin account balance, data: []goex.MarginBalance
+func (margin *Margin) GetUserBalance() interface{} {
+	path := "/v1/cross-margin/accounts/balance"
+	if margin.isIsolated() {
+		path = "/v1/margin/accounts/balance"
+	}
+	result := margin.spot.httpGet(path, &url.Values{}, true)
+	if result["code"] != 0 {
+		return result
+	}
+	result["data"] = marginBalances(result["data"])
+	return result
+}
+
+// Borrow borrow coin, data: loan id
+func (margin *Margin) Borrow(symbol goex.Symbol, coin, amount string) interface{} {
+	params := &url.Values{}
+	params.Set("currency", strings.ToLower(coin))
+	params.Set("amount", amount)
+	path := "/v1/cross-margin/orders"
+	if margin.isIsolated() {
+		params.Set("symbol", margin.spot.getSymbol(symbol))
+		path = "/v1/margin/orders"
+	}
+	result := margin.spot.httpPost(path, params, true)
+	if result["code"] != 0 {
+		return result
+	}
+	body, _ := result["data"].(map[string]interface{})
+	result["data"] = goex.ToString(body["data"])
+	return result
+}
+
+// Repay repay coin by general repayment of the margin account, data: repay id
+func (margin *Margin) Repay(symbol goex.Symbol, coin, amount string) interface{} {
+	accountID, result := margin.accountID(symbol)
+	if result != nil {
+		return result
+	}
+	params := &url.Values{}
+	params.Set("accountId", accountID)
+	params.Set("currency", strings.ToLower(coin))
+	params.Set("amount", amount)
+	result = margin.spot.httpPost("/v2/account/repayment", params, true)
+	if result["code"] != 0 {
+		return result
+	}
+	body, _ := result["data"].(map[string]interface{})
+	list, _ := body["data"].([]interface{})
+	ids := make([]string, 0, len(list))
+	for _, item := range list {
+		if record, ok := item.(map[string]interface{}); ok {
+			ids = append(ids, goex.ToString(record["repayId"]))
+		}
+	}
+	result["data"] = strings.Join(ids, ",")
+	return result
+}
+
+// GetInterestHistory interest of loan orders, options start-date, end-date,
+// from and direct, data: []goex.MarginInterest
+func (margin *Margin) GetInterestHistory(symbol goex.Symbol, coin string, size int, options map[string]string) interface{} {
+	params := &url.Values{}
+	path := "/v1/cross-margin/loan-orders"
+	if margin.isIsolated() {
+		params.Set("symbol", margin.spot.getSymbol(symbol))
+		path = "/v1/margin/loan-orders"
+	}
+	if coin != "" {
+		params.Set("currency", strings.ToLower(coin))
+	}
+	if size != 0 {
+		params.Set("size", strconv.Itoa(size))
+	}
+	for _, key := range []string{"start-date", "end-date", "from", "direct"} {
+		if value, ok := options[key]; ok {
+			params.Set(key, value)
+		}
+	}
+	result := margin.spot.httpGet(path, params, true)
+	if result["code"] != 0 {
+		return result
+	}
+	result["data"] = marginInterests(result["data"])
+	return result
+}
+
+// PlaceOrder place margin order, stop-limit orders are placed when price
+// reaches StopPrice
+func (margin *Margin) PlaceOrder(order *goex.PlaceOrder) interface{} {
+	accountID, result := margin.accountID(order.Symbol)
+	if result != nil {
+		return result
+	}
+	params, err := margin.spot.orderParams(order, accountID, margin.source())
+	if err != nil {
+		return goex.InvalidOrder(err)
+	}
+	return margin.spot.httpPost("/v1/order/orders/place", params, true)
+}
+
+// PlaceLimitOrder place limit margin order
+func (margin *Margin) PlaceLimitOrder(symbol goex.Symbol, price string, amount string, side goex.TradeSide, clientOrderId string) interface{} {
+	return margin.PlaceOrder(&goex.PlaceOrder{
+		Symbol:        symbol,
+		ClientOrderId: clientOrderId,
+		Price:         price,
+		Amount:        amount,
+		Side:          side,
+		TradeType:     goex.LIMIT,
+	})
+}
+
+// PlaceMarketOrder place market margin order, amount of buy orders is in quote coin
+func (margin *Margin) PlaceMarketOrder(symbol goex.Symbol, amount string, side goex.TradeSide, clientOrderId string) interface{} {
+	return margin.PlaceOrder(&goex.PlaceOrder{
+		Symbol:        symbol,
+		ClientOrderId: clientOrderId,
+		Amount:        amount,
+		Side:          side,
+		TradeType:     goex.MARKET,
+	})
+}
+
+// BatchPlaceLimitOrder batch place limit margin order
+func (margin *Margin) BatchPlaceLimitOrder(orders []goex.LimitOrder) interface{} {
+	var trustOrders []map[string]interface{}
+	for _, item := range orders {
+		accountID, result := margin.accountID(item.Symbol)
+		if result != nil {
+			return result
+		}
+		params, err := margin.spot.orderParams(&goex.PlaceOrder{
+			Symbol:        item.Symbol,
+			ClientOrderId: item.ClientOrderId,
+			Price:         item.Price,
+			Amount:        item.Amount,
+			Side:          item.Side,
+			TradeType:     goex.LIMIT,
+			TimeInForce:   item.TimeInForce,
+		}, accountID, margin.source())
+		if err != nil {
+			return goex.InvalidOrder(err)
+		}
+		param := map[string]interface{}{}
+		for key := range *params {
+			param[key] = params.Get(key)
+		}
+		trustOrders = append(trustOrders, param)
+	}
+	return margin.spot.httpPostBatch("/v1/order/batch-orders", trustOrders, true)
+}
+
+// CancelOrder cancel margin order
+func (margin *Margin) CancelOrder(symbol goex.Symbol, orderId, clientOrderId string) interface{} {
+	return margin.spot.CancelOrder(symbol, orderId, clientOrderId)
+}
+
+// BatchCancelOrder batch cancel margin order
+func (margin *Margin) BatchCancelOrder(symbol goex.Symbol, orderIds, clientOrderIds string) interface{} {
+	return margin.spot.BatchCancelOrder(symbol, orderIds, clientOrderIds)
+}
+
+// GetUserOpenTrustOrders user open margin order list, options side, from and direct
+func (margin *Margin) GetUserOpenTrustOrders(symbol goex.Symbol, size int, options map[string]string) interface{} {
+	accountID, result := margin.accountID(symbol)
+	if result != nil {
+		return result
+	}
+	params := &url.Values{}
+	params.Set("account-id", accountID)
+	params.Set("symbol", margin.spot.getSymbol(symbol))
+	if size != 0 {
+		params.Set("size", strconv.Itoa(size))
+	}
+	for _, key := range []string{"side", "from", "direct"} {
+		if value, ok := options[key]; ok {
+			params.Set(key, value)
+		}
+	}
+	return margin.spot.httpGet("/v1/order/openOrders", params, true)
+}
+
+// GetUserOrderInfo user margin order info
+func (margin *Margin) GetUserOrderInfo(symbol goex.Symbol, orderID, clientOrderID string) interface{} {
+	return margin.spot.GetUserOrderInfo(symbol, orderID, clientOrderID)
+}
+
+// GetUserTradeOrders user trade list of symbol, huobi lists trades of all
+// accounts of the symbol
+func (margin *Margin) GetUserTradeOrders(symbol goex.Symbol, size int, options map[string]string) interface{} {
+	return margin.spot.GetUserTradeOrders(symbol, size, options)
+}
+
+// GetUserTrustOrders user order list of symbol, huobi lists orders of all
+// accounts of the symbol
+func (margin *Margin) GetUserTrustOrders(symbol goex.Symbol, status string, size int, options map[string]string) interface{} {
+	return margin.spot.GetUserTrustOrders(symbol, status, size, options)
+}
+
+// accountID id of the margin account of symbol, the account list is loaded
+// once, result is the error response when there is no such account
+func (margin *Margin) accountID(symbol goex.Symbol) (string, map[string]interface{}) {
+	key := "super-margin"
+	if margin.isIsolated() {
+		key = margin.spot.getSymbol(symbol)
+	}
+	margin.mutex.Lock()
+	defer margin.mutex.Unlock()
+	if id, ok := margin.accounts[key]; ok {
+		return id, nil
+	}
+	result := margin.spot.httpGet("/v1/account/accounts", &url.Values{}, true)
+	if result["code"] != 0 {
+		return "", result
+	}
+	margin.accounts = marginAccounts(result["data"])
+	if id, ok := margin.accounts[key]; ok {
+		return id, nil
+	}
+	retData := goex.ReturnAPIError(goex.ExchangeError).(map[string]interface{})
+	retData["error"] = fmt.Sprintf("margin account of %s is not found", key)
+	return "", retData
+}
+
+// source order source of the margin type
+func (margin *Margin) source() string {
+	if margin.isIsolated() {
+		return "margin-api"
+	}
+	return "super-margin-api"
+}
+
+func (margin *Margin) isIsolated() bool {
+	return margin.GetMarginType() == goex.MARGIN_ISOLATED
+}
+
+// marginAccounts margin account ids of account list, the cross margin account
+// by its type super-margin and isolated accounts by symbol
+func marginAccounts(data interface{}) map[string]string {
+	accounts := map[string]string{}
+	body, _ := data.(map[string]interface{})
+	list, _ := body["data"].([]interface{})
+	for _, item := range list {
+		record, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		switch record["type"] {
+		case "super-margin":
+			accounts["super-margin"] = goex.ToString(record["id"])
+		case "margin":
+			accounts[goex.ToString(record["subtype"])] = goex.ToString(record["id"])
+		}
+	}
+	return accounts
+}
+
+// marginBalances balances of the cross margin account object or the isolated
+// margin account list, loan and interest are negative balances
+func marginBalances(data interface{}) []goex.MarginBalance {
+	body, _ := data.(map[string]interface{})
+	accounts, ok := body["data"].([]interface{})
+	if !ok {
+		accounts = []interface{}{body["data"]}
+	}
+	balances := []goex.MarginBalance{}
+	for _, item := range accounts {
+		account, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		symbol := goex.ToString(account["symbol"])
+		index := map[string]int{}
+		list, _ := account["list"].([]interface{})
+		for _, entry := range list {
+			record, ok := entry.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			coin := goex.ToString(record["currency"])
+			position, ok := index[coin]
+			if !ok {
+				position = len(balances)
+				index[coin] = position
+				balances = append(balances, goex.MarginBalance{Symbol: symbol, Coin: coin})
+			}
+			balance := &balances[position]
+			amount := math.Abs(goex.ToFloat(record["balance"]))
+			switch record["type"] {
+			case "trade":
+				balance.Free = amount
+			case "frozen":
+				balance.Frozen = amount
+			case "loan":
+				balance.Borrowed = amount
+			case "interest":
+				balance.Interest = amount
+			}
+		}
+	}
+	return balances
+}
+
+// marginInterests interest of loan orders, newest first
+func marginInterests(data interface{}) []goex.MarginInterest {
+	body, _ := data.(map[string]interface{})
+	list, _ := body["data"].([]interface{})
+	interests := make([]goex.MarginInterest, 0, len(list))
+	for _, item := range list {
+		record, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		interests = append(interests, goex.MarginInterest{
+			Symbol:   goex.ToString(record["symbol"]),
+			Coin:     goex.ToString(record["currency"]),
+			Interest: goex.ToFloat(record["interest-amount"]),
+			Rate:     goex.ToFloat(record["interest-rate"]),
+			Time:     goex.ParseTimestamp(record["accrued-at"]),
+		})
+	}
+	sort.SliceStable(interests, func(i, j int) bool { return interests[i].Time > interests[j].Time })
+	return interests
+}
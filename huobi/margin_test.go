@@ -0,0 +1,89 @@
+package huobi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	goex "github.com/primitivelab/goexchange"
+)
+
+func getMarginInstance() goex.MarginAPI {
+
+	client = &http.Client{}
+	config, err := goex.LoadConfig("huobi")
+	if err != nil {
+		fmt.Println(err)
+	}
+	if config != nil {
+		apiKey = config["key"].(string)
+		secretKey = config["secret"].(string)
+	}
+
+	conf := goex.APIConfig{}
+	conf.ApiKey = apiKey
+	conf.ApiSecretKey = secretKey
+	conf.HttpClient = client
+	market := NewMarginWithConfig(&conf, goex.MARGIN_CROSSED)
+	return market
+}
+
+func TestMargin_GetUserBalance(t *testing.T) {
+	market := getMarginInstance()
+
+	response := market.GetUserBalance()
+	b, _ := json.Marshal(response)
+	t.Log(string(b))
+}
+
+func TestMargin_GetInterestHistory(t *testing.T) {
+	market := getMarginInstance()
+
+	response := market.GetInterestHistory(goex.NewSymbol("btc", "usdt"), "usdt", 10, nil)
+	b, _ := json.Marshal(response)
+	t.Log(string(b))
+}
+
+func TestMargin_GetUserOpenTrustOrders(t *testing.T) {
+	market := getMarginInstance()
+
+	response := market.GetUserOpenTrustOrders(goex.NewSymbol("btc", "usdt"), 10, nil)
+	b, _ := json.Marshal(response)
+	t.Log(string(b))
+}
+
+func TestMarginBalances(t *testing.T) {
+	var _ goex.MarginAPI = &Margin{}
+
+	var data interface{}
+	json.Unmarshal([]byte(`{"status":"ok","data":{"id":1,"type":"cross-margin","state":"working","list":[
+		{"currency":"usdt","type":"trade","balance":"100"},{"currency":"usdt","type":"frozen","balance":"10"},
+		{"currency":"usdt","type":"loan","balance":"-50"},{"currency":"usdt","type":"interest","balance":"-0.1"},
+		{"currency":"btc","type":"trade","balance":"1"}]}}`), &data)
+	balances := marginBalances(data)
+	if len(balances) != 2 || balances[0].Coin != "usdt" || balances[0].Free != 100 || balances[0].Frozen != 10 || balances[0].Borrowed != 50 || balances[0].Interest != 0.1 {
+		t.Fatalf("unexpected cross balances %+v", balances)
+	}
+
+	json.Unmarshal([]byte(`{"status":"ok","data":[{"id":2,"type":"margin","symbol":"btcusdt","list":[{"currency":"btc","type":"trade","balance":"1"}]},
+		{"id":3,"type":"margin","symbol":"ethusdt","list":[{"currency":"btc","type":"trade","balance":"2"}]}]}`), &data)
+	balances = marginBalances(data)
+	if len(balances) != 2 || balances[0].Symbol != "btcusdt" || balances[1].Symbol != "ethusdt" || balances[1].Free != 2 {
+		t.Fatalf("unexpected isolated balances %+v", balances)
+	}
+
+	json.Unmarshal([]byte(`{"status":"ok","data":[{"id":1,"type":"spot","subtype":""},{"id":2,"type":"margin","subtype":"btcusdt"},
+		{"id":3,"type":"super-margin","subtype":""}]}`), &data)
+	accounts := marginAccounts(data)
+	if len(accounts) != 2 || accounts["btcusdt"] != "2" || accounts["super-margin"] != "3" {
+		t.Fatalf("unexpected accounts %v", accounts)
+	}
+
+	json.Unmarshal([]byte(`{"status":"ok","data":[{"id":1,"currency":"usdt","symbol":"btcusdt","interest-amount":"0.01","interest-rate":"0.0002","accrued-at":1620000000000},
+		{"id":2,"currency":"usdt","symbol":"btcusdt","interest-amount":"0.02","interest-rate":"0.0002","accrued-at":1620003600000}]}`), &data)
+	interests := marginInterests(data)
+	if len(interests) != 2 || interests[0].Interest != 0.02 || interests[1].Coin != "usdt" {
+		t.Fatalf("unexpected interests %+v", interests)
+	}
+}
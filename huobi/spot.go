@@ -2,6 +2,7 @@ package huobi
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -197,32 +198,77 @@ func (spot *Spot) GetUserCommissionRate(symbol goex.Symbol) interface{} {
 	return result
 }
 
-// PlaceOrder place order
+// PlaceOrder place order, stop-limit and take-profit-limit orders are placed
+// when price reaches StopPrice
 func (spot *Spot) PlaceOrder(order *goex.PlaceOrder) interface{} {
+	params, err := spot.orderParams(order, spot.accountId, "spot-api")
+	if err != nil {
+		return goex.InvalidOrder(err)
+	}
+	result := spot.httpPost("/v1/order/orders/place", params, true)
+	return result
+}
+
+// orderParams validated order params of account, source is spot-api or a margin source
+func (spot *Spot) orderParams(order *goex.PlaceOrder, accountID, source string) (*url.Values, error) {
+	if err := goex.ValidateOrder(order); err != nil {
+		return nil, err
+	}
+	if order.ReduceOnly || order.ClosePosition {
+		return nil, errors.New("reduce only and close position are for contract orders")
+	}
+	// market buys spend quote coin and market sells sell base coin
+	mode := goex.MarketOrderByQuote
+	if order.Side == goex.SELL {
+		mode = goex.MarketOrderByBase
+	}
+	if err := goex.CheckMarketMode(order, mode); err != nil {
+		return nil, err
+	}
 	params := &url.Values{}
-	params.Set("account-id", spot.accountId)
+	params.Set("account-id", accountID)
 	params.Set("symbol", spot.getSymbol(order.Symbol))
 	params.Set("price", order.Price)
 	params.Set("amount", order.Amount)
-	params.Set("source", "spot-api")
+	params.Set("source", source)
 	if order.ClientOrderId != "" {
 		params.Set("client-order-id", order.ClientOrderId)
 	}
 	side := order.Side.String()
 	tradeType := ""
-	switch order.TimeInForce {
-	case goex.IOC:
-		tradeType = "ioc"
-	case goex.FOK:
-		tradeType = "limit-fok"
-	case goex.POC:
-		tradeType = "limit-maker"
+	switch order.TradeType {
+	case goex.STOP_LIMIT, goex.TAKE_PROFIT_LIMIT:
+		if err := goex.CheckTimeInForce(order.TimeInForce); err != nil {
+			return nil, err
+		}
+		tradeType = "stop-limit"
+		params.Set("stop-price", order.StopPrice)
+		params.Set("operator", "lte")
+		if goex.TriggerAbove(order) {
+			params.Set("operator", "gte")
+		}
+	case goex.LIMIT:
+		switch order.TimeInForce {
+		case goex.IOC:
+			tradeType = "ioc"
+		case goex.FOK:
+			tradeType = "limit-fok"
+		case goex.POC, goex.GTX:
+			tradeType = "limit-maker"
+		default:
+			tradeType = goex.LIMIT
+		}
+	case "", goex.MARKET:
+		// market orders fill what they can and cancel the rest
+		if err := goex.CheckTimeInForce(order.TimeInForce, goex.IOC); err != nil {
+			return nil, err
+		}
+		tradeType = goex.MARKET
 	default:
-		tradeType = order.TradeType
+		return nil, errors.New("huobi spot has no " + order.TradeType + " orders")
 	}
 	params.Set("type", fmt.Sprintf("%s-%s", side, tradeType))
-	result := spot.httpPost("/v1/order/orders/place", params, true)
-	return result
+	return params, nil
 }
 
 // PlaceLimitOrder place limit order
@@ -264,23 +310,27 @@ func (spot *Spot) PlaceMarketOrder(symbol goex.Symbol, amount string, side goex.
 	return result
 }
 
-// BatchPlaceLimitOrder batch place limit order
+// BatchPlaceLimitOrder batch place limit order, time in force maps to the
+// ioc, limit-fok and limit-maker types as it does for PlaceOrder
 func (spot *Spot) BatchPlaceLimitOrder(orders []goex.LimitOrder) interface{} {
 	var trustOrders []map[string]interface{}
 	for _, item := range orders {
+		params, err := spot.orderParams(&goex.PlaceOrder{
+			Symbol:        item.Symbol,
+			ClientOrderId: item.ClientOrderId,
+			Price:         item.Price,
+			Amount:        item.Amount,
+			Side:          item.Side,
+			TradeType:     goex.LIMIT,
+			TimeInForce:   item.TimeInForce,
+		}, spot.accountId, "spot-api")
+		if err != nil {
+			return goex.InvalidOrder(err)
+		}
 		param := map[string]interface{}{}
-
-		param["account-id"] = spot.accountId
-		param["symbol"] = spot.getSymbol(item.Symbol)
-		param["price"] = item.Price
-		param["amount"] = item.Amount
-		if item.Side == goex.BUY {
-			param["type"] = "buy-limit"
-		} else {
-			param["type"] = "sell-limit"
+		for key := range *params {
+			param[key] = params.Get(key)
 		}
-		param["source"] = "spot-api"
-		param["client-order-id"] = item.ClientOrderId
 		trustOrders = append(trustOrders, param)
 	}
 	result := spot.httpPostBatch("/v1/order/batch-orders", trustOrders, true)
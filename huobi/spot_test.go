@@ -242,3 +242,94 @@ func TestHuobiSpot_BatchCancelAllOrder(t *testing.T) {
 	b, _ := json.Marshal(response)
 	t.Log(string(b))
 }
+
+func TestHuobiSpot_GetDepositAddresses(t *testing.T) {
+	market := getInstance()
+
+	response := market.GetDepositAddresses("usdt", "")
+	b, _ := json.Marshal(response)
+	t.Log(string(b))
+}
+
+func TestHuobiSpot_GetDepositHistory(t *testing.T) {
+	market := getInstance()
+
+	response := market.GetDepositHistory("usdt", 10, nil)
+	b, _ := json.Marshal(response)
+	t.Log(string(b))
+}
+
+func TestHuobiSpot_GetWithdrawHistory(t *testing.T) {
+	market := getInstance()
+
+	response := market.GetWithdrawHistory("usdt", 10, nil)
+	b, _ := json.Marshal(response)
+	t.Log(string(b))
+}
+
+func TestHuobiSpot_TransferAsset(t *testing.T) {
+	market := getInstance()
+
+	response := market.TransferAsset(&goex.TransferRequest{Coin: "usdt", From: goex.ACCOUNT_SPOT, To: goex.ACCOUNT_SWAP_USDT, Amount: "1"})
+	b, _ := json.Marshal(response)
+	t.Log(string(b))
+}
+
+func TestHuobiSpot_GetTransferHistory(t *testing.T) {
+	market := getInstance()
+
+	response := market.GetTransferHistory("usdt", goex.ACCOUNT_SPOT, goex.ACCOUNT_SWAP_USDT, 10, nil)
+	b, _ := json.Marshal(response)
+	t.Log(string(b))
+}
+
+func TestHuobiSpot_GetSubAccounts(t *testing.T) {
+	market := getInstance()
+
+	response := market.GetSubAccounts()
+	b, _ := json.Marshal(response)
+	t.Log(string(b))
+}
+
+func TestHuobiSpot_GetSubAccountBalances(t *testing.T) {
+	market := getInstance()
+
+	response := market.GetSubAccountBalances("")
+	b, _ := json.Marshal(response)
+	t.Log(string(b))
+}
+
+func TestSpotStopLimitParams(t *testing.T) {
+	spot := &Spot{}
+	params, err := spot.orderParams(&goex.PlaceOrder{
+		Symbol:    goex.NewSymbol("btc", "usdt"),
+		Side:      goex.BUY,
+		TradeType: goex.STOP_LIMIT,
+		Amount:    "1",
+		Price:     "101",
+		StopPrice: "100",
+	}, "1", "spot-api")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if params.Get("type") != "buy-stop-limit" || params.Get("operator") != "gte" || params.Get("stop-price") != "100" {
+		t.Fatalf("unexpected params %s", params.Encode())
+	}
+	params, err = spot.orderParams(&goex.PlaceOrder{Side: goex.SELL, TradeType: goex.LIMIT, Amount: "1", Price: "101", TimeInForce: goex.POC}, "1", "spot-api")
+	if err != nil || params.Get("type") != "sell-limit-maker" {
+		t.Fatalf("unexpected post only params %v: %v", params, err)
+	}
+	if _, err := spot.orderParams(&goex.PlaceOrder{Side: goex.SELL, TradeType: goex.MARKET, Amount: "1", TimeInForce: goex.FOK}, "1", "spot-api"); err == nil {
+		t.Fatal("expected error of fok market order")
+	}
+	if _, err := spot.orderParams(&goex.PlaceOrder{TradeType: goex.STOP_MARKET, Amount: "1", StopPrice: "100"}, "1", "spot-api"); err == nil {
+		t.Fatal("expected error of stop market order")
+	}
+	if _, err := spot.orderParams(&goex.PlaceOrder{Side: goex.BUY, TradeType: goex.MARKET, Amount: "1", MarketMode: goex.MarketOrderByBase}, "1", "spot-api"); err == nil {
+		t.Fatal("expected error of market buy by base")
+	}
+	result := spot.BatchPlaceLimitOrder([]goex.LimitOrder{{Side: goex.BUY, Amount: "1", Price: "100", TimeInForce: 9}})
+	if result.(map[string]interface{})["code"] != goex.TimeInForceParamsError.Code {
+		t.Fatalf("batch order of unknown time in force must be rejected, got %v", result)
+	}
+}
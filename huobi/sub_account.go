@@ -0,0 +1,187 @@
+package huobi
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	goex "github.com/primitivelab/goexchange"
+)
+
+// GetSubAccounts sub-account list of master account, id is sub-account uid
+func (spot *Spot) GetSubAccounts() interface{} {
+	params := &url.Values{}
+	result := spot.httpGet("/v2/sub-user/user-list", params, true)
+	if result["code"] != 0 {
+		return result
+	}
+
+	data, _ := result["data"].(map[string]interface{})
+	list, _ := data["data"].([]interface{})
+	accounts := make([]goex.SubAccount, 0, len(list))
+	for _, item := range list {
+		record, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		accounts = append(accounts, goex.SubAccount{
+			Id:      goex.ToString(record["uid"]),
+			Name:    goex.ToString(record["uid"]),
+			Enabled: record["userState"] == "normal",
+		})
+	}
+	result["data"] = accounts
+	return result
+}
+
+// GetSubAccountBalances spot balances of sub-account uid
+func (spot *Spot) GetSubAccountBalances(subAccount string) interface{} {
+	params := &url.Values{}
+	result := spot.httpGet(fmt.Sprintf("/v1/account/accounts/%s", subAccount), params, true)
+	if result["code"] != 0 {
+		return result
+	}
+
+	data, _ := result["data"].(map[string]interface{})
+	accounts, _ := data["data"].([]interface{})
+	balances := []goex.AssetBalance{}
+	index := map[string]int{}
+	for _, item := range accounts {
+		account, ok := item.(map[string]interface{})
+		if !ok || account["type"] != "spot" {
+			continue
+		}
+		list, _ := account["list"].([]interface{})
+		for _, item := range list {
+			record, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			coin := goex.ToString(record["currency"])
+			if _, ok := index[coin]; !ok {
+				index[coin] = len(balances)
+				balances = append(balances, goex.AssetBalance{Coin: coin})
+			}
+			balance := &balances[index[coin]]
+			if record["type"] == "frozen" {
+				balance.Frozen += goex.ToFloat(record["balance"])
+			} else {
+				balance.Free += goex.ToFloat(record["balance"])
+			}
+		}
+	}
+	result["data"] = balances
+	return result
+}
+
+// SubAccountTransfer transfer between master and sub-account spot accounts,
+// huobi does not transfer between sub-accounts
+func (spot *Spot) SubAccountTransfer(request *goex.SubTransferRequest) interface{} {
+	if (request.From == "") == (request.To == "") {
+		return goex.ReturnAPIError(goex.TransferAccountError)
+	}
+	params := &url.Values{}
+	params.Set("currency", strings.ToLower(request.Coin))
+	params.Set("amount", request.Amount)
+	if request.From == "" {
+		params.Set("sub-uid", request.To)
+		params.Set("type", "master-transfer-out")
+	} else {
+		params.Set("sub-uid", request.From)
+		params.Set("type", "master-transfer-in")
+	}
+	for key, value := range request.Options {
+		params.Set(key, value)
+	}
+
+	result := spot.httpPost("/v1/subuser/transfer", params, true)
+	if result["code"] != 0 {
+		return result
+	}
+	data, _ := result["data"].(map[string]interface{})
+	result["data"] = goex.ToString(data["data"])
+	return result
+}
+
+// CreateSubAccountAPIKey create api key of sub-account uid, the google
+// authenticator code of master account is passed by request.Options["otpToken"]
+func (spot *Spot) CreateSubAccountAPIKey(request *goex.SubAPIKeyRequest) interface{} {
+	permissions := []string{"readOnly"}
+	if request.Trade {
+		permissions = append(permissions, "trade")
+	}
+	if request.Withdraw {
+		permissions = append(permissions, "withdraw")
+	}
+	params := &url.Values{}
+	params.Set("subUid", request.SubAccount)
+	params.Set("note", request.Label)
+	params.Set("permission", strings.Join(permissions, ","))
+	if len(request.IPs) > 0 {
+		params.Set("ipAddresses", strings.Join(request.IPs, ","))
+	}
+	for key, value := range request.Options {
+		params.Set(key, value)
+	}
+
+	result := spot.httpPost("/v2/user/api-key/generation", params, true)
+	if result["code"] != 0 {
+		return result
+	}
+	data, _ := result["data"].(map[string]interface{})
+	record, _ := data["data"].(map[string]interface{})
+	key := parseSubAPIKey(request.SubAccount, record)
+	key.SecretKey = goex.ToString(record["secretKey"])
+	result["data"] = key
+	return result
+}
+
+// GetSubAccountAPIKeys api key list of sub-account uid
+func (spot *Spot) GetSubAccountAPIKeys(subAccount string) interface{} {
+	params := &url.Values{}
+	params.Set("uid", subAccount)
+	result := spot.httpGet("/v2/user/api-key", params, true)
+	if result["code"] != 0 {
+		return result
+	}
+
+	data, _ := result["data"].(map[string]interface{})
+	list, _ := data["data"].([]interface{})
+	keys := make([]goex.SubAPIKey, 0, len(list))
+	for _, item := range list {
+		if record, ok := item.(map[string]interface{}); ok {
+			keys = append(keys, parseSubAPIKey(subAccount, record))
+		}
+	}
+	result["data"] = keys
+	return result
+}
+
+// DeleteSubAccountAPIKey delete api key of sub-account uid
+func (spot *Spot) DeleteSubAccountAPIKey(subAccount, apiKey string) interface{} {
+	params := &url.Values{}
+	params.Set("subUid", subAccount)
+	params.Set("accessKey", apiKey)
+	result := spot.httpPost("/v2/user/api-key/deletion", params, true)
+	if result["code"] != 0 {
+		return result
+	}
+	result["data"] = apiKey
+	return result
+}
+
+func parseSubAPIKey(subAccount string, record map[string]interface{}) goex.SubAPIKey {
+	key := goex.SubAPIKey{
+		SubAccount: subAccount,
+		Label:      goex.ToString(record["note"]),
+		ApiKey:     goex.ToString(record["accessKey"]),
+		Time:       goex.ParseTimestamp(record["createTime"]),
+	}
+	if permission := goex.ToString(record["permission"]); permission != "" {
+		key.Permissions = strings.Split(permission, ",")
+	}
+	if ips := goex.ToString(record["ipAddresses"]); ips != "" {
+		key.IPs = strings.Split(ips, ",")
+	}
+	return key
+}
@@ -1,6 +1,12 @@
 package huobi
 
-import "github.com/primitivelab/goexchange"
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/primitivelab/goexchange"
+)
 
 // Swap swap api interface
 type Swap interface {
@@ -22,3 +28,248 @@ type Swap interface {
 	// Get exchange http request
 	HTTPRequest(requestURL, method string, options interface{}, signed bool) interface{}
 }
+
+// swapOrderPriceType huobi contract order price type, market orders take
+// up to 20 levels and the rest is canceled
+func swapOrderPriceType(tradeType string, timeInForce goexchange.TimeInForce) string {
+	if tradeType == goexchange.MARKET {
+		return "optimal_20_ioc"
+	}
+	switch timeInForce {
+	case goexchange.IOC:
+		return "ioc"
+	case goexchange.FOK:
+		return "fok"
+	case goexchange.POC, goexchange.GTX:
+		return "post_only"
+	default:
+		return "limit"
+	}
+}
+
+// swapOrderParams huobi contract order params of a validated order, amount is
+// number of contracts, offset is open when order has none and lever rate is
+// sent when it is known
+func swapOrderParams(contractCode string, order *goexchange.PlaceOrder, leverRate int) map[string]interface{} {
+	// swapOrder checked that amount is a whole number
+	volume, _ := strconv.ParseInt(order.Amount, 10, 64)
+	param := map[string]interface{}{
+		"contract_code":    contractCode,
+		"volume":           volume,
+		"direction":        order.Side.String(),
+		"offset":           goexchange.OFFSET_OPEN,
+		"order_price_type": swapOrderPriceType(order.TradeType, order.TimeInForce),
+	}
+	if order.Offset != "" {
+		param["offset"] = order.Offset
+	}
+	if order.TradeType != goexchange.MARKET {
+		param["price"] = goexchange.ToFloat(order.Price)
+	}
+	if leverRate > 0 {
+		param["lever_rate"] = leverRate
+	}
+	if clientOrderID, err := strconv.ParseInt(order.ClientOrderId, 10, 64); err == nil {
+		param["client_order_id"] = clientOrderID
+	}
+	return param
+}
+
+// swapOrder validated order of huobi contracts, reduce only orders are
+// sent as close orders and trailing stops need an activation price
+func swapOrder(order *goexchange.PlaceOrder) (*goexchange.PlaceOrder, error) {
+	if err := goexchange.ValidateOrder(order); err != nil {
+		return nil, err
+	}
+	if order.ClosePosition {
+		return nil, errors.New("huobi contract orders need amount, close position is not supported")
+	}
+	if _, err := strconv.ParseInt(order.Amount, 10, 64); err != nil {
+		return nil, errors.New("huobi contract amount must be a whole number of contracts, got " + order.Amount)
+	}
+	// amount is number of contracts, it is in neither coin
+	if err := goexchange.CheckMarketMode(order); err != nil {
+		return nil, err
+	}
+	// triggered and market orders take the book at optimal levels, only limit
+	// orders carry time in force
+	if goexchange.IsTriggerOrder(order.TradeType) {
+		if err := goexchange.CheckTimeInForce(order.TimeInForce); err != nil {
+			return nil, err
+		}
+	} else if order.TradeType == goexchange.MARKET {
+		if err := goexchange.CheckTimeInForce(order.TimeInForce, goexchange.IOC); err != nil {
+			return nil, err
+		}
+	}
+	if order.TradeType == goexchange.TRAILING_STOP && goexchange.ToFloat(order.StopPrice) <= 0 {
+		return nil, errors.New("huobi trailing stop orders need stop price as activation price")
+	}
+	if !order.ReduceOnly {
+		return order, nil
+	}
+	closeOrder := *order
+	closeOrder.Offset = goexchange.OFFSET_CLOSE
+	return &closeOrder, nil
+}
+
+// swapTriggerParams params of a validated trigger order and the api it is
+// sent to, trigger_order for stop and take-profit orders and track_order for
+// trailing stops, triggered orders take up to 5 levels at market
+func swapTriggerParams(contractCode string, order *goexchange.PlaceOrder, leverRate int) (map[string]interface{}, string) {
+	// swapOrder checked that amount is a whole number
+	volume, _ := strconv.ParseInt(order.Amount, 10, 64)
+	param := map[string]interface{}{
+		"contract_code":    contractCode,
+		"volume":           volume,
+		"direction":        order.Side.String(),
+		"offset":           goexchange.OFFSET_OPEN,
+		"order_price_type": "optimal_5",
+	}
+	if order.Offset != "" {
+		param["offset"] = order.Offset
+	}
+	if leverRate > 0 {
+		param["lever_rate"] = leverRate
+	}
+	if order.TradeType == goexchange.TRAILING_STOP {
+		param["callback_rate"] = goexchange.ToFloat(order.CallbackRate) / 100
+		param["active_price"] = goexchange.ToFloat(order.StopPrice)
+		return param, "track_order"
+	}
+	param["trigger_type"] = "le"
+	if goexchange.TriggerAbove(order) {
+		param["trigger_type"] = "ge"
+	}
+	param["trigger_price"] = goexchange.ToFloat(order.StopPrice)
+	if goexchange.IsLimitOrder(order.TradeType) {
+		param["order_price_type"] = "limit"
+		param["order_price"] = goexchange.ToFloat(order.Price)
+	}
+	return param, "trigger_order"
+}
+
+// oneWayOrderParams order params of one-way position mode, which takes offset
+// both and closes positions with reduce only orders
+func oneWayOrderParams(param map[string]interface{}, order *goexchange.PlaceOrder) {
+	param["offset"] = "both"
+	if order.Offset == goexchange.OFFSET_CLOSE {
+		param["reduce_only"] = 1
+	}
+}
+
+// accountLeverRate lever rate of the contract account info record whose key
+// is value, huobi requires lever rate on orders so it is an error when the
+// account has none
+func accountLeverRate(data interface{}, key, value string) (int, error) {
+	list, _ := data.([]interface{})
+	for _, item := range list {
+		record, ok := item.(map[string]interface{})
+		if !ok || goexchange.ToString(record[key]) != value {
+			continue
+		}
+		if leverRate := int(goexchange.ToFloat(record["lever_rate"])); leverRate > 0 {
+			return leverRate, nil
+		}
+	}
+	return 0, errors.New("lever rate of " + value + " is unknown, set it by SetLeverRate")
+}
+
+// swapOrderIDParams set order_id or client_order_id of comma separated ids
+func swapOrderIDParams(param map[string]interface{}, orderIds, clientOrderIds string) {
+	if clientOrderIds != "" {
+		param["client_order_id"] = strings.Replace(clientOrderIds, " ", "", -1)
+	} else {
+		param["order_id"] = strings.Replace(orderIds, " ", "", -1)
+	}
+}
+
+// contractOrderStatus goexchange order status of huobi contract order status
+// codes, which are not the okex codes ParseOrderStatus knows
+var contractOrderStatus = map[string]string{
+	"1":  goexchange.ORDER_STATUS_NEW,
+	"2":  goexchange.ORDER_STATUS_NEW,
+	"3":  goexchange.ORDER_STATUS_NEW,
+	"4":  goexchange.ORDER_STATUS_PARTIALLY_FILLED,
+	"5":  goexchange.ORDER_STATUS_CANCELED,
+	"6":  goexchange.ORDER_STATUS_FILLED,
+	"7":  goexchange.ORDER_STATUS_CANCELED,
+	"11": goexchange.ORDER_STATUS_NEW,
+}
+
+// contractOrders replace status codes of the contract orders in result by
+// goexchange.ORDER_STATUS_*, orders are the data list or its "orders" list
+func contractOrders(result map[string]interface{}) map[string]interface{} {
+	if result["code"] != 0 {
+		return result
+	}
+	data := result["data"]
+	if object, ok := data.(map[string]interface{}); ok {
+		if orders, ok := object["orders"]; ok {
+			data = orders
+		} else {
+			data = []interface{}{object}
+		}
+	}
+	list, _ := data.([]interface{})
+	for _, item := range list {
+		if order, ok := item.(map[string]interface{}); ok {
+			if status, ok := contractOrderStatus[goexchange.ToString(order["status"])]; ok {
+				order["status"] = status
+			}
+		}
+	}
+	return result
+}
+
+// swapHistoryParams set page size and history options
+func swapHistoryParams(param map[string]interface{}, size int, options map[string]string) {
+	if size != 0 {
+		param["page_size"] = size
+	}
+	for _, key := range []string{"create_date", "page_index"} {
+		if value, ok := options[key]; ok {
+			param[key], _ = strconv.Atoi(value)
+		}
+	}
+}
+
+// parseSwapPositions typed positions of huobi position list, liquidation
+// price is taken from the account of accounts with the same value of key
+func parseSwapPositions(positions, accounts interface{}, key string) []goexchange.Position {
+	liquidation := map[string]float64{}
+	accountList, _ := accounts.([]interface{})
+	for _, item := range accountList {
+		if account, ok := item.(map[string]interface{}); ok {
+			liquidation[goexchange.ToString(account[key])] = goexchange.ToFloat(account["liquidation_price"])
+		}
+	}
+
+	list, _ := positions.([]interface{})
+	records := []goexchange.Position{}
+	for _, item := range list {
+		record, ok := item.(map[string]interface{})
+		if !ok || goexchange.ToFloat(record["volume"]) == 0 {
+			continue
+		}
+		position := goexchange.Position{
+			Symbol:           goexchange.ToString(record["contract_code"]),
+			Side:             goexchange.POSITION_LONG,
+			Amount:           goexchange.ToFloat(record["volume"]),
+			EntryPrice:       goexchange.ToFloat(record["cost_open"]),
+			MarkPrice:        goexchange.ToFloat(record["last_price"]),
+			UnrealizedPnl:    goexchange.ToFloat(record["profit_unreal"]),
+			Leverage:         goexchange.ToFloat(record["lever_rate"]),
+			MarginType:       goexchange.MARGIN_ISOLATED,
+			LiquidationPrice: liquidation[goexchange.ToString(record[key])],
+		}
+		if record["direction"] == "sell" {
+			position.Side = goexchange.POSITION_SHORT
+		}
+		if marginType := goexchange.ParseMarginType(record["margin_mode"]); marginType != "" {
+			position.MarginType = marginType
+		}
+		records = append(records, position)
+	}
+	return records
+}
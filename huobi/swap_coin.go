@@ -6,6 +6,7 @@ import (
 	"net/url"
 	"strconv"
 	"strings"
+	"sync"
 
 	goex "github.com/primitivelab/goexchange"
 )
@@ -17,6 +18,11 @@ type SwapCoin struct {
 	accountId  string
 	accessKey  string
 	secretKey  string
+	mutex      sync.Mutex
+	// spot endpoint of account transfers, set by SetSpotEndpoint
+	spotURL string
+	// lever rate by contract code, set by SetLeverRate or read on first order
+	leverRates map[string]int
 }
 
 // NewSwapCoin new instance
@@ -146,6 +152,229 @@ func (swap *SwapCoin) GetPremiumIndex(symbol goex.Symbol) interface{} {
 	return result
 }
 
+// GetUserBalance user account balance
+func (swap *SwapCoin) GetUserBalance() interface{} {
+	return swap.httpPostData("/swap-api/v1/swap_account_info", map[string]interface{}{})
+}
+
+// GetUserAssets user account balance and open positions
+func (swap *SwapCoin) GetUserAssets() interface{} {
+	result := swap.httpPostData("/swap-api/v1/swap_account_info", map[string]interface{}{})
+	if result["code"] != 0 {
+		return result
+	}
+	positions := swap.httpPostData("/swap-api/v1/swap_position_info", map[string]interface{}{})
+	if positions["code"] != 0 {
+		return positions
+	}
+	result["data"] = map[string]interface{}{
+		"accounts":  result["data"],
+		"positions": positions["data"],
+	}
+	return result
+}
+
+// GetUserPositions user open positions, all contracts when symbol is empty,
+// data: []goex.Position
+func (swap *SwapCoin) GetUserPositions(symbol goex.Symbol) interface{} {
+	params := map[string]interface{}{}
+	if symbol.CoinFrom != "" {
+		params["contract_code"] = swap.getSymbol(symbol)
+	}
+	result := swap.httpPostData("/swap-api/v1/swap_position_info", params)
+	if result["code"] != 0 {
+		return result
+	}
+	accounts := swap.httpPostData("/swap-api/v1/swap_account_info", map[string]interface{}{})
+	if accounts["code"] != 0 {
+		return accounts
+	}
+	result["data"] = parseSwapPositions(result["data"], accounts["data"], "contract_code")
+	return result
+}
+
+// ClosePosition close positions of contract at market when price is empty,
+// or with limit orders at price
+func (swap *SwapCoin) ClosePosition(symbol goex.Symbol, price string) interface{} {
+	return goex.ClosePositions(swap.GetUserPositions(symbol), symbol, price, swap.PlaceOrder)
+}
+
+// SetLeverRate switch lever rate of contract, later orders of it are sent with
+// the rate, orders of contracts without it are sent with the rate of the account
+func (swap *SwapCoin) SetLeverRate(symbol goex.Symbol, leverRate int) interface{} {
+	params := map[string]interface{}{
+		"contract_code": swap.getSymbol(symbol),
+		"lever_rate":    leverRate,
+	}
+	result := swap.httpPostData("/swap-api/v1/swap_switch_lever_rate", params)
+	if result["code"] != 0 {
+		return result
+	}
+	swap.setLeverRate(swap.getSymbol(symbol), leverRate)
+	return result
+}
+
+// PlaceOrder place order, amount is number of contracts, trigger orders are
+// sent to trigger_order or track_order
+func (swap *SwapCoin) PlaceOrder(order *goex.PlaceOrder) interface{} {
+	order, err := swapOrder(order)
+	if err != nil {
+		return goex.InvalidOrder(err)
+	}
+	contractCode := swap.getSymbol(order.Symbol)
+	leverRate, result := swap.leverRate(contractCode)
+	if result != nil {
+		return result
+	}
+	if goex.IsTriggerOrder(order.TradeType) {
+		params, kind := swapTriggerParams(contractCode, order, leverRate)
+		return swap.httpPostData("/swap-api/v1/swap_"+kind, params)
+	}
+	params := swapOrderParams(contractCode, order, leverRate)
+	return swap.httpPostData("/swap-api/v1/swap_order", params)
+}
+
+// PlaceLimitOrder place limit order to open position
+func (swap *SwapCoin) PlaceLimitOrder(symbol goex.Symbol, price string, amount string, side goex.TradeSide, ClientOrderID string) interface{} {
+	return swap.PlaceOrder(&goex.PlaceOrder{
+		Symbol:        symbol,
+		ClientOrderId: ClientOrderID,
+		Price:         price,
+		Amount:        amount,
+		Side:          side,
+		TradeType:     goex.LIMIT,
+	})
+}
+
+// PlaceMarketOrder place market order to open position
+func (swap *SwapCoin) PlaceMarketOrder(symbol goex.Symbol, amount string, side goex.TradeSide, ClientOrderID string) interface{} {
+	return swap.PlaceOrder(&goex.PlaceOrder{
+		Symbol:        symbol,
+		ClientOrderId: ClientOrderID,
+		Amount:        amount,
+		Side:          side,
+		TradeType:     goex.MARKET,
+	})
+}
+
+// BatchPlaceLimitOrder batch place limit order, huobi accepts 10 orders at most
+func (swap *SwapCoin) BatchPlaceLimitOrder(orders []goex.LimitOrder) interface{} {
+	var ordersData []map[string]interface{}
+	for index, item := range orders {
+		if index > 9 {
+			break
+		}
+		contractCode := swap.getSymbol(item.Symbol)
+		order, err := swapOrder(&goex.PlaceOrder{
+			Symbol:        item.Symbol,
+			ClientOrderId: item.ClientOrderId,
+			Price:         item.Price,
+			Amount:        item.Amount,
+			Side:          item.Side,
+			TradeType:     goex.LIMIT,
+			TimeInForce:   item.TimeInForce,
+			Offset:        item.Offset,
+		})
+		if err != nil {
+			return goex.InvalidOrder(err)
+		}
+		leverRate, result := swap.leverRate(contractCode)
+		if result != nil {
+			return result
+		}
+		ordersData = append(ordersData, swapOrderParams(contractCode, order, leverRate))
+	}
+	params := map[string]interface{}{"orders_data": ordersData}
+	return swap.httpPostData("/swap-api/v1/swap_batchorder", params)
+}
+
+// CancelOrder cancel user trust order
+func (swap *SwapCoin) CancelOrder(symbol goex.Symbol, orderID, clientOrderID string) interface{} {
+	return swap.BatchCancelOrder(symbol, orderID, clientOrderID)
+}
+
+// BatchCancelOrder batch cancel trust order, ids are separated by comma
+func (swap *SwapCoin) BatchCancelOrder(symbol goex.Symbol, orderIds, clientOrderIds string) interface{} {
+	params := map[string]interface{}{"contract_code": swap.getSymbol(symbol)}
+	swapOrderIDParams(params, orderIds, clientOrderIds)
+	return swap.httpPostData("/swap-api/v1/swap_cancel", params)
+}
+
+// BatchCancelAllOrder batch cancel all orders
+func (swap *SwapCoin) BatchCancelAllOrder(symbol goex.Symbol) interface{} {
+	params := map[string]interface{}{"contract_code": swap.getSymbol(symbol)}
+	return swap.httpPostData("/swap-api/v1/swap_cancelall", params)
+}
+
+// GetUserOpenTrustOrders user open trust order list, status is one of
+// ORDER_STATUS_*, options page_index
+func (swap *SwapCoin) GetUserOpenTrustOrders(symbol goex.Symbol, size int, options map[string]string) interface{} {
+	params := map[string]interface{}{"contract_code": swap.getSymbol(symbol)}
+	if size != 0 {
+		params["page_size"] = size
+	}
+	if pageIndex, ok := options["page_index"]; ok {
+		params["page_index"], _ = strconv.Atoi(pageIndex)
+	}
+	return contractOrders(swap.httpPostData("/swap-api/v1/swap_openorders", params))
+}
+
+// GetUserOrderInfo user trust order info, status is one of ORDER_STATUS_*
+func (swap *SwapCoin) GetUserOrderInfo(symbol goex.Symbol, orderID, clientOrderID string) interface{} {
+	params := map[string]interface{}{"contract_code": swap.getSymbol(symbol)}
+	swapOrderIDParams(params, orderID, clientOrderID)
+	return contractOrders(swap.httpPostData("/swap-api/v1/swap_order_info", params))
+}
+
+// GetUserTradeOrders user fill list, options create_date days default 7 and page_index
+func (swap *SwapCoin) GetUserTradeOrders(symbol goex.Symbol, size int, options map[string]string) interface{} {
+	params := map[string]interface{}{
+		"contract_code": swap.getSymbol(symbol),
+		"trade_type":    0,
+		"create_date":   7,
+	}
+	swapHistoryParams(params, size, options)
+	return swap.httpPostData("/swap-api/v1/swap_matchresults", params)
+}
+
+// GetUserTrustOrders user history trust order list, status is huobi status
+// list like "3,4", empty for all, statuses of the orders are ORDER_STATUS_*,
+// options create_date days default 7 and page_index
+func (swap *SwapCoin) GetUserTrustOrders(symbol goex.Symbol, status string, size int, options map[string]string) interface{} {
+	params := map[string]interface{}{
+		"contract_code": swap.getSymbol(symbol),
+		"trade_type":    0,
+		"type":          1,
+		"status":        "0",
+		"create_date":   7,
+	}
+	if status != "" {
+		params["status"] = status
+	}
+	swapHistoryParams(params, size, options)
+	return contractOrders(swap.httpPostData("/swap-api/v1/swap_hisorders", params))
+}
+
+// GetUserAssetsIncomes user assets changes records, options type is huobi
+// record types like "30,31", create_date days and page_index
+func (swap *SwapCoin) GetUserAssetsIncomes(symbol goex.Symbol, size int, options map[string]string) interface{} {
+	params := map[string]interface{}{}
+	if symbol.CoinFrom != "" {
+		params["contract_code"] = swap.getSymbol(symbol)
+	}
+	if recordType, ok := options["type"]; ok {
+		params["type"] = recordType
+	}
+	swapHistoryParams(params, size, options)
+	return swap.httpPostData("/swap-api/v1/swap_financial_record", params)
+}
+
+// GetUserCommissionRate user current commission rate
+func (swap *SwapCoin) GetUserCommissionRate(symbol goex.Symbol) interface{} {
+	params := map[string]interface{}{"contract_code": swap.getSymbol(symbol)}
+	return swap.httpPostData("/swap-api/v1/swap_fee", params)
+}
+
 // HTTPRequest request url
 func (swap *SwapCoin) HTTPRequest(requestURL, method string, options interface{}, signed bool) interface{} {
 	method = strings.ToUpper(method)
@@ -211,6 +440,48 @@ func (swap *SwapCoin) httpPostBatch(path string, params interface{}, signed bool
 	return swap.handlerResponse(&responseMap)
 }
 
+// httpPostData signed post, data of result is data field of huobi response
+func (swap *SwapCoin) httpPostData(path string, params map[string]interface{}) map[string]interface{} {
+	result := swap.httpPostBatch(path, params, true)
+	if result["code"] != 0 {
+		return result
+	}
+	result["data"] = result["data"].(map[string]interface{})["data"]
+	return result
+}
+
+// leverRate lever rate of contract, it is read from the account of contract
+// when it was not set, the failed response is returned when it is unknown
+func (swap *SwapCoin) leverRate(contractCode string) (int, map[string]interface{}) {
+	swap.mutex.Lock()
+	leverRate, ok := swap.leverRates[contractCode]
+	swap.mutex.Unlock()
+	if ok {
+		return leverRate, nil
+	}
+	params := map[string]interface{}{"contract_code": contractCode}
+	result := swap.httpPostData("/swap-api/v1/swap_account_info", params)
+	if result["code"] != 0 {
+		return 0, result
+	}
+	leverRate, err := accountLeverRate(result["data"], "contract_code", contractCode)
+	if err != nil {
+		return 0, goex.InvalidOrder(err)
+	}
+	swap.setLeverRate(contractCode, leverRate)
+	return leverRate, nil
+}
+
+// setLeverRate remember lever rate of contract
+func (swap *SwapCoin) setLeverRate(contractCode string, leverRate int) {
+	swap.mutex.Lock()
+	defer swap.mutex.Unlock()
+	if swap.leverRates == nil {
+		swap.leverRates = map[string]int{}
+	}
+	swap.leverRates[contractCode] = leverRate
+}
+
 // handlerResponse Handler response data format
 func (swap *SwapCoin) handlerResponse(responseMap *goex.HttpClientResponse) map[string]interface{} {
 	retData := make(map[string]interface{})
@@ -273,6 +544,6 @@ func (swap *SwapCoin) sign(method, path string, params *url.Values) string {
 }
 
 // getSymbol format symbol method
-func (swap SwapCoin) getSymbol(symbol goex.Symbol) string {
+func (swap *SwapCoin) getSymbol(symbol goex.Symbol) string {
 	return symbol.ToUpper().ToSymbol("-")
 }
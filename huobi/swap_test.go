@@ -216,3 +216,221 @@ func TestSwap_GetPremiumIndex(t *testing.T) {
 // 	b, _ := json.Marshal(response)
 // 	t.Log(string(b))
 // }
+
+func TestSwap_GetUserBalance(t *testing.T) {
+	market := getSwapInstance().(*SwapCoin)
+
+	response := market.GetUserBalance()
+	b, _ := json.Marshal(response)
+	t.Log(string(b))
+}
+
+func TestSwap_GetUserPositions(t *testing.T) {
+	market := getSwapInstance().(*SwapCoin)
+
+	response := market.GetUserPositions(goex.NewSymbol(CoinFrom, CoinTo))
+	b, _ := json.Marshal(response)
+	t.Log(string(b))
+}
+
+func TestSwap_PlaceLimitOrder(t *testing.T) {
+	market := getSwapInstance().(*SwapCoin)
+
+	response := market.PlaceLimitOrder(goex.NewSymbol(CoinFrom, CoinTo), "10", "1", goex.BUY, "")
+	b, _ := json.Marshal(response)
+	t.Log(string(b))
+}
+
+func TestSwap_GetUserOpenTrustOrders(t *testing.T) {
+	market := getSwapInstance().(*SwapCoin)
+
+	response := market.GetUserOpenTrustOrders(goex.NewSymbol(CoinFrom, CoinTo), 10, nil)
+	b, _ := json.Marshal(response)
+	t.Log(string(b))
+}
+
+func TestSwap_GetUserTradeOrders(t *testing.T) {
+	market := getSwapInstance().(*SwapCoin)
+
+	response := market.GetUserTradeOrders(goex.NewSymbol(CoinFrom, CoinTo), 10, nil)
+	b, _ := json.Marshal(response)
+	t.Log(string(b))
+}
+
+func TestSwapOrderParams(t *testing.T) {
+	order := &goex.PlaceOrder{
+		Symbol:        goex.NewSymbol("btc", "usdt"),
+		ClientOrderId: "123",
+		Price:         "30000.5",
+		Amount:        "2",
+		Side:          goex.SELL,
+		TradeType:     goex.LIMIT,
+		TimeInForce:   goex.GTX,
+		Offset:        goex.OFFSET_CLOSE,
+	}
+	params := swapOrderParams("BTC-USDT", order, 10)
+	if params["volume"] != int64(2) || params["price"] != 30000.5 || params["client_order_id"] != int64(123) {
+		t.Fatalf("unexpected params %v", params)
+	}
+	if params["direction"] != "sell" || params["offset"] != "close" || params["order_price_type"] != "post_only" || params["lever_rate"] != 10 {
+		t.Fatalf("unexpected params %v", params)
+	}
+
+	order.TradeType = goex.MARKET
+	order.Offset = ""
+	params = swapOrderParams("BTC-USDT", order, 0)
+	if _, ok := params["price"]; ok || params["order_price_type"] != "optimal_20_ioc" || params["offset"] != "open" {
+		t.Fatalf("unexpected params %v", params)
+	}
+	if _, ok := params["lever_rate"]; ok {
+		t.Fatalf("lever rate should not be sent, got %v", params)
+	}
+}
+
+func TestSwapOrderVolume(t *testing.T) {
+	if _, err := swapOrder(&goex.PlaceOrder{Side: goex.BUY, TradeType: goex.MARKET, Amount: "1.5"}); err == nil {
+		t.Fatal("expected error of fractional contracts")
+	}
+
+	var data interface{}
+	json.Unmarshal([]byte(`[{"symbol":"BTC","contract_code":"BTC-USD","lever_rate":20},{"symbol":"ETH","contract_code":"ETH-USD"}]`), &data)
+	if leverRate, err := accountLeverRate(data, "contract_code", "BTC-USD"); err != nil || leverRate != 20 {
+		t.Fatalf("unexpected lever rate %d, %v", leverRate, err)
+	}
+	if leverRate, err := accountLeverRate(data, "symbol", "BTC"); err != nil || leverRate != 20 {
+		t.Fatalf("unexpected lever rate %d, %v", leverRate, err)
+	}
+	if _, err := accountLeverRate(data, "symbol", "ETH"); err == nil {
+		t.Fatal("expected error of account without lever rate")
+	}
+}
+
+func TestContractOrders(t *testing.T) {
+	bodies := []string{
+		`[{"order_id":28,"volume":2,"trade_volume":1,"status":4,"direction":"buy"}]`,
+		`{"orders":[{"order_id":28,"volume":2,"trade_volume":1,"status":4,"direction":"buy"},{"order_id":29,"volume":1,"status":2,"direction":"sell"}],"total_page":1}`,
+	}
+	for _, body := range bodies {
+		var data interface{}
+		json.Unmarshal([]byte(body), &data)
+		orders, err := goex.ParseOrders(contractOrders(goex.ReturnAPIData(data)))
+		if err != nil || orders[0].Status != goex.ORDER_STATUS_PARTIALLY_FILLED {
+			t.Fatalf("unexpected orders of %s: %+v, %v", body, orders, err)
+		}
+		if len(orders) == 2 && orders[1].Status != goex.ORDER_STATUS_NEW {
+			t.Fatalf("preparing order should be new, got %+v", orders[1])
+		}
+	}
+	statuses := map[float64]string{3: goex.ORDER_STATUS_NEW, 5: goex.ORDER_STATUS_CANCELED, 6: goex.ORDER_STATUS_FILLED, 7: goex.ORDER_STATUS_CANCELED}
+	for code, expect := range statuses {
+		result := goex.ReturnAPIData(map[string]interface{}{"order_id": 1.0, "status": code})
+		if order, _ := goex.ParseOrder(contractOrders(result)); order.Status != expect {
+			t.Fatalf("unexpected status of %v: %s", code, order.Status)
+		}
+	}
+}
+
+func TestParseSwapPositions(t *testing.T) {
+	var positions, accounts interface{}
+	json.Unmarshal([]byte(`[
+		{"contract_code":"BTC-USDT","volume":3,"cost_open":30000,"last_price":31000,"profit_unreal":-30,"lever_rate":5,"direction":"sell","margin_mode":"isolated"},
+		{"contract_code":"ETH-USDT","volume":0,"direction":"buy"}
+	]`), &positions)
+	json.Unmarshal([]byte(`[{"contract_code":"BTC-USDT","liquidation_price":36000}]`), &accounts)
+
+	list := parseSwapPositions(positions, accounts, "contract_code")
+	if len(list) != 1 {
+		t.Fatalf("expected 1 position, got %+v", list)
+	}
+	position := list[0]
+	if position.Side != goex.POSITION_SHORT || position.Amount != 3 || position.EntryPrice != 30000 || position.MarkPrice != 31000 ||
+		position.UnrealizedPnl != -30 || position.Leverage != 5 || position.MarginType != goex.MARGIN_ISOLATED || position.LiquidationPrice != 36000 {
+		t.Fatalf("unexpected position %+v", position)
+	}
+}
+
+func TestLevels(t *testing.T) {
+	var _ goex.LeverageAPI = &SwapUsdt{}
+	var _ goex.LeverageAPI = &SwapCoin{}
+
+	var data interface{}
+	json.Unmarshal([]byte(`[{"contract_code":"BTC-USDT","available_level_rate":"1,2,3,5,10,20"}]`), &data)
+	levels := goex.ReturnAPIData(parseLevels(data))
+	if _, ok := checkLevel(levels, 5); !ok {
+		t.Fatal("lever rate 5 should be available")
+	}
+	if result, ok := checkLevel(levels, 4); ok || result["code"] != goex.LeverageSettingError.Code {
+		t.Fatalf("lever rate 4 should be rejected, got %v", result)
+	}
+	brackets := leverageBrackets(goex.ReturnAPIData(parseLevels(data)))["data"].([]goex.LeverageBracket)
+	if len(brackets) != 1 || brackets[0].MaxLeverage != 20 {
+		t.Fatalf("unexpected brackets %+v", brackets)
+	}
+
+	order := &goex.PlaceOrder{Side: goex.SELL, Offset: goex.OFFSET_CLOSE, Amount: "1"}
+	params := swapOrderParams("BTC-USDT", order, 0)
+	oneWayOrderParams(params, order)
+	if params["offset"] != "both" || params["reduce_only"] != 1 {
+		t.Fatalf("unexpected params %v", params)
+	}
+}
+
+func TestSwap_GetFundingRate(t *testing.T) {
+	market := getSwapInstance().(*SwapCoin)
+
+	response := market.GetFundingRate(goex.NewSymbol(CoinFrom, CoinTo))
+	b, _ := json.Marshal(response)
+	t.Log(string(b))
+}
+
+func TestSwap_GetFundingRateHistory(t *testing.T) {
+	market := getSwapInstance().(*SwapCoin)
+
+	response := market.GetFundingRateHistory(goex.NewSymbol(CoinFrom, CoinTo), 10, nil)
+	b, _ := json.Marshal(response)
+	t.Log(string(b))
+}
+
+func TestFundingRates(t *testing.T) {
+	var _ goex.FundingAPI = &SwapUsdt{}
+	var _ goex.FundingAPI = &SwapCoin{}
+
+	var data interface{}
+	json.Unmarshal([]byte(`{"status":"ok","data":{"contract_code":"BTC-USDT","funding_rate":"0.0001","estimated_rate":"0.0003",
+		"funding_time":"1620000000000","next_funding_time":"1620028800000"}}`), &data)
+	info := fundingInfo(data)
+	if info.Symbol != "BTC-USDT" || info.Rate != 0.0001 || info.PredictedRate != 0.0003 || info.NextFundingTime != 1620000000000 {
+		t.Fatalf("unexpected funding info %+v", info)
+	}
+
+	json.Unmarshal([]byte(`{"status":"ok","data":{"total_page":1,"current_page":1,"data":[
+		{"contract_code":"BTC-USDT","funding_rate":"0.0001","realized_rate":"0.0001","funding_time":"1619971200000"},
+		{"contract_code":"BTC-USDT","funding_rate":"0.0002","realized_rate":"0.0002","funding_time":"1620000000000"}]}}`), &data)
+	rates := fundingRates(data)
+	if len(rates) != 2 || rates[0].Rate != 0.0002 || rates[0].Time != 1620000000000 || rates[1].Symbol != "BTC-USDT" {
+		t.Fatalf("unexpected funding rates %+v", rates)
+	}
+}
+
+func TestSwapTriggerParams(t *testing.T) {
+	order, err := swapOrder(&goex.PlaceOrder{Side: goex.SELL, TradeType: goex.STOP_LIMIT, Amount: "2", Price: "89", StopPrice: "90", ReduceOnly: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	param, kind := swapTriggerParams("BTC-USD", order, 5)
+	if kind != "trigger_order" || param["trigger_type"] != "le" || param["offset"] != goex.OFFSET_CLOSE ||
+		param["order_price_type"] != "limit" || param["order_price"] != 89.0 || param["volume"] != int64(2) {
+		t.Fatalf("unexpected trigger params %v", param)
+	}
+	order, err = swapOrder(&goex.PlaceOrder{Side: goex.BUY, TradeType: goex.TRAILING_STOP, Amount: "1", CallbackRate: "2", StopPrice: "100"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	param, kind = swapTriggerParams("BTC-USD", order, 0)
+	if kind != "track_order" || param["callback_rate"] != 0.02 || param["active_price"] != 100.0 {
+		t.Fatalf("unexpected track params %v", param)
+	}
+	if _, err := swapOrder(&goex.PlaceOrder{TradeType: goex.TRAILING_STOP, Amount: "1", CallbackRate: "2"}); err == nil {
+		t.Fatal("expected error of trailing stop without activation price")
+	}
+}
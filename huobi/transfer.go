@@ -0,0 +1,191 @@
+package huobi
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+
+	goex "github.com/primitivelab/goexchange"
+)
+
+// transferAccounts huobi account names of /v2/account/transfer
+var transferAccounts = map[string]string{
+	goex.ACCOUNT_SPOT:      "spot",
+	goex.ACCOUNT_SWAP_USDT: "linear-swap",
+	goex.ACCOUNT_SWAP_COIN: "swap",
+}
+
+// ledgerAccounts account of huobi ledger transfer type prefix
+var ledgerAccounts = map[string]string{
+	"linear-swap":  goex.ACCOUNT_SWAP_USDT,
+	"swap":         goex.ACCOUNT_SWAP_COIN,
+	"futures":      goex.ACCOUNT_FUTURES,
+	"margin":       goex.ACCOUNT_MARGIN,
+	"cross-margin": goex.ACCOUNT_MARGIN,
+}
+
+// TransferAsset transfer between spot and other accounts, one side must be
+// spot. Symbol is the isolated margin symbol like btcusdt, empty for cross
+// margin, or the usdt swap margin account like BTC-USDT, default USDT cross
+func (spot *Spot) TransferAsset(request *goex.TransferRequest) interface{} {
+	if request.From != goex.ACCOUNT_SPOT && request.To != goex.ACCOUNT_SPOT {
+		return goex.ReturnAPIError(goex.TransferAccountError)
+	}
+	other := request.To
+	if other == goex.ACCOUNT_SPOT {
+		other = request.From
+	}
+
+	params := &url.Values{}
+	params.Set("currency", strings.ToLower(request.Coin))
+	params.Set("amount", request.Amount)
+	var path string
+	switch other {
+	case goex.ACCOUNT_SWAP_USDT, goex.ACCOUNT_SWAP_COIN:
+		path = "/v2/account/transfer"
+		params.Set("from", transferAccounts[request.From])
+		params.Set("to", transferAccounts[request.To])
+		if other == goex.ACCOUNT_SWAP_USDT {
+			params.Set("margin-account", "USDT")
+			if request.Symbol != "" {
+				params.Set("margin-account", strings.ToUpper(request.Symbol))
+			}
+		}
+	case goex.ACCOUNT_FUTURES:
+		path = "/v1/futures/transfer"
+		params.Set("type", "pro-to-futures")
+		if request.From == goex.ACCOUNT_FUTURES {
+			params.Set("type", "futures-to-pro")
+		}
+	case goex.ACCOUNT_MARGIN:
+		direction := "in"
+		if request.From == goex.ACCOUNT_MARGIN {
+			direction = "out"
+		}
+		path = "/v1/cross-margin/transfer-" + direction
+		if request.Symbol != "" {
+			path = "/v1/dw/transfer-" + direction + "/margin"
+			params.Set("symbol", strings.ToLower(request.Symbol))
+		}
+	default:
+		return goex.ReturnAPIError(goex.TransferAccountError)
+	}
+	for key, value := range request.Options {
+		params.Set(key, value)
+	}
+
+	result := spot.httpPost(path, params, true)
+	if result["code"] != 0 {
+		return result
+	}
+	data, _ := result["data"].(map[string]interface{})
+	result["data"] = goex.ToString(data["data"])
+	return result
+}
+
+// GetTransferHistory transfer records of spot account ledger, from or to
+// empty for any account, options startTime, endTime and fromId
+func (spot *Spot) GetTransferHistory(coin, from, to string, size int, options map[string]string) interface{} {
+	params := &url.Values{}
+	params.Set("accountId", spot.accountId)
+	params.Set("transactTypes", "transfer")
+	if coin != "" {
+		params.Set("currency", strings.ToLower(coin))
+	}
+	if size != 0 {
+		params.Set("limit", strconv.Itoa(size))
+	}
+	for _, key := range []string{"startTime", "endTime", "fromId"} {
+		if value, ok := options[key]; ok {
+			params.Set(key, value)
+		}
+	}
+
+	result := spot.httpGet("/v2/account/ledger", params, true)
+	if result["code"] != 0 {
+		return result
+	}
+
+	data, _ := result["data"].(map[string]interface{})
+	list, _ := data["data"].([]interface{})
+	transfers := make([]goex.AccountTransfer, 0, len(list))
+	for _, item := range list {
+		record, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		transfer := goex.AccountTransfer{
+			Id:     goex.ToString(record["transactId"]),
+			Coin:   goex.ToString(record["currency"]),
+			From:   goex.ACCOUNT_SPOT,
+			To:     goex.ACCOUNT_SPOT,
+			Amount: goex.ToFloat(record["transactAmt"]),
+			Status: goex.TRANSFER_STATUS_SUCCESS,
+			Time:   goex.ParseTimestamp(record["transactTime"]),
+		}
+		// transfer type is like margin-transfer-in, in means from spot to that account
+		other := goex.ACCOUNT_SPOT
+		transferType := goex.ToString(record["transferType"])
+		if index := strings.Index(transferType, "-transfer-"); index > 0 {
+			if account, ok := ledgerAccounts[transferType[:index]]; ok {
+				other = account
+			}
+		}
+		if transfer.Amount < 0 {
+			transfer.Amount = -transfer.Amount
+			transfer.To = other
+		} else {
+			transfer.From = other
+		}
+		if (from != "" && transfer.From != from) || (to != "" && transfer.To != to) {
+			continue
+		}
+		transfers = append(transfers, transfer)
+	}
+	result["data"] = transfers
+	return result
+}
+
+// TransferIn move coin from spot account to usdt margined swap cross margin account
+func (swap *SwapUsdt) TransferIn(coin, amount string) interface{} {
+	return swap.transfer(coin, amount, goex.ACCOUNT_SPOT, goex.ACCOUNT_SWAP_USDT)
+}
+
+// TransferOut move coin from usdt margined swap cross margin account to spot account
+func (swap *SwapUsdt) TransferOut(coin, amount string) interface{} {
+	return swap.transfer(coin, amount, goex.ACCOUNT_SWAP_USDT, goex.ACCOUNT_SPOT)
+}
+
+// SetSpotEndpoint spot endpoint TransferIn and TransferOut are sent to,
+// default https://api.huobi.pro
+func (swap *SwapUsdt) SetSpotEndpoint(endpoint string) {
+	swap.spotURL = endpoint
+}
+
+// transfer account transfer is a spot api, so it is sent to the spot endpoint
+func (swap *SwapUsdt) transfer(coin, amount, from, to string) interface{} {
+	spot := New(swap.httpClient, swap.spotURL, swap.accessKey, swap.secretKey, swap.accountId)
+	return spot.TransferAsset(&goex.TransferRequest{Coin: coin, From: from, To: to, Amount: amount})
+}
+
+// TransferIn move coin from spot account to coin margined swap account
+func (swap *SwapCoin) TransferIn(coin, amount string) interface{} {
+	return swap.transfer(coin, amount, goex.ACCOUNT_SPOT, goex.ACCOUNT_SWAP_COIN)
+}
+
+// TransferOut move coin from coin margined swap account to spot account
+func (swap *SwapCoin) TransferOut(coin, amount string) interface{} {
+	return swap.transfer(coin, amount, goex.ACCOUNT_SWAP_COIN, goex.ACCOUNT_SPOT)
+}
+
+// SetSpotEndpoint spot endpoint TransferIn and TransferOut are sent to,
+// default https://api.huobi.pro
+func (swap *SwapCoin) SetSpotEndpoint(endpoint string) {
+	swap.spotURL = endpoint
+}
+
+// transfer account transfer is a spot api, so it is sent to the spot endpoint
+func (swap *SwapCoin) transfer(coin, amount, from, to string) interface{} {
+	spot := New(swap.httpClient, swap.spotURL, swap.accessKey, swap.secretKey, swap.accountId)
+	return spot.TransferAsset(&goex.TransferRequest{Coin: coin, From: from, To: to, Amount: amount})
+}
@@ -0,0 +1,129 @@
+package huobi
+
+import (
+	"net/url"
+
+	goex "github.com/primitivelab/goexchange"
+)
+
+// transferStatus huobi deposit and withdraw state
+var transferStatus = map[string]string{
+	"unknown":         goex.TRANSFER_STATUS_PENDING,
+	"confirming":      goex.TRANSFER_STATUS_PENDING,
+	"confirmed":       goex.TRANSFER_STATUS_SUCCESS,
+	"safe":            goex.TRANSFER_STATUS_SUCCESS,
+	"orphan":          goex.TRANSFER_STATUS_FAILED,
+	"submitted":       goex.TRANSFER_STATUS_PENDING,
+	"reexamine":       goex.TRANSFER_STATUS_PENDING,
+	"pass":            goex.TRANSFER_STATUS_PENDING,
+	"pre-transfer":    goex.TRANSFER_STATUS_PENDING,
+	"wallet-transfer": goex.TRANSFER_STATUS_PENDING,
+	"canceled":        goex.TRANSFER_STATUS_CANCELED,
+	"repealed":        goex.TRANSFER_STATUS_CANCELED,
+	"reject":          goex.TRANSFER_STATUS_FAILED,
+	"wallet-reject":   goex.TRANSFER_STATUS_FAILED,
+	"confirm-error":   goex.TRANSFER_STATUS_FAILED,
+}
+
+// GetDepositAddresses deposit addresses of coin, all chains when chain is empty
+func (spot *Spot) GetDepositAddresses(coin, chain string) interface{} {
+	result := spot.GetUserDepositAddress(coin, nil).(map[string]interface{})
+	if result["code"] != 0 {
+		return result
+	}
+
+	list, _ := result["data"].(map[string]interface{})["data"].([]interface{})
+	addresses := []goex.DepositAddress{}
+	for _, item := range list {
+		record, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		address := goex.DepositAddress{
+			Coin:    goex.ToString(record["currency"]),
+			Chain:   goex.ToString(record["chain"]),
+			Address: goex.ToString(record["address"]),
+			Tag:     goex.ToString(record["addressTag"]),
+		}
+		if chain == "" || address.Chain == chain {
+			addresses = append(addresses, address)
+		}
+	}
+	result["data"] = addresses
+	return result
+}
+
+// ApplyWithdraw user withdraw, huobi requires request fee
+func (spot *Spot) ApplyWithdraw(request *goex.WithdrawRequest) interface{} {
+	params := &url.Values{}
+	// typed fields are set last so that options can not override them
+	for key, value := range request.Options {
+		params.Set(key, value)
+	}
+	params.Set("address", request.Address)
+	params.Set("amount", request.Amount)
+	params.Set("currency", request.Coin)
+	if request.Fee != "" {
+		params.Set("fee", request.Fee)
+	}
+	if request.Tag != "" {
+		params.Set("addr-tag", request.Tag)
+	}
+	if request.Chain != "" {
+		params.Set("chain", request.Chain)
+	}
+	if request.ClientId != "" {
+		params.Set("client-order-id", request.ClientId)
+	}
+	result := spot.httpPost("/v1/dw/withdraw/api/create", params, true)
+	if result["code"] != 0 {
+		return result
+	}
+	result["data"] = goex.ToString(result["data"].(map[string]interface{})["data"])
+	return result
+}
+
+// GetDepositHistory user deposit record list, data: []goex.Transfer
+func (spot *Spot) GetDepositHistory(coin string, size int, options map[string]string) interface{} {
+	result := spot.GetUserDepositRecords(coin, size, options).(map[string]interface{})
+	if result["code"] != 0 {
+		return result
+	}
+	result["data"] = spot.parseTransfers(result["data"].(map[string]interface{})["data"], goex.TRANSFER_DEPOSIT)
+	return result
+}
+
+// GetWithdrawHistory user withdraw record list, data: []goex.Transfer
+func (spot *Spot) GetWithdrawHistory(coin string, size int, options map[string]string) interface{} {
+	result := spot.GetUserWithdrawRecords(coin, size, options).(map[string]interface{})
+	if result["code"] != 0 {
+		return result
+	}
+	result["data"] = spot.parseTransfers(result["data"].(map[string]interface{})["data"], goex.TRANSFER_WITHDRAW)
+	return result
+}
+
+func (spot *Spot) parseTransfers(data interface{}, transferType string) []goex.Transfer {
+	list, _ := data.([]interface{})
+	transfers := make([]goex.Transfer, 0, len(list))
+	for _, item := range list {
+		record, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		transfers = append(transfers, goex.Transfer{
+			Id:      goex.ToString(record["id"]),
+			TxId:    goex.ToString(record["tx-hash"]),
+			Type:    transferType,
+			Coin:    goex.ToString(record["currency"]),
+			Chain:   goex.ToString(record["chain"]),
+			Address: goex.ToString(record["address"]),
+			Tag:     goex.ToString(record["address-tag"]),
+			Amount:  goex.ToFloat(record["amount"]),
+			Fee:     goex.ToFloat(record["fee"]),
+			Status:  transferStatus[goex.ToString(record["state"])],
+			Time:    goex.ParseTimestamp(record["created-at"]),
+		})
+	}
+	return transfers
+}
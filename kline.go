@@ -0,0 +1,162 @@
+package goexchange
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// Kline typed kline bar, Time is open time in millisecond
+type Kline struct {
+	Time   int64
+	Open   float64
+	High   float64
+	Low    float64
+	Close  float64
+	Volume float64
+}
+
+// klinePeriodMillisecond kline period duration, month and year are approximate
+var klinePeriodMillisecond = map[int]int64{
+	KLINE_PERIOD_1MINUTE:  60 * 1000,
+	KLINE_PERIOD_3MINUTE:  3 * 60 * 1000,
+	KLINE_PERIOD_5MINUTE:  5 * 60 * 1000,
+	KLINE_PERIOD_15MINUTE: 15 * 60 * 1000,
+	KLINE_PERIOD_30MINUTE: 30 * 60 * 1000,
+	KLINE_PERIOD_60MINUTE: 60 * 60 * 1000,
+	KLINE_PERIOD_1HOUR:    60 * 60 * 1000,
+	KLINE_PERIOD_2HOUR:    2 * 60 * 60 * 1000,
+	KLINE_PERIOD_3HOUR:    3 * 60 * 60 * 1000,
+	KLINE_PERIOD_4HOUR:    4 * 60 * 60 * 1000,
+	KLINE_PERIOD_6HOUR:    6 * 60 * 60 * 1000,
+	KLINE_PERIOD_8HOUR:    8 * 60 * 60 * 1000,
+	KLINE_PERIOD_12HOUR:   12 * 60 * 60 * 1000,
+	KLINE_PERIOD_1DAY:     24 * 60 * 60 * 1000,
+	KLINE_PERIOD_3DAY:     3 * 24 * 60 * 60 * 1000,
+	KLINE_PERIOD_5DAY:     5 * 24 * 60 * 60 * 1000,
+	KLINE_PERIOD_7DAY:     7 * 24 * 60 * 60 * 1000,
+	KLINE_PERIOD_1WEEK:    7 * 24 * 60 * 60 * 1000,
+	KLINE_PERIOD_1MONTH:   30 * 24 * 60 * 60 * 1000,
+	KLINE_PERIOD_1YEAR:    365 * 24 * 60 * 60 * 1000,
+}
+
+// KlinePeriodMillisecond duration of kline period, 0 when period is unknown
+func KlinePeriodMillisecond(period int) int64 {
+	return klinePeriodMillisecond[period]
+}
+
+// klineArrayIndex index of time, open, high, low, close, volume in array klines
+var klineArrayIndex = map[string][6]int{
+	// [t, quote volume, close, high, low, open, base volume]
+	EXCHANGE_GATE: {0, 5, 3, 4, 2, 6},
+	// [t, open, close, high, low, volume, amount]
+	EXCHANGE_MCX: {0, 1, 3, 4, 2, 5},
+}
+
+// ParseKline parse GetKline response into typed klines sorted by time, array
+// klines are [time, open, high, low, close, volume, ...] except exchanges
+// listed in klineArrayIndex, object klines use open/high/low/close keys
+func ParseKline(exchange string, result interface{}) ([]Kline, error) {
+	data := result
+	if retData, ok := result.(map[string]interface{}); ok {
+		if _, isResponse := retData["code"]; isResponse {
+			var err error
+			data, err = ParseResponse(result)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	if object, ok := data.(map[string]interface{}); ok {
+		for _, key := range []string{"data", "candles", "result"} {
+			if list, ok := object[key].([]interface{}); ok {
+				data = list
+				break
+			}
+		}
+	}
+	list, ok := data.([]interface{})
+	if !ok {
+		return nil, errors.New("kline data is not a list")
+	}
+
+	index, ok := klineArrayIndex[exchange]
+	if !ok {
+		index = [6]int{0, 1, 2, 3, 4, 5}
+	}
+	klines := make([]Kline, 0, len(list))
+	for _, item := range list {
+		var kline Kline
+		switch bar := item.(type) {
+		case []interface{}:
+			if len(bar) <= maxIndex(index) {
+				continue
+			}
+			kline = Kline{
+				Time:   ParseTimestamp(bar[index[0]]),
+				Open:   ToFloat(bar[index[1]]),
+				High:   ToFloat(bar[index[2]]),
+				Low:    ToFloat(bar[index[3]]),
+				Close:  ToFloat(bar[index[4]]),
+				Volume: ToFloat(bar[index[5]]),
+			}
+		case map[string]interface{}:
+			kline = Kline{
+				Open:  ToFloat(bar["open"]),
+				High:  ToFloat(bar["high"]),
+				Low:   ToFloat(bar["low"]),
+				Close: ToFloat(bar["close"]),
+			}
+			for _, key := range []string{"id", "time", "date", "t", "ts", "timestamp"} {
+				if value, ok := bar[key]; ok {
+					kline.Time = ParseTimestamp(value)
+					break
+				}
+			}
+			for _, key := range []string{"amount", "volume", "vol", "v"} {
+				if value, ok := bar[key]; ok {
+					kline.Volume = ToFloat(value)
+					break
+				}
+			}
+		default:
+			continue
+		}
+		if kline.Close > 0 {
+			klines = append(klines, kline)
+		}
+	}
+	sort.SliceStable(klines, func(i, j int) bool { return klines[i].Time < klines[j].Time })
+	return klines, nil
+}
+
+// ParseTimestamp millisecond of second or millisecond number, numeric string,
+// RFC3339 string or "2006-01-02 15:04:05" UTC string
+func ParseTimestamp(value interface{}) int64 {
+	if text, ok := value.(string); ok {
+		if _, err := strconv.ParseFloat(text, 64); err != nil {
+			for _, layout := range []string{time.RFC3339Nano, "2006-01-02 15:04:05"} {
+				if t, err := time.Parse(layout, text); err == nil {
+					return t.UnixNano() / int64(time.Millisecond)
+				}
+			}
+			return 0
+		}
+	}
+	timestamp := int64(ToFloat(value))
+	if timestamp > 0 && timestamp < 100000000000 {
+		timestamp *= 1000
+	}
+	return timestamp
+}
+
+func maxIndex(index [6]int) int {
+	max := 0
+	for _, i := range index {
+		if i > max {
+			max = i
+		}
+	}
+	return max
+}
@@ -0,0 +1,38 @@
+package goexchange
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseKline(t *testing.T) {
+	bodies := map[string]string{
+		EXCHANGE_BINANCE: `[[1600000060000,"2","4","1","3","10",1600000119999],[1600000000000,"1","2","1","2","5",1600000059999]]`,
+		EXCHANGE_OKEX:    `[["2020-09-13T12:27:40.000Z","2","4","1","3","10"],["2020-09-13T12:26:40.000Z","1","2","1","2","5"]]`,
+		EXCHANGE_GATE:    `[["1600000060","30","3","4","1","2","10"],["1600000000","10","2","2","1","1","5"]]`,
+		EXCHANGE_HUOBI:   `[{"id":1600000060,"open":2,"close":3,"low":1,"high":4,"amount":10,"vol":30},{"id":1600000000,"open":1,"close":2,"low":1,"high":2,"amount":5,"vol":10}]`,
+	}
+	for exchange, body := range bodies {
+		var data interface{}
+		json.Unmarshal([]byte(body), &data)
+		klines, err := ParseKline(exchange, map[string]interface{}{"code": 0, "data": data})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(klines) != 2 || klines[0].Time > klines[1].Time {
+			t.Fatalf("%s klines must be sorted by time: %v", exchange, klines)
+		}
+		last := klines[1]
+		if last.Open != 2 || last.High != 4 || last.Low != 1 || last.Close != 3 || last.Volume != 10 {
+			t.Fatalf("unexpected %s kline: %v", exchange, last)
+		}
+	}
+}
+
+func TestParseTimestamp(t *testing.T) {
+	for _, value := range []interface{}{1600000000, "1600000000", 1600000000000.0, "2020-09-13T12:26:40Z", "2020-09-13 12:26:40"} {
+		if ParseTimestamp(value) != 1600000000000 {
+			t.Fatalf("unexpected timestamp of %v: %d", value, ParseTimestamp(value))
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package ledger
+
+import (
+	"encoding/csv"
+	"io"
+	"math"
+	"time"
+
+	goex "github.com/primitivelab/goexchange"
+)
+
+// csvHeader columns of WriteCSV
+var csvHeader = []string{
+	"time", "symbol", "strategy", "fill_id", "order_id", "side", "price", "amount",
+	"fee", "fee_coin", "fee_quote", "realized_pnl", "position", "avg_cost",
+}
+
+// WriteCSV write entries as csv with a header line, times are RFC3339 in UTC
+// and values are in the quote coin of the symbol
+func (ledger *Ledger) WriteCSV(writer io.Writer) error {
+	out := csv.NewWriter(writer)
+	if err := out.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, entry := range ledger.Entries() {
+		record := []string{
+			time.Unix(0, entry.Time*int64(time.Millisecond)).UTC().Format(time.RFC3339),
+			entry.Symbol.ToSymbol("_"),
+			entry.Strategy,
+			entry.FillId,
+			entry.OrderId,
+			entry.Side.String(),
+			goex.FloatToString(entry.Price),
+			goex.FloatToString(entry.Amount),
+			goex.FloatToString(entry.Fee),
+			entry.FeeCoin,
+			formatValue(entry.FeeQuote),
+			formatValue(entry.Realized),
+			formatValue(entry.Position),
+			formatValue(entry.AvgCost),
+		}
+		if err := out.Write(record); err != nil {
+			return err
+		}
+	}
+	out.Flush()
+	return out.Error()
+}
+
+// formatValue format computed value with float noise rounded off
+func formatValue(value float64) string {
+	return goex.FloatToString(math.Round(value*1e10) / 1e10)
+}
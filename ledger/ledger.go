@@ -0,0 +1,414 @@
+package ledger
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	goex "github.com/primitivelab/goexchange"
+)
+
+// lot matching methods
+const (
+	METHOD_FIFO    = "fifo"
+	METHOD_LIFO    = "lifo"
+	METHOD_AVERAGE = "average"
+)
+
+// FillAPI fill history method of spot, margin and swap adapters
+type FillAPI interface {
+	GetUserTradeOrders(symbol goex.Symbol, size int, options map[string]string) interface{}
+}
+
+// TickerAPI ticker method used to price fee coins and mark positions
+type TickerAPI interface {
+	GetTicker(symbol goex.Symbol) interface{}
+}
+
+// Config ledger config
+type Config struct {
+	// lot matching of closing fills, default METHOD_FIFO
+	Method string
+	// strategy of fill, like the strategy tag of its client order id, default
+	// every fill belongs to strategy ""
+	Strategy func(fill *goex.Fill) string
+	// prices fee coins other than base and quote coin, like bnb, ht or gt, at
+	// their current price, and marks positions
+	Pricer TickerAPI
+	// fixed prices of fee coins in quote coin by lower case coin, used before
+	// Pricer
+	FeePrices map[string]float64
+}
+
+// lot open amount at Price per unit, fees included
+type lot struct {
+	Amount float64
+	Price  float64
+}
+
+// Position lots of one symbol and strategy, amounts are in base coin and
+// values in quote coin. Amount is negative for short positions, AvgCost and
+// Realized include fees and Fees is the value of fees paid, fees that could
+// not be valued are left out and listed in UnpricedFees by coin
+type Position struct {
+	Symbol       goex.Symbol
+	Strategy     string
+	Amount       float64
+	AvgCost      float64
+	Realized     float64
+	Fees         float64
+	UnpricedFees map[string]float64
+	// last price of Pricer and pnl of Amount at it, 0 without Pricer
+	Mark       float64
+	Unrealized float64
+
+	lots     []lot
+	lastTime int64
+}
+
+// Entry one applied fill, Realized is the pnl it realized and Position and
+// AvgCost the position after it
+type Entry struct {
+	Time     int64
+	Symbol   goex.Symbol
+	Strategy string
+	FillId   string
+	OrderId  string
+	Side     goex.TradeSide
+	Price    float64
+	Amount   float64
+	Fee      float64
+	FeeCoin  string
+	// fee value in quote coin, 0 when it could not be valued
+	FeeQuote float64
+	Realized float64
+	Position float64
+	AvgCost  float64
+}
+
+type positionKey struct {
+	symbol   goex.Symbol
+	strategy string
+}
+
+// Ledger realized and unrealized pnl of fills by symbol and strategy
+type Ledger struct {
+	config    Config
+	mutex     sync.Mutex
+	positions map[positionKey]*Position
+	entries   []Entry
+	// applied fill ids by symbol
+	applied map[string]bool
+	// fee coin prices by coin and quote coin
+	prices map[string]float64
+}
+
+// New new instance
+func New(config *Config) *Ledger {
+	ledger := &Ledger{positions: map[positionKey]*Position{}, applied: map[string]bool{}, prices: map[string]float64{}}
+	if config != nil {
+		ledger.config = *config
+	}
+	if ledger.config.Method == "" {
+		ledger.config.Method = METHOD_FIFO
+	}
+	return ledger
+}
+
+// Load fetch fills of symbol from api and add them, number of fills added
+func (ledger *Ledger) Load(api FillAPI, symbol goex.Symbol, size int, options map[string]string) (int, error) {
+	fills, err := goex.ParseFills(api.GetUserTradeOrders(symbol, size, options))
+	if err != nil {
+		return 0, err
+	}
+	return ledger.Add(symbol, fills...)
+}
+
+// Add apply fills of symbol in time order, fills applied before are skipped
+// so that overlapping fill pages can be added, number of fills added, error
+// on a fill older than the applied fills of its position
+func (ledger *Ledger) Add(symbol goex.Symbol, fills ...goex.Fill) (int, error) {
+	switch ledger.config.Method {
+	case METHOD_FIFO, METHOD_LIFO, METHOD_AVERAGE:
+	default:
+		return 0, errors.New("unknown lot method " + ledger.config.Method)
+	}
+	sorted := append([]goex.Fill{}, fills...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Time < sorted[j].Time })
+	prices := ledger.feePrices(symbol, sorted)
+
+	ledger.mutex.Lock()
+	defer ledger.mutex.Unlock()
+	added := 0
+	for i := range sorted {
+		fill := &sorted[i]
+		id := symbol.ToSymbol("_") + "|" + fill.Id
+		if fill.Id != "" && ledger.applied[id] {
+			continue
+		}
+		if err := ledger.apply(symbol, fill, prices); err != nil {
+			return added, fmt.Errorf("fill %s: %v", fill.Id, err)
+		}
+		if fill.Id != "" {
+			ledger.applied[id] = true
+		}
+		added++
+	}
+	return added, nil
+}
+
+// Position copy of position of symbol and strategy, marked when Pricer is set
+func (ledger *Ledger) Position(symbol goex.Symbol, strategy string) (Position, bool) {
+	ledger.mutex.Lock()
+	position, ok := ledger.positions[positionKey{symbol, strategy}]
+	if !ok {
+		ledger.mutex.Unlock()
+		return Position{}, false
+	}
+	copied := copyPosition(position)
+	ledger.mutex.Unlock()
+	ledger.mark(&copied)
+	return copied, true
+}
+
+// Positions copies of all positions sorted by symbol and strategy, marked
+// when Pricer is set
+func (ledger *Ledger) Positions() []Position {
+	ledger.mutex.Lock()
+	positions := make([]Position, 0, len(ledger.positions))
+	for _, position := range ledger.positions {
+		positions = append(positions, copyPosition(position))
+	}
+	ledger.mutex.Unlock()
+	sort.Slice(positions, func(i, j int) bool {
+		left, right := positions[i].Symbol.ToSymbol("_"), positions[j].Symbol.ToSymbol("_")
+		if left != right {
+			return left < right
+		}
+		return positions[i].Strategy < positions[j].Strategy
+	})
+	marks := map[goex.Symbol]float64{}
+	for i := range positions {
+		if mark, ok := marks[positions[i].Symbol]; ok {
+			positions[i].Mark = mark
+			positions[i].Unrealized = (mark - positions[i].AvgCost) * positions[i].Amount
+			continue
+		}
+		ledger.mark(&positions[i])
+		marks[positions[i].Symbol] = positions[i].Mark
+	}
+	return positions
+}
+
+// Entries applied fills in the order they were applied
+func (ledger *Ledger) Entries() []Entry {
+	ledger.mutex.Lock()
+	defer ledger.mutex.Unlock()
+	return append([]Entry{}, ledger.entries...)
+}
+
+// ExpectedPnl expected fee in quote coin of trading amount at price with
+// rate, eg: of GetUserCommissionRate, and the pnl the trade would realize
+// on the position of symbol and strategy, the ledger is not changed, both are
+// 0 when amount is not positive
+func (ledger *Ledger) ExpectedPnl(symbol goex.Symbol, strategy string, side goex.TradeSide, price, amount float64, rate *goex.CommissionRate, maker bool) (fee, realized float64) {
+	if amount <= 0 {
+		return 0, 0
+	}
+	fee = rate.Fee(price, amount, maker)
+	ledger.mutex.Lock()
+	defer ledger.mutex.Unlock()
+	position := &Position{}
+	if current, ok := ledger.positions[positionKey{symbol, strategy}]; ok {
+		copied := copyPosition(current)
+		position = &copied
+	}
+	unit := (price*amount + fee) / amount
+	if side == goex.SELL {
+		unit = (price*amount - fee) / amount
+	}
+	return fee, ledger.match(position, side, amount, unit)
+}
+
+// apply fill to its position, prices are the fee coin prices of feePrices,
+// mutex is held
+func (ledger *Ledger) apply(symbol goex.Symbol, fill *goex.Fill, prices map[string]float64) error {
+	if fill.Side != goex.BUY && fill.Side != goex.SELL {
+		return errors.New("side must be buy or sell")
+	}
+	if fill.Amount <= 0 || fill.Price <= 0 {
+		return errors.New("amount and price must be positive")
+	}
+	strategy := ""
+	if ledger.config.Strategy != nil {
+		strategy = ledger.config.Strategy(fill)
+	}
+	key := positionKey{symbol, strategy}
+	position, ok := ledger.positions[key]
+	if !ok {
+		position = &Position{Symbol: symbol, Strategy: strategy, UnpricedFees: map[string]float64{}}
+	}
+	if fill.Time < position.lastTime {
+		return errors.New("fill is older than the applied fills of its position")
+	}
+
+	base, quote := strings.ToLower(symbol.CoinFrom), strings.ToLower(symbol.CoinTo)
+	feeCoin := fill.FeeCoin
+	if feeCoin == "" {
+		feeCoin = quote
+	}
+	// base coin fees change the amount received or spent, other fees the
+	// cash paid or received
+	amount, cash, feeQuote := fill.Amount, fill.Price*fill.Amount, 0.0
+	switch feeCoin {
+	case base:
+		feeQuote = fill.Fee * fill.Price
+		amount += float64(-fill.Side) * fill.Fee
+	case quote:
+		feeQuote = fill.Fee
+		cash += float64(fill.Side) * fill.Fee
+	default:
+		if price := prices[feeCoin]; price > 0 {
+			feeQuote = fill.Fee * price
+			cash += float64(fill.Side) * feeQuote
+		} else if fill.Fee > 0 {
+			position.UnpricedFees[feeCoin] += fill.Fee
+		}
+	}
+	if amount <= 0 {
+		return errors.New("fee takes the whole amount")
+	}
+
+	realized := ledger.match(position, fill.Side, amount, cash/amount)
+	position.Realized += realized
+	position.Fees += feeQuote
+	position.lastTime = fill.Time
+	ledger.positions[key] = position
+	ledger.entries = append(ledger.entries, Entry{
+		Time:     fill.Time,
+		Symbol:   symbol,
+		Strategy: strategy,
+		FillId:   fill.Id,
+		OrderId:  fill.OrderId,
+		Side:     fill.Side,
+		Price:    fill.Price,
+		Amount:   fill.Amount,
+		Fee:      fill.Fee,
+		FeeCoin:  feeCoin,
+		FeeQuote: feeQuote,
+		Realized: realized,
+		Position: position.Amount,
+		AvgCost:  position.AvgCost,
+	})
+	return nil
+}
+
+// match close opposite lots of position with amount at unit price, open a lot
+// of the rest and return the realized pnl
+func (ledger *Ledger) match(position *Position, side goex.TradeSide, amount, unit float64) float64 {
+	realized := 0.0
+	direction := float64(side)
+	for amount > 1e-12 && len(position.lots) > 0 && position.lots[0].Amount*direction < 0 {
+		index := 0
+		if ledger.config.Method == METHOD_LIFO {
+			index = len(position.lots) - 1
+		}
+		open := &position.lots[index]
+		take := amount
+		if take > -open.Amount*direction {
+			take = -open.Amount * direction
+		}
+		// selling closes long lots and buying closes short lots
+		realized += (unit - open.Price) * take * -direction
+		open.Amount += take * direction
+		amount -= take
+		if open.Amount*direction > -1e-12 {
+			position.lots = append(position.lots[:index], position.lots[index+1:]...)
+		}
+	}
+	if amount > 1e-12 {
+		if ledger.config.Method == METHOD_AVERAGE && len(position.lots) > 0 {
+			open := &position.lots[0]
+			total := open.Amount + amount*direction
+			open.Price = (open.Price*open.Amount + unit*amount*direction) / total
+			open.Amount = total
+		} else {
+			position.lots = append(position.lots, lot{Amount: amount * direction, Price: unit})
+		}
+	}
+
+	position.Amount, position.AvgCost = 0, 0
+	value := 0.0
+	for _, open := range position.lots {
+		position.Amount += open.Amount
+		value += open.Amount * open.Price
+	}
+	if position.Amount != 0 {
+		position.AvgCost = value / position.Amount
+	}
+	return realized
+}
+
+// feePrices prices in quote coin of the fee coins of fills other than base
+// and quote coin, they are looked up before the mutex is taken
+func (ledger *Ledger) feePrices(symbol goex.Symbol, fills []goex.Fill) map[string]float64 {
+	base, quote := strings.ToLower(symbol.CoinFrom), strings.ToLower(symbol.CoinTo)
+	prices := map[string]float64{}
+	for _, fill := range fills {
+		coin := fill.FeeCoin
+		if _, ok := prices[coin]; ok || coin == "" || coin == base || coin == quote {
+			continue
+		}
+		prices[coin] = ledger.feePrice(coin, quote)
+	}
+	return prices
+}
+
+// feePrice price of fee coin in quote coin from FeePrices or Pricer, 0 when
+// it can not be priced, prices of Pricer are cached once they are found
+func (ledger *Ledger) feePrice(coin, quote string) float64 {
+	if price, ok := ledger.config.FeePrices[coin]; ok {
+		return price
+	}
+	key := coin + "/" + quote
+	ledger.mutex.Lock()
+	price, ok := ledger.prices[key]
+	ledger.mutex.Unlock()
+	if ok || ledger.config.Pricer == nil {
+		return price
+	}
+	ticker, err := goex.ParseTicker(ledger.config.Pricer.GetTicker(goex.NewSymbol(coin, quote)))
+	if err != nil || ticker.Last <= 0 {
+		return 0
+	}
+	ledger.mutex.Lock()
+	ledger.prices[key] = ticker.Last
+	ledger.mutex.Unlock()
+	return ticker.Last
+}
+
+// mark set Mark and Unrealized of position from Pricer
+func (ledger *Ledger) mark(position *Position) {
+	if ledger.config.Pricer == nil {
+		return
+	}
+	ticker, err := goex.ParseTicker(ledger.config.Pricer.GetTicker(position.Symbol))
+	if err != nil || ticker.Last <= 0 {
+		return
+	}
+	position.Mark = ticker.Last
+	position.Unrealized = (ticker.Last - position.AvgCost) * position.Amount
+}
+
+// copyPosition copy of position with its own lots and unpriced fees
+func copyPosition(position *Position) Position {
+	copied := *position
+	copied.lots = append([]lot{}, position.lots...)
+	copied.UnpricedFees = map[string]float64{}
+	for coin, fee := range position.UnpricedFees {
+		copied.UnpricedFees[coin] = fee
+	}
+	return copied
+}
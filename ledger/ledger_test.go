@@ -0,0 +1,201 @@
+package ledger
+
+import (
+	"bytes"
+	"encoding/csv"
+	"math"
+	"strings"
+	"testing"
+
+	goex "github.com/primitivelab/goexchange"
+	"github.com/primitivelab/goexchange/paper"
+)
+
+var btcUsdt = goex.NewSymbol("btc", "usdt")
+
+func near(left, right float64) bool {
+	return math.Abs(left-right) < 1e-9
+}
+
+func getFills() []goex.Fill {
+	return []goex.Fill{
+		{Id: "1", Side: goex.BUY, Price: 100, Amount: 1, Time: 1},
+		{Id: "2", Side: goex.BUY, Price: 120, Amount: 1, Time: 2},
+		{Id: "3", Side: goex.SELL, Price: 130, Amount: 1, Time: 3},
+	}
+}
+
+func setPrice(spot *paper.Spot, symbol goex.Symbol, price float64) {
+	spot.SetDepth(symbol, &goex.Depth{
+		Bids: []goex.DepthRecord{{Price: price * 0.99, Amount: 10}},
+		Asks: []goex.DepthRecord{{Price: price * 1.01, Amount: 10}},
+	})
+}
+
+func TestLedger_Methods(t *testing.T) {
+	expected := map[string][2]float64{
+		METHOD_FIFO:    {30, 120},
+		METHOD_LIFO:    {10, 100},
+		METHOD_AVERAGE: {20, 110},
+	}
+	for method, values := range expected {
+		ledger := New(&Config{Method: method})
+		if added, err := ledger.Add(btcUsdt, getFills()...); err != nil || added != 3 {
+			t.Fatalf("%s: added %d, %v", method, added, err)
+		}
+		position, ok := ledger.Position(btcUsdt, "")
+		if !ok || !near(position.Realized, values[0]) || !near(position.AvgCost, values[1]) || position.Amount != 1 {
+			t.Fatalf("%s: unexpected position %+v", method, position)
+		}
+	}
+	if _, err := New(&Config{Method: "hifo"}).Add(btcUsdt, getFills()...); err == nil {
+		t.Fatal("expected error of unknown method")
+	}
+}
+
+func TestLedger_Fees(t *testing.T) {
+	ledger := New(&Config{FeePrices: map[string]float64{"bnb": 20}})
+	ledger.Add(btcUsdt,
+		goex.Fill{Id: "1", Side: goex.BUY, Price: 100, Amount: 1, Fee: 0.1, FeeCoin: "usdt", Time: 1},
+		goex.Fill{Id: "2", Side: goex.SELL, Price: 110, Amount: 1, Fee: 0.11, FeeCoin: "usdt", Time: 2},
+		goex.Fill{Id: "3", Side: goex.BUY, Price: 100, Amount: 1, Fee: 0.005, FeeCoin: "bnb", Time: 3},
+		goex.Fill{Id: "4", Side: goex.SELL, Price: 100, Amount: 0.5, Fee: 1, FeeCoin: "xyz", Time: 4},
+	)
+	position, _ := ledger.Position(btcUsdt, "")
+	if !near(position.Realized, 9.79-0.05) || !near(position.Fees, 0.31) || position.UnpricedFees["xyz"] != 1 {
+		t.Fatalf("unexpected position %+v", position)
+	}
+	if !near(position.Amount, 0.5) || !near(position.AvgCost, 100.1) {
+		t.Fatalf("unexpected open lots %+v", position)
+	}
+
+	// base coin fee reduces the amount bought
+	ledger = New(nil)
+	ledger.Add(btcUsdt,
+		goex.Fill{Id: "1", Side: goex.BUY, Price: 100, Amount: 1, Fee: 0.01, FeeCoin: "btc", Time: 1},
+		goex.Fill{Id: "2", Side: goex.SELL, Price: 110, Amount: 0.99, Time: 2},
+	)
+	position, _ = ledger.Position(btcUsdt, "")
+	if !near(position.Realized, 108.9-100) || !near(position.Fees, 1) || position.Amount != 0 {
+		t.Fatalf("unexpected position %+v", position)
+	}
+}
+
+// pricer fails until ok and reads the ledger to show it is not locked
+type pricer struct {
+	ledger *Ledger
+	ok     bool
+}
+
+func (api *pricer) GetTicker(symbol goex.Symbol) interface{} {
+	api.ledger.Entries()
+	if !api.ok {
+		return goex.ReturnAPIError(goex.ExchangeError)
+	}
+	return goex.ReturnAPIData(map[string]interface{}{"last": "20"})
+}
+
+func TestLedger_FeePricer(t *testing.T) {
+	api := &pricer{}
+	ledger := New(&Config{Pricer: api})
+	api.ledger = ledger
+	ledger.Add(btcUsdt, goex.Fill{Id: "1", Side: goex.BUY, Price: 100, Amount: 1, Fee: 0.01, FeeCoin: "bnb", Time: 1})
+	api.ok = true
+	ledger.Add(btcUsdt, goex.Fill{Id: "2", Side: goex.BUY, Price: 100, Amount: 1, Fee: 0.01, FeeCoin: "bnb", Time: 2})
+	position, _ := ledger.Position(btcUsdt, "")
+	if position.UnpricedFees["bnb"] != 0.01 || !near(position.Fees, 0.2) {
+		t.Fatalf("failed fee price should not be cached: %+v", position)
+	}
+	if fee, realized := ledger.ExpectedPnl(btcUsdt, "", goex.SELL, 110, 0, &goex.CommissionRate{Taker: 0.001}, false); fee != 0 || realized != 0 {
+		t.Fatalf("unexpected expected fee %v and pnl %v of no amount", fee, realized)
+	}
+}
+
+func TestLedger_Add(t *testing.T) {
+	ledger := New(&Config{Strategy: func(fill *goex.Fill) string {
+		return strings.SplitN(fill.ClientOrderId, "-", 2)[0]
+	}})
+	ledger.Add(btcUsdt,
+		goex.Fill{Id: "1", ClientOrderId: "grid-1", Side: goex.SELL, Price: 100, Amount: 2, Time: 1},
+		goex.Fill{Id: "2", ClientOrderId: "mm-1", Side: goex.BUY, Price: 100, Amount: 1, Time: 2},
+	)
+	if added, err := ledger.Add(btcUsdt,
+		goex.Fill{Id: "2", ClientOrderId: "mm-1", Side: goex.BUY, Price: 100, Amount: 1, Time: 2},
+		goex.Fill{Id: "3", ClientOrderId: "grid-2", Side: goex.BUY, Price: 90, Amount: 3, Time: 3},
+	); err != nil || added != 1 {
+		t.Fatalf("added %d, %v", added, err)
+	}
+	grid, _ := ledger.Position(btcUsdt, "grid")
+	if grid.Realized != 20 || grid.Amount != 1 || grid.AvgCost != 90 {
+		t.Fatalf("unexpected grid position %+v", grid)
+	}
+	if mm, _ := ledger.Position(btcUsdt, "mm"); mm.Amount != 1 || mm.Realized != 0 {
+		t.Fatalf("unexpected mm position %+v", mm)
+	}
+	if positions := ledger.Positions(); len(positions) != 2 || positions[0].Strategy != "grid" {
+		t.Fatalf("unexpected positions %+v", positions)
+	}
+	if _, err := ledger.Add(btcUsdt, goex.Fill{Id: "4", ClientOrderId: "grid-3", Side: goex.SELL, Price: 90, Amount: 1, Time: 2}); err == nil {
+		t.Fatal("expected error of out of order fill")
+	}
+	if len(ledger.Entries()) != 3 {
+		t.Fatalf("unexpected entries %+v", ledger.Entries())
+	}
+}
+
+func TestLedger_Load(t *testing.T) {
+	var _ FillAPI = &paper.Swap{}
+	spot := paper.NewSpot(nil, &paper.Config{
+		Exchange: goex.EXCHANGE_BINANCE,
+		Balances: map[string]float64{"usdt": 1000},
+		Fees:     &paper.FeeSchedule{Default: paper.Fee{Maker: 0.001, Taker: 0.001}},
+	})
+	setPrice(spot, btcUsdt, 100)
+	spot.PlaceOrder(&goex.PlaceOrder{Symbol: btcUsdt, Side: goex.BUY, TradeType: goex.LIMIT, Price: "110", Amount: "2"})
+	spot.PlaceOrder(&goex.PlaceOrder{Symbol: btcUsdt, Side: goex.SELL, TradeType: goex.LIMIT, Price: "90", Amount: "1"})
+
+	ledger := New(&Config{Pricer: spot})
+	if added, err := ledger.Load(spot, btcUsdt, 100, nil); err != nil || added != 2 {
+		t.Fatalf("added %d, %v", added, err)
+	}
+	if added, _ := ledger.Load(spot, btcUsdt, 100, nil); added != 0 {
+		t.Fatalf("fills were added twice")
+	}
+	position, _ := ledger.Position(btcUsdt, "")
+	unit := 101 / 0.999
+	if !near(position.Realized, 99*(1-0.001)-unit) || !near(position.Amount, 0.998) || !near(position.AvgCost, unit) {
+		t.Fatalf("unexpected position %+v", position)
+	}
+	if position.Mark != 100 || !near(position.Unrealized, (100-unit)*0.998) {
+		t.Fatalf("unexpected marked position %+v", position)
+	}
+
+	fee, realized := ledger.ExpectedPnl(btcUsdt, "", goex.SELL, 110, 0.5, &goex.CommissionRate{Maker: 0.001, Taker: 0.002}, false)
+	if !near(fee, 0.11) || !near(realized, (110*0.5-0.11)-unit*0.5) {
+		t.Fatalf("unexpected expected fee %v and pnl %v", fee, realized)
+	}
+	if position, _ := ledger.Position(btcUsdt, ""); !near(position.Amount, 0.998) {
+		t.Fatalf("expected pnl changed the ledger %+v", position)
+	}
+}
+
+func TestLedger_WriteCSV(t *testing.T) {
+	ledger := New(nil)
+	ledger.Add(btcUsdt,
+		goex.Fill{Id: "1", OrderId: "10", Side: goex.BUY, Price: 100, Amount: 1, Fee: 0.1, FeeCoin: "usdt", Time: 1600000000000},
+		goex.Fill{Id: "2", OrderId: "11", Side: goex.SELL, Price: 110, Amount: 1, Fee: 0.11, FeeCoin: "usdt", Time: 1600000001000},
+	)
+	buffer := &bytes.Buffer{}
+	if err := ledger.WriteCSV(buffer); err != nil {
+		t.Fatal(err)
+	}
+	records, err := csv.NewReader(buffer).ReadAll()
+	if err != nil || len(records) != 3 || records[0][0] != "time" {
+		t.Fatalf("unexpected csv %v, %v", records, err)
+	}
+	last := records[2]
+	if last[0] != "2020-09-13T12:26:41Z" || last[1] != "btc_usdt" || last[3] != "2" || last[4] != "11" ||
+		last[9] != "usdt" || last[11] != "9.79" || last[12] != "0" {
+		t.Fatalf("unexpected csv record %v", last)
+	}
+}
@@ -0,0 +1,74 @@
+package goexchange
+
+import (
+	"errors"
+	"strconv"
+)
+
+// 持仓模式
+const (
+	// 单向持仓
+	POSITION_MODE_ONE_WAY = "one_way"
+	// 双向持仓
+	POSITION_MODE_HEDGE = "hedge"
+)
+
+// LeverageSetting leverage, margin type and position mode of a contract
+type LeverageSetting struct {
+	Symbol string
+	// leverage of one-way positions and long positions
+	Leverage int
+	// differs from Leverage only when the exchange sets leverage per side
+	ShortLeverage int
+	// MARGIN_CROSSED or MARGIN_ISOLATED
+	MarginType string
+	// POSITION_MODE_ONE_WAY or POSITION_MODE_HEDGE
+	PositionMode string
+}
+
+// LeverageBracket max leverage of positions up to NotionalCap, which is 0
+// for the last bracket, in quote coin or contracts as the exchange counts it
+type LeverageBracket struct {
+	NotionalCap float64
+	MaxLeverage int
+}
+
+// LeverageAPI leverage, margin type and position mode api of swap adapters,
+// leverage is checked against the brackets before it is sent
+type LeverageAPI interface {
+
+	// 杠杆设置, data: LeverageSetting
+	GetLeverage(symbol Symbol) interface{}
+	// 杠杆分层, data: []LeverageBracket
+	GetLeverageBrackets(symbol Symbol) interface{}
+	// 设置杠杆, 双向持仓时同时设置多空杠杆
+	SetLeverage(symbol Symbol, leverage int) interface{}
+	// 设置保证金模式, MARGIN_CROSSED 或 MARGIN_ISOLATED
+	SetMarginType(symbol Symbol, marginType string) interface{}
+	// 设置持仓模式, POSITION_MODE_ONE_WAY 或 POSITION_MODE_HEDGE
+	SetPositionMode(symbol Symbol, mode string) interface{}
+}
+
+// CheckLeverage error when leverage is below 1 or above the max leverage of brackets
+func CheckLeverage(leverage int, brackets []LeverageBracket) error {
+	if leverage < 1 {
+		return errors.New("leverage must be at least 1")
+	}
+	maxLeverage := 0
+	for _, bracket := range brackets {
+		if bracket.MaxLeverage > maxLeverage {
+			maxLeverage = bracket.MaxLeverage
+		}
+	}
+	if maxLeverage > 0 && leverage > maxLeverage {
+		return errors.New("leverage " + strconv.Itoa(leverage) + " is above max leverage " + strconv.Itoa(maxLeverage))
+	}
+	return nil
+}
+
+// InvalidLeverageSetting response of leverage setting that is rejected before sending
+func InvalidLeverageSetting(err error) map[string]interface{} {
+	retData := ReturnAPIError(LeverageSettingError).(map[string]interface{})
+	retData["error"] = err.Error()
+	return retData
+}
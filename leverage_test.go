@@ -0,0 +1,23 @@
+package goexchange
+
+import "testing"
+
+func TestCheckLeverage(t *testing.T) {
+	brackets := []LeverageBracket{{NotionalCap: 50000, MaxLeverage: 125}, {MaxLeverage: 20}}
+	for _, leverage := range []int{1, 20, 125} {
+		if err := CheckLeverage(leverage, brackets); err != nil {
+			t.Errorf("%d: %v", leverage, err)
+		}
+	}
+	for _, leverage := range []int{0, 126} {
+		if err := CheckLeverage(leverage, brackets); err == nil {
+			t.Errorf("%d should be rejected", leverage)
+		}
+	}
+	if err := CheckLeverage(200, nil); err != nil {
+		t.Errorf("leverage without brackets should pass, got %v", err)
+	}
+	if _, err := ParseResponse(InvalidLeverageSetting(CheckLeverage(0, nil))); err == nil {
+		t.Error("invalid setting should be an error response")
+	}
+}
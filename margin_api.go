@@ -0,0 +1,66 @@
+package goexchange
+
+// MarginBalance balance of coin in a margin account, Symbol is the isolated
+// margin symbol and empty for the cross margin account
+type MarginBalance struct {
+	Symbol   string
+	Coin     string
+	Free     float64
+	Frozen   float64
+	Borrowed float64
+	Interest float64
+}
+
+// NetAsset balance left after paying back loan and interest
+func (balance *MarginBalance) NetAsset() float64 {
+	return balance.Free + balance.Frozen - balance.Borrowed - balance.Interest
+}
+
+// MarginInterest interest charged on a loan, Rate is the daily rate
+type MarginInterest struct {
+	Symbol   string
+	Coin     string
+	Interest float64
+	Rate     float64
+	Time     int64
+}
+
+// MarginAPI margin account api interface, an instance trades the cross or
+// the isolated margin account, its order methods mirror SpotAPI
+type MarginAPI interface {
+
+	// exchange name
+	GetExchangeName() string
+	// 杠杆模式, MARGIN_CROSSED 或 MARGIN_ISOLATED
+	GetMarginType() string
+
+	// 杠杆账户余额, 逐仓为全部交易对的余额, data: []MarginBalance
+	GetUserBalance() interface{}
+	// 借币, symbol 为逐仓交易对, data: loan id
+	Borrow(symbol Symbol, coin, amount string) interface{}
+	// 还币, 先还利息再还本金, data: repay id
+	Repay(symbol Symbol, coin, amount string) interface{}
+	// 利息记录, 时间倒序, data: []MarginInterest
+	GetInterestHistory(symbol Symbol, coin string, size int, options map[string]string) interface{}
+
+	// 下单
+	PlaceOrder(order *PlaceOrder) interface{}
+	// 下限价单
+	PlaceLimitOrder(symbol Symbol, price string, amount string, side TradeSide, ClientOrderId string) interface{}
+	// 下市价单
+	PlaceMarketOrder(symbol Symbol, amount string, side TradeSide, ClientOrderId string) interface{}
+	// 批量下限价单
+	BatchPlaceLimitOrder(orders []LimitOrder) interface{}
+	// 撤单
+	CancelOrder(symbol Symbol, orderId, clientOrderId string) interface{}
+	// 批量撤单
+	BatchCancelOrder(symbol Symbol, orderIds, clientOrderIds string) interface{}
+	// 我的当前委托单
+	GetUserOpenTrustOrders(symbol Symbol, size int, options map[string]string) interface{}
+	// 委托单详情
+	GetUserOrderInfo(symbol Symbol, orderId, clientOrderId string) interface{}
+	// 我的成交单列表
+	GetUserTradeOrders(symbol Symbol, size int, options map[string]string) interface{}
+	// 我的委托单列表
+	GetUserTrustOrders(symbol Symbol, status string, size int, options map[string]string) interface{}
+}
@@ -29,6 +29,20 @@ type PlaceOrder struct {
 	Side            TradeSide
 	TradeType       string
 	TimeInForce     TimeInForce
+	// 合约开平方向, OFFSET_OPEN 或 OFFSET_CLOSE
+	Offset          string
+	// 交割合约类型, 如 CONTRACT_QUARTER
+	ContractType    string
+	// 触发价, 止损止盈单必填, 跟踪止损单为激活价
+	StopPrice       string
+	// 跟踪止损回调比例, 百分比, 如 "1" 为 1%
+	CallbackRate    string
+	// 只减仓
+	ReduceOnly      bool
+	// 触发后市价平掉全部仓位, 只用于合约市价止损止盈单, 不需要 Amount
+	ClosePosition   bool
+	// 市价单 Amount 的单位, MarketOrderByBase 或 MarketOrderByQuote, 不支持时下单失败
+	MarketMode      MarketOrderMode
 	options         map[string]string
 }
 
@@ -39,5 +53,9 @@ type LimitOrder struct {
 	Amount 	        string
 	Side            TradeSide
 	TimeInForce     TimeInForce
+	// 合约开平方向, OFFSET_OPEN 或 OFFSET_CLOSE
+	Offset          string
+	// 交割合约类型, 如 CONTRACT_QUARTER
+	ContractType    string
 	options         map[string]string
 }
@@ -0,0 +1,260 @@
+package monitor
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	goex "github.com/primitivelab/goexchange"
+	"github.com/primitivelab/goexchange/builder"
+)
+
+// Config monitor config
+type Config struct {
+	Symbols []goex.Symbol
+	// poll interval of Run, default 1 second
+	Interval time.Duration
+	// depth levels requested from each exchange, default 5
+	DepthSize int
+	// poll GetTicker instead of GetDepth, tickers without best sizes give
+	// events with zero Amount, which skip withdrawal cost and min notional
+	UseTicker bool
+	// taker fee rate by exchange name, eg: {"binance": 0.001}
+	TakerFees map[string]float64
+	// withdrawal fee by coin in coin units, eg: {"btc": 0.0005}
+	WithdrawFees map[string]float64
+	// min value in quote coin of an opportunity
+	MinNotional float64
+	// min NetSpread of an opportunity, eg: 0.002
+	MinSpread float64
+}
+
+// Quote best book of symbol on one exchange
+type Quote struct {
+	Exchange string
+	Symbol   goex.Symbol
+	Depth    *goex.Depth
+	Time     int64
+	Err      error
+}
+
+// Event spread of buying symbol on BuyExchange and selling it on SellExchange
+type Event struct {
+	Symbol       goex.Symbol
+	BuyExchange  string
+	SellExchange string
+	// best ask of BuyExchange and best bid of SellExchange
+	BuyPrice  float64
+	SellPrice float64
+	// (SellPrice - BuyPrice) / BuyPrice before any cost
+	Spread float64
+	// Profit / Notional, or spread after fees when Amount is 0
+	NetSpread float64
+	// amount tradable while selling still pays after fees
+	Amount   float64
+	Notional float64
+	// profit in quote coin after fees and withdrawal of Amount
+	Profit      float64
+	Opportunity bool
+	Time        int64
+}
+
+// Monitor polls books of symbols across exchanges and emits spread events
+type Monitor struct {
+	config   Config
+	apis     []goex.SpotAPI
+	mutex    sync.Mutex
+	quotes   map[string]map[string]*Quote
+	handlers []func(event *Event)
+}
+
+// New new instance
+func New(config *Config, apis ...goex.SpotAPI) *Monitor {
+	monitor := &Monitor{apis: apis, quotes: map[string]map[string]*Quote{}}
+	if config != nil {
+		monitor.config = *config
+	}
+	if monitor.config.Interval == 0 {
+		monitor.config.Interval = time.Second
+	}
+	if monitor.config.DepthSize == 0 {
+		monitor.config.DepthSize = 5
+	}
+	return monitor
+}
+
+// NewWithBuilder new instance of exchanges built by apiBuilder
+func NewWithBuilder(apiBuilder *builder.APIBuilder, config *Config, exchanges ...string) (*Monitor, error) {
+	apis := make([]goex.SpotAPI, 0, len(exchanges))
+	for _, exchange := range exchanges {
+		api := apiBuilder.Build(exchange)
+		if api == nil {
+			return nil, errors.New("exchange is not supported: " + exchange)
+		}
+		apis = append(apis, api)
+	}
+	return New(config, apis...), nil
+}
+
+// OnEvent add handler called with every event of Poll
+func (monitor *Monitor) OnEvent(handler func(event *Event)) {
+	monitor.mutex.Lock()
+	defer monitor.mutex.Unlock()
+	monitor.handlers = append(monitor.handlers, handler)
+}
+
+// Quotes latest quotes of symbol by exchange name
+func (monitor *Monitor) Quotes(symbol goex.Symbol) map[string]Quote {
+	monitor.mutex.Lock()
+	defer monitor.mutex.Unlock()
+	quotes := map[string]Quote{}
+	for exchange, quote := range monitor.quotes[symbolKey(symbol)] {
+		quotes[exchange] = *quote
+	}
+	return quotes
+}
+
+// Run poll every interval until stop is closed
+func (monitor *Monitor) Run(stop <-chan struct{}) {
+	ticker := time.NewTicker(monitor.config.Interval)
+	defer ticker.Stop()
+	for {
+		monitor.Poll()
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Poll fetch books of all symbols and exchanges concurrently once, then emit
+// an event for every exchange pair of every symbol
+func (monitor *Monitor) Poll() []*Event {
+	quotes := make([]*Quote, 0, len(monitor.config.Symbols)*len(monitor.apis))
+	var wait sync.WaitGroup
+	for _, symbol := range monitor.config.Symbols {
+		for _, api := range monitor.apis {
+			quote := &Quote{Exchange: api.GetExchangeName(), Symbol: symbol}
+			quotes = append(quotes, quote)
+			wait.Add(1)
+			go func(api goex.SpotAPI, quote *Quote) {
+				defer wait.Done()
+				monitor.fetch(api, quote)
+			}(api, quote)
+		}
+	}
+	wait.Wait()
+
+	monitor.mutex.Lock()
+	for _, quote := range quotes {
+		key := symbolKey(quote.Symbol)
+		if monitor.quotes[key] == nil {
+			monitor.quotes[key] = map[string]*Quote{}
+		}
+		monitor.quotes[key][quote.Exchange] = quote
+	}
+	handlers := append([]func(event *Event){}, monitor.handlers...)
+	monitor.mutex.Unlock()
+
+	events := []*Event{}
+	for _, buy := range quotes {
+		for _, sell := range quotes {
+			if buy.Err != nil || sell.Err != nil || buy.Exchange == sell.Exchange || symbolKey(buy.Symbol) != symbolKey(sell.Symbol) {
+				continue
+			}
+			if event := monitor.evaluate(buy, sell); event != nil {
+				events = append(events, event)
+			}
+		}
+	}
+	for _, event := range events {
+		for _, handler := range handlers {
+			handler(event)
+		}
+	}
+	return events
+}
+
+// fetch book of quote from api
+func (monitor *Monitor) fetch(api goex.SpotAPI, quote *Quote) {
+	quote.Time = goex.GetNowMillisecond()
+	if !monitor.config.UseTicker {
+		quote.Depth, quote.Err = goex.ParseDepth(api.GetDepth(quote.Symbol, monitor.config.DepthSize, nil))
+		return
+	}
+	ticker, err := goex.ParseTicker(api.GetTicker(quote.Symbol))
+	if err != nil {
+		quote.Err = err
+		return
+	}
+	quote.Depth = &goex.Depth{
+		Bids: []goex.DepthRecord{{Price: ticker.Bid, Amount: ticker.BidAmount}},
+		Asks: []goex.DepthRecord{{Price: ticker.Ask, Amount: ticker.AskAmount}},
+	}
+}
+
+// evaluate spread of buying on buy quote and selling on sell quote, levels
+// are walked while the fee adjusted bid stays above the fee adjusted ask
+func (monitor *Monitor) evaluate(buy, sell *Quote) *Event {
+	ask := buy.Depth.BestAsk()
+	bid := sell.Depth.BestBid()
+	if ask.Price <= 0 || bid.Price <= 0 {
+		return nil
+	}
+	buyFee := monitor.config.TakerFees[buy.Exchange]
+	sellFee := monitor.config.TakerFees[sell.Exchange]
+	event := &Event{
+		Symbol:       buy.Symbol,
+		BuyExchange:  buy.Exchange,
+		SellExchange: sell.Exchange,
+		BuyPrice:     ask.Price,
+		SellPrice:    bid.Price,
+		Spread:       (bid.Price - ask.Price) / ask.Price,
+		Time:         goex.GetNowMillisecond(),
+	}
+
+	asks := append([]goex.DepthRecord{}, buy.Depth.Asks...)
+	bids := append([]goex.DepthRecord{}, sell.Depth.Bids...)
+	for i, j := 0, 0; i < len(asks) && j < len(bids); {
+		cost := asks[i].Price * (1 + buyFee)
+		income := bids[j].Price * (1 - sellFee)
+		if income <= cost {
+			break
+		}
+		amount := asks[i].Amount
+		if bids[j].Amount < amount {
+			amount = bids[j].Amount
+		}
+		if amount <= 0 {
+			break
+		}
+		event.Amount += amount
+		event.Notional += amount * asks[i].Price
+		event.Profit += amount * (income - cost)
+		asks[i].Amount -= amount
+		bids[j].Amount -= amount
+		if asks[i].Amount <= 0 {
+			i++
+		}
+		if bids[j].Amount <= 0 {
+			j++
+		}
+	}
+
+	if event.Amount == 0 {
+		event.NetSpread = (bid.Price*(1-sellFee) - ask.Price*(1+buyFee)) / ask.Price
+		event.Opportunity = event.NetSpread > 0 && event.NetSpread >= monitor.config.MinSpread && monitor.config.MinNotional == 0
+		return event
+	}
+	withdraw := monitor.config.WithdrawFees[strings.ToLower(buy.Symbol.CoinFrom)]*ask.Price + monitor.config.WithdrawFees[strings.ToLower(buy.Symbol.CoinTo)]
+	event.Profit -= withdraw
+	event.NetSpread = event.Profit / event.Notional
+	event.Opportunity = event.Profit > 0 && event.NetSpread >= monitor.config.MinSpread && event.Notional >= monitor.config.MinNotional
+	return event
+}
+
+func symbolKey(symbol goex.Symbol) string {
+	return symbol.ToLower().String()
+}
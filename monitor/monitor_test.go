@@ -0,0 +1,75 @@
+package monitor
+
+import (
+	"math"
+	"testing"
+
+	goex "github.com/primitivelab/goexchange"
+	"github.com/primitivelab/goexchange/paper"
+)
+
+var btcUsdt = goex.NewSymbol("btc", "usdt")
+
+func getVenue(exchange string, bid, ask goex.DepthRecord) goex.SpotAPI {
+	spot := paper.NewSpot(nil, &paper.Config{Exchange: exchange})
+	spot.SetDepth(btcUsdt, &goex.Depth{Bids: []goex.DepthRecord{bid}, Asks: []goex.DepthRecord{ask}})
+	return spot
+}
+
+func getMonitor(config *Config) *Monitor {
+	config.Symbols = []goex.Symbol{btcUsdt}
+	return New(config,
+		getVenue(goex.EXCHANGE_BINANCE, goex.DepthRecord{Price: 99, Amount: 1}, goex.DepthRecord{Price: 100, Amount: 2}),
+		getVenue(goex.EXCHANGE_HUOBI, goex.DepthRecord{Price: 102, Amount: 1.5}, goex.DepthRecord{Price: 103, Amount: 1}),
+	)
+}
+
+func TestMonitor_Poll(t *testing.T) {
+	monitor := getMonitor(&Config{
+		TakerFees:    map[string]float64{goex.EXCHANGE_BINANCE: 0.001, goex.EXCHANGE_HUOBI: 0.002},
+		WithdrawFees: map[string]float64{"btc": 0.001},
+		MinSpread:    0.005,
+	})
+	handled := 0
+	monitor.OnEvent(func(event *Event) { handled++ })
+
+	events := monitor.Poll()
+	if len(events) != 2 || handled != 2 {
+		t.Fatalf("expect event of both exchange pairs: %d %d", len(events), handled)
+	}
+	for _, event := range events {
+		if event.BuyExchange == goex.EXCHANGE_HUOBI {
+			if event.Opportunity || event.Amount != 0 {
+				t.Fatalf("buying high must not be an opportunity: %+v", event)
+			}
+			continue
+		}
+		profit := 1.5*(102*0.998-100*1.001) - 0.001*100
+		if event.Amount != 1.5 || math.Abs(event.Profit-profit) > 1e-9 || !event.Opportunity || event.Spread != 0.02 {
+			t.Fatalf("unexpected opportunity: %+v", event)
+		}
+	}
+	if len(monitor.Quotes(btcUsdt)) != 2 {
+		t.Fatalf("unexpected quotes: %v", monitor.Quotes(btcUsdt))
+	}
+}
+
+func TestMonitor_Thresholds(t *testing.T) {
+	monitor := getMonitor(&Config{MinNotional: 1000})
+	for _, event := range monitor.Poll() {
+		if event.Opportunity {
+			t.Fatalf("opportunity below min notional: %+v", event)
+		}
+	}
+
+	monitor = getMonitor(&Config{UseTicker: true, MinSpread: 0.01})
+	opportunities := 0
+	for _, event := range monitor.Poll() {
+		if event.Opportunity {
+			opportunities++
+		}
+	}
+	if opportunities != 1 {
+		t.Fatalf("expect 1 ticker opportunity, got %d", opportunities)
+	}
+}
@@ -148,16 +148,41 @@ func (spot *MxcSpot) GetUserBalance() interface{} {
 	return result
 }
 
-// 批量下单
+// 下单, 只支持限价单, IOC 和 post only 是 mxc 的订单类型
 func (spot *MxcSpot) PlaceOrder(order *PlaceOrder) interface{} {
+	if err := ValidateOrder(order); err != nil {
+		return InvalidOrder(err)
+	}
+	// mxc has no market orders, so no market mode is honored
+	if err := CheckMarketMode(order); err != nil {
+		return InvalidOrder(err)
+	}
+	if order.TradeType != LIMIT {
+		return ReturnAPIError(MethodNotExistError)
+	}
+	if err := CheckTimeInForce(order.TimeInForce, IOC, POC, GTX); err != nil {
+		return InvalidOrder(err)
+	}
 	params := &url.Values{}
-
-	result := spot.httpPost("/open/api/v2/account/info", params, true)
-	if result["code"] != 0 {
-		return result
+	params.Set("symbol", order.Symbol.ToUpper().String())
+	params.Set("price", order.Price)
+	params.Set("quantity", order.Amount)
+	params.Set("trade_type", MXC_BUY)
+	if order.Side == SELL {
+		params.Set("trade_type", MXC_SELL)
 	}
-
-	return result
+	switch order.TimeInForce {
+	case IOC:
+		params.Set("order_type", "IMMEDIATE_OR_CANCEL")
+	case POC, GTX:
+		params.Set("order_type", "POST_ONLY")
+	default:
+		params.Set("order_type", "LIMIT_ORDER")
+	}
+	if order.ClientOrderId != "" {
+		params.Set("client_order_id", order.ClientOrderId)
+	}
+	return spot.httpPost("/open/api/v2/order/place", params, true)
 }
 
 // 下限价单
@@ -188,9 +213,9 @@ func (spot *MxcSpot) PlaceMarketOrder(symbol Symbol, amount string, side TradeSi
 	return ReturnAPIError(MethodNotExistError)
 }
 
-// 批量下限价单
+// 批量下限价单, 没有实现
 func (spot *MxcSpot) BatchPlaceLimitOrder(orders []LimitOrder) interface{} {
-	return nil
+	return ReturnAPIError(MethodNotExistError)
 }
 
 // 撤单
@@ -124,3 +124,24 @@ func TestMxcSpot_BatchCancelOrder(t *testing.T) {
 	b, _ := json.Marshal(response)
 	t.Log(string(b))
 }
+
+func TestMxcSpot_PlaceOrderUnsupported(t *testing.T) {
+	market := New(client, baseUrl, apiKey, secretKey)
+	symbol := NewSymbol("eos", "usdt")
+	result := market.PlaceOrder(&PlaceOrder{Symbol: symbol, Side: BUY, TradeType: LIMIT, Price: "1", Amount: "1", TimeInForce: FOK})
+	if result.(map[string]interface{})["code"] != TimeInForceParamsError.Code {
+		t.Fatalf("expected time in force error, got %v", result)
+	}
+	result = market.PlaceOrder(&PlaceOrder{Symbol: symbol, Side: BUY, TradeType: MARKET, Amount: "1"})
+	if result.(map[string]interface{})["code"] != MethodNotExistError.Code {
+		t.Fatalf("expected unsupported market order, got %v", result)
+	}
+}
+
+func TestMxcSpot_PlaceOrderMarketMode(t *testing.T) {
+	market := New(client, baseUrl, apiKey, secretKey)
+	result := market.PlaceOrder(&PlaceOrder{Symbol: NewSymbol("eos", "usdt"), Side: BUY, TradeType: MARKET, Amount: "10", MarketMode: MarketOrderByQuote})
+	if result.(map[string]interface{})["code"] != OrderParamsError.Code {
+		t.Fatalf("expected market mode error, got %v", result)
+	}
+}
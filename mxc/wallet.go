@@ -0,0 +1,25 @@
+package mxc
+
+import (
+	. "github.com/primitivelab/goexchange"
+)
+
+// GetDepositAddresses deposit address is not supported
+func (spot *MxcSpot) GetDepositAddresses(coin, chain string) interface{} {
+	return ReturnAPIError(MethodNotExistError)
+}
+
+// ApplyWithdraw withdraw is not supported
+func (spot *MxcSpot) ApplyWithdraw(request *WithdrawRequest) interface{} {
+	return ReturnAPIError(MethodNotExistError)
+}
+
+// GetDepositHistory deposit record is not supported
+func (spot *MxcSpot) GetDepositHistory(coin string, size int, options map[string]string) interface{} {
+	return ReturnAPIError(MethodNotExistError)
+}
+
+// GetWithdrawHistory withdraw record is not supported
+func (spot *MxcSpot) GetWithdrawHistory(coin string, size int, options map[string]string) interface{} {
+	return ReturnAPIError(MethodNotExistError)
+}
@@ -0,0 +1,65 @@
+package okex
+
+import (
+	"errors"
+
+	goex "github.com/primitivelab/goexchange"
+)
+
+// algoParams v3 algo order params shared by spot, margin, swap and futures,
+// trailing stops are order_type 2 activated at StopPrice, the other trigger
+// orders are order_type 1 placed at Price or at market once triggered
+func algoParams(order *goex.PlaceOrder) (map[string]string, error) {
+	if err := goex.ValidateOrder(order); err != nil {
+		return nil, err
+	}
+	if order.ClosePosition {
+		return nil, errors.New("okex algo orders can not close position, use reduce only with amount")
+	}
+	// triggered orders rest as plain limit or market orders
+	if err := goex.CheckTimeInForce(order.TimeInForce); err != nil {
+		return nil, err
+	}
+	params := map[string]string{"size": order.Amount}
+	if order.TradeType == goex.TRAILING_STOP {
+		params["order_type"] = "2"
+		params["callback_rate"] = goex.FloatToString(goex.ToFloat(order.CallbackRate) / 100)
+		if order.StopPrice != "" {
+			params["trigger_price"] = order.StopPrice
+		}
+		return params, nil
+	}
+	params["order_type"] = "1"
+	params["trigger_price"] = order.StopPrice
+	if goex.IsLimitOrder(order.TradeType) {
+		params["algo_type"] = "1"
+		params["algo_price"] = order.Price
+	} else {
+		params["algo_type"] = "2"
+	}
+	return params, nil
+}
+
+// placeAlgoOrder place spot algo order, mode 1 of spot or 2 of margin account
+func (spot *Spot) placeAlgoOrder(order *goex.PlaceOrder, mode string) interface{} {
+	params, err := algoParams(order)
+	if err != nil {
+		return goex.InvalidOrder(err)
+	}
+	params["instrument_id"] = order.Symbol.ToUpper().ToSymbol("-")
+	params["mode"] = mode
+	params["side"] = order.Side.String()
+	return handlerOrderError(spot.httpPost("/api/spot/v3/order_algo", params, true))
+}
+
+// placeAlgoOrder place swap or futures algo order of instrument, reduce only
+// orders close position
+func (swap *Swap) placeAlgoOrder(path, instrumentID string, order *goex.PlaceOrder) interface{} {
+	params, err := algoParams(order)
+	if err != nil {
+		return goex.InvalidOrder(err)
+	}
+	params["instrument_id"] = instrumentID
+	params["type"] = positionType(order)
+	return swap.handlerOrderError(swap.httpPost(path, params, true))
+}
@@ -0,0 +1,70 @@
+package okex
+
+import (
+	goex "github.com/primitivelab/goexchange"
+)
+
+// amendParams v3 amend_order params shared by spot, swap and futures, okex
+// keeps the order id and cancels the order when amending fails
+func amendParams(order *goex.AmendOrder) (map[string]string, error) {
+	if err := goex.ValidateAmendOrder(order); err != nil {
+		return nil, err
+	}
+	params := map[string]string{"cancel_on_fail": "0"}
+	if order.ClientOrderId != "" {
+		params["client_oid"] = order.ClientOrderId
+	} else {
+		params["order_id"] = order.OrderId
+	}
+	if order.Price != "" {
+		params["new_price"] = order.Price
+	}
+	if order.Amount != "" {
+		params["new_size"] = order.Amount
+	}
+	return params, nil
+}
+
+// amendResult order id of amend_order response
+func amendResult(retData map[string]interface{}) map[string]interface{} {
+	retData = handlerOrderError(retData)
+	if retData["code"] != 0 {
+		return retData
+	}
+	data, _ := retData["data"].(map[string]interface{})
+	retData["data"] = goex.ToString(data["order_id"])
+	return retData
+}
+
+// AmendOrder amend price or size of a resting spot order, data: order id
+func (spot *Spot) AmendOrder(order *goex.AmendOrder) interface{} {
+	params, err := amendParams(order)
+	if err != nil {
+		return goex.InvalidOrder(err)
+	}
+	instrumentID := order.Symbol.ToUpper().ToSymbol("-")
+	params["instrument_id"] = instrumentID
+	return amendResult(spot.httpPost("/api/spot/v3/amend_order/"+instrumentID, params, true))
+}
+
+// AmendOrder amend price or size of a resting swap order, data: order id
+func (swap *Swap) AmendOrder(order *goex.AmendOrder) interface{} {
+	params, err := amendParams(order)
+	if err != nil {
+		return goex.InvalidOrder(err)
+	}
+	return amendResult(swap.httpPost("/api/swap/v3/amend_order/"+swap.getSymbol(order.Symbol), params, true))
+}
+
+// AmendOrder amend price or size of a resting futures order, data: order id
+func (futures *Futures) AmendOrder(order *goex.AmendOrder) interface{} {
+	contract, err := futures.resolve(order.Symbol, order.ContractType)
+	if err != nil {
+		return goex.ContractNotFound(err)
+	}
+	params, err := amendParams(order)
+	if err != nil {
+		return goex.InvalidOrder(err)
+	}
+	return amendResult(futures.swap.httpPost("/api/futures/v3/amend_order/"+contract.Code, params, true))
+}
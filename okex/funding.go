@@ -0,0 +1,58 @@
+package okex
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+
+	goex "github.com/primitivelab/goexchange"
+)
+
+// GetFundingRate current and estimated funding of contract, data: goex.FundingInfo
+func (swap *Swap) GetFundingRate(symbol goex.Symbol) interface{} {
+	instrumentId := swap.getSymbol(symbol)
+	result := swap.httpGet(fmt.Sprintf("/api/swap/v3/instruments/%s/funding_time", instrumentId), nil, false)
+	if result["code"] != 0 {
+		return result
+	}
+	record, _ := result["data"].(map[string]interface{})
+	result["data"] = goex.FundingInfo{
+		Symbol:          instrumentId,
+		Rate:            goex.ToFloat(record["funding_rate"]),
+		PredictedRate:   goex.ToFloat(record["estimated_rate"]),
+		NextFundingTime: goex.ParseTimestamp(record["funding_time"]),
+	}
+	return result
+}
+
+// GetFundingRateHistory settled funding rates, options after and before, data: []goex.FundingRate
+func (swap *Swap) GetFundingRateHistory(symbol goex.Symbol, size int, options map[string]string) interface{} {
+	params := &url.Values{}
+	swap.pageParams(params, size, options)
+	instrumentId := swap.getSymbol(symbol)
+	result := swap.httpGet(fmt.Sprintf("/api/swap/v3/instruments/%s/historical_funding_rate", instrumentId), swap.query(params), false)
+	if result["code"] != 0 {
+		return result
+	}
+	result["data"] = fundingRates(result["data"])
+	return result
+}
+
+// fundingRates realized rates of historical_funding_rate list, newest first
+func fundingRates(data interface{}) []goex.FundingRate {
+	list, _ := data.([]interface{})
+	rates := make([]goex.FundingRate, 0, len(list))
+	for _, item := range list {
+		record, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		rates = append(rates, goex.FundingRate{
+			Symbol: goex.ToString(record["instrument_id"]),
+			Rate:   goex.ToFloat(record["realized_rate"]),
+			Time:   goex.ParseTimestamp(record["funding_time"]),
+		})
+	}
+	sort.SliceStable(rates, func(i, j int) bool { return rates[i].Time > rates[j].Time })
+	return rates
+}
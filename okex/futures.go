@@ -0,0 +1,376 @@
+package okex
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	goex "github.com/primitivelab/goexchange"
+)
+
+// futuresContractType okex alias of delivery contracts
+var futuresContractType = map[string]string{
+	"this_week":  goex.CONTRACT_THIS_WEEK,
+	"next_week":  goex.CONTRACT_NEXT_WEEK,
+	"quarter":    goex.CONTRACT_QUARTER,
+	"bi_quarter": goex.CONTRACT_NEXT_QUARTER,
+}
+
+// Futures okex delivery contract, it shares signing and order params with Swap
+type Futures struct {
+	swap      *Swap
+	contracts goex.FuturesContracts
+}
+
+// NewFutures new instance
+func NewFutures(client *http.Client, baseURL, apiKey, secretKey, passphrase string) *Futures {
+	return &Futures{swap: NewSwap(client, baseURL, apiKey, secretKey, passphrase)}
+}
+
+// NewFuturesWithConfig new instance with config struct
+func NewFuturesWithConfig(config *goex.APIConfig) *Futures {
+	return &Futures{swap: NewSwapWithConfig(config)}
+}
+
+// GetExchangeName get exchange name
+func (futures *Futures) GetExchangeName() string {
+	return goex.EXCHANGE_OKEX
+}
+
+// GetContractList delivery contract list, data: []goex.FuturesContract
+func (futures *Futures) GetContractList() interface{} {
+	result := futures.swap.httpGet("/api/futures/v3/instruments", nil, false)
+	if result["code"] != 0 {
+		return result
+	}
+
+	list, _ := result["data"].([]interface{})
+	contracts := make([]goex.FuturesContract, 0, len(list))
+	for _, item := range list {
+		record, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		contractType, ok := futuresContractType[goex.ToString(record["alias"])]
+		if !ok {
+			continue
+		}
+		coins := strings.Split(goex.ToString(record["underlying"]), "-")
+		if len(coins) != 2 {
+			continue
+		}
+		contracts = append(contracts, goex.FuturesContract{
+			Symbol:       goex.NewSymbol(coins[0], coins[1]),
+			ContractType: contractType,
+			Code:         goex.ToString(record["instrument_id"]),
+			ContractSize: goex.ToFloat(record["contract_val"]),
+			ListTime:     futuresDate(record["listing"], 0),
+			DeliveryTime: futuresDate(record["delivery"], 8*time.Hour),
+		})
+	}
+	result["data"] = contracts
+	return result
+}
+
+// GetContract current contract of symbol and contract type, data: goex.FuturesContract
+func (futures *Futures) GetContract(symbol goex.Symbol, contractType string) interface{} {
+	contract, err := futures.resolve(symbol, contractType)
+	if err != nil {
+		return goex.ContractNotFound(err)
+	}
+	return goex.ReturnAPIData(contract)
+}
+
+// GetDepth exchange depth data, options depth
+func (futures *Futures) GetDepth(symbol goex.Symbol, contractType string, size int, options map[string]string) interface{} {
+	contract, err := futures.resolve(symbol, contractType)
+	if err != nil {
+		return goex.ContractNotFound(err)
+	}
+	params := &url.Values{}
+	if size != 0 {
+		params.Set("size", strconv.Itoa(size))
+	}
+	if depth, ok := options["depth"]; ok {
+		params.Set("depth", depth)
+	}
+	return futures.swap.httpGet(fmt.Sprintf("/api/futures/v3/instruments/%s/book", contract.Code), futures.swap.query(params), false)
+}
+
+// GetTicker exchange ticker data
+func (futures *Futures) GetTicker(symbol goex.Symbol, contractType string) interface{} {
+	contract, err := futures.resolve(symbol, contractType)
+	if err != nil {
+		return goex.ContractNotFound(err)
+	}
+	return futures.swap.httpGet(fmt.Sprintf("/api/futures/v3/instruments/%s/ticker", contract.Code), nil, false)
+}
+
+// GetKline exchange kline data, options start and end
+func (futures *Futures) GetKline(symbol goex.Symbol, contractType string, period, size int, options map[string]string) interface{} {
+	contract, err := futures.resolve(symbol, contractType)
+	if err != nil {
+		return goex.ContractNotFound(err)
+	}
+	params := &url.Values{}
+	periodStr, ok := klinePeriod[period]
+	if !ok {
+		periodStr = "60"
+	}
+	params.Set("granularity", periodStr)
+	for _, key := range []string{"start", "end"} {
+		if value, ok := options[key]; ok {
+			params.Set(key, value)
+		}
+	}
+	return futures.swap.httpGet(fmt.Sprintf("/api/futures/v3/instruments/%s/candles", contract.Code), params, false)
+}
+
+// GetTrade exchange trade order data, options after and before
+func (futures *Futures) GetTrade(symbol goex.Symbol, contractType string, size int, options map[string]string) interface{} {
+	contract, err := futures.resolve(symbol, contractType)
+	if err != nil {
+		return goex.ContractNotFound(err)
+	}
+	params := &url.Values{}
+	futures.swap.pageParams(params, size, options)
+	return futures.swap.httpGet(fmt.Sprintf("/api/futures/v3/instruments/%s/trades", contract.Code), futures.swap.query(params), false)
+}
+
+// GetDeliveryPrices settled delivery prices of underlying, data: []goex.DeliveryPrice
+func (futures *Futures) GetDeliveryPrices(symbol goex.Symbol, size int) interface{} {
+	params := &url.Values{}
+	params.Set("underlying", symbol.ToUpper().ToSymbol("-"))
+	if size != 0 {
+		params.Set("limit", strconv.Itoa(size))
+	}
+	result := futures.swap.httpGet("/api/futures/v3/settlement/history", params, false)
+	if result["code"] != 0 {
+		return result
+	}
+
+	list, _ := result["data"].([]interface{})
+	prices := make([]goex.DeliveryPrice, 0, len(list))
+	for _, item := range list {
+		record, ok := item.(map[string]interface{})
+		if !ok || record["type"] != "delivery" {
+			continue
+		}
+		prices = append(prices, goex.DeliveryPrice{
+			Code:  goex.ToString(record["instrument_id"]),
+			Price: goex.ToFloat(record["price"]),
+			Time:  goex.ParseTimestamp(record["timestamp"]),
+		})
+	}
+	result["data"] = prices
+	return result
+}
+
+// GetUserBalance user account balance of all underlyings
+func (futures *Futures) GetUserBalance() interface{} {
+	return futures.swap.httpGet("/api/futures/v3/accounts", nil, true)
+}
+
+// GetUserPositions user position of contract, data: []goex.Position
+func (futures *Futures) GetUserPositions(symbol goex.Symbol, contractType string) interface{} {
+	contract, err := futures.resolve(symbol, contractType)
+	if err != nil {
+		return goex.ContractNotFound(err)
+	}
+	result := futures.swap.httpGet(fmt.Sprintf("/api/futures/v3/%s/position", contract.Code), nil, true)
+	if result["code"] != 0 {
+		return result
+	}
+	result["data"] = futures.parsePositions(result["data"])
+	return result
+}
+
+// PlaceOrder place order, amount is number of contracts, offset is open
+// when order has none, trigger orders are placed as algo orders
+func (futures *Futures) PlaceOrder(order *goex.PlaceOrder) interface{} {
+	if err := checkContractOrder(order); err != nil {
+		return goex.InvalidOrder(err)
+	}
+	contract, err := futures.resolve(order.Symbol, order.ContractType)
+	if err != nil {
+		return goex.ContractNotFound(err)
+	}
+	if goex.IsTriggerOrder(order.TradeType) {
+		return futures.swap.placeAlgoOrder("/api/futures/v3/order_algo", contract.Code, order)
+	}
+	params := futures.swap.orderParams(order)
+	params["instrument_id"] = contract.Code
+	return futures.swap.handlerOrderError(futures.swap.httpPost("/api/futures/v3/order", params, true))
+}
+
+// CancelOrder cancel user trust order
+func (futures *Futures) CancelOrder(symbol goex.Symbol, contractType, orderID, clientOrderID string) interface{} {
+	contract, err := futures.resolve(symbol, contractType)
+	if err != nil {
+		return goex.ContractNotFound(err)
+	}
+	id := orderID
+	if clientOrderID != "" {
+		id = clientOrderID
+	}
+	path := fmt.Sprintf("/api/futures/v3/cancel_order/%s/%s", contract.Code, id)
+	return futures.swap.handlerOrderError(futures.swap.httpPost(path, nil, true))
+}
+
+// BatchCancelAllOrder cancel all open orders of contract, open orders are
+// listed and canceled 10 at a time
+func (futures *Futures) BatchCancelAllOrder(symbol goex.Symbol, contractType string) interface{} {
+	contract, err := futures.resolve(symbol, contractType)
+	if err != nil {
+		return goex.ContractNotFound(err)
+	}
+	result := futures.orders(contract.Code, "6", 100, nil)
+	if result["code"] != 0 {
+		return result
+	}
+	data, _ := result["data"].(map[string]interface{})
+	list, _ := data["order_info"].([]interface{})
+	var ids []string
+	for _, item := range list {
+		if order, ok := item.(map[string]interface{}); ok {
+			ids = append(ids, goex.ToString(order["order_id"]))
+		}
+	}
+
+	var canceled []interface{}
+	for start := 0; start < len(ids); start += 10 {
+		end := start + 10
+		if end > len(ids) {
+			end = len(ids)
+		}
+		params := map[string][]string{"ids": ids[start:end]}
+		path := fmt.Sprintf("/api/futures/v3/cancel_batch_orders/%s", contract.Code)
+		result = futures.swap.httpPost(path, params, true)
+		if result["code"] != 0 {
+			return result
+		}
+		canceled = append(canceled, result["data"])
+	}
+	result["data"] = canceled
+	return result
+}
+
+// GetUserOrderInfo user trust order info
+func (futures *Futures) GetUserOrderInfo(symbol goex.Symbol, contractType, orderID, clientOrderID string) interface{} {
+	contract, err := futures.resolve(symbol, contractType)
+	if err != nil {
+		return goex.ContractNotFound(err)
+	}
+	id := orderID
+	if clientOrderID != "" {
+		id = clientOrderID
+	}
+	return futures.swap.httpGet(fmt.Sprintf("/api/futures/v3/orders/%s/%s", contract.Code, id), nil, true)
+}
+
+// GetUserOpenTrustOrders user open and partially filled order list, options after and before
+func (futures *Futures) GetUserOpenTrustOrders(symbol goex.Symbol, contractType string, size int, options map[string]string) interface{} {
+	return futures.GetUserTrustOrders(symbol, contractType, "6", size, options)
+}
+
+// GetUserTrustOrders user order list of okex state, default 7 for completed
+// orders, options after and before
+func (futures *Futures) GetUserTrustOrders(symbol goex.Symbol, contractType, status string, size int, options map[string]string) interface{} {
+	contract, err := futures.resolve(symbol, contractType)
+	if err != nil {
+		return goex.ContractNotFound(err)
+	}
+	if status == "" {
+		status = "7"
+	}
+	return futures.orders(contract.Code, status, size, options)
+}
+
+// GetUserTradeOrders user fill list, options order_id, after and before
+func (futures *Futures) GetUserTradeOrders(symbol goex.Symbol, contractType string, size int, options map[string]string) interface{} {
+	contract, err := futures.resolve(symbol, contractType)
+	if err != nil {
+		return goex.ContractNotFound(err)
+	}
+	params := &url.Values{}
+	params.Set("instrument_id", contract.Code)
+	futures.swap.pageParams(params, size, options, "order_id")
+	return futures.swap.httpGet("/api/futures/v3/fills", params, true)
+}
+
+// HTTPRequest request url
+func (futures *Futures) HTTPRequest(requestURL, method string, options interface{}, signed bool) interface{} {
+	return futures.swap.HTTPRequest(requestURL, method, options, signed)
+}
+
+// orders order list of contract code and okex state
+func (futures *Futures) orders(code, state string, size int, options map[string]string) map[string]interface{} {
+	params := &url.Values{}
+	params.Set("state", state)
+	futures.swap.pageParams(params, size, options)
+	return futures.swap.httpGet(fmt.Sprintf("/api/futures/v3/orders/%s", code), params, true)
+}
+
+// parsePositions typed positions of okex futures holdings, which hold the
+// long and short side of a contract in one record
+func (futures *Futures) parsePositions(data interface{}) []goex.Position {
+	object, _ := data.(map[string]interface{})
+	holding, _ := object["holding"].([]interface{})
+	positions := []goex.Position{}
+	for _, item := range holding {
+		record, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for _, side := range []string{goex.POSITION_LONG, goex.POSITION_SHORT} {
+			amount := goex.ToFloat(record[side+"_qty"])
+			if amount == 0 {
+				continue
+			}
+			position := goex.Position{
+				Symbol:           goex.ToString(record["instrument_id"]),
+				Side:             side,
+				Amount:           amount,
+				EntryPrice:       goex.ToFloat(record[side+"_avg_cost"]),
+				MarkPrice:        goex.ToFloat(record["last"]),
+				UnrealizedPnl:    goex.ToFloat(record[side+"_unrealised_pnl"]),
+				Leverage:         goex.ToFloat(record["leverage"]),
+				MarginType:       goex.ParseMarginType(object["margin_mode"]),
+				LiquidationPrice: goex.ToFloat(record["liquidation_price"]),
+			}
+			// fixed margin holds leverage and liquidation price by side
+			if leverage, ok := record[side+"_leverage"]; ok {
+				position.Leverage = goex.ToFloat(leverage)
+			}
+			if price, ok := record[side+"_liqui_price"]; ok {
+				position.LiquidationPrice = goex.ToFloat(price)
+			}
+			positions = append(positions, position)
+		}
+	}
+	return positions
+}
+
+// resolve current contract of symbol and contract type
+func (futures *Futures) resolve(symbol goex.Symbol, contractType string) (goex.FuturesContract, error) {
+	return futures.contracts.Resolve(symbol, contractType, func() ([]goex.FuturesContract, error) {
+		data, err := goex.ParseResponse(futures.GetContractList())
+		if err != nil {
+			return nil, err
+		}
+		return data.([]goex.FuturesContract), nil
+	})
+}
+
+// futuresDate millisecond timestamp of okex date like 2021-06-25 with
+// offset of the time of day in UTC, okex delivers at 08:00
+func futuresDate(value interface{}, offset time.Duration) int64 {
+	date, err := time.Parse("2006-01-02", goex.ToString(value))
+	if err != nil {
+		return 0
+	}
+	return date.Add(offset).UnixNano() / int64(time.Millisecond)
+}
@@ -0,0 +1,112 @@
+package okex
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	goex "github.com/primitivelab/goexchange"
+)
+
+func getFuturesInstance() goex.FuturesAPI {
+
+	client = &http.Client{}
+	config, err := goex.LoadConfig("okex")
+	if err != nil {
+		fmt.Println(err)
+	}
+	if config != nil {
+		apiKey = config["key"].(string)
+		secretKey = config["secret"].(string)
+		passphrase, _ = config["passphrase"].(string)
+	}
+
+	conf := goex.APIConfig{}
+	conf.ApiKey = apiKey
+	conf.ApiSecretKey = secretKey
+	conf.ApiPassphrase = passphrase
+	conf.HttpClient = client
+	market := NewFuturesWithConfig(&conf)
+	return market
+}
+
+func TestFutures_GetContractList(t *testing.T) {
+	market := getFuturesInstance()
+
+	response := market.GetContractList()
+	b, _ := json.Marshal(response)
+	t.Log(string(b))
+}
+
+func TestFutures_GetContract(t *testing.T) {
+	market := getFuturesInstance()
+
+	response := market.GetContract(goex.NewSymbol("btc", "usd"), goex.CONTRACT_QUARTER)
+	b, _ := json.Marshal(response)
+	t.Log(string(b))
+}
+
+func TestFutures_GetDepth(t *testing.T) {
+	market := getFuturesInstance()
+
+	response := market.GetDepth(goex.NewSymbol("btc", "usd"), goex.CONTRACT_QUARTER, 10, nil)
+	b, _ := json.Marshal(response)
+	t.Log(string(b))
+}
+
+func TestFutures_GetDeliveryPrices(t *testing.T) {
+	market := getFuturesInstance()
+
+	response := market.GetDeliveryPrices(goex.NewSymbol("btc", "usd"), 10)
+	b, _ := json.Marshal(response)
+	t.Log(string(b))
+}
+
+func TestFutures_GetUserPositions(t *testing.T) {
+	market := getFuturesInstance()
+
+	response := market.GetUserPositions(goex.NewSymbol("btc", "usd"), goex.CONTRACT_QUARTER)
+	b, _ := json.Marshal(response)
+	t.Log(string(b))
+}
+
+func TestFutures_GetUserOpenTrustOrders(t *testing.T) {
+	market := getFuturesInstance()
+
+	response := market.GetUserOpenTrustOrders(goex.NewSymbol("btc", "usd"), goex.CONTRACT_QUARTER, 10, nil)
+	b, _ := json.Marshal(response)
+	t.Log(string(b))
+}
+
+func TestFuturesDate(t *testing.T) {
+	if futuresDate("2021-06-25", 8*time.Hour) != 1624608000000 {
+		t.Fatal(futuresDate("2021-06-25", 8*time.Hour))
+	}
+	if futuresDate("", 0) != 0 {
+		t.Fatal("empty date should be 0")
+	}
+}
+
+func TestFutures_ParsePositions(t *testing.T) {
+	market := &Futures{}
+
+	var data interface{}
+	json.Unmarshal([]byte(`{"margin_mode":"fixed","holding":[
+		{"instrument_id":"BTC-USD-210625","long_qty":"3","long_avg_cost":"30000","long_unrealised_pnl":"0.01","long_leverage":"20","long_liqui_price":"25000",
+		"short_qty":"1","short_avg_cost":"31000","short_unrealised_pnl":"-0.01","short_leverage":"10","short_liqui_price":"40000","last":"30500"}
+	]}`), &data)
+
+	positions := market.parsePositions(data)
+	if len(positions) != 2 {
+		t.Fatalf("expected 2 positions, got %+v", positions)
+	}
+	long, short := positions[0], positions[1]
+	if long.Side != goex.POSITION_LONG || long.Amount != 3 || long.Leverage != 20 || long.LiquidationPrice != 25000 || long.MarginType != goex.MARGIN_ISOLATED {
+		t.Fatalf("unexpected long position %+v", long)
+	}
+	if short.Side != goex.POSITION_SHORT || short.Amount != 1 || short.EntryPrice != 31000 || short.MarkPrice != 30500 || short.LiquidationPrice != 40000 {
+		t.Fatalf("unexpected short position %+v", short)
+	}
+}
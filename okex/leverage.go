@@ -0,0 +1,119 @@
+package okex
+
+import (
+	"errors"
+	"net/url"
+
+	goex "github.com/primitivelab/goexchange"
+)
+
+// GetLeverage leverage per side and margin mode of contract, okex swaps are
+// always in hedge position mode, data: goex.LeverageSetting
+func (swap *Swap) GetLeverage(symbol goex.Symbol) interface{} {
+	result := swap.GetLeverRate(symbol).(map[string]interface{})
+	if result["code"] != 0 {
+		return result
+	}
+	record, _ := result["data"].(map[string]interface{})
+	result["data"] = goex.LeverageSetting{
+		Symbol:        swap.getSymbol(symbol),
+		Leverage:      int(goex.ToFloat(record["long_leverage"])),
+		ShortLeverage: int(goex.ToFloat(record["short_leverage"])),
+		MarginType:    goex.ParseMarginType(record["margin_mode"]),
+		PositionMode:  goex.POSITION_MODE_HEDGE,
+	}
+	return result
+}
+
+// GetLeverageBrackets position tiers of contract, v3 has no position tiers api
+// so the public v5 one is used, it needs no signature and tiers of the
+// underlying are those of the v3 swap, data: []goex.LeverageBracket
+func (swap *Swap) GetLeverageBrackets(symbol goex.Symbol) interface{} {
+	params := &url.Values{}
+	params.Set("instType", "SWAP")
+	params.Set("tdMode", "cross")
+	params.Set("uly", symbol.ToUpper().ToSymbol("-"))
+	result := swap.httpGet("/api/v5/public/position-tiers", params, false)
+	if result["code"] != 0 {
+		return result
+	}
+	body, _ := result["data"].(map[string]interface{})
+	if code := goex.ToString(body["code"]); code != "0" {
+		retData := goex.ReturnAPIError(goex.ExchangeError).(map[string]interface{})
+		retData["error"] = code + ": " + goex.ToString(body["msg"])
+		return retData
+	}
+	result["data"] = leverageBrackets(body["data"])
+	return result
+}
+
+// SetLeverage set leverage of both sides after checking it against the
+// brackets, keeping the margin mode of contract
+func (swap *Swap) SetLeverage(symbol goex.Symbol, leverage int) interface{} {
+	result := swap.GetLeverageBrackets(symbol).(map[string]interface{})
+	if result["code"] != 0 {
+		return result
+	}
+	if err := goex.CheckLeverage(leverage, result["data"].([]goex.LeverageBracket)); err != nil {
+		return goex.InvalidLeverageSetting(err)
+	}
+	result = swap.GetLeverage(symbol).(map[string]interface{})
+	if result["code"] != 0 {
+		return result
+	}
+	current := result["data"].(goex.LeverageSetting)
+	return swap.setLeverage(symbol, current.MarginType, leverage, leverage)
+}
+
+// SetMarginType switch margin mode of contract, okex switches it by setting
+// leverage of side 3 for crossed and of sides 1 and 2 for fixed margin
+func (swap *Swap) SetMarginType(symbol goex.Symbol, marginType string) interface{} {
+	if marginType != goex.MARGIN_CROSSED && marginType != goex.MARGIN_ISOLATED {
+		return goex.InvalidLeverageSetting(errors.New("unknown margin type " + marginType))
+	}
+	result := swap.GetLeverage(symbol).(map[string]interface{})
+	if result["code"] != 0 {
+		return result
+	}
+	current := result["data"].(goex.LeverageSetting)
+	return swap.setLeverage(symbol, marginType, current.Leverage, current.ShortLeverage)
+}
+
+// SetPositionMode okex swaps have hedge position mode only
+func (swap *Swap) SetPositionMode(symbol goex.Symbol, mode string) interface{} {
+	if mode != goex.POSITION_MODE_HEDGE {
+		return goex.InvalidLeverageSetting(errors.New("okex swaps support hedge position mode only"))
+	}
+	return goex.ReturnAPIData(nil)
+}
+
+// setLeverage set leverage in margin type, fixed margin sets long and short
+// leverage one after the other
+func (swap *Swap) setLeverage(symbol goex.Symbol, marginType string, long, short int) interface{} {
+	if marginType != goex.MARGIN_ISOLATED {
+		return swap.SetLeverRate(symbol, long, map[string]string{"side": "3"})
+	}
+	result := swap.SetLeverRate(symbol, long, map[string]string{"side": "1"}).(map[string]interface{})
+	if result["code"] != 0 {
+		return result
+	}
+	return swap.SetLeverRate(symbol, short, map[string]string{"side": "2"})
+}
+
+// leverageBrackets brackets of okex v5 position tiers
+func leverageBrackets(data interface{}) []goex.LeverageBracket {
+	list, _ := data.([]interface{})
+	brackets := make([]goex.LeverageBracket, 0, len(list))
+	for index, item := range list {
+		record, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		bracket := goex.LeverageBracket{MaxLeverage: int(goex.ToFloat(record["maxLever"]))}
+		if index < len(list)-1 {
+			bracket.NotionalCap = goex.ToFloat(record["maxSz"])
+		}
+		brackets = append(brackets, bracket)
+	}
+	return brackets
+}
@@ -0,0 +1,301 @@
+package okex
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	goex "github.com/primitivelab/goexchange"
+)
+
+// Margin okex v3 margin account, every instrument has its own isolated
+// margin account, it signs requests with the keys of Spot
+type Margin struct {
+	spot *Spot
+}
+
+// NewMargin new instance
+func NewMargin(client *http.Client, baseUrl, apiKey, secretKey, passphrase string) *Margin {
+	return &Margin{spot: New(client, baseUrl, apiKey, secretKey, passphrase)}
+}
+
+// NewMarginWithConfig new instance with config struct
+func NewMarginWithConfig(config *goex.APIConfig) *Margin {
+	return &Margin{spot: NewWithConfig(config)}
+}
+
+// GetExchangeName get exchange name
+func (margin *Margin) GetExchangeName() string {
+	return goex.EXCHANGE_OKEX
+}
+
+// GetMarginType okex v3 margin accounts are isolated
+func (margin *Margin) GetMarginType() string {
+	return goex.MARGIN_ISOLATED
+}
+
+// GetUserBalance margin account balance of all instruments, data: []goex.MarginBalance
+func (margin *Margin) GetUserBalance() interface{} {
+	result := margin.spot.httpGet("/api/margin/v3/accounts", nil, true)
+	if result["code"] != 0 {
+		return result
+	}
+	result["data"] = marginBalances(result["data"])
+	return result
+}
+
+// Borrow borrow coin, data: borrow id
+func (margin *Margin) Borrow(symbol goex.Symbol, coin, amount string) interface{} {
+	params := map[string]string{
+		"instrument_id": margin.getSymbol(symbol),
+		"currency":      strings.ToUpper(coin),
+		"amount":        amount,
+	}
+	result := handlerOrderError(margin.spot.httpPost("/api/margin/v3/accounts/borrow", params, true))
+	if result["code"] != 0 {
+		return result
+	}
+	data, _ := result["data"].(map[string]interface{})
+	result["data"] = goex.ToString(data["borrow_id"])
+	return result
+}
+
+// Repay repay coin, data: repayment id
+func (margin *Margin) Repay(symbol goex.Symbol, coin, amount string) interface{} {
+	params := map[string]string{
+		"instrument_id": margin.getSymbol(symbol),
+		"currency":      strings.ToUpper(coin),
+		"amount":        amount,
+	}
+	result := handlerOrderError(margin.spot.httpPost("/api/margin/v3/accounts/repayment", params, true))
+	if result["code"] != 0 {
+		return result
+	}
+	data, _ := result["data"].(map[string]interface{})
+	result["data"] = goex.ToString(data["repayment_id"])
+	return result
+}
+
+// GetInterestHistory interest of borrow records of instrument, options status,
+// after and before, data: []goex.MarginInterest
+func (margin *Margin) GetInterestHistory(symbol goex.Symbol, coin string, size int, options map[string]string) interface{} {
+	params := margin.pageParams(size, options, "status")
+	result := margin.spot.httpGet("/api/margin/v3/accounts/"+margin.getSymbol(symbol)+"/borrowed", params, true)
+	if result["code"] != 0 {
+		return result
+	}
+	interests := marginInterests(result["data"])
+	if coin != "" {
+		filtered := interests[:0]
+		for _, interest := range interests {
+			if strings.EqualFold(interest.Coin, coin) {
+				filtered = append(filtered, interest)
+			}
+		}
+		interests = filtered
+	}
+	result["data"] = interests
+	return result
+}
+
+// PlaceOrder place margin order, trigger orders are placed as algo orders
+func (margin *Margin) PlaceOrder(order *goex.PlaceOrder) interface{} {
+	if err := checkMarketMode(order); err != nil {
+		return goex.InvalidOrder(err)
+	}
+	if goex.IsTriggerOrder(order.TradeType) {
+		return margin.spot.placeAlgoOrder(order, "2")
+	}
+	params := orderParams(order)
+	params["margin_trading"] = "2"
+	return handlerOrderError(margin.spot.httpPost("/api/margin/v3/orders", params, true))
+}
+
+// PlaceLimitOrder place limit margin order
+func (margin *Margin) PlaceLimitOrder(symbol goex.Symbol, price string, amount string, side goex.TradeSide, ClientOrderId string) interface{} {
+	return margin.PlaceOrder(&goex.PlaceOrder{
+		Symbol:        symbol,
+		ClientOrderId: ClientOrderId,
+		Price:         price,
+		Amount:        amount,
+		Side:          side,
+		TradeType:     goex.LIMIT,
+	})
+}
+
+// PlaceMarketOrder place market margin order, amount of buy orders is in quote coin
+func (margin *Margin) PlaceMarketOrder(symbol goex.Symbol, amount string, side goex.TradeSide, ClientOrderId string) interface{} {
+	return margin.PlaceOrder(&goex.PlaceOrder{
+		Symbol:        symbol,
+		ClientOrderId: ClientOrderId,
+		Amount:        amount,
+		Side:          side,
+		TradeType:     goex.MARKET,
+	})
+}
+
+// BatchPlaceLimitOrder batch place limit margin order
+func (margin *Margin) BatchPlaceLimitOrder(orders []goex.LimitOrder) interface{} {
+	params := make([]map[string]interface{}, 0, len(orders))
+	for _, item := range orders {
+		param := orderParams(&goex.PlaceOrder{
+			Symbol:        item.Symbol,
+			ClientOrderId: item.ClientOrderId,
+			Price:         item.Price,
+			Amount:        item.Amount,
+			Side:          item.Side,
+			TradeType:     goex.LIMIT,
+			TimeInForce:   item.TimeInForce,
+		})
+		param["margin_trading"] = "2"
+		params = append(params, param)
+	}
+	return margin.spot.httpPost("/api/margin/v3/batch_orders", params, true)
+}
+
+// CancelOrder cancel margin order
+func (margin *Margin) CancelOrder(symbol goex.Symbol, orderId, clientOrderId string) interface{} {
+	params := map[string]string{"instrument_id": margin.getSymbol(symbol)}
+	id := orderId
+	if clientOrderId != "" {
+		params["client_oid"] = clientOrderId
+		id = clientOrderId
+	} else {
+		params["order_id"] = orderId
+	}
+	return handlerOrderError(margin.spot.httpPost("/api/margin/v3/cancel_orders/"+id, params, true))
+}
+
+// BatchCancelOrder batch cancel margin order
+func (margin *Margin) BatchCancelOrder(symbol goex.Symbol, orderIds, clientOrderIds string) interface{} {
+	param := map[string]interface{}{"instrument_id": margin.getSymbol(symbol)}
+	if clientOrderIds != "" {
+		param["client_oids"] = strings.Split(clientOrderIds, ",")
+	} else {
+		param["order_ids"] = strings.Split(orderIds, ",")
+	}
+	return margin.spot.httpPost("/api/margin/v3/cancel_batch_orders", []map[string]interface{}{param}, true)
+}
+
+// GetUserOpenTrustOrders user open margin order list, options after and before
+func (margin *Margin) GetUserOpenTrustOrders(symbol goex.Symbol, size int, options map[string]string) interface{} {
+	params := margin.pageParams(size, options)
+	params["instrument_id"] = margin.getSymbol(symbol)
+	return margin.spot.httpGet("/api/margin/v3/orders_pending", params, true)
+}
+
+// GetUserOrderInfo user margin order info
+func (margin *Margin) GetUserOrderInfo(symbol goex.Symbol, orderId, clientOrderId string) interface{} {
+	params := map[string]string{"instrument_id": margin.getSymbol(symbol)}
+	id := orderId
+	if clientOrderId != "" {
+		id = clientOrderId
+	}
+	return margin.spot.httpGet("/api/margin/v3/orders/"+id, params, true)
+}
+
+// GetUserTradeOrders user margin fill list, options order_id, after and before
+func (margin *Margin) GetUserTradeOrders(symbol goex.Symbol, size int, options map[string]string) interface{} {
+	params := margin.pageParams(size, options, "order_id")
+	params["instrument_id"] = margin.getSymbol(symbol)
+	return margin.spot.httpGet("/api/margin/v3/fills", params, true)
+}
+
+// GetUserTrustOrders user margin order list of okex state, options after and before
+func (margin *Margin) GetUserTrustOrders(symbol goex.Symbol, status string, size int, options map[string]string) interface{} {
+	params := margin.pageParams(size, options)
+	params["instrument_id"] = margin.getSymbol(symbol)
+	params["state"] = status
+	return margin.spot.httpGet("/api/margin/v3/orders", params, true)
+}
+
+// pageParams limit, after, before and keys of options
+func (margin *Margin) pageParams(size int, options map[string]string, keys ...string) map[string]string {
+	params := map[string]string{}
+	if size != 0 {
+		params["limit"] = strconv.Itoa(size)
+	}
+	for _, key := range append([]string{"after", "before"}, keys...) {
+		if value, ok := options[key]; ok {
+			params[key] = value
+		}
+	}
+	return params
+}
+
+// handlerOrderError v3 order, algo and margin apis answer errors with error_code in body
+func handlerOrderError(retData map[string]interface{}) map[string]interface{} {
+	if retData["code"] != 0 {
+		return retData
+	}
+	data, _ := retData["data"].(map[string]interface{})
+	if code := goex.ToString(data["error_code"]); code != "" && code != "0" {
+		retData["code"] = goex.ExchangeError.Code
+		retData["msg"] = goex.ExchangeError.Msg
+		retData["error"] = code + ": " + goex.ToString(data["error_message"])
+		retData["data"] = nil
+	}
+	return retData
+}
+
+func (margin *Margin) getSymbol(symbol goex.Symbol) string {
+	return symbol.ToUpper().ToSymbol("-")
+}
+
+// marginBalances balances of margin accounts, every account holds its coins
+// under keys like currency:BTC
+func marginBalances(data interface{}) []goex.MarginBalance {
+	list, _ := data.([]interface{})
+	balances := []goex.MarginBalance{}
+	for _, item := range list {
+		account, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		symbol := goex.ToString(account["instrument_id"])
+		keys := make([]string, 0, 2)
+		for key := range account {
+			if strings.HasPrefix(key, "currency:") {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			record, ok := account[key].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			balances = append(balances, goex.MarginBalance{
+				Symbol:   symbol,
+				Coin:     strings.TrimPrefix(key, "currency:"),
+				Free:     goex.ToFloat(record["available"]),
+				Frozen:   goex.ToFloat(record["hold"]),
+				Borrowed: goex.ToFloat(record["borrowed"]),
+				Interest: goex.ToFloat(record["lending_fee"]),
+			})
+		}
+	}
+	return balances
+}
+
+// marginInterests interest of borrow records, newest first
+func marginInterests(data interface{}) []goex.MarginInterest {
+	list, _ := data.([]interface{})
+	interests := make([]goex.MarginInterest, 0, len(list))
+	for _, item := range list {
+		record, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		interests = append(interests, goex.MarginInterest{
+			Symbol:   goex.ToString(record["instrument_id"]),
+			Coin:     goex.ToString(record["currency"]),
+			Interest: goex.ToFloat(record["interest"]),
+			Rate:     goex.ToFloat(record["rate"]),
+			Time:     goex.ParseTimestamp(record["created_at"]),
+		})
+	}
+	sort.SliceStable(interests, func(i, j int) bool { return interests[i].Time > interests[j].Time })
+	return interests
+}
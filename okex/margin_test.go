@@ -0,0 +1,76 @@
+package okex
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	goex "github.com/primitivelab/goexchange"
+)
+
+func getMarginInstance() goex.MarginAPI {
+
+	client = &http.Client{}
+	config, err := goex.LoadConfig("okex")
+	if err != nil {
+		fmt.Println(err)
+	}
+	if config != nil {
+		apiKey = config["key"].(string)
+		secretKey = config["secret"].(string)
+		passphrase, _ = config["passphrase"].(string)
+	}
+
+	conf := goex.APIConfig{}
+	conf.ApiKey = apiKey
+	conf.ApiSecretKey = secretKey
+	conf.ApiPassphrase = passphrase
+	conf.HttpClient = client
+	market := NewMarginWithConfig(&conf)
+	return market
+}
+
+func TestMargin_GetUserBalance(t *testing.T) {
+	market := getMarginInstance()
+
+	response := market.GetUserBalance()
+	b, _ := json.Marshal(response)
+	t.Log(string(b))
+}
+
+func TestMargin_GetInterestHistory(t *testing.T) {
+	market := getMarginInstance()
+
+	response := market.GetInterestHistory(goex.NewSymbol("btc", "usdt"), "usdt", 10, nil)
+	b, _ := json.Marshal(response)
+	t.Log(string(b))
+}
+
+func TestMargin_GetUserOpenTrustOrders(t *testing.T) {
+	market := getMarginInstance()
+
+	response := market.GetUserOpenTrustOrders(goex.NewSymbol("btc", "usdt"), 10, nil)
+	b, _ := json.Marshal(response)
+	t.Log(string(b))
+}
+
+func TestMarginBalances(t *testing.T) {
+	var _ goex.MarginAPI = &Margin{}
+
+	var data interface{}
+	json.Unmarshal([]byte(`[{"instrument_id":"BTC-USDT","risk_rate":"10",
+		"currency:BTC":{"available":"0.5","balance":"0.6","borrowed":"0","hold":"0.1","lending_fee":"0"},
+		"currency:USDT":{"available":"100","balance":"100","borrowed":"50","hold":"0","lending_fee":"0.1"}}]`), &data)
+	balances := marginBalances(data)
+	if len(balances) != 2 || balances[0].Coin != "BTC" || balances[0].Frozen != 0.1 || balances[1].Symbol != "BTC-USDT" || balances[1].Borrowed != 50 {
+		t.Fatalf("unexpected balances %+v", balances)
+	}
+
+	json.Unmarshal([]byte(`[{"borrow_id":"1","instrument_id":"BTC-USDT","currency":"USDT","interest":"0.01","rate":"0.0002","created_at":"2021-05-03T00:00:00.000Z"},
+		{"borrow_id":"2","instrument_id":"BTC-USDT","currency":"USDT","interest":"0.02","rate":"0.0002","created_at":"2021-05-03T08:00:00.000Z"}]`), &data)
+	interests := marginInterests(data)
+	if len(interests) != 2 || interests[0].Interest != 0.02 || interests[0].Time != 1620028800000 {
+		t.Fatalf("unexpected interests %+v", interests)
+	}
+}
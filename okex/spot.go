@@ -163,9 +163,36 @@ func (spot *Spot) GetUserBalance() interface{} {
 	return result
 }
 
-// 批量下单
+// 下单, 止损止盈和跟踪委托为策略委托
 func (spot *Spot) PlaceOrder(order *PlaceOrder) interface{} {
+	if err := checkMarketMode(order); err != nil {
+		return InvalidOrder(err)
+	}
+	if IsTriggerOrder(order.TradeType) {
+		return spot.placeAlgoOrder(order, "1")
+	}
+	retData := spot.httpPost("/api/spot/v3/orders", orderParams(order), true)
+	spot.handlerError(retData)
+	return retData
+}
+
+// checkMarketMode market buys take notional in quote coin and market sells
+// take size in base coin, market orders have no time in force but IOC
+func checkMarketMode(order *PlaceOrder) error {
+	if IsMarketOrder(order) {
+		if err := CheckTimeInForce(order.TimeInForce, IOC); err != nil {
+			return err
+		}
+	}
+	if order.Side == SELL {
+		return CheckMarketMode(order, MarketOrderByBase)
+	}
+	return CheckMarketMode(order, MarketOrderByQuote)
+}
 
+// orderParams v3 order params shared by spot and margin orders, type and size
+// are sent as PlaceLimitOrder and PlaceMarketOrder send them
+func orderParams(order *PlaceOrder) map[string]interface{} {
 	params := map[string]interface{}{}
 	params["instrument_id"] = order.Symbol.ToUpper().ToSymbol("-")
 	if order.ClientOrderId != "" {
@@ -173,26 +200,26 @@ func (spot *Spot) PlaceOrder(order *PlaceOrder) interface{} {
 	}
 	params["side"] = order.Side.String()
 	if order.TradeType == LIMIT {
+		params["type"] = LIMIT
 		params["price"] = order.Price
-		params["amount"] = order.Amount
+		params["size"] = order.Amount
 		switch order.TimeInForce {
 		case IOC:
 			params["order_type"] = 3
 		case FOK:
 			params["order_type"] = 2
-		case POC:
+		case POC, GTX:
 			params["order_type"] = 1
 		}
 	} else {
+		params["type"] = MARKET
 		if order.Side == BUY {
 			params["notional"] = order.Amount
 		} else {
 			params["size"] = order.Amount
 		}
 	}
-	retData := spot.httpPost("/api/spot/v3/orders", params, true)
-	spot.handlerError(retData)
-	return retData
+	return params
 }
 
 // 下限价单
@@ -251,7 +278,7 @@ func (spot *Spot) BatchPlaceLimitOrder(orders []LimitOrder) interface{} {
 			param["order_type"] = 3
 		case FOK:
 			param["order_type"] = 2
-		case POC:
+		case POC, GTX:
 			param["order_type"] = 1
 		}
 		params = append(params, param)
@@ -390,13 +417,29 @@ func (spot *Spot) HttpRequest(requestUrl, method string, options interface{}, si
 	method = strings.ToUpper(method)
 	switch method {
 	case HTTP_GET:
-		return spot.httpGet(requestUrl, options.(map[string]string), signed)
+		return spot.httpGet(requestUrl, stringOptions(options), signed)
 	case HTTP_POST:
 		return spot.httpPost(requestUrl, options, signed)
 	}
 	return nil
 }
 
+// stringOptions query options of HttpRequest, options are map[string]string
+// or map[string]interface{}
+func stringOptions(options interface{}) map[string]string {
+	switch options := options.(type) {
+	case map[string]string:
+		return options
+	case map[string]interface{}:
+		params := make(map[string]string, len(options))
+		for key, value := range options {
+			params[key] = ToString(value)
+		}
+		return params
+	}
+	return nil
+}
+
 func (spot *Spot) httpGet(url string, params map[string]string, signed bool) map[string]interface{} {
 	var responseMap HttpClientResponse
 	var headers map[string]string
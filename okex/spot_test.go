@@ -171,3 +171,71 @@ func TestHttpRequest(t *testing.T) {
 	b, _ := json.Marshal(response)
 	t.Log(string(b))
 }
+
+func TestGetDepositAddresses(t *testing.T) {
+	market := New(client, "", apiKey, secretKey, passphrase)
+	response := market.GetDepositAddresses("usdt", "")
+	b, _ := json.Marshal(response)
+	t.Log(string(b))
+}
+
+func TestGetDepositHistory(t *testing.T) {
+	market := New(client, "", apiKey, secretKey, passphrase)
+	response := market.GetDepositHistory("usdt", 10, nil)
+	b, _ := json.Marshal(response)
+	t.Log(string(b))
+}
+
+func TestGetWithdrawHistory(t *testing.T) {
+	market := New(client, "", apiKey, secretKey, passphrase)
+	response := market.GetWithdrawHistory("usdt", 10, nil)
+	b, _ := json.Marshal(response)
+	t.Log(string(b))
+}
+
+func TestGetSubAccounts(t *testing.T) {
+	market := New(client, "", apiKey, secretKey, passphrase)
+	response := market.GetSubAccounts()
+	b, _ := json.Marshal(response)
+	t.Log(string(b))
+}
+
+func TestGetSubAccountBalances(t *testing.T) {
+	market := New(client, "", apiKey, secretKey, passphrase)
+	response := market.GetSubAccountBalances("")
+	b, _ := json.Marshal(response)
+	t.Log(string(b))
+}
+
+func TestCheckMarketMode(t *testing.T) {
+	if err := checkMarketMode(&PlaceOrder{Side: BUY, TradeType: MARKET, MarketMode: MarketOrderByQuote}); err != nil {
+		t.Fatal(err)
+	}
+	if err := checkMarketMode(&PlaceOrder{Side: SELL, TradeType: MARKET, MarketMode: MarketOrderByQuote}); err == nil {
+		t.Fatal("expected error of market sell by quote")
+	}
+}
+
+func TestOrderParams(t *testing.T) {
+	params := orderParams(&PlaceOrder{Symbol: NewSymbol("btc", "usdt"), Side: BUY, TradeType: LIMIT, Price: "100", Amount: "2"})
+	if params["type"] != LIMIT || params["size"] != "2" || params["price"] != "100" || params["instrument_id"] != "BTC-USDT" {
+		t.Fatalf("unexpected limit params %v", params)
+	}
+	params = orderParams(&PlaceOrder{Symbol: NewSymbol("btc", "usdt"), Side: BUY, TradeType: MARKET, Amount: "50"})
+	if params["type"] != MARKET || params["notional"] != "50" {
+		t.Fatalf("unexpected market params %v", params)
+	}
+}
+
+func TestStringOptions(t *testing.T) {
+	params := stringOptions(map[string]interface{}{"granularity": 300, "limit": "10"})
+	if params["granularity"] != "300" || params["limit"] != "10" {
+		t.Fatalf("unexpected options %v", params)
+	}
+	if params := stringOptions(map[string]string{"limit": "10"}); params["limit"] != "10" {
+		t.Fatalf("unexpected options %v", params)
+	}
+	if stringOptions(nil) != nil {
+		t.Fatal("nil options should stay nil")
+	}
+}
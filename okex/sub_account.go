@@ -0,0 +1,205 @@
+package okex
+
+import (
+	"strings"
+
+	. "github.com/primitivelab/goexchange"
+)
+
+// 子账户列表, v3 没有子账户管理接口, 使用签名方式相同的 v5 接口, id 为子账户名
+func (spot *Spot) GetSubAccounts() interface{} {
+	result := spot.httpGet("/api/v5/users/subaccount/list", nil, true)
+	if !spot.handlerV5Error(result) {
+		return result
+	}
+
+	list, _ := result["data"].([]interface{})
+	accounts := make([]SubAccount, 0, len(list))
+	for _, item := range list {
+		record, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		accounts = append(accounts, SubAccount{
+			Id:      ToString(record["subAcct"]),
+			Name:    ToString(record["label"]),
+			Enabled: record["enable"] == true,
+			Time:    ParseTimestamp(record["ts"]),
+		})
+	}
+	result["data"] = accounts
+	return result
+}
+
+// 子账户交易账户资产
+func (spot *Spot) GetSubAccountBalances(subAccount string) interface{} {
+	params := map[string]string{"subAcct": subAccount}
+	result := spot.httpGet("/api/v5/account/subaccount/balances", params, true)
+	if !spot.handlerV5Error(result) {
+		return result
+	}
+
+	list, _ := result["data"].([]interface{})
+	balances := []AssetBalance{}
+	for _, item := range list {
+		account, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		details, _ := account["details"].([]interface{})
+		for _, detail := range details {
+			record, ok := detail.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			balances = append(balances, AssetBalance{
+				Coin:   ToString(record["ccy"]),
+				Free:   ToFloat(record["availBal"]),
+				Frozen: ToFloat(record["frozenBal"]),
+			})
+		}
+	}
+	result["data"] = balances
+	return result
+}
+
+// 母子账户资金账户划转, 子账户之间划转使用 subaccount/transfer 接口
+func (spot *Spot) SubAccountTransfer(request *SubTransferRequest) interface{} {
+	if request.From == request.To {
+		return ReturnAPIError(TransferAccountError)
+	}
+	path := "/api/v5/asset/transfer"
+	params := map[string]string{
+		"ccy":  strings.ToUpper(request.Coin),
+		"amt":  request.Amount,
+		"from": "6",
+		"to":   "6",
+	}
+	if request.From == "" {
+		params["type"] = "1"
+		params["subAcct"] = request.To
+	} else if request.To == "" {
+		params["type"] = "2"
+		params["subAcct"] = request.From
+	} else {
+		path = "/api/v5/asset/subaccount/transfer"
+		params["fromSubAccount"] = request.From
+		params["toSubAccount"] = request.To
+	}
+	for key, value := range request.Options {
+		params[key] = value
+	}
+
+	result := spot.httpPost(path, params, true)
+	if !spot.handlerV5Error(result) {
+		return result
+	}
+	list, _ := result["data"].([]interface{})
+	result["data"] = ""
+	if len(list) > 0 {
+		record, _ := list[0].(map[string]interface{})
+		result["data"] = ToString(record["transId"])
+	}
+	return result
+}
+
+// 创建子账户 api key, request.Passphrase 必填
+func (spot *Spot) CreateSubAccountAPIKey(request *SubAPIKeyRequest) interface{} {
+	permissions := []string{"read_only"}
+	if request.Trade {
+		permissions = append(permissions, "trade")
+	}
+	if request.Withdraw {
+		permissions = append(permissions, "withdraw")
+	}
+	params := map[string]string{
+		"subAcct":    request.SubAccount,
+		"label":      request.Label,
+		"passphrase": request.Passphrase,
+		"perm":       strings.Join(permissions, ","),
+	}
+	if len(request.IPs) > 0 {
+		params["ip"] = strings.Join(request.IPs, ",")
+	}
+	for key, value := range request.Options {
+		params[key] = value
+	}
+
+	result := spot.httpPost("/api/v5/users/subaccount/apikey", params, true)
+	if !spot.handlerV5Error(result) {
+		return result
+	}
+	list, _ := result["data"].([]interface{})
+	key := SubAPIKey{SubAccount: request.SubAccount}
+	if len(list) > 0 {
+		record, _ := list[0].(map[string]interface{})
+		key = parseSubAPIKey(request.SubAccount, record)
+		key.SecretKey = ToString(record["secretKey"])
+		key.Passphrase = ToString(record["passphrase"])
+	}
+	result["data"] = key
+	return result
+}
+
+// 子账户 api key 列表
+func (spot *Spot) GetSubAccountAPIKeys(subAccount string) interface{} {
+	params := map[string]string{"subAcct": subAccount}
+	result := spot.httpGet("/api/v5/users/subaccount/apikey", params, true)
+	if !spot.handlerV5Error(result) {
+		return result
+	}
+
+	list, _ := result["data"].([]interface{})
+	keys := make([]SubAPIKey, 0, len(list))
+	for _, item := range list {
+		if record, ok := item.(map[string]interface{}); ok {
+			keys = append(keys, parseSubAPIKey(subAccount, record))
+		}
+	}
+	result["data"] = keys
+	return result
+}
+
+// 删除子账户 api key
+func (spot *Spot) DeleteSubAccountAPIKey(subAccount, apiKey string) interface{} {
+	params := map[string]string{"subAcct": subAccount, "apiKey": apiKey}
+	result := spot.httpPost("/api/v5/users/subaccount/delete-apikey", params, true)
+	if !spot.handlerV5Error(result) {
+		return result
+	}
+	result["data"] = apiKey
+	return result
+}
+
+// v5 接口错误处理, 成功时 data 替换为返回的 data 列表
+func (spot *Spot) handlerV5Error(retData map[string]interface{}) bool {
+	if retData["code"] != 0 {
+		return false
+	}
+	data, _ := retData["data"].(map[string]interface{})
+	if code := ToString(data["code"]); code != "0" {
+		retData["code"] = ExchangeError.Code
+		retData["msg"] = ExchangeError.Msg
+		retData["error"] = code + ": " + ToString(data["msg"])
+		retData["data"] = nil
+		return false
+	}
+	retData["data"] = data["data"]
+	return true
+}
+
+func parseSubAPIKey(subAccount string, record map[string]interface{}) SubAPIKey {
+	key := SubAPIKey{
+		SubAccount: subAccount,
+		Label:      ToString(record["label"]),
+		ApiKey:     ToString(record["apiKey"]),
+		Time:       ParseTimestamp(record["ts"]),
+	}
+	if perm := ToString(record["perm"]); perm != "" {
+		key.Permissions = strings.Split(perm, ",")
+	}
+	if ip := ToString(record["ip"]); ip != "" {
+		key.IPs = strings.Split(ip, ",")
+	}
+	return key
+}
@@ -150,12 +150,240 @@ func (swap *Swap) GetPremiumIndex(symbol goex.Symbol) interface{} {
 	return result
 }
 
+// GetUserBalance user account balance of all contracts
+func (swap *Swap) GetUserBalance() interface{} {
+	return swap.httpGet("/api/swap/v3/accounts", nil, true)
+}
+
+// GetUserAssets user account balance and open positions of all contracts
+func (swap *Swap) GetUserAssets() interface{} {
+	result := swap.GetUserBalance().(map[string]interface{})
+	if result["code"] != 0 {
+		return result
+	}
+	positions := swap.GetUserPositions(goex.Symbol{}).(map[string]interface{})
+	if positions["code"] != 0 {
+		return positions
+	}
+	result["data"] = map[string]interface{}{
+		"accounts":  result["data"],
+		"positions": positions["data"],
+	}
+	return result
+}
+
+// GetUserPositions user open positions, all contracts when symbol is empty,
+// data: []goex.Position
+func (swap *Swap) GetUserPositions(symbol goex.Symbol) interface{} {
+	path := "/api/swap/v3/position"
+	if symbol.CoinFrom != "" {
+		path = fmt.Sprintf("/api/swap/v3/%s/position", swap.getSymbol(symbol))
+	}
+	result := swap.httpGet(path, nil, true)
+	if result["code"] != 0 {
+		return result
+	}
+	result["data"] = swap.parsePositions(result["data"])
+	return result
+}
+
+// ClosePosition close positions of contract at market when price is empty,
+// or with limit orders at price
+func (swap *Swap) ClosePosition(symbol goex.Symbol, price string) interface{} {
+	return goex.ClosePositions(swap.GetUserPositions(symbol), symbol, price, swap.PlaceOrder)
+}
+
+// GetLeverRate leverage and margin mode settings of contract
+func (swap *Swap) GetLeverRate(symbol goex.Symbol) interface{} {
+	return swap.httpGet(fmt.Sprintf("/api/swap/v3/accounts/%s/settings", swap.getSymbol(symbol)), nil, true)
+}
+
+// SetLeverRate set leverage of contract in crossed margin mode, options side
+// 1 or 2 sets long or short leverage in fixed margin mode
+func (swap *Swap) SetLeverRate(symbol goex.Symbol, leverRate int, options map[string]string) interface{} {
+	params := map[string]string{
+		"leverage": strconv.Itoa(leverRate),
+		"side":     "3",
+	}
+	if side, ok := options["side"]; ok {
+		params["side"] = side
+	}
+	return swap.httpPost(fmt.Sprintf("/api/swap/v3/accounts/%s/leverage", swap.getSymbol(symbol)), params, true)
+}
+
+// PlaceOrder place order, amount is number of contracts, offset is open
+// when order has none, trigger orders are placed as algo orders
+func (swap *Swap) PlaceOrder(order *goex.PlaceOrder) interface{} {
+	if err := checkContractOrder(order); err != nil {
+		return goex.InvalidOrder(err)
+	}
+	if goex.IsTriggerOrder(order.TradeType) {
+		return swap.placeAlgoOrder("/api/swap/v3/order_algo", swap.getSymbol(order.Symbol), order)
+	}
+	params := swap.orderParams(order)
+	params["instrument_id"] = swap.getSymbol(order.Symbol)
+	return swap.handlerOrderError(swap.httpPost("/api/swap/v3/order", params, true))
+}
+
+// PlaceLimitOrder place limit order to open position
+func (swap *Swap) PlaceLimitOrder(symbol goex.Symbol, price string, amount string, side goex.TradeSide, ClientOrderID string) interface{} {
+	return swap.PlaceOrder(&goex.PlaceOrder{
+		Symbol:        symbol,
+		ClientOrderId: ClientOrderID,
+		Price:         price,
+		Amount:        amount,
+		Side:          side,
+		TradeType:     goex.LIMIT,
+	})
+}
+
+// PlaceMarketOrder place market order to open position
+func (swap *Swap) PlaceMarketOrder(symbol goex.Symbol, amount string, side goex.TradeSide, ClientOrderID string) interface{} {
+	return swap.PlaceOrder(&goex.PlaceOrder{
+		Symbol:        symbol,
+		ClientOrderId: ClientOrderID,
+		Amount:        amount,
+		Side:          side,
+		TradeType:     goex.MARKET,
+	})
+}
+
+// BatchPlaceLimitOrder batch place limit order of the first order's
+// contract, okex accepts 10 orders at most
+func (swap *Swap) BatchPlaceLimitOrder(orders []goex.LimitOrder) interface{} {
+	if len(orders) == 0 {
+		return goex.ReturnAPIData([]interface{}{})
+	}
+	var orderData []map[string]string
+	for index, item := range orders {
+		if index > 9 {
+			break
+		}
+		orderData = append(orderData, swap.orderParams(&goex.PlaceOrder{
+			Symbol:        item.Symbol,
+			ClientOrderId: item.ClientOrderId,
+			Price:         item.Price,
+			Amount:        item.Amount,
+			Side:          item.Side,
+			TradeType:     goex.LIMIT,
+			TimeInForce:   item.TimeInForce,
+			Offset:        item.Offset,
+		}))
+	}
+	params := map[string]interface{}{
+		"instrument_id": swap.getSymbol(orders[0].Symbol),
+		"order_data":    orderData,
+	}
+	return swap.httpPost("/api/swap/v3/orders", params, true)
+}
+
+// CancelOrder cancel user trust order
+func (swap *Swap) CancelOrder(symbol goex.Symbol, orderID, clientOrderID string) interface{} {
+	id := orderID
+	if clientOrderID != "" {
+		id = clientOrderID
+	}
+	path := fmt.Sprintf("/api/swap/v3/cancel_order/%s/%s", swap.getSymbol(symbol), id)
+	return swap.handlerOrderError(swap.httpPost(path, nil, true))
+}
+
+// BatchCancelOrder batch cancel trust order, ids are separated by comma
+func (swap *Swap) BatchCancelOrder(symbol goex.Symbol, orderIds, clientOrderIds string) interface{} {
+	params := map[string][]string{}
+	if clientOrderIds != "" {
+		params["client_oids"] = strings.Split(strings.Replace(clientOrderIds, " ", "", -1), ",")
+	} else {
+		params["ids"] = strings.Split(strings.Replace(orderIds, " ", "", -1), ",")
+	}
+	path := fmt.Sprintf("/api/swap/v3/cancel_batch_orders/%s", swap.getSymbol(symbol))
+	return swap.httpPost(path, params, true)
+}
+
+// BatchCancelAllOrder cancel all open orders, okex has no cancel all api so
+// open orders are listed and canceled 10 at a time
+func (swap *Swap) BatchCancelAllOrder(symbol goex.Symbol) interface{} {
+	result := swap.GetUserOpenTrustOrders(symbol, 100, nil).(map[string]interface{})
+	if result["code"] != 0 {
+		return result
+	}
+	data, _ := result["data"].(map[string]interface{})
+	list, _ := data["order_info"].([]interface{})
+	var ids []string
+	for _, item := range list {
+		if order, ok := item.(map[string]interface{}); ok {
+			ids = append(ids, goex.ToString(order["order_id"]))
+		}
+	}
+
+	var canceled []interface{}
+	for start := 0; start < len(ids); start += 10 {
+		end := start + 10
+		if end > len(ids) {
+			end = len(ids)
+		}
+		result = swap.BatchCancelOrder(symbol, strings.Join(ids[start:end], ","), "").(map[string]interface{})
+		if result["code"] != 0 {
+			return result
+		}
+		canceled = append(canceled, result["data"])
+	}
+	result["data"] = canceled
+	return result
+}
+
+// GetUserOpenTrustOrders user open and partially filled order list, options after and before
+func (swap *Swap) GetUserOpenTrustOrders(symbol goex.Symbol, size int, options map[string]string) interface{} {
+	return swap.GetUserTrustOrders(symbol, "6", size, options)
+}
+
+// GetUserOrderInfo user trust order info
+func (swap *Swap) GetUserOrderInfo(symbol goex.Symbol, orderID, clientOrderID string) interface{} {
+	id := orderID
+	if clientOrderID != "" {
+		id = clientOrderID
+	}
+	return swap.httpGet(fmt.Sprintf("/api/swap/v3/orders/%s/%s", swap.getSymbol(symbol), id), nil, true)
+}
+
+// GetUserTradeOrders user fill list, options order_id, after and before
+func (swap *Swap) GetUserTradeOrders(symbol goex.Symbol, size int, options map[string]string) interface{} {
+	params := &url.Values{}
+	params.Set("instrument_id", swap.getSymbol(symbol))
+	swap.pageParams(params, size, options, "order_id")
+	return swap.httpGet("/api/swap/v3/fills", params, true)
+}
+
+// GetUserTrustOrders user order list of okex state, default 7 for completed
+// orders, options after and before
+func (swap *Swap) GetUserTrustOrders(symbol goex.Symbol, status string, size int, options map[string]string) interface{} {
+	params := &url.Values{}
+	params.Set("state", "7")
+	if status != "" {
+		params.Set("state", status)
+	}
+	swap.pageParams(params, size, options)
+	return swap.httpGet(fmt.Sprintf("/api/swap/v3/orders/%s", swap.getSymbol(symbol)), params, true)
+}
+
+// GetUserAssetsIncomes user bills of contract, options type, after and before
+func (swap *Swap) GetUserAssetsIncomes(symbol goex.Symbol, size int, options map[string]string) interface{} {
+	params := &url.Values{}
+	swap.pageParams(params, size, options, "type")
+	return swap.httpGet(fmt.Sprintf("/api/swap/v3/accounts/%s/ledger", swap.getSymbol(symbol)), swap.query(params), true)
+}
+
+// GetUserCommissionRate user current commission rate
+func (swap *Swap) GetUserCommissionRate(symbol goex.Symbol) interface{} {
+	params := &url.Values{}
+	params.Set("instrument_id", swap.getSymbol(symbol))
+	return swap.httpGet("/api/swap/v3/trade_fee", params, true)
+}
+
 // HTTPRequest request url
 func (swap *Swap) HTTPRequest(requestURL, method string, options interface{}, signed bool) interface{} {
 	method = strings.ToUpper(method)
 	params := &url.Values{}
-	mapOptions := options.(map[string]string)
-	for key, val := range mapOptions {
+	for key, val := range stringOptions(options) {
 		params.Set(key, val)
 	}
 	switch method {
@@ -252,6 +480,133 @@ func (swap *Swap) handlerResponse(responseMap *goex.HttpClientResponse) map[stri
 	return returnData
 }
 
+// checkContractOrder market mode and time in force of swap and futures
+// orders, amount is number of contracts and market orders are IOC only
+func checkContractOrder(order *goex.PlaceOrder) error {
+	if goex.IsMarketOrder(order) {
+		if err := goex.CheckTimeInForce(order.TimeInForce, goex.IOC); err != nil {
+			return err
+		}
+	}
+	return goex.CheckMarketMode(order)
+}
+
+// orderParams okex swap order params, type is 1 open long, 2 open short,
+// 3 close long and 4 close short
+func (swap *Swap) orderParams(order *goex.PlaceOrder) map[string]string {
+	params := map[string]string{"size": order.Amount}
+	if order.ClientOrderId != "" {
+		params["client_oid"] = order.ClientOrderId
+	}
+	params["type"] = positionType(order)
+	if order.TradeType == goex.MARKET {
+		params["order_type"] = "4"
+		return params
+	}
+	params["price"] = order.Price
+	switch order.TimeInForce {
+	case goex.POC, goex.GTX:
+		params["order_type"] = "1"
+	case goex.FOK:
+		params["order_type"] = "2"
+	case goex.IOC:
+		params["order_type"] = "3"
+	default:
+		params["order_type"] = "0"
+	}
+	return params
+}
+
+// positionType type of contract order, 1 open long, 2 open short, 3 close
+// long and 4 close short, reduce only orders close position
+func positionType(order *goex.PlaceOrder) string {
+	if order.Offset == goex.OFFSET_CLOSE || order.ReduceOnly {
+		if order.Side == goex.BUY {
+			return "4"
+		}
+		return "3"
+	}
+	if order.Side == goex.SELL {
+		return "2"
+	}
+	return "1"
+}
+
+// pageParams set limit and paging options with extra option keys
+func (swap *Swap) pageParams(params *url.Values, size int, options map[string]string, keys ...string) {
+	if size != 0 {
+		params.Set("limit", strconv.Itoa(size))
+	}
+	for _, key := range append([]string{"after", "before"}, keys...) {
+		if value, ok := options[key]; ok {
+			params.Set(key, value)
+		}
+	}
+}
+
+// query nil for empty params, signed requests sign the query string
+func (swap *Swap) query(params *url.Values) *url.Values {
+	if params == nil || len(*params) == 0 {
+		return nil
+	}
+	return params
+}
+
+// handlerOrderError order apis answer errors with error_code in body
+func (swap *Swap) handlerOrderError(retData map[string]interface{}) map[string]interface{} {
+	if retData["code"] != 0 {
+		return retData
+	}
+	data, _ := retData["data"].(map[string]interface{})
+	if code := goex.ToString(data["error_code"]); code != "" && code != "0" {
+		retData["code"] = goex.ExchangeError.Code
+		retData["msg"] = goex.ExchangeError.Msg
+		retData["error"] = code + ": " + goex.ToString(data["error_message"])
+		retData["data"] = nil
+	}
+	return retData
+}
+
+// parsePositions typed positions of one position object or a list of them,
+// margin_mode of holdings falls back to the one of the object
+func (swap *Swap) parsePositions(data interface{}) []goex.Position {
+	list, ok := data.([]interface{})
+	if !ok {
+		list = []interface{}{data}
+	}
+	positions := []goex.Position{}
+	for _, item := range list {
+		object, _ := item.(map[string]interface{})
+		holding, _ := object["holding"].([]interface{})
+		for _, item := range holding {
+			record, ok := item.(map[string]interface{})
+			if !ok || goex.ToFloat(record["position"]) == 0 {
+				continue
+			}
+			marginMode, ok := record["margin_mode"]
+			if !ok {
+				marginMode = object["margin_mode"]
+			}
+			position := goex.Position{
+				Symbol:           goex.ToString(record["instrument_id"]),
+				Side:             goex.POSITION_LONG,
+				Amount:           goex.ToFloat(record["position"]),
+				EntryPrice:       goex.ToFloat(record["avg_cost"]),
+				MarkPrice:        goex.ToFloat(record["last"]),
+				UnrealizedPnl:    goex.ToFloat(record["unrealized_pnl"]),
+				Leverage:         goex.ToFloat(record["leverage"]),
+				MarginType:       goex.ParseMarginType(marginMode),
+				LiquidationPrice: goex.ToFloat(record["liquidation_price"]),
+			}
+			if record["side"] == "short" {
+				position.Side = goex.POSITION_SHORT
+			}
+			positions = append(positions, position)
+		}
+	}
+	return positions
+}
+
 // getSymbol format symbol method
 func (swap Swap) getSymbol(symbol goex.Symbol) string {
 	return symbol.ToUpper().ToSymbol("-") + "-SWAP"
@@ -15,7 +15,7 @@ import (
 var CoinFrom = "dot"
 var CoinTo = "usdt"
 
-func getSwapInstance() Swap {
+func getSwapInstance() *Swap {
 
 	client = &http.Client{}
 	config, err := goex.LoadConfig("okex")
@@ -25,16 +25,17 @@ func getSwapInstance() Swap {
 	if config != nil {
 		apiKey = config["key"].(string)
 		secretKey = config["secret"].(string)
+		passphrase, _ = config["passphrase"].(string)
 		// baseURL = config["url"].(string)
 	}
 
-	// market := NewSwapUsdt(client, baseURL, apiKey, secretKey)
-	// market := NewSwapCoin(client, baseURL, apiKey, secretKey)
+	// market := NewSwap(client, baseURL, apiKey, secretKey, passphrase)
 	conf := goex.APIConfig{}
 	conf.ApiKey = apiKey
 	conf.ApiSecretKey = secretKey
+	conf.ApiPassphrase = passphrase
 	conf.HttpClient = client
-	market := NewSwapUsdtWithConfig(&conf)
+	market := NewSwapWithConfig(&conf)
 	return market
 }
 
@@ -86,133 +87,262 @@ func TestSwap_GetPremiumIndex(t *testing.T) {
 	t.Log(string(b))
 }
 
-// func TestSwap_GetUserBalance(t *testing.T) {
-// 	market := getSwapInstance()
-// 	response := market.GetUserBalance()
-// 	b, _ := json.Marshal(response)
-// 	t.Log(string(b))
-// }
+func TestSwap_GetUserBalance(t *testing.T) {
+	market := getSwapInstance()
+	response := market.GetUserBalance()
+	b, _ := json.Marshal(response)
+	t.Log(string(b))
+}
+
+func TestSwap_GetUserAssets(t *testing.T) {
+	market := getSwapInstance()
+	response := market.GetUserAssets()
+	b, _ := json.Marshal(response)
+	t.Log(string(b))
+}
+
+func TestSwap_GetUserPositions(t *testing.T) {
+	market := getSwapInstance()
+	response := market.GetUserPositions(goex.Symbol{})
+	b, _ := json.Marshal(response)
+	t.Log(string(b))
+}
+
+func TestSwap_GetUserAssetsIncomes(t *testing.T) {
+	market := getSwapInstance()
+	response := market.GetUserAssetsIncomes(goex.NewSymbol(CoinFrom, CoinTo), 5, nil)
+	b, _ := json.Marshal(response)
+	t.Log(string(b))
+}
+
+func TestSwap_GetUserCommissionRate(t *testing.T) {
+	market := getSwapInstance()
+	response := market.GetUserCommissionRate(goex.NewSymbol(CoinFrom, CoinTo))
+	b, _ := json.Marshal(response)
+	t.Log(string(b))
+}
+
+func TestSwap_GetUserOpenTrustOrders(t *testing.T) {
+	market := getSwapInstance()
+
+	response := market.GetUserOpenTrustOrders(goex.NewSymbol(CoinFrom, CoinTo), 2, nil)
+	b, _ := json.Marshal(response)
+	t.Log(string(b))
+}
+
+func TestSwap_GetUserOrderInfo(t *testing.T) {
+	market := getSwapInstance()
+
+	response := market.GetUserOrderInfo(goex.NewSymbol(CoinFrom, CoinTo), "2785058797", "")
+	b, _ := json.Marshal(response)
+	t.Log(string(b))
+}
+
+func TestSwap_GetUserTrustOrders(t *testing.T) {
+	market := getSwapInstance()
+
+	response := market.GetUserTrustOrders(goex.NewSymbol(CoinFrom, CoinTo), "", 10, nil)
+	b, _ := json.Marshal(response)
+	t.Log(string(b))
+}
+
+func TestSwap_GetUserTradeOrders(t *testing.T) {
+	market := getSwapInstance()
+
+	response := market.GetUserTradeOrders(goex.NewSymbol(CoinFrom, CoinTo), 10, nil)
+	b, _ := json.Marshal(response)
+	t.Log(string(b))
+}
+
+func TestSwap_PlaceLimitOrder(t *testing.T) {
+	market := getSwapInstance()
+
+	response := market.PlaceLimitOrder(goex.NewSymbol(CoinFrom, CoinTo), "1", "10", goex.BUY, "")
+	b, _ := json.Marshal(response)
+	t.Log(string(b))
+}
+
+func TestSwap_BatchPlaceLimitOrder(t *testing.T) {
+	market := getSwapInstance()
+
+	// symbol Symbol, status string, size int, options map[string]string
+
+	order := goex.LimitOrder{}
+	order.Symbol = goex.NewSymbol(CoinFrom, CoinTo)
+	order.Price = "1"
+	order.Amount = "10"
+	order.Side = goex.BUY
+
+	order1 := goex.LimitOrder{}
+	order1.Symbol = goex.NewSymbol(CoinFrom, CoinTo)
+	order1.Price = "1"
+	order1.Amount = "20"
+	order1.Side = goex.BUY
+
+	orders := []goex.LimitOrder{order, order1}
+
+	response := market.BatchPlaceLimitOrder(orders)
+	b, _ := json.Marshal(response)
+	t.Log(string(b))
+}
+
+func TestSwap_PlaceMarketOrder(t *testing.T) {
+	market := getSwapInstance()
+
+	response := market.PlaceMarketOrder(goex.NewSymbol(CoinFrom, CoinTo), "1", goex.BUY, "")
+	b, _ := json.Marshal(response)
+	t.Log(string(b))
+}
 
-// func TestSwap_GetUserAssets(t *testing.T) {
-// 	market := getSwapInstance()
-// 	response := market.GetUserAssets()
-// 	b, _ := json.Marshal(response)
-// 	t.Log(string(b))
-// }
-
-// func TestSwap_GetUserPositions(t *testing.T) {
-// 	market := getSwapInstance()
-// 	response := market.GetUserPositions(goex.Symbol{})
-// 	b, _ := json.Marshal(response)
-// 	t.Log(string(b))
-// }
-
-// func TestSwap_GetUserAssetsIncomes(t *testing.T) {
-// 	market := getSwapInstance()
-// 	response := market.GetUserAssetsIncomes(goex.Symbol{}, 5, nil)
-// 	b, _ := json.Marshal(response)
-// 	t.Log(string(b))
-// }
-
-// func TestSwap_GetUserCommissionRate(t *testing.T) {
-// 	market := getSwapInstance()
-// 	response := market.GetUserCommissionRate(goex.NewSymbol(CoinFrom, CoinTo))
-// 	b, _ := json.Marshal(response)
-// 	t.Log(string(b))
-// }
-
-// func TestSwap_GetUserOpenTrustOrders(t *testing.T) {
-// 	market := getSwapInstance()
-
-// 	response := market.GetUserOpenTrustOrders(goex.NewSymbol(CoinFrom, CoinTo), 2, nil)
-// 	b, _ := json.Marshal(response)
-// 	t.Log(string(b))
-// }
-
-// func TestSwap_GetUserOrderInfo(t *testing.T) {
-// 	market := getSwapInstance()
-
-// 	response := market.GetUserOrderInfo(goex.NewSymbol(CoinFrom, CoinTo), "2785058797", "")
-// 	b, _ := json.Marshal(response)
-// 	t.Log(string(b))
-// }
-
-// func TestSwap_GetUserTrustOrders(t *testing.T) {
-// 	market := getSwapInstance()
-
-// 	response := market.GetUserTrustOrders(goex.NewSymbol(CoinFrom, CoinTo), "", 10, nil)
-// 	b, _ := json.Marshal(response)
-// 	t.Log(string(b))
-// }
-
-// func TestSwap_GetUserTradeOrders(t *testing.T) {
-// 	market := getSwapInstance()
-
-// 	response := market.GetUserTradeOrders(goex.NewSymbol(CoinFrom, CoinTo), 10, nil)
-// 	b, _ := json.Marshal(response)
-// 	t.Log(string(b))
-// }
-
-// func TestSwap_PlaceLimitOrder(t *testing.T) {
-// 	market := getSwapInstance()
-
-// 	response := market.PlaceLimitOrder(goex.NewSymbol(CoinFrom, CoinTo), "1", "10", goex.BUY, "")
-// 	b, _ := json.Marshal(response)
-// 	t.Log(string(b))
-// }
-
-// func TestSwap_BatchPlaceLimitOrder(t *testing.T) {
-// 	market := getSwapInstance()
-
-// 	// symbol Symbol, status string, size int, options map[string]string
-
-// 	order := goex.LimitOrder{}
-// 	order.Symbol = goex.NewSymbol(CoinFrom, CoinTo)
-// 	order.Price = "1"
-// 	order.Amount = "10"
-// 	order.Side = goex.BUY
-
-// 	order1 := goex.LimitOrder{}
-// 	order1.Symbol = goex.NewSymbol(CoinFrom, CoinTo)
-// 	order1.Price = "1"
-// 	order1.Amount = "20"
-// 	order1.Side = goex.BUY
-
-// 	orders := []goex.LimitOrder{order, order1}
-
-// 	response := market.BatchPlaceLimitOrder(orders)
-// 	b, _ := json.Marshal(response)
-// 	t.Log(string(b))
-// }
-
-// func TestSwap_PlaceMarketOrder(t *testing.T) {
-// 	market := getSwapInstance()
-
-// 	response := market.PlaceMarketOrder(goex.NewSymbol(CoinFrom, CoinTo), "1", BUY, "")
-// 	b, _ := json.Marshal(response)
-// 	t.Log(string(b))
-// }
-
-// func TestSwap_CancelOrder(t *testing.T) {
-// 	market := getSwapInstance()
-
-// 	response := market.CancelOrder(goex.NewSymbol(CoinFrom, CoinTo), "2786207147", "")
-// 	b, _ := json.Marshal(response)
-// 	t.Log(string(b))
-// }
-
-// func TestSwap_BatchCancelOrder(t *testing.T) {
-// 	market := getSwapInstance()
-
-// 	response := market.BatchCancelOrder(goex.NewSymbol(CoinFrom, CoinTo), "2786678083,2786678832", "")
-// 	b, _ := json.Marshal(response)
-// 	t.Log(string(b))
-// }
-
-// func TestSwap_BatchCancelAllOrder(t *testing.T) {
-// 	market := getSwapInstance()
-
-// 	response := market.BatchCancelAllOrder(goex.NewSymbol(CoinFrom, CoinTo))
-// 	b, _ := json.Marshal(response)
-// 	t.Log(string(b))
-// }
+func TestSwap_CancelOrder(t *testing.T) {
+	market := getSwapInstance()
+
+	response := market.CancelOrder(goex.NewSymbol(CoinFrom, CoinTo), "2786207147", "")
+	b, _ := json.Marshal(response)
+	t.Log(string(b))
+}
+
+func TestSwap_BatchCancelOrder(t *testing.T) {
+	market := getSwapInstance()
+
+	response := market.BatchCancelOrder(goex.NewSymbol(CoinFrom, CoinTo), "2786678083,2786678832", "")
+	b, _ := json.Marshal(response)
+	t.Log(string(b))
+}
+
+func TestSwap_BatchCancelAllOrder(t *testing.T) {
+	market := getSwapInstance()
+
+	response := market.BatchCancelAllOrder(goex.NewSymbol(CoinFrom, CoinTo))
+	b, _ := json.Marshal(response)
+	t.Log(string(b))
+}
+
+func TestSwap_GetFundingRate(t *testing.T) {
+	market := getSwapInstance()
+
+	response := market.GetFundingRate(goex.NewSymbol(CoinFrom, CoinTo))
+	b, _ := json.Marshal(response)
+	t.Log(string(b))
+}
+
+func TestSwap_GetFundingRateHistory(t *testing.T) {
+	market := getSwapInstance()
+
+	response := market.GetFundingRateHistory(goex.NewSymbol(CoinFrom, CoinTo), 10, nil)
+	b, _ := json.Marshal(response)
+	t.Log(string(b))
+}
+
+func TestSwap_OrderParams(t *testing.T) {
+	market := getSwapInstance()
+
+	tests := []struct {
+		order     goex.PlaceOrder
+		orderType string
+		kind      string
+	}{
+		{goex.PlaceOrder{Side: goex.BUY, TradeType: goex.LIMIT}, "1", "0"},
+		{goex.PlaceOrder{Side: goex.SELL, TradeType: goex.LIMIT, TimeInForce: goex.IOC}, "2", "3"},
+		{goex.PlaceOrder{Side: goex.SELL, TradeType: goex.LIMIT, TimeInForce: goex.GTX, Offset: goex.OFFSET_CLOSE}, "3", "1"},
+		{goex.PlaceOrder{Side: goex.BUY, TradeType: goex.MARKET, Offset: goex.OFFSET_CLOSE}, "4", "4"},
+	}
+	for _, test := range tests {
+		params := market.orderParams(&test.order)
+		if params["type"] != test.orderType || params["order_type"] != test.kind {
+			t.Errorf("%+v: expected type %s order_type %s, got %v", test.order, test.orderType, test.kind, params)
+		}
+	}
+}
+
+func TestSwap_PlaceOrderTimeInForce(t *testing.T) {
+	for _, timeInForce := range []goex.TimeInForce{goex.POC, goex.FOK} {
+		order := &goex.PlaceOrder{Symbol: goex.NewSymbol("btc", "usdt"), Side: goex.BUY, TradeType: goex.MARKET, Amount: "1", TimeInForce: timeInForce}
+		if result := (&Swap{}).PlaceOrder(order).(map[string]interface{}); result["code"] != goex.TimeInForceParamsError.Code {
+			t.Fatalf("%v: expected time in force error, got %v", timeInForce, result)
+		}
+	}
+	if err := checkContractOrder(&goex.PlaceOrder{TradeType: goex.MARKET, TimeInForce: goex.IOC}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSwap_ParsePositions(t *testing.T) {
+	market := getSwapInstance()
+
+	var data interface{}
+	json.Unmarshal([]byte(`[{"margin_mode":"crossed","holding":[
+		{"instrument_id":"BTC-USDT-SWAP","side":"short","position":"2","avg_cost":"30000","last":"29000","unrealized_pnl":"20","leverage":"10","liquidation_price":"35000"},
+		{"instrument_id":"BTC-USDT-SWAP","side":"long","position":"0"}
+	]}]`), &data)
+
+	positions := market.parsePositions(data)
+	if len(positions) != 1 {
+		t.Fatalf("expected 1 position, got %+v", positions)
+	}
+	position := positions[0]
+	if position.Side != goex.POSITION_SHORT || position.Amount != 2 || position.EntryPrice != 30000 || position.MarkPrice != 29000 ||
+		position.UnrealizedPnl != 20 || position.Leverage != 10 || position.MarginType != goex.MARGIN_CROSSED || position.LiquidationPrice != 35000 {
+		t.Fatalf("unexpected position %+v", position)
+	}
+}
+
+func TestSwap_LeverageBrackets(t *testing.T) {
+	var _ goex.LeverageAPI = &Swap{}
+
+	var data interface{}
+	json.Unmarshal([]byte(`[{"tier":"1","maxSz":"500","maxLever":"100"},{"tier":"2","maxSz":"1000","maxLever":"75"}]`), &data)
+	brackets := leverageBrackets(data)
+	if len(brackets) != 2 || brackets[0].MaxLeverage != 100 || brackets[0].NotionalCap != 500 || brackets[1].NotionalCap != 0 {
+		t.Fatalf("unexpected brackets %+v", brackets)
+	}
+}
+
+func TestSwap_FundingRates(t *testing.T) {
+	var _ goex.FundingAPI = &Swap{}
+
+	var data interface{}
+	json.Unmarshal([]byte(`[{"instrument_id":"BTC-USDT-SWAP","funding_rate":"0.0001","realized_rate":"0.00011","funding_time":"2021-05-03T08:00:00.000Z"},
+		{"instrument_id":"BTC-USDT-SWAP","funding_rate":"0.0002","realized_rate":"0.00021","funding_time":"2021-05-03T16:00:00.000Z"}]`), &data)
+	rates := fundingRates(data)
+	if len(rates) != 2 || rates[0].Rate != 0.00021 || rates[0].Time != 1620057600000 || rates[1].Symbol != "BTC-USDT-SWAP" {
+		t.Fatalf("unexpected funding rates %+v", rates)
+	}
+}
+
+func TestAlgoParams(t *testing.T) {
+	params, err := algoParams(&goex.PlaceOrder{Side: goex.SELL, TradeType: goex.STOP_MARKET, Amount: "1", StopPrice: "90"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if params["order_type"] != "1" || params["algo_type"] != "2" || params["trigger_price"] != "90" {
+		t.Fatalf("unexpected trigger params %v", params)
+	}
+	params, err = algoParams(&goex.PlaceOrder{Side: goex.SELL, TradeType: goex.TRAILING_STOP, Amount: "1", CallbackRate: "0.5"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if params["order_type"] != "2" || params["callback_rate"] != "0.005" {
+		t.Fatalf("unexpected trailing params %v", params)
+	}
+	if _, err := algoParams(&goex.PlaceOrder{TradeType: goex.STOP_MARKET, StopPrice: "90", ClosePosition: true}); err == nil {
+		t.Fatal("expected error of close position order")
+	}
+	if positionType(&goex.PlaceOrder{Side: goex.BUY, ReduceOnly: true}) != "4" {
+		t.Fatal("reduce only buy should close short")
+	}
+}
+
+func TestAmendParams(t *testing.T) {
+	var _ goex.AmendAPI = &Spot{}
+	var _ goex.AmendAPI = &Swap{}
+	var _ goex.AmendAPI = &Futures{}
+
+	params, err := amendParams(&goex.AmendOrder{ClientOrderId: "c1", Price: "100"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if params["client_oid"] != "c1" || params["new_price"] != "100" || params["new_size"] != "" {
+		t.Fatalf("unexpected params %v", params)
+	}
+}
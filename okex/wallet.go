@@ -0,0 +1,142 @@
+package okex
+
+import (
+	"strings"
+
+	. "github.com/primitivelab/goexchange"
+)
+
+// okex 充值状态
+var depositStatus = map[string]string{
+	"0": TRANSFER_STATUS_PENDING,
+	"1": TRANSFER_STATUS_SUCCESS,
+	"2": TRANSFER_STATUS_SUCCESS,
+	"8": TRANSFER_STATUS_PENDING,
+}
+
+// okex 提现状态
+var withdrawStatus = map[string]string{
+	"-3": TRANSFER_STATUS_PENDING,
+	"-2": TRANSFER_STATUS_CANCELED,
+	"-1": TRANSFER_STATUS_FAILED,
+	"0":  TRANSFER_STATUS_PENDING,
+	"1":  TRANSFER_STATUS_PENDING,
+	"2":  TRANSFER_STATUS_SUCCESS,
+	"3":  TRANSFER_STATUS_PENDING,
+	"4":  TRANSFER_STATUS_PENDING,
+	"5":  TRANSFER_STATUS_PENDING,
+}
+
+// 充值地址, chain 为空时返回全部链
+func (spot *Spot) GetDepositAddresses(coin, chain string) interface{} {
+	params := map[string]string{"currency": strings.ToLower(coin)}
+	result := spot.httpGet("/api/account/v3/deposit/address", params, true)
+	if result["code"] != 0 {
+		return result
+	}
+
+	list, _ := result["data"].([]interface{})
+	addresses := []DepositAddress{}
+	for _, item := range list {
+		record, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		address := DepositAddress{
+			Coin:    ToString(record["currency"]),
+			Chain:   ToString(record["chain"]),
+			Address: ToString(record["address"]),
+		}
+		for _, key := range []string{"tag", "memo", "payment_id"} {
+			if tag := ToString(record[key]); tag != "" {
+				address.Tag = tag
+				break
+			}
+		}
+		if chain == "" || address.Chain == chain {
+			addresses = append(addresses, address)
+		}
+	}
+	result["data"] = addresses
+	return result
+}
+
+// 提现, 资金密码通过 request.Options["trade_pwd"] 传入, 手续费必填
+func (spot *Spot) ApplyWithdraw(request *WithdrawRequest) interface{} {
+	// typed fields are set last so that options can not override them
+	params := map[string]string{}
+	for key, value := range request.Options {
+		params[key] = value
+	}
+	params["currency"] = strings.ToLower(request.Coin)
+	params["amount"] = request.Amount
+	params["destination"] = "4"
+	params["to_address"] = request.Address
+	params["fee"] = request.Fee
+	if request.Tag != "" {
+		params["to_address"] = request.Address + ":" + request.Tag
+	}
+	if request.Chain != "" {
+		params["chain"] = request.Chain
+	}
+
+	result := spot.httpPost("/api/account/v3/withdrawal", params, true)
+	if result["code"] != 0 {
+		return result
+	}
+	data, _ := result["data"].(map[string]interface{})
+	result["data"] = ToString(data["withdrawal_id"])
+	return result
+}
+
+// 充值记录
+func (spot *Spot) GetDepositHistory(coin string, size int, options map[string]string) interface{} {
+	return spot.transferHistory("/api/account/v3/deposit/history", coin, size, TRANSFER_DEPOSIT, depositStatus)
+}
+
+// 提现记录
+func (spot *Spot) GetWithdrawHistory(coin string, size int, options map[string]string) interface{} {
+	return spot.transferHistory("/api/account/v3/withdrawal/history", coin, size, TRANSFER_WITHDRAW, withdrawStatus)
+}
+
+// 充提记录, okex 最多返回 100 条
+func (spot *Spot) transferHistory(path, coin string, size int, transferType string, status map[string]string) interface{} {
+	if coin != "" {
+		path = path + "/" + strings.ToLower(coin)
+	}
+	result := spot.httpGet(path, nil, true)
+	if result["code"] != 0 {
+		return result
+	}
+
+	list, _ := result["data"].([]interface{})
+	transfers := make([]Transfer, 0, len(list))
+	for _, item := range list {
+		record, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		transfer := Transfer{
+			Id:      ToString(record["deposit_id"]),
+			TxId:    ToString(record["txid"]),
+			Type:    transferType,
+			Coin:    ToString(record["currency"]),
+			Chain:   ToString(record["chain"]),
+			Address: ToString(record["to"]),
+			Tag:     ToString(record["tag"]),
+			Amount:  ToFloat(record["amount"]),
+			Fee:     ToFloat(record["fee"]),
+			Status:  status[ToString(record["status"])],
+			Time:    ParseTimestamp(record["timestamp"]),
+		}
+		if transferType == TRANSFER_WITHDRAW {
+			transfer.Id = ToString(record["withdrawal_id"])
+		}
+		transfers = append(transfers, transfer)
+		if size > 0 && len(transfers) >= size {
+			break
+		}
+	}
+	result["data"] = transfers
+	return result
+}
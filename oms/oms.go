@@ -0,0 +1,621 @@
+package oms
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	goex "github.com/primitivelab/goexchange"
+	"github.com/primitivelab/goexchange/builder"
+)
+
+// alert kinds
+const (
+	// tracked open order is missing from the open orders of the exchange and
+	// can not be queried
+	ALERT_ORPHANED = "orphaned"
+	// open order or order event of the exchange the manager did not place
+	ALERT_UNKNOWN = "unknown"
+)
+
+// API order methods of spot, margin and swap adapters the manager needs
+type API interface {
+	goex.OrderAPI
+	GetUserOpenTrustOrders(symbol goex.Symbol, size int, options map[string]string) interface{}
+}
+
+// Config manager config
+type Config struct {
+	// poll interval of open orders in Run, default 5 seconds
+	Interval time.Duration
+	// reconcile interval against exchange open orders in Run, default 1 minute
+	ReconcileInterval time.Duration
+	// size of GetUserOpenTrustOrders, default 100
+	PageSize int
+	// symbols reconciled besides the symbols of tracked orders, so that
+	// unknown orders are found before the manager places any
+	Symbols []goex.Symbol
+	// orders are saved to Store after every change and loaded by New, nil
+	// keeps them in memory only
+	Store Store
+	// current time, default time.Now
+	Clock func() time.Time
+}
+
+// Order tracked order, FilledCash is the filled value in quote coin
+type Order struct {
+	Id            string         `json:"id"`
+	OrderId       string         `json:"orderId"`
+	ClientOrderId string         `json:"clientOrderId"`
+	Symbol        goex.Symbol    `json:"symbol"`
+	Side          goex.TradeSide `json:"side"`
+	Type          string         `json:"type"`
+	Price         float64        `json:"price"`
+	Amount        float64        `json:"amount"`
+	FilledAmount  float64        `json:"filledAmount"`
+	FilledCash    float64        `json:"filledCash"`
+	Status        string         `json:"status"`
+	// ids of fills added by HandleFill
+	FillIds []string `json:"fillIds,omitempty"`
+	// order can not be queried and is not open on the exchange
+	Orphaned   bool   `json:"orphaned"`
+	Error      string `json:"error"`
+	CreateTime int64  `json:"createTime"`
+	UpdateTime int64  `json:"updateTime"`
+}
+
+// AvgPrice average filled price
+func (order *Order) AvgPrice() float64 {
+	if order.FilledAmount == 0 {
+		return 0
+	}
+	return order.FilledCash / order.FilledAmount
+}
+
+// IsOpen order may still be filled, orders whose place answer was lost are
+// open until they are found or orphaned
+func (order *Order) IsOpen() bool {
+	return !order.Orphaned && (order.Status == goex.ORDER_STATUS_NEW ||
+		order.Status == goex.ORDER_STATUS_PARTIALLY_FILLED || order.Status == goex.ORDER_STATUS_UNKNOWN)
+}
+
+// Alert orphaned or unknown order
+type Alert struct {
+	Kind          string      `json:"kind"`
+	Symbol        goex.Symbol `json:"symbol"`
+	OrderId       string      `json:"orderId"`
+	ClientOrderId string      `json:"clientOrderId"`
+	Message       string      `json:"message"`
+	Time          int64       `json:"time"`
+}
+
+// Manager order management over one adapter, give strategies the manager
+// instead of the adapter so every order is recorded, order state comes from
+// HandleOrder and HandleFill of a stream or from Poll, Reconcile compares it
+// with the open orders of the exchange
+type Manager struct {
+	api      API
+	config   Config
+	mutex    sync.Mutex
+	orders   map[string]*Order
+	sequence int64
+	// alerted orders by kind and order id, every order alerts once
+	alerted       map[string]bool
+	handlers      []func(order *Order)
+	alertHandlers []func(alert *Alert)
+}
+
+// New new instance, orders saved in config.Store are restored
+func New(api API, config *Config) (*Manager, error) {
+	manager := &Manager{api: api, orders: map[string]*Order{}, alerted: map[string]bool{}}
+	if config != nil {
+		manager.config = *config
+	}
+	if manager.config.Interval == 0 {
+		manager.config.Interval = 5 * time.Second
+	}
+	if manager.config.ReconcileInterval == 0 {
+		manager.config.ReconcileInterval = time.Minute
+	}
+	if manager.config.PageSize == 0 {
+		manager.config.PageSize = 100
+	}
+	if manager.config.Clock == nil {
+		manager.config.Clock = time.Now
+	}
+	if manager.config.Store == nil {
+		return manager, nil
+	}
+	data, err := manager.config.Store.Load()
+	if err != nil || len(data) == 0 {
+		return manager, err
+	}
+	var orders []*Order
+	if err := json.Unmarshal(data, &orders); err != nil {
+		return nil, err
+	}
+	for _, order := range orders {
+		manager.orders[order.Id] = order
+		if id, _ := strconv.ParseInt(order.Id, 10, 64); id > manager.sequence {
+			manager.sequence = id
+		}
+	}
+	return manager, nil
+}
+
+// NewWithBuilder new instance of spot exchange built by apiBuilder
+func NewWithBuilder(apiBuilder *builder.APIBuilder, exchange string, config *Config) (*Manager, error) {
+	api := apiBuilder.Build(exchange)
+	if api == nil {
+		return nil, errors.New("exchange is not supported: " + exchange)
+	}
+	return New(api, config)
+}
+
+// OnOrder add handler called with a copy of every order changed by the manager
+func (manager *Manager) OnOrder(handler func(order *Order)) {
+	manager.mutex.Lock()
+	defer manager.mutex.Unlock()
+	manager.handlers = append(manager.handlers, handler)
+}
+
+// OnAlert add handler called with every alert
+func (manager *Manager) OnAlert(handler func(alert *Alert)) {
+	manager.mutex.Lock()
+	defer manager.mutex.Unlock()
+	manager.alertHandlers = append(manager.alertHandlers, handler)
+}
+
+// PlaceOrder place order and track it, orders whose place answer was lost
+// are tracked by client order id with unknown status
+func (manager *Manager) PlaceOrder(order *goex.PlaceOrder) interface{} {
+	result := manager.api.PlaceOrder(order)
+	placed, err := goex.ParseOrder(result)
+	if err != nil && (order.ClientOrderId == "" || !isTransportError(err)) {
+		return result
+	}
+	tracked := &Order{
+		ClientOrderId: order.ClientOrderId,
+		Symbol:        order.Symbol,
+		Side:          order.Side,
+		Type:          order.TradeType,
+		Price:         goex.ToFloat(order.Price),
+		Amount:        goex.ToFloat(order.Amount),
+		Status:        goex.ORDER_STATUS_UNKNOWN,
+	}
+	if tracked.Type == "" {
+		tracked.Type = goex.MARKET
+	}
+	if err == nil {
+		tracked.OrderId = placed.OrderId
+		tracked.Status = goex.ORDER_STATUS_NEW
+		if placed.ClientOrderId != "" {
+			tracked.ClientOrderId = placed.ClientOrderId
+		}
+		apply(tracked, placed)
+	} else {
+		tracked.Error = err.Error()
+	}
+	manager.mutex.Lock()
+	manager.add(tracked)
+	manager.finish([]*Order{tracked}, nil)
+	return result
+}
+
+// CancelOrder cancel order and refresh it, the order is tracked as canceled
+// only once the exchange reports it
+func (manager *Manager) CancelOrder(symbol goex.Symbol, orderId, clientOrderId string) interface{} {
+	result := manager.api.CancelOrder(symbol, orderId, clientOrderId)
+	if _, err := goex.ParseResponse(result); err == nil {
+		manager.refresh(symbol, orderId, clientOrderId)
+	}
+	return result
+}
+
+// GetUserOrderInfo query order and update it when it is tracked
+func (manager *Manager) GetUserOrderInfo(symbol goex.Symbol, orderId, clientOrderId string) interface{} {
+	result := manager.api.GetUserOrderInfo(symbol, orderId, clientOrderId)
+	if order, err := goex.ParseOrder(result); err == nil {
+		manager.handle(symbol, order, false)
+	}
+	return result
+}
+
+// Track add order placed elsewhere, like an order of a previous run
+func (manager *Manager) Track(symbol goex.Symbol, order *goex.Order) *Order {
+	manager.mutex.Lock()
+	tracked := manager.find(order.OrderId, order.ClientOrderId)
+	if tracked == nil {
+		tracked = &Order{OrderId: order.OrderId, ClientOrderId: order.ClientOrderId, Symbol: symbol, Status: goex.ORDER_STATUS_UNKNOWN}
+		manager.add(tracked)
+	}
+	apply(tracked, order)
+	copied := *tracked
+	manager.finish([]*Order{tracked}, nil)
+	return &copied
+}
+
+// HandleOrder update tracked order from an order event of a stream, events
+// of orders the manager does not track raise an unknown alert
+func (manager *Manager) HandleOrder(symbol goex.Symbol, order *goex.Order) {
+	manager.handle(symbol, order, true)
+}
+
+// HandleFill add fill of amount at price to tracked order from a fill event
+// of a stream, false when the order is not tracked or the fill is not
+// applied, fills whose fillId was added before and fills of final orders are
+// skipped, final orders take filled amounts of order events, and filled
+// amount is capped at Amount
+func (manager *Manager) HandleFill(orderId, clientOrderId, fillId string, amount, price float64) bool {
+	manager.mutex.Lock()
+	tracked := manager.find(orderId, clientOrderId)
+	if tracked == nil || isFinal(tracked.Status) || hasFill(tracked, fillId) {
+		manager.mutex.Unlock()
+		return false
+	}
+	if tracked.Amount > 0 && tracked.FilledAmount+amount > tracked.Amount {
+		amount = tracked.Amount - tracked.FilledAmount
+	}
+	if fillId != "" {
+		tracked.FillIds = append(tracked.FillIds, fillId)
+	}
+	tracked.FilledAmount += amount
+	tracked.FilledCash += amount * price
+	if tracked.Amount > 0 && tracked.FilledAmount >= tracked.Amount {
+		tracked.Status = goex.ORDER_STATUS_FILLED
+	} else {
+		tracked.Status = goex.ORDER_STATUS_PARTIALLY_FILLED
+	}
+	manager.finish([]*Order{tracked}, nil)
+	return true
+}
+
+// Order copy of tracked order by order id or client order id
+func (manager *Manager) Order(orderId, clientOrderId string) (Order, bool) {
+	manager.mutex.Lock()
+	defer manager.mutex.Unlock()
+	tracked := manager.find(orderId, clientOrderId)
+	if tracked == nil {
+		return Order{}, false
+	}
+	return *tracked, true
+}
+
+// Orders copies of tracked orders sorted by create time, only open ones
+// when open is true
+func (manager *Manager) Orders(open bool) []Order {
+	manager.mutex.Lock()
+	defer manager.mutex.Unlock()
+	orders := make([]Order, 0, len(manager.orders))
+	for _, order := range manager.sorted() {
+		if !open || order.IsOpen() {
+			orders = append(orders, *order)
+		}
+	}
+	return orders
+}
+
+// Remove stop tracking closed orders last updated before time in milliseconds
+func (manager *Manager) Remove(before int64) error {
+	manager.mutex.Lock()
+	defer manager.mutex.Unlock()
+	for id, order := range manager.orders {
+		if !order.IsOpen() && order.UpdateTime < before {
+			delete(manager.orders, id)
+		}
+	}
+	return manager.save()
+}
+
+// Run poll every interval and reconcile every reconcile interval until stop
+// is closed
+func (manager *Manager) Run(stop <-chan struct{}) {
+	ticker := time.NewTicker(manager.config.Interval)
+	defer ticker.Stop()
+	reconciled := manager.config.Clock()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			manager.Poll()
+			if manager.config.Clock().Sub(reconciled) >= manager.config.ReconcileInterval {
+				manager.Reconcile()
+				reconciled = manager.config.Clock()
+			}
+		}
+	}
+}
+
+// Poll query every open tracked order, error of the last failed query
+func (manager *Manager) Poll() error {
+	var lastErr error
+	for _, order := range manager.Orders(true) {
+		if err := manager.refresh(order.Symbol, order.OrderId, order.ClientOrderId); err != nil {
+			lastErr = err
+		}
+	}
+	return lastErr
+}
+
+// Reconcile compare open orders of the exchange with tracked orders of every
+// symbol, open exchange orders that are not tracked raise unknown alerts and
+// tracked open orders the exchange does not list are queried, those that can
+// not be queried are orphaned
+func (manager *Manager) Reconcile() error {
+	var lastErr error
+	for _, symbol := range manager.symbols() {
+		orders, err := goex.ParseOrders(manager.api.GetUserOpenTrustOrders(symbol, manager.config.PageSize, nil))
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		listed := map[string]bool{}
+		for i := range orders {
+			listed[orders[i].OrderId] = true
+			manager.handle(symbol, &orders[i], true)
+		}
+		for _, order := range manager.Orders(true) {
+			if order.Symbol != symbol || (order.OrderId != "" && listed[order.OrderId]) {
+				continue
+			}
+			err := manager.refresh(symbol, order.OrderId, order.ClientOrderId)
+			if err != nil && !isTransportError(err) {
+				manager.orphan(order.Id, err)
+			}
+		}
+	}
+	return lastErr
+}
+
+// refresh query order and update it
+func (manager *Manager) refresh(symbol goex.Symbol, orderId, clientOrderId string) error {
+	order, err := goex.ParseOrder(manager.api.GetUserOrderInfo(symbol, orderId, clientOrderId))
+	if err != nil {
+		return err
+	}
+	if order.OrderId == "" {
+		order.OrderId = orderId
+	}
+	if order.ClientOrderId == "" {
+		order.ClientOrderId = clientOrderId
+	}
+	manager.handle(symbol, order, false)
+	return nil
+}
+
+// handle apply order to the tracked order, alert is raised for open orders
+// that are not tracked when alertUnknown is true
+func (manager *Manager) handle(symbol goex.Symbol, order *goex.Order, alertUnknown bool) {
+	manager.mutex.Lock()
+	tracked := manager.find(order.OrderId, order.ClientOrderId)
+	if tracked == nil {
+		var alerts []*Alert
+		key := ALERT_UNKNOWN + ":" + order.OrderId
+		if alertUnknown && order.IsOpen() && !manager.alerted[key] {
+			manager.alerted[key] = true
+			alerts = append(alerts, &Alert{
+				Kind:          ALERT_UNKNOWN,
+				Symbol:        symbol,
+				OrderId:       order.OrderId,
+				ClientOrderId: order.ClientOrderId,
+				Message:       "open order was not placed through the manager",
+				Time:          manager.now(),
+			})
+		}
+		manager.finish(nil, alerts)
+		return
+	}
+	before := *tracked
+	apply(tracked, order)
+	if reflect.DeepEqual(*tracked, before) {
+		manager.mutex.Unlock()
+		return
+	}
+	manager.finish([]*Order{tracked}, nil)
+}
+
+// orphan mark tracked order orphaned and alert
+func (manager *Manager) orphan(id string, err error) {
+	manager.mutex.Lock()
+	tracked, ok := manager.orders[id]
+	if !ok || !tracked.IsOpen() {
+		manager.mutex.Unlock()
+		return
+	}
+	tracked.Orphaned = true
+	tracked.Error = err.Error()
+	alert := &Alert{
+		Kind:          ALERT_ORPHANED,
+		Symbol:        tracked.Symbol,
+		OrderId:       tracked.OrderId,
+		ClientOrderId: tracked.ClientOrderId,
+		Message:       "open order is not listed by the exchange: " + err.Error(),
+		Time:          manager.now(),
+	}
+	manager.finish([]*Order{tracked}, []*Alert{alert})
+}
+
+// apply exchange state of order to tracked, filled amount never goes back and
+// closed orders are not reopened by stale updates
+func apply(tracked *Order, order *goex.Order) {
+	if tracked.OrderId == "" {
+		tracked.OrderId = order.OrderId
+	}
+	if tracked.ClientOrderId == "" {
+		tracked.ClientOrderId = order.ClientOrderId
+	}
+	if tracked.Side == 0 {
+		tracked.Side = order.Side
+	}
+	if tracked.Type == "" {
+		tracked.Type = order.Type
+	}
+	if tracked.Price == 0 {
+		tracked.Price = order.Price
+	}
+	if order.Amount > 0 {
+		tracked.Amount = order.Amount
+	}
+	if order.FilledAmount > tracked.FilledAmount {
+		tracked.FilledAmount = order.FilledAmount
+		tracked.FilledCash = order.FilledCash
+		if order.FilledCash == 0 {
+			tracked.FilledCash = order.FilledAmount * tracked.Price
+		}
+	}
+	if order.Status != goex.ORDER_STATUS_UNKNOWN && !isFinal(tracked.Status) {
+		tracked.Status = order.Status
+		tracked.Orphaned = false
+	}
+	if tracked.CreateTime == 0 {
+		tracked.CreateTime = order.CreateTime
+	}
+}
+
+// isFinal order status that never changes again
+// hasFill fill id was added to order
+func hasFill(order *Order, fillId string) bool {
+	if fillId == "" {
+		return false
+	}
+	for _, id := range order.FillIds {
+		if id == fillId {
+			return true
+		}
+	}
+	return false
+}
+
+func isFinal(status string) bool {
+	return status == goex.ORDER_STATUS_FILLED || status == goex.ORDER_STATUS_CANCELED ||
+		status == goex.ORDER_STATUS_REJECTED || status == goex.ORDER_STATUS_EXPIRED
+}
+
+// isTransportError the request may have reached the exchange, but its answer
+// was lost
+func isTransportError(err error) bool {
+	var apiErr *goex.APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	code := goex.ToFloat(apiErr.Code)
+	return code == float64(goex.HttpClientInternalError.Code) || code == float64(goex.HttpRequestError.Code) ||
+		code == float64(goex.JsonUnmarshalError.Code)
+}
+
+// symbols of tracked open orders and of config, in order
+func (manager *Manager) symbols() []goex.Symbol {
+	manager.mutex.Lock()
+	defer manager.mutex.Unlock()
+	seen := map[goex.Symbol]bool{}
+	symbols := make([]goex.Symbol, 0, len(manager.config.Symbols))
+	for _, symbol := range manager.config.Symbols {
+		if !seen[symbol] {
+			seen[symbol] = true
+			symbols = append(symbols, symbol)
+		}
+	}
+	for _, order := range manager.sorted() {
+		if order.IsOpen() && !seen[order.Symbol] {
+			seen[order.Symbol] = true
+			symbols = append(symbols, order.Symbol)
+		}
+	}
+	return symbols
+}
+
+// add new tracked order, mutex is held
+func (manager *Manager) add(order *Order) {
+	manager.sequence++
+	order.Id = strconv.FormatInt(manager.sequence, 10)
+	if order.CreateTime == 0 {
+		order.CreateTime = manager.now()
+	}
+	manager.orders[order.Id] = order
+}
+
+// find tracked order by order id, then by client order id, mutex is held
+func (manager *Manager) find(orderId, clientOrderId string) *Order {
+	if orderId != "" {
+		for _, order := range manager.orders {
+			if order.OrderId == orderId {
+				return order
+			}
+		}
+	}
+	if clientOrderId != "" {
+		for _, order := range manager.orders {
+			if order.ClientOrderId == clientOrderId {
+				return order
+			}
+		}
+	}
+	return nil
+}
+
+// finish save changed orders, unlock and notify handlers
+func (manager *Manager) finish(orders []*Order, alerts []*Alert) error {
+	now := manager.now()
+	changed := make([]*Order, 0, len(orders))
+	for _, order := range orders {
+		order.UpdateTime = now
+		copied := *order
+		changed = append(changed, &copied)
+	}
+	var err error
+	if len(orders) > 0 {
+		err = manager.save()
+	}
+	handlers := manager.handlers
+	alertHandlers := manager.alertHandlers
+	manager.mutex.Unlock()
+	for _, order := range changed {
+		for _, handler := range handlers {
+			handler(order)
+		}
+	}
+	for _, alert := range alerts {
+		for _, handler := range alertHandlers {
+			handler(alert)
+		}
+	}
+	return err
+}
+
+// save write all orders to store
+func (manager *Manager) save() error {
+	if manager.config.Store == nil {
+		return nil
+	}
+	data, err := json.Marshal(manager.sorted())
+	if err != nil {
+		return err
+	}
+	return manager.config.Store.Save(data)
+}
+
+// sorted orders by create time and id
+func (manager *Manager) sorted() []*Order {
+	orders := make([]*Order, 0, len(manager.orders))
+	for _, order := range manager.orders {
+		orders = append(orders, order)
+	}
+	sort.Slice(orders, func(i, j int) bool {
+		if orders[i].CreateTime != orders[j].CreateTime {
+			return orders[i].CreateTime < orders[j].CreateTime
+		}
+		left, _ := strconv.ParseInt(orders[i].Id, 10, 64)
+		right, _ := strconv.ParseInt(orders[j].Id, 10, 64)
+		return left < right
+	})
+	return orders
+}
+
+func (manager *Manager) now() int64 {
+	return manager.config.Clock().UnixNano() / int64(time.Millisecond)
+}
@@ -0,0 +1,180 @@
+package oms
+
+import (
+	"testing"
+	"time"
+
+	goex "github.com/primitivelab/goexchange"
+	"github.com/primitivelab/goexchange/paper"
+)
+
+var btcUsdt = goex.NewSymbol("btc", "usdt")
+
+type memoryStore struct {
+	data []byte
+}
+
+func (store *memoryStore) Load() ([]byte, error) {
+	return store.data, nil
+}
+
+func (store *memoryStore) Save(data []byte) error {
+	store.data = append([]byte{}, data...)
+	return nil
+}
+
+// lostSpot paper spot whose place answers are lost on the way back
+type lostSpot struct {
+	*paper.Spot
+}
+
+func (spot *lostSpot) PlaceOrder(order *goex.PlaceOrder) interface{} {
+	spot.Spot.PlaceOrder(order)
+	return goex.ReturnAPIError(goex.HttpClientInternalError)
+}
+
+func getPaperInstance() *paper.Spot {
+	spot := paper.NewSpot(nil, &paper.Config{
+		Balances: map[string]float64{"usdt": 100000, "btc": 10},
+	})
+	setPrice(spot, 100)
+	return spot
+}
+
+func setPrice(spot *paper.Spot, price float64) {
+	spot.SetDepth(btcUsdt, &goex.Depth{
+		Bids: []goex.DepthRecord{{Price: price - 1, Amount: 10}},
+		Asks: []goex.DepthRecord{{Price: price + 1, Amount: 10}},
+	})
+}
+
+func limitOrder(side goex.TradeSide, price string) *goex.PlaceOrder {
+	return &goex.PlaceOrder{Symbol: btcUsdt, Side: side, TradeType: goex.LIMIT, Price: price, Amount: "1"}
+}
+
+func TestManager_Poll(t *testing.T) {
+	var _ goex.OrderAPI = &Manager{}
+	spot := getPaperInstance()
+	manager, _ := New(spot, nil)
+	var changed []*Order
+	manager.OnOrder(func(order *Order) { changed = append(changed, order) })
+
+	resting, err := goex.ParseOrder(manager.PlaceOrder(limitOrder(goex.BUY, "90")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	manager.PlaceOrder(limitOrder(goex.BUY, "101"))
+	orders := manager.Orders(false)
+	if len(orders) != 2 || orders[0].Status != goex.ORDER_STATUS_NEW || orders[1].Status != goex.ORDER_STATUS_FILLED || orders[1].AvgPrice() != 101 {
+		t.Fatalf("unexpected orders %+v", orders)
+	}
+	if open := manager.Orders(true); len(open) != 1 || open[0].OrderId != resting.OrderId {
+		t.Fatalf("unexpected open orders %+v", open)
+	}
+
+	setPrice(spot, 85)
+	changed = nil
+	if err := manager.Poll(); err != nil {
+		t.Fatal(err)
+	}
+	order, _ := manager.Order(resting.OrderId, "")
+	if order.Status != goex.ORDER_STATUS_FILLED || order.FilledAmount != 1 || order.AvgPrice() != 90 || len(changed) != 1 {
+		t.Fatalf("unexpected polled order %+v", order)
+	}
+	if err := manager.Poll(); err != nil || len(changed) != 1 {
+		t.Fatalf("poll without changes notified %+v: %v", changed, err)
+	}
+}
+
+func TestManager_Reconcile(t *testing.T) {
+	spot := getPaperInstance()
+	manager, _ := New(spot, &Config{Symbols: []goex.Symbol{btcUsdt}})
+	var alerts []*Alert
+	manager.OnAlert(func(alert *Alert) { alerts = append(alerts, alert) })
+
+	manager.PlaceOrder(limitOrder(goex.BUY, "90"))
+	spot.PlaceOrder(limitOrder(goex.SELL, "110"))
+	manager.Track(btcUsdt, &goex.Order{OrderId: "999", Status: goex.ORDER_STATUS_NEW})
+	for i := 0; i < 2; i++ {
+		if err := manager.Reconcile(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(alerts) != 2 || alerts[0].Kind != ALERT_UNKNOWN || alerts[1].Kind != ALERT_ORPHANED || alerts[1].OrderId != "999" {
+		t.Fatalf("unexpected alerts %+v", alerts)
+	}
+	if order, _ := manager.Order("999", ""); !order.Orphaned || order.IsOpen() {
+		t.Fatalf("unexpected orphaned order %+v", order)
+	}
+	if open := manager.Orders(true); len(open) != 1 || open[0].Price != 90 {
+		t.Fatalf("unexpected open orders %+v", open)
+	}
+}
+
+func TestManager_LostPlace(t *testing.T) {
+	spot := getPaperInstance()
+	manager, _ := New(&lostSpot{spot}, nil)
+	order := limitOrder(goex.BUY, "90")
+	order.ClientOrderId = "c1"
+	manager.PlaceOrder(order)
+	tracked, ok := manager.Order("", "c1")
+	if !ok || tracked.Status != goex.ORDER_STATUS_UNKNOWN || tracked.OrderId != "" || !tracked.IsOpen() {
+		t.Fatalf("unexpected lost order %+v", tracked)
+	}
+	manager.Poll()
+	if tracked, _ = manager.Order("", "c1"); tracked.Status != goex.ORDER_STATUS_NEW || tracked.OrderId != spot.GetOrders()[0].OrderId {
+		t.Fatalf("lost order is not found %+v", tracked)
+	}
+	manager.PlaceOrder(limitOrder(goex.BUY, "90"))
+	if len(manager.Orders(false)) != 1 {
+		t.Fatal("lost order without client order id is tracked")
+	}
+}
+
+func TestManager_Events(t *testing.T) {
+	clock := time.Unix(1600000000, 0)
+	store := &memoryStore{}
+	config := &Config{Store: store, Clock: func() time.Time { return clock }}
+	manager, _ := New(getPaperInstance(), config)
+	var alerts []*Alert
+	manager.OnAlert(func(alert *Alert) { alerts = append(alerts, alert) })
+	placed, _ := goex.ParseOrder(manager.PlaceOrder(limitOrder(goex.SELL, "110")))
+
+	manager.HandleFill(placed.OrderId, "", "f1", 0.4, 110)
+	if manager.HandleFill(placed.OrderId, "", "f1", 0.4, 110) {
+		t.Fatal("fill is applied twice")
+	}
+	manager.HandleOrder(btcUsdt, &goex.Order{OrderId: placed.OrderId, FilledAmount: 0.3, Status: goex.ORDER_STATUS_PARTIALLY_FILLED})
+	order, _ := manager.Order(placed.OrderId, "")
+	if order.Status != goex.ORDER_STATUS_PARTIALLY_FILLED || order.FilledAmount != 0.4 {
+		t.Fatalf("unexpected partially filled order %+v", order)
+	}
+	manager.HandleFill(placed.OrderId, "", "f2", 0.6, 111)
+	if manager.HandleFill(placed.OrderId, "", "f3", 0.1, 111) {
+		t.Fatal("fill of filled order is applied")
+	}
+	manager.HandleOrder(btcUsdt, &goex.Order{OrderId: placed.OrderId, FilledAmount: 0.4, Status: goex.ORDER_STATUS_NEW})
+	if order, _ = manager.Order(placed.OrderId, ""); order.Status != goex.ORDER_STATUS_FILLED || order.AvgPrice() != 110.6 {
+		t.Fatalf("unexpected filled order %+v", order)
+	}
+	if manager.HandleFill("404", "", "f4", 1, 100) {
+		t.Fatal("fill of unknown order is applied")
+	}
+	manager.HandleOrder(btcUsdt, &goex.Order{OrderId: "404", Status: goex.ORDER_STATUS_NEW})
+	if len(alerts) != 1 || alerts[0].Kind != ALERT_UNKNOWN {
+		t.Fatalf("unexpected alerts %+v", alerts)
+	}
+
+	restored, err := New(getPaperInstance(), config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if order, _ = restored.Order(placed.OrderId, ""); order.FilledAmount != 1 || order.Status != goex.ORDER_STATUS_FILLED {
+		t.Fatalf("unexpected restored order %+v", order)
+	}
+	clock = clock.Add(time.Minute)
+	restored.Remove(clock.UnixNano() / int64(time.Millisecond))
+	if orders := restored.Orders(false); len(orders) != 0 {
+		t.Fatalf("unexpected orders after remove %+v", orders)
+	}
+}
@@ -0,0 +1,257 @@
+package goexchange
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+// 订单状态
+const (
+	ORDER_STATUS_NEW              = "new"
+	ORDER_STATUS_PARTIALLY_FILLED = "partially_filled"
+	ORDER_STATUS_FILLED           = "filled"
+	ORDER_STATUS_CANCELED         = "canceled"
+	ORDER_STATUS_REJECTED         = "rejected"
+	ORDER_STATUS_EXPIRED          = "expired"
+	ORDER_STATUS_UNKNOWN          = "unknown"
+)
+
+// orderStatus exchange status words, lower case without "-", "_" and " " except numeric okex states,
+// adapters of exchanges with other numeric codes replace them, like huobi contracts do
+var orderStatus = map[string]string{
+	"new":               ORDER_STATUS_NEW,
+	"created":           ORDER_STATUS_NEW,
+	"submitted":         ORDER_STATUS_NEW,
+	"presubmitted":      ORDER_STATUS_NEW,
+	"open":              ORDER_STATUS_NEW,
+	"live":              ORDER_STATUS_NEW,
+	"init":              ORDER_STATUS_NEW,
+	"0":                 ORDER_STATUS_NEW,
+	"partiallyfilled":   ORDER_STATUS_PARTIALLY_FILLED,
+	"partialfilled":     ORDER_STATUS_PARTIALLY_FILLED,
+	"1":                 ORDER_STATUS_PARTIALLY_FILLED,
+	"filled":            ORDER_STATUS_FILLED,
+	"closed":            ORDER_STATUS_FILLED,
+	"2":                 ORDER_STATUS_FILLED,
+	"canceled":          ORDER_STATUS_CANCELED,
+	"cancelled":         ORDER_STATUS_CANCELED,
+	"partialcanceled":   ORDER_STATUS_CANCELED,
+	"partiallycanceled": ORDER_STATUS_CANCELED,
+	"-1":                ORDER_STATUS_CANCELED,
+	"rejected":          ORDER_STATUS_REJECTED,
+	"failed":            ORDER_STATUS_REJECTED,
+	"-2":                ORDER_STATUS_REJECTED,
+	"expired":           ORDER_STATUS_EXPIRED,
+}
+
+// Order typed order of any adapter
+type Order struct {
+	OrderId       string
+	ClientOrderId string
+	Symbol        string
+	Side          TradeSide
+	Type          string
+	Price         float64
+	Amount        float64
+	FilledAmount  float64
+	// filled value in quote coin
+	FilledCash float64
+	Status     string
+	CreateTime int64
+}
+
+// AvgPrice average filled price
+func (order *Order) AvgPrice() float64 {
+	if order.FilledAmount == 0 {
+		return 0
+	}
+	return order.FilledCash / order.FilledAmount
+}
+
+// IsOpen order is waiting to be filled
+func (order *Order) IsOpen() bool {
+	return order.Status == ORDER_STATUS_NEW || order.Status == ORDER_STATUS_PARTIALLY_FILLED
+}
+
+// ParseOrderStatus normalize exchange order status into ORDER_STATUS_*
+func ParseOrderStatus(status interface{}) string {
+	text := strings.ToLower(FloatToString(ToFloat(status)))
+	if value, ok := status.(string); ok {
+		text = strings.ToLower(value)
+	}
+	if normalized, ok := orderStatus[text]; ok {
+		return normalized
+	}
+	text = strings.NewReplacer("-", "", "_", "", " ", "").Replace(text)
+	if normalized, ok := orderStatus[text]; ok {
+		return normalized
+	}
+	return ORDER_STATUS_UNKNOWN
+}
+
+// ParseOrder parse PlaceOrder or GetUserOrderInfo response into typed order,
+// responses holding only the order id give a new order with that id
+func ParseOrder(result interface{}) (*Order, error) {
+	data := result
+	if retData, ok := result.(map[string]interface{}); ok {
+		if _, isResponse := retData["code"]; isResponse {
+			var err error
+			data, err = ParseResponse(result)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	for level := 0; level < 3; level++ {
+		object, ok := data.(map[string]interface{})
+		if !ok {
+			break
+		}
+		nested := false
+		for _, key := range []string{"data", "result", "order"} {
+			if value, ok := object[key]; ok && value != nil {
+				data = value
+				nested = true
+				break
+			}
+		}
+		if !nested {
+			break
+		}
+	}
+	if list, ok := data.([]interface{}); ok && len(list) == 1 {
+		data = list[0]
+	}
+
+	switch value := data.(type) {
+	case string, float64, json.Number:
+		return &Order{OrderId: idString(value), Status: ORDER_STATUS_NEW}, nil
+	case map[string]interface{}:
+		return parseOrderObject(value), nil
+	}
+	return nil, errors.New("order data is not an object")
+}
+
+// ParseOrders parse GetUserOpenTrustOrders or GetUserTrustOrders response into
+// typed orders, lists nested like huobi's {"orders": [...]} or okex's
+// {"order_info": [...]} are found
+func ParseOrders(result interface{}) ([]Order, error) {
+	data := result
+	if retData, ok := result.(map[string]interface{}); ok {
+		if _, isResponse := retData["code"]; isResponse {
+			var err error
+			data, err = ParseResponse(result)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	for level := 0; level < 3; level++ {
+		object, ok := data.(map[string]interface{})
+		if !ok {
+			break
+		}
+		nested := false
+		for _, key := range []string{"data", "orders", "order_info", "list", "result"} {
+			if value, ok := object[key]; ok && value != nil {
+				data = value
+				nested = true
+				break
+			}
+		}
+		if !nested {
+			break
+		}
+	}
+	if data == nil {
+		return []Order{}, nil
+	}
+	list, ok := data.([]interface{})
+	if !ok {
+		return nil, errors.New("order data is not a list")
+	}
+	orders := make([]Order, 0, len(list))
+	for _, item := range list {
+		if object, ok := item.(map[string]interface{}); ok {
+			orders = append(orders, *parseOrderObject(object))
+		}
+	}
+	return orders, nil
+}
+
+// parseOrderObject parse one order object
+func parseOrderObject(object map[string]interface{}) *Order {
+	order := &Order{Status: ORDER_STATUS_NEW}
+	if value, ok := firstValue(object, "orderId", "order_id", "order-id", "orderID", "id"); ok {
+		order.OrderId = idString(value)
+	}
+	if value, ok := firstValue(object, "clientOrderId", "client_oid", "client-order-id", "clientOid", "client_order_id", "origClientOrderId", "text"); ok {
+		order.ClientOrderId = idString(value)
+	}
+	if value, ok := firstValue(object, "symbol", "instrument_id", "currency_pair", "contract_code"); ok {
+		order.Symbol, _ = value.(string)
+	}
+	if value, ok := firstValue(object, "price"); ok {
+		order.Price = ToFloat(value)
+	}
+	if value, ok := firstValue(object, "origQty", "amount", "size", "quantity", "volume"); ok {
+		order.Amount = ToFloat(value)
+	}
+	if value, ok := firstValue(object, "executedQty", "filled_size", "field-amount", "filled-amount", "filled_amount", "deal_volume", "cumQuantity", "trade_volume", "filled_qty"); ok {
+		order.FilledAmount = ToFloat(value)
+	} else if left, ok := object["left"]; ok {
+		order.FilledAmount = order.Amount - ToFloat(left)
+	}
+	if value, ok := firstValue(object, "cummulativeQuoteQty", "cumQuote", "filled_notional", "field-cash-amount", "filled-cash-amount", "filled_total", "deal_money", "trade_turnover"); ok {
+		order.FilledCash = ToFloat(value)
+	} else if value, ok := firstValue(object, "avgPrice", "price_avg", "avg_price", "trade_avg_price", "avg_deal_price"); ok {
+		order.FilledCash = ToFloat(value) * order.FilledAmount
+	}
+	if value, ok := firstValue(object, "time", "created-at", "created_at", "create_time_ms", "create_time", "timestamp", "createdAt"); ok {
+		order.CreateTime = ParseTimestamp(value)
+	}
+	if value, ok := firstValue(object, "status", "state", "order_status"); ok {
+		order.Status = ParseOrderStatus(value)
+	}
+
+	kind, _ := object["type"].(string)
+	kind = strings.ToLower(kind)
+	order.Type = LIMIT
+	if strings.Contains(kind, MARKET) {
+		order.Type = MARKET
+	}
+	side, _ := firstValue(object, "side", "direction")
+	sideText, _ := side.(string)
+	if sideText == "" {
+		sideText = kind
+	}
+	sideText = strings.ToLower(sideText)
+	if strings.HasPrefix(sideText, "buy") {
+		order.Side = BUY
+	} else if strings.HasPrefix(sideText, "sell") {
+		order.Side = SELL
+	}
+	return order
+}
+
+// firstValue value of the first key present in object
+func firstValue(object map[string]interface{}, keys ...string) (interface{}, bool) {
+	for _, key := range keys {
+		if value, ok := object[key]; ok && value != nil {
+			return value, true
+		}
+	}
+	return nil, false
+}
+
+// idString id number or string as string
+func idString(value interface{}) string {
+	switch id := value.(type) {
+	case string:
+		return id
+	case json.Number:
+		return id.String()
+	}
+	return FloatToString(ToFloat(value))
+}
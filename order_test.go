@@ -0,0 +1,80 @@
+package goexchange
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseOrder(t *testing.T) {
+	bodies := []string{
+		`{"symbol":"BTCUSDT","orderId":28,"clientOrderId":"c1","price":"100","origQty":"2","executedQty":"1","cummulativeQuoteQty":"100","status":"PARTIALLY_FILLED","type":"LIMIT","side":"BUY","time":1600000000000}`,
+		`{"data":{"id":28,"symbol":"btcusdt","client-order-id":"c1","amount":"2","price":"100","created-at":1600000000000,"type":"buy-limit","field-amount":"1","field-cash-amount":"100","state":"partial-filled"}}`,
+		`{"order_id":"28","client_oid":"c1","price":"100","size":"2","filled_size":"1","filled_notional":"100","side":"buy","type":"limit","state":"1","timestamp":"2020-09-13T12:26:40.000Z"}`,
+		`{"id":"28","text":"c1","create_time":"1600000000","status":"open","type":"limit","side":"buy","amount":"2","price":"100","left":"1","filled_total":"100"}`,
+	}
+	for _, body := range bodies {
+		var data interface{}
+		json.Unmarshal([]byte(body), &data)
+		order, err := ParseOrder(map[string]interface{}{"code": 0, "data": data})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if order.OrderId != "28" || order.ClientOrderId != "c1" || order.Side != BUY || order.Type != LIMIT ||
+			order.Amount != 2 || order.FilledAmount != 1 || order.AvgPrice() != 100 || order.CreateTime != 1600000000000 || !order.IsOpen() {
+			t.Fatalf("unexpected order of %s: %+v", body, order)
+		}
+	}
+
+	order, err := ParseOrder(map[string]interface{}{"code": 0, "data": "123"})
+	if err != nil || order.OrderId != "123" || order.Status != ORDER_STATUS_NEW {
+		t.Fatalf("unexpected order of id response: %+v", order)
+	}
+	order, err = ParseOrder(map[string]interface{}{"code": 0, "data": json.Number("1234567890123456789")})
+	if err != nil || order.OrderId != "1234567890123456789" {
+		t.Fatalf("unexpected order of json.Number id response: %+v", order)
+	}
+}
+
+func TestParseOrderStatus(t *testing.T) {
+	statuses := map[interface{}]string{
+		"FILLED":           ORDER_STATUS_FILLED,
+		"partial-canceled": ORDER_STATUS_CANCELED,
+		"cancelled":        ORDER_STATUS_CANCELED,
+		"-1":               ORDER_STATUS_CANCELED,
+		2.0:                ORDER_STATUS_FILLED,
+		"partiallyFilled":  ORDER_STATUS_PARTIALLY_FILLED,
+		"whatever":         ORDER_STATUS_UNKNOWN,
+	}
+	for status, expect := range statuses {
+		if ParseOrderStatus(status) != expect {
+			t.Fatalf("unexpected status of %v: %s", status, ParseOrderStatus(status))
+		}
+	}
+}
+
+func TestParseOrders(t *testing.T) {
+	bodies := []string{
+		`[{"symbol":"BTCUSDT","orderId":28,"origQty":"2","executedQty":"1","status":"PARTIALLY_FILLED","side":"BUY"},{"symbol":"BTCUSDT","orderId":29,"origQty":"1","status":"NEW","side":"SELL"}]`,
+		`{"orders":[{"order_id":28,"volume":2,"trade_volume":1,"status":"partially_filled","direction":"buy"},{"order_id":29,"volume":1,"status":"new","direction":"sell"}],"total_page":1}`,
+		`{"order_info":[{"order_id":"28","size":"2","filled_qty":"1","state":"1","type":"1"},{"order_id":"29","size":"1","state":"0","type":"2"}]}`,
+	}
+	for _, body := range bodies {
+		var data interface{}
+		json.Unmarshal([]byte(body), &data)
+		orders, err := ParseOrders(map[string]interface{}{"code": 0, "data": data})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(orders) != 2 || orders[0].OrderId != "28" || orders[0].Amount != 2 || orders[1].OrderId != "29" ||
+			orders[0].Status != ORDER_STATUS_PARTIALLY_FILLED || orders[1].Status != ORDER_STATUS_NEW {
+			t.Fatalf("unexpected orders of %s: %+v", body, orders)
+		}
+	}
+	orders, err := ParseOrders(map[string]interface{}{"code": 0, "data": nil})
+	if err != nil || len(orders) != 0 {
+		t.Fatalf("unexpected orders of empty response: %+v, %v", orders, err)
+	}
+	if _, err := ParseOrders(ReturnAPIError(ExchangeError)); err == nil {
+		t.Fatal("expected error of error response")
+	}
+}
@@ -0,0 +1,206 @@
+package goexchange
+
+import (
+	"errors"
+	"fmt"
+)
+
+// MarketOrderMode unit of Amount of market orders
+type MarketOrderMode int
+
+const (
+	// Amount keeps the meaning of each adapter, see its PlaceMarketOrder
+	MarketOrderDefault MarketOrderMode = iota
+	// Amount is in base coin, eg: BTC of BTC/USDT
+	MarketOrderByBase
+	// Amount is in quote coin, eg: USDT of BTC/USDT
+	MarketOrderByQuote
+)
+
+func (mode MarketOrderMode) String() string {
+	switch mode {
+	case MarketOrderByBase:
+		return "base"
+	case MarketOrderByQuote:
+		return "quote"
+	default:
+		return "default"
+	}
+}
+
+func (timeInForce TimeInForce) String() string {
+	switch timeInForce {
+	case GTC:
+		return "GTC"
+	case POC:
+		return "POC"
+	case IOC:
+		return "IOC"
+	case FOK:
+		return "FOK"
+	case GTX:
+		return "GTX"
+	default:
+		return "unknown"
+	}
+}
+
+// IsPostOnly POC and GTX orders are canceled instead of taking liquidity
+func (timeInForce TimeInForce) IsPostOnly() bool {
+	return timeInForce == POC || timeInForce == GTX
+}
+
+// TimeInForceError time in force an adapter can not honor for an order
+type TimeInForceError struct {
+	TimeInForce TimeInForce
+	Reason      string
+}
+
+func (err *TimeInForceError) Error() string {
+	message := "time in force " + err.TimeInForce.String() + " is not supported"
+	if err.Reason != "" {
+		message += ", " + err.Reason
+	}
+	return message
+}
+
+// CheckTimeInForce *TimeInForceError when timeInForce is neither GTC nor
+// one of supported
+func CheckTimeInForce(timeInForce TimeInForce, supported ...TimeInForce) error {
+	if timeInForce == GTC {
+		return nil
+	}
+	for _, item := range supported {
+		if timeInForce == item {
+			return nil
+		}
+	}
+	return &TimeInForceError{TimeInForce: timeInForce}
+}
+
+// OcoOrder one-cancels-the-other pair of a limit maker order at Price and a
+// stop-limit order at StopLimitPrice triggered at StopPrice, a sell pair
+// takes profit above and stops loss below the market, a buy pair the reverse
+type OcoOrder struct {
+	Symbol         Symbol
+	ClientOrderId  string
+	Side           TradeSide
+	Amount         string
+	Price          string
+	StopPrice      string
+	StopLimitPrice string
+}
+
+// IsTriggerOrder trade type is triggered by StopPrice before it is placed
+func IsTriggerOrder(tradeType string) bool {
+	switch tradeType {
+	case STOP_LIMIT, STOP_MARKET, TAKE_PROFIT_LIMIT, TAKE_PROFIT_MARKET, TRAILING_STOP:
+		return true
+	}
+	return false
+}
+
+// IsLimitOrder trade type is placed as a limit order at Price
+func IsLimitOrder(tradeType string) bool {
+	return tradeType == LIMIT || tradeType == STOP_LIMIT || tradeType == TAKE_PROFIT_LIMIT
+}
+
+// TriggerAbove order triggers when price rises to StopPrice, which are stop
+// and trailing stop buys and take-profit sells, the others trigger on a fall
+func TriggerAbove(order *PlaceOrder) bool {
+	switch order.TradeType {
+	case STOP_LIMIT, STOP_MARKET:
+		return order.Side == BUY
+	case TAKE_PROFIT_LIMIT, TAKE_PROFIT_MARKET:
+		return order.Side == SELL
+	}
+	return order.Side == BUY
+}
+
+// ValidateOrder check the fields trade type of order requires, an empty
+// trade type is a market order
+func ValidateOrder(order *PlaceOrder) error {
+	switch order.TradeType {
+	case "", LIMIT, MARKET, STOP_LIMIT, STOP_MARKET, TAKE_PROFIT_LIMIT, TAKE_PROFIT_MARKET, TRAILING_STOP:
+	default:
+		return errors.New("unknown trade type " + order.TradeType)
+	}
+	if order.TimeInForce < GTC || order.TimeInForce > GTX {
+		return &TimeInForceError{TimeInForce: order.TimeInForce, Reason: "unknown value"}
+	}
+	if order.MarketMode < MarketOrderDefault || order.MarketMode > MarketOrderByQuote {
+		return fmt.Errorf("unknown market order mode %d", order.MarketMode)
+	}
+	if order.ClosePosition {
+		if order.TradeType != STOP_MARKET && order.TradeType != TAKE_PROFIT_MARKET {
+			return errors.New("close position is for stop market and take profit market orders only")
+		}
+	} else if ToFloat(order.Amount) <= 0 {
+		return fmt.Errorf("amount %q must be positive", order.Amount)
+	}
+	if IsLimitOrder(order.TradeType) && ToFloat(order.Price) <= 0 {
+		return fmt.Errorf("price %q of %s order must be positive", order.Price, order.TradeType)
+	}
+	if IsTriggerOrder(order.TradeType) && order.TradeType != TRAILING_STOP && ToFloat(order.StopPrice) <= 0 {
+		return fmt.Errorf("stop price %q of %s order must be positive", order.StopPrice, order.TradeType)
+	}
+	if order.TradeType == TRAILING_STOP && ToFloat(order.CallbackRate) <= 0 {
+		return fmt.Errorf("callback rate %q of trailing stop order must be positive", order.CallbackRate)
+	}
+	return nil
+}
+
+// ValidateOcoOrder check prices of oco order are on the right sides of StopPrice
+func ValidateOcoOrder(order *OcoOrder) error {
+	if ToFloat(order.Amount) <= 0 {
+		return fmt.Errorf("amount %q must be positive", order.Amount)
+	}
+	price, stopPrice := ToFloat(order.Price), ToFloat(order.StopPrice)
+	if price <= 0 || stopPrice <= 0 || ToFloat(order.StopLimitPrice) <= 0 {
+		return errors.New("price, stop price and stop limit price must be positive")
+	}
+	if order.Side == SELL && price <= stopPrice {
+		return errors.New("price of sell oco order must be above stop price")
+	}
+	if order.Side == BUY && price >= stopPrice {
+		return errors.New("price of buy oco order must be below stop price")
+	}
+	return nil
+}
+
+// IsMarketOrder order is a market order, an empty trade type is one too
+func IsMarketOrder(order *PlaceOrder) bool {
+	return order.TradeType == MARKET || order.TradeType == ""
+}
+
+// CheckMarketMode reject market order whose MarketMode is not one of modes,
+// the modes the adapter honors for the side of order, triggered market orders
+// take their amount as the adapter does by default and reject any other mode,
+// limit orders and orders of MarketOrderDefault always pass
+func CheckMarketMode(order *PlaceOrder, modes ...MarketOrderMode) error {
+	if IsLimitOrder(order.TradeType) || order.MarketMode == MarketOrderDefault {
+		return nil
+	}
+	if !IsMarketOrder(order) {
+		return fmt.Errorf("%s orders by %s are not supported, market modes are for market orders", order.TradeType, order.MarketMode)
+	}
+	for _, mode := range modes {
+		if order.MarketMode == mode {
+			return nil
+		}
+	}
+	return fmt.Errorf("%s market orders by %s are not supported", order.Side, order.MarketMode)
+}
+
+// InvalidOrder error response of order params rejected before sending, code
+// of TimeInForceError is TimeInForceParamsError
+func InvalidOrder(err error) map[string]interface{} {
+	status := OrderParamsError
+	var timeInForceError *TimeInForceError
+	if errors.As(err, &timeInForceError) {
+		status = TimeInForceParamsError
+	}
+	retData := ReturnAPIError(status).(map[string]interface{})
+	retData["error"] = err.Error()
+	return retData
+}
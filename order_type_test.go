@@ -0,0 +1,117 @@
+package goexchange
+
+import "testing"
+
+func TestValidateOrder(t *testing.T) {
+	tests := []struct {
+		order PlaceOrder
+		valid bool
+	}{
+		{PlaceOrder{TradeType: MARKET, Amount: "1"}, true},
+		{PlaceOrder{Amount: "1"}, true},
+		{PlaceOrder{TradeType: MARKET}, false},
+		{PlaceOrder{TradeType: "ICEBERG", Amount: "1"}, false},
+		{PlaceOrder{TradeType: LIMIT, Amount: "1"}, false},
+		{PlaceOrder{TradeType: LIMIT, Amount: "1", Price: "100"}, true},
+		{PlaceOrder{TradeType: STOP_LIMIT, Amount: "1", Price: "100"}, false},
+		{PlaceOrder{TradeType: STOP_LIMIT, Amount: "1", Price: "100", StopPrice: "99"}, true},
+		{PlaceOrder{TradeType: TAKE_PROFIT_MARKET, StopPrice: "120", ClosePosition: true}, true},
+		{PlaceOrder{TradeType: LIMIT, Price: "100", ClosePosition: true}, false},
+		{PlaceOrder{TradeType: TRAILING_STOP, Amount: "1"}, false},
+		{PlaceOrder{TradeType: TRAILING_STOP, Amount: "1", CallbackRate: "1.5"}, true},
+	}
+	for _, test := range tests {
+		if err := ValidateOrder(&test.order); (err == nil) != test.valid {
+			t.Errorf("%+v: expected valid %v, got %v", test.order, test.valid, err)
+		}
+	}
+}
+
+func TestValidateOcoOrder(t *testing.T) {
+	order := OcoOrder{Side: SELL, Amount: "1", Price: "110", StopPrice: "90", StopLimitPrice: "89"}
+	if err := ValidateOcoOrder(&order); err != nil {
+		t.Fatal(err)
+	}
+	order.Side = BUY
+	if err := ValidateOcoOrder(&order); err == nil {
+		t.Fatal("expected error of buy oco order above stop price")
+	}
+	order.Price, order.StopPrice, order.StopLimitPrice = "90", "110", "111"
+	if err := ValidateOcoOrder(&order); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTriggerAbove(t *testing.T) {
+	tests := []struct {
+		tradeType string
+		side      TradeSide
+		above     bool
+	}{
+		{STOP_MARKET, SELL, false},
+		{STOP_LIMIT, BUY, true},
+		{TAKE_PROFIT_LIMIT, SELL, true},
+		{TAKE_PROFIT_MARKET, BUY, false},
+		{TRAILING_STOP, BUY, true},
+	}
+	for _, test := range tests {
+		if above := TriggerAbove(&PlaceOrder{TradeType: test.tradeType, Side: test.side}); above != test.above {
+			t.Errorf("%s %s: expected %v, got %v", test.tradeType, test.side, test.above, above)
+		}
+	}
+}
+
+func TestInvalidOrder(t *testing.T) {
+	retData := InvalidOrder(ValidateOrder(&PlaceOrder{TradeType: LIMIT}))
+	if retData["code"] != OrderParamsError.Code || retData["error"] == "" {
+		t.Fatalf("unexpected response %v", retData)
+	}
+}
+
+func TestCheckMarketMode(t *testing.T) {
+	tests := []struct {
+		order PlaceOrder
+		modes []MarketOrderMode
+		valid bool
+	}{
+		{PlaceOrder{TradeType: MARKET}, nil, true},
+		{PlaceOrder{TradeType: MARKET, MarketMode: MarketOrderByQuote}, nil, false},
+		{PlaceOrder{MarketMode: MarketOrderByQuote}, []MarketOrderMode{MarketOrderByQuote}, true},
+		{PlaceOrder{TradeType: MARKET, MarketMode: MarketOrderByBase}, []MarketOrderMode{MarketOrderByQuote}, false},
+		{PlaceOrder{TradeType: LIMIT, MarketMode: MarketOrderByBase}, nil, true},
+		{PlaceOrder{TradeType: STOP_MARKET, MarketMode: MarketOrderByQuote}, []MarketOrderMode{MarketOrderByQuote}, false},
+		{PlaceOrder{TradeType: TAKE_PROFIT_MARKET, MarketMode: MarketOrderByBase}, []MarketOrderMode{MarketOrderByBase}, false},
+		{PlaceOrder{TradeType: STOP_MARKET}, nil, true},
+	}
+	for _, test := range tests {
+		if err := CheckMarketMode(&test.order, test.modes...); (err == nil) != test.valid {
+			t.Errorf("%+v %v: expected valid %v, got %v", test.order, test.modes, test.valid, err)
+		}
+	}
+	if err := ValidateOrder(&PlaceOrder{Amount: "1", MarketMode: 7}); err == nil {
+		t.Error("expected error of unknown market mode")
+	}
+}
+
+func TestCheckTimeInForce(t *testing.T) {
+	if GTX.String() != "GTX" || !POC.IsPostOnly() || IOC.IsPostOnly() {
+		t.Fatal("unexpected time in force names")
+	}
+	if err := CheckTimeInForce(GTC); err != nil {
+		t.Fatal(err)
+	}
+	if err := CheckTimeInForce(IOC, IOC, FOK); err != nil {
+		t.Fatal(err)
+	}
+	err := CheckTimeInForce(POC, IOC)
+	if err == nil || err.Error() != "time in force POC is not supported" {
+		t.Fatalf("unexpected error %v", err)
+	}
+	retData := InvalidOrder(err)
+	if retData["code"] != TimeInForceParamsError.Code {
+		t.Fatalf("unexpected response %v", retData)
+	}
+	if err := ValidateOrder(&PlaceOrder{Amount: "1", TimeInForce: 9}); err == nil {
+		t.Error("expected error of unknown time in force")
+	}
+}
@@ -0,0 +1,671 @@
+package paper
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+
+	goex "github.com/primitivelab/goexchange"
+)
+
+// order status, same words as binance
+const (
+	ORDER_NEW              = "NEW"
+	ORDER_PARTIALLY_FILLED = "PARTIALLY_FILLED"
+	ORDER_FILLED           = "FILLED"
+	ORDER_CANCELED         = "CANCELED"
+	ORDER_REJECTED         = "REJECTED"
+	ORDER_EXPIRED          = "EXPIRED"
+)
+
+// amountEpsilon amounts below it are treated as zero
+const amountEpsilon = 1e-12
+
+// DepthSource order book supplier, GetDepth of every spot and swap adapter satisfies it
+type DepthSource interface {
+	GetDepth(symbol goex.Symbol, size int, options map[string]string) map[string]interface{}
+}
+
+// Fee maker and taker fee rate
+type Fee struct {
+	Maker float64
+	Taker float64
+}
+
+// FeeSchedule fee rates by symbol, Default is used for symbols not listed
+type FeeSchedule struct {
+	Default Fee
+	Symbols map[string]Fee
+}
+
+// GetFee fee rate of symbol
+func (schedule *FeeSchedule) GetFee(symbol goex.Symbol) Fee {
+	if schedule == nil {
+		return Fee{}
+	}
+	if fee, ok := schedule.Symbols[symbol.ToLower().String()]; ok {
+		return fee
+	}
+	return schedule.Default
+}
+
+// Config simulated exchange config
+type Config struct {
+	// exchange name reported by GetExchangeName, default "paper"
+	Exchange string
+	// initial free balance by coin, eg: {"usdt": 10000}
+	Balances map[string]float64
+	Fees     *FeeSchedule
+	// depth size requested from DepthSource, default 20
+	DepthSize int
+	// milliseconds between placing an order and it reaching the book
+	Latency int64
+	// current millisecond timestamp, default wall clock
+	Clock func() int64
+	// default leverage of swap positions, default 1
+	Leverage int
+}
+
+// Order simulated order
+type Order struct {
+	OrderId       string  `json:"orderId"`
+	ClientOrderId string  `json:"clientOrderId"`
+	Symbol        string  `json:"symbol"`
+	Side          string  `json:"side"`
+	Type          string  `json:"type"`
+	TimeInForce   string  `json:"timeInForce"`
+	Price         float64 `json:"price"`
+	Amount        float64 `json:"origQty"`
+	FilledAmount  float64 `json:"executedQty"`
+	FilledCash    float64 `json:"cummulativeQuoteQty"`
+	Fee           float64 `json:"fee"`
+	FeeCoin       string  `json:"feeAsset"`
+	Status        string  `json:"status"`
+	CreateTime    int64   `json:"time"`
+	UpdateTime    int64   `json:"updateTime"`
+
+	symbol      goex.Symbol
+	side        goex.TradeSide
+	timeInForce goex.TimeInForce
+	activeTime  int64
+	active      bool
+	locked      float64
+}
+
+// Remaining amount not filled yet
+func (order *Order) Remaining() float64 {
+	remaining := order.Amount - order.FilledAmount
+	if remaining < amountEpsilon {
+		return 0
+	}
+	return remaining
+}
+
+// AvgPrice average filled price
+func (order *Order) AvgPrice() float64 {
+	if order.FilledAmount == 0 {
+		return 0
+	}
+	return order.FilledCash / order.FilledAmount
+}
+
+// IsOpen order is waiting to be filled
+func (order *Order) IsOpen() bool {
+	return order.Status == ORDER_NEW || order.Status == ORDER_PARTIALLY_FILLED
+}
+
+// Fill simulated trade of an order
+type Fill struct {
+	TradeId       string  `json:"id"`
+	OrderId       string  `json:"orderId"`
+	ClientOrderId string  `json:"clientOrderId"`
+	Symbol        string  `json:"symbol"`
+	Side          string  `json:"side"`
+	Price         float64 `json:"price"`
+	Amount        float64 `json:"qty"`
+	Fee           float64 `json:"commission"`
+	FeeCoin       string  `json:"commissionAsset"`
+	Maker         bool    `json:"isMaker"`
+	Time          int64   `json:"time"`
+}
+
+// account balance keeping of spot or swap
+type account interface {
+	// reserve lock funds of a new order
+	reserve(order *Order) error
+	// affordable max amount of order fillable at price
+	affordable(order *Order, price, amount float64) float64
+	// settle apply fill to balances and set its fee
+	settle(order *Order, fill *Fill, rate float64)
+	// release unlock funds left of a closed order
+	release(order *Order)
+}
+
+// engine in memory matching of orders against depth snapshots and trades
+type engine struct {
+	mutex     sync.Mutex
+	config    Config
+	source    DepthSource
+	account   account
+	depths    map[string]*goex.Depth
+	orders    map[string]*Order
+	orderList []*Order
+	fills     []*Fill
+	sequence  int64
+}
+
+func newEngine(source DepthSource, config *Config) *engine {
+	e := &engine{
+		source: source,
+		depths: map[string]*goex.Depth{},
+		orders: map[string]*Order{},
+	}
+	if config != nil {
+		e.config = *config
+	}
+	if e.config.Exchange == "" {
+		e.config.Exchange = goex.EXCHANGE_PAPER
+	}
+	if e.config.DepthSize == 0 {
+		e.config.DepthSize = 20
+	}
+	if e.config.Clock == nil {
+		e.config.Clock = goex.GetNowMillisecond
+	}
+	if e.config.Leverage == 0 {
+		e.config.Leverage = 1
+	}
+	return e
+}
+
+func (e *engine) now() int64 {
+	return e.config.Clock()
+}
+
+// depth current book of symbol, static depth first then fetched, the book
+// of source fetchDepth returned, static depth is not copied so that fills
+// consume its levels until the next SetDepth, mutex is held
+func (e *engine) depth(symbol goex.Symbol, fetched *goex.Depth) *goex.Depth {
+	if depth, ok := e.depths[symbolKey(symbol)]; ok {
+		return depth
+	}
+	if fetched == nil {
+		return &goex.Depth{Symbol: symbol}
+	}
+	return fetched
+}
+
+// fetchDepth book of symbol from source, it is requested without holding the
+// mutex, nil without source or when a static depth is set
+func (e *engine) fetchDepth(symbol goex.Symbol) *goex.Depth {
+	if e.source == nil {
+		return nil
+	}
+	e.mutex.Lock()
+	_, static := e.depths[symbolKey(symbol)]
+	e.mutex.Unlock()
+	if static {
+		return nil
+	}
+	depth, err := goex.ParseDepth(e.source.GetDepth(symbol, e.config.DepthSize, nil))
+	if err != nil {
+		return nil
+	}
+	depth.Symbol = symbol
+	return depth
+}
+
+// setDepth replace static book of symbol and match open orders against it
+func (e *engine) setDepth(symbol goex.Symbol, depth *goex.Depth) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	depth = copyDepth(depth)
+	e.depths[symbolKey(symbol)] = depth
+	e.match(symbol, depth)
+}
+
+// sync match open orders of symbol against current book
+func (e *engine) sync(symbol goex.Symbol) {
+	e.mutex.Lock()
+	open := e.hasOpenOrders(symbol)
+	e.mutex.Unlock()
+	if !open {
+		return
+	}
+	fetched := e.fetchDepth(symbol)
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	e.match(symbol, e.depth(symbol, fetched))
+}
+
+// submit validate, reserve and match a new order
+func (e *engine) submit(order *Order) (*Order, error) {
+	// orders without latency are matched on submit
+	var fetched *goex.Depth
+	if e.config.Latency <= 0 {
+		fetched = e.fetchDepth(order.symbol)
+	}
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	if order.Amount <= 0 {
+		return nil, errors.New("order amount must be positive")
+	}
+	if order.Type == goex.LIMIT && order.Price <= 0 {
+		return nil, errors.New("limit order price must be positive")
+	}
+	if order.Type == goex.MARKET && (order.timeInForce == goex.POC || order.timeInForce == goex.GTX) {
+		return nil, errors.New("market order can not be post only")
+	}
+	if order.ClientOrderId != "" {
+		for _, item := range e.orderList {
+			if item.ClientOrderId == order.ClientOrderId && item.IsOpen() {
+				return nil, errors.New("duplicate client order id")
+			}
+		}
+	}
+
+	now := e.now()
+	e.sequence++
+	order.OrderId = strconv.FormatInt(e.sequence, 10)
+	order.Symbol = symbolKey(order.symbol)
+	order.Side = order.side.String()
+	order.TimeInForce = timeInForceName(order.timeInForce)
+	order.Status = ORDER_NEW
+	order.CreateTime = now
+	order.UpdateTime = now
+	order.activeTime = now + e.config.Latency
+	if err := e.account.reserve(order); err != nil {
+		return nil, err
+	}
+	e.orders[order.OrderId] = order
+	e.orderList = append(e.orderList, order)
+
+	if order.activeTime <= now {
+		e.match(order.symbol, e.depth(order.symbol, fetched))
+	}
+	return order, nil
+}
+
+// cancel close an open order
+func (e *engine) cancel(orderID, clientOrderID string) (*Order, error) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	order := e.find(orderID, clientOrderID)
+	if order == nil {
+		return nil, errors.New("order does not exist")
+	}
+	if !order.IsOpen() {
+		return nil, errors.New("order is already closed")
+	}
+	e.close(order, ORDER_CANCELED)
+	return order, nil
+}
+
+// applyTrade fill resting limit orders crossed by a market trade
+func (e *engine) applyTrade(symbol goex.Symbol, price, amount float64) {
+	// the book is only needed to activate due orders
+	e.mutex.Lock()
+	due := e.hasDueOrders(symbol)
+	e.mutex.Unlock()
+	var fetched *goex.Depth
+	if due {
+		fetched = e.fetchDepth(symbol)
+	}
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	e.trade(symbol, fetched, price, amount)
+}
+
+// replayTrade replace static book of symbol, activate due orders against it
+// and fill resting orders with the amount they left of the trade
+func (e *engine) replayTrade(symbol goex.Symbol, depth *goex.Depth, price, amount float64) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	depth = copyDepth(depth)
+	e.depths[symbolKey(symbol)] = depth
+	now := e.now()
+	key := symbolKey(symbol)
+	for _, order := range e.orderList {
+		if order.Symbol != key || !order.IsOpen() || order.active || order.activeTime > now {
+			continue
+		}
+		filled := order.FilledAmount
+		e.activate(order, depth)
+		amount -= order.FilledAmount - filled
+	}
+	pruneDepth(depth)
+	// the book keeps what is left of the trade for orders placed after it
+	left := e.trade(symbol, nil, price, amount)
+	depth.Bids = limitLevels(depth.Bids, left)
+	depth.Asks = limitLevels(depth.Asks, left)
+	pruneDepth(depth)
+}
+
+// trade fill resting limit orders crossed by a market trade and return the
+// amount left of it, due orders are activated against the book of depth,
+// mutex is held
+func (e *engine) trade(symbol goex.Symbol, fetched *goex.Depth, price, amount float64) float64 {
+	now := e.now()
+	key := symbolKey(symbol)
+	for _, order := range e.orderList {
+		if amount <= amountEpsilon {
+			return 0
+		}
+		if order.Symbol != key || !order.IsOpen() || order.activeTime > now {
+			continue
+		}
+		if !order.active {
+			depth := e.depth(symbol, fetched)
+			e.activate(order, depth)
+			pruneDepth(depth)
+			if !order.IsOpen() {
+				continue
+			}
+		}
+		if order.Type != goex.LIMIT {
+			continue
+		}
+		if (order.side == goex.BUY && price > order.Price) || (order.side == goex.SELL && price < order.Price) {
+			continue
+		}
+		fillAmount := e.account.affordable(order, order.Price, minFloat(order.Remaining(), amount))
+		if fillAmount <= amountEpsilon {
+			continue
+		}
+		e.fill(order, order.Price, fillAmount, true)
+		amount -= fillAmount
+	}
+	return amount
+}
+
+// match activate due orders and fill resting ones, depth levels are consumed
+func (e *engine) match(symbol goex.Symbol, depth *goex.Depth) {
+	now := e.now()
+	key := symbolKey(symbol)
+	for _, order := range e.orderList {
+		if order.Symbol != key || !order.IsOpen() || order.activeTime > now {
+			continue
+		}
+		if !order.active {
+			e.activate(order, depth)
+			continue
+		}
+		e.take(order, depth, true)
+	}
+	pruneDepth(depth)
+}
+
+// activate first match of an order when it reaches the book
+func (e *engine) activate(order *Order, depth *goex.Depth) {
+	order.active = true
+	liquidity := 0.0
+	for _, level := range opposite(order, depth) {
+		if !crossing(order, level.Price) {
+			break
+		}
+		liquidity += level.Amount
+	}
+
+	switch order.timeInForce {
+	case goex.POC, goex.GTX:
+		if liquidity > 0 {
+			e.close(order, ORDER_REJECTED)
+			return
+		}
+	case goex.FOK:
+		if liquidity < order.Remaining()-amountEpsilon {
+			e.close(order, ORDER_EXPIRED)
+			return
+		}
+	}
+
+	e.take(order, depth, false)
+	if order.IsOpen() && (order.Type == goex.MARKET || order.timeInForce == goex.IOC || order.timeInForce == goex.FOK) {
+		e.close(order, ORDER_EXPIRED)
+	}
+}
+
+// take fill order against crossing levels, resting orders fill at their own price
+func (e *engine) take(order *Order, depth *goex.Depth, maker bool) {
+	levels := opposite(order, depth)
+	for i := range levels {
+		if order.Remaining() == 0 || !crossing(order, levels[i].Price) {
+			return
+		}
+		if levels[i].Amount <= amountEpsilon {
+			continue
+		}
+		price := levels[i].Price
+		if maker {
+			price = order.Price
+		}
+		amount := e.account.affordable(order, price, minFloat(order.Remaining(), levels[i].Amount))
+		if amount <= amountEpsilon {
+			return
+		}
+		e.fill(order, price, amount, maker)
+		levels[i].Amount -= amount
+	}
+}
+
+// fill record a trade of order
+func (e *engine) fill(order *Order, price, amount float64, maker bool) {
+	fee := e.fees().GetFee(order.symbol)
+	rate := fee.Taker
+	if maker {
+		rate = fee.Maker
+	}
+	now := e.now()
+	fill := &Fill{
+		TradeId:       strconv.Itoa(len(e.fills) + 1),
+		OrderId:       order.OrderId,
+		ClientOrderId: order.ClientOrderId,
+		Symbol:        order.Symbol,
+		Side:          order.Side,
+		Price:         price,
+		Amount:        amount,
+		Maker:         maker,
+		Time:          now,
+	}
+	e.account.settle(order, fill, rate)
+	e.fills = append(e.fills, fill)
+
+	order.FilledAmount += amount
+	order.FilledCash += price * amount
+	order.Fee += fill.Fee
+	order.FeeCoin = fill.FeeCoin
+	order.UpdateTime = now
+	if order.Remaining() == 0 {
+		e.close(order, ORDER_FILLED)
+	} else {
+		order.Status = ORDER_PARTIALLY_FILLED
+	}
+}
+
+// close finish order and unlock funds left
+func (e *engine) close(order *Order, status string) {
+	order.Status = status
+	order.UpdateTime = e.now()
+	e.account.release(order)
+}
+
+func (e *engine) fees() *FeeSchedule {
+	return e.config.Fees
+}
+
+func (e *engine) find(orderID, clientOrderID string) *Order {
+	if orderID != "" {
+		return e.orders[orderID]
+	}
+	for i := len(e.orderList) - 1; i >= 0; i-- {
+		if clientOrderID != "" && e.orderList[i].ClientOrderId == clientOrderID {
+			return e.orderList[i]
+		}
+	}
+	return nil
+}
+
+// hasDueOrders symbol has open orders past their latency waiting to be activated
+func (e *engine) hasDueOrders(symbol goex.Symbol) bool {
+	now := e.now()
+	key := symbolKey(symbol)
+	for _, order := range e.orderList {
+		if order.Symbol == key && order.IsOpen() && !order.active && order.activeTime <= now {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *engine) hasOpenOrders(symbol goex.Symbol) bool {
+	key := symbolKey(symbol)
+	for _, order := range e.orderList {
+		if order.Symbol == key && order.IsOpen() {
+			return true
+		}
+	}
+	return false
+}
+
+// ordersOf orders of symbol newest first, filtered by status when given
+func (e *engine) ordersOf(symbol goex.Symbol, status string, openOnly bool, size int) []*Order {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	key := symbolKey(symbol)
+	orders := []*Order{}
+	for i := len(e.orderList) - 1; i >= 0; i-- {
+		order := e.orderList[i]
+		if order.Symbol != key || (openOnly && !order.IsOpen()) {
+			continue
+		}
+		if status != "" && !strings.EqualFold(status, order.Status) {
+			continue
+		}
+		copyOrder := *order
+		orders = append(orders, &copyOrder)
+		if size > 0 && len(orders) >= size {
+			break
+		}
+	}
+	return orders
+}
+
+// fillsOf fills of symbol newest first
+func (e *engine) fillsOf(symbol goex.Symbol, size int) []*Fill {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	key := symbolKey(symbol)
+	fills := []*Fill{}
+	for i := len(e.fills) - 1; i >= 0; i-- {
+		if e.fills[i].Symbol != key {
+			continue
+		}
+		copyFill := *e.fills[i]
+		fills = append(fills, &copyFill)
+		if size > 0 && len(fills) >= size {
+			break
+		}
+	}
+	return fills
+}
+
+// orderType simulated type of order, an empty trade type is a market order
+// and trigger orders are not simulated
+func orderType(order *goex.PlaceOrder) (string, error) {
+	switch order.TradeType {
+	case goex.LIMIT:
+		return goex.LIMIT, nil
+	case goex.MARKET, "":
+		return goex.MARKET, nil
+	}
+	return "", errors.New(order.TradeType + " orders are not supported by paper exchange")
+}
+
+// opposite book side an order takes from
+func opposite(order *Order, depth *goex.Depth) []goex.DepthRecord {
+	if depth == nil {
+		return nil
+	}
+	if order.side == goex.BUY {
+		return depth.Asks
+	}
+	return depth.Bids
+}
+
+// crossing level price is marketable for order
+func crossing(order *Order, price float64) bool {
+	if order.Type == goex.MARKET {
+		return true
+	}
+	if order.side == goex.BUY {
+		return price <= order.Price
+	}
+	return price >= order.Price
+}
+
+func copyDepth(depth *goex.Depth) *goex.Depth {
+	if depth == nil {
+		return nil
+	}
+	copied := *depth
+	copied.Bids = append([]goex.DepthRecord{}, depth.Bids...)
+	copied.Asks = append([]goex.DepthRecord{}, depth.Asks...)
+	return &copied
+}
+
+// pruneDepth remove levels filled up by matching
+func pruneDepth(depth *goex.Depth) {
+	if depth == nil {
+		return
+	}
+	depth.Bids = pruneLevels(depth.Bids)
+	depth.Asks = pruneLevels(depth.Asks)
+}
+
+// limitLevels cut levels to amount in total
+func limitLevels(levels []goex.DepthRecord, amount float64) []goex.DepthRecord {
+	for i := range levels {
+		levels[i].Amount = minFloat(levels[i].Amount, amount)
+		amount -= levels[i].Amount
+	}
+	return levels
+}
+
+func pruneLevels(levels []goex.DepthRecord) []goex.DepthRecord {
+	kept := levels[:0]
+	for _, level := range levels {
+		if level.Amount > amountEpsilon {
+			kept = append(kept, level)
+		}
+	}
+	return kept
+}
+
+func symbolKey(symbol goex.Symbol) string {
+	return symbol.ToLower().String()
+}
+
+func timeInForceName(timeInForce goex.TimeInForce) string {
+	switch timeInForce {
+	case goex.POC:
+		return "POC"
+	case goex.IOC:
+		return "IOC"
+	case goex.FOK:
+		return "FOK"
+	case goex.GTX:
+		return "GTX"
+	}
+	return "GTC"
+}
+
+func minFloat(a, b float64) float64 {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,426 @@
+package paper
+
+import (
+	"encoding/json"
+	"errors"
+	"sort"
+	"strings"
+
+	goex "github.com/primitivelab/goexchange"
+)
+
+// Balance simulated coin balance
+type Balance struct {
+	Asset  string  `json:"asset"`
+	Free   float64 `json:"free"`
+	Locked float64 `json:"locked"`
+}
+
+// Spot simulated spot exchange, implements goex.SpotAPI
+type Spot struct {
+	*engine
+	balances map[string]*Balance
+}
+
+// NewSpot new instance, orders are matched against books of source, which can
+// be any spot adapter, or nil when books are fed by SetDepth
+func NewSpot(source DepthSource, config *Config) *Spot {
+	instance := new(Spot)
+	instance.engine = newEngine(source, config)
+	instance.engine.account = instance
+	instance.balances = map[string]*Balance{}
+	for coin, amount := range instance.config.Balances {
+		instance.balance(coin).Free = amount
+	}
+	return instance
+}
+
+// SetDepth feed order book of symbol, it replaces the source book of symbol,
+// filled levels are used up until the next SetDepth
+func (spot *Spot) SetDepth(symbol goex.Symbol, depth *goex.Depth) {
+	spot.setDepth(symbol, depth)
+}
+
+// ApplyTrade feed a market trade, resting orders crossed by it are filled
+func (spot *Spot) ApplyTrade(symbol goex.Symbol, price, amount float64) {
+	spot.applyTrade(symbol, price, amount)
+}
+
+// ReplayTrade replace the book of symbol by depth and feed a market trade of
+// amount at price, orders reaching the book take from depth and resting
+// orders fill against the trade only, together they fill at most amount
+func (spot *Spot) ReplayTrade(symbol goex.Symbol, depth *goex.Depth, price, amount float64) {
+	spot.replayTrade(symbol, depth, price, amount)
+}
+
+// Sync match open orders of symbol against the current book
+func (spot *Spot) Sync(symbol goex.Symbol) {
+	spot.sync(symbol)
+}
+
+// GetBalances copy of all balances
+func (spot *Spot) GetBalances() map[string]Balance {
+	spot.mutex.Lock()
+	defer spot.mutex.Unlock()
+	balances := map[string]Balance{}
+	for coin, balance := range spot.balances {
+		balances[coin] = *balance
+	}
+	return balances
+}
+
+// GetOrders copy of all orders, oldest first
+func (spot *Spot) GetOrders() []Order {
+	spot.mutex.Lock()
+	defer spot.mutex.Unlock()
+	orders := make([]Order, 0, len(spot.orderList))
+	for _, order := range spot.orderList {
+		orders = append(orders, *order)
+	}
+	return orders
+}
+
+// GetFills copy of all fills, oldest first
+func (spot *Spot) GetFills() []Fill {
+	spot.mutex.Lock()
+	defer spot.mutex.Unlock()
+	fills := make([]Fill, 0, len(spot.fills))
+	for _, fill := range spot.fills {
+		fills = append(fills, *fill)
+	}
+	return fills
+}
+
+// GetExchangeName get exchange name
+func (spot *Spot) GetExchangeName() string {
+	return spot.config.Exchange
+}
+
+// GetCoinList exchange coin list of source
+func (spot *Spot) GetCoinList() interface{} {
+	if source, ok := spot.source.(goex.SpotAPI); ok {
+		return source.GetCoinList()
+	}
+	return goex.ReturnAPIError(goex.MethodNotExistError)
+}
+
+// GetSymbolList exchange symbol list of source
+func (spot *Spot) GetSymbolList() interface{} {
+	if source, ok := spot.source.(goex.SpotAPI); ok {
+		return source.GetSymbolList()
+	}
+	return goex.ReturnAPIError(goex.MethodNotExistError)
+}
+
+// GetDepth depth fed by SetDepth, or of source
+func (spot *Spot) GetDepth(symbol goex.Symbol, size int, options map[string]string) map[string]interface{} {
+	spot.mutex.Lock()
+	depth, ok := spot.depths[symbolKey(symbol)]
+	data := depthData(depth, size)
+	spot.mutex.Unlock()
+	if !ok && spot.source != nil {
+		return spot.source.GetDepth(symbol, size, options)
+	}
+	return goex.ReturnAPIData(data)
+}
+
+// GetTicker ticker of source, or best prices of depth fed by SetDepth
+func (spot *Spot) GetTicker(symbol goex.Symbol) interface{} {
+	if source, ok := spot.source.(goex.SpotAPI); ok {
+		return source.GetTicker(symbol)
+	}
+	fetched := spot.fetchDepth(symbol)
+	spot.mutex.Lock()
+	defer spot.mutex.Unlock()
+	return goex.ReturnAPIData(tickerData(symbol, spot.depth(symbol, fetched)))
+}
+
+// GetKline kline of source
+func (spot *Spot) GetKline(symbol goex.Symbol, period, size int, options map[string]string) interface{} {
+	if source, ok := spot.source.(goex.SpotAPI); ok {
+		return source.GetKline(symbol, period, size, options)
+	}
+	return goex.ReturnAPIError(goex.MethodNotExistError)
+}
+
+// GetTrade trade of source
+func (spot *Spot) GetTrade(symbol goex.Symbol, size int, options map[string]string) interface{} {
+	if source, ok := spot.source.(goex.SpotAPI); ok {
+		return source.GetTrade(symbol, size, options)
+	}
+	return goex.ReturnAPIError(goex.MethodNotExistError)
+}
+
+// GetUserBalance simulated balances, data: {"balances": [{"asset", "free", "locked"}]}
+func (spot *Spot) GetUserBalance() interface{} {
+	balances := spot.GetBalances()
+	list := make([]Balance, 0, len(balances))
+	for _, balance := range balances {
+		list = append(list, balance)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Asset < list[j].Asset })
+	return goex.ReturnAPIData(jsonData(map[string]interface{}{"balances": list}))
+}
+
+// PlaceOrder place order
+func (spot *Spot) PlaceOrder(order *goex.PlaceOrder) interface{} {
+	// simulated amounts are in base coin
+	if err := goex.CheckMarketMode(order, goex.MarketOrderByBase); err != nil {
+		return goex.InvalidOrder(err)
+	}
+	tradeType, err := orderType(order)
+	if err != nil {
+		return goex.InvalidOrder(err)
+	}
+	return spot.place(&Order{
+		ClientOrderId: order.ClientOrderId,
+		Type:          tradeType,
+		Price:         goex.ToFloat(order.Price),
+		Amount:        goex.ToFloat(order.Amount),
+		symbol:        order.Symbol,
+		side:          order.Side,
+		timeInForce:   order.TimeInForce,
+	})
+}
+
+// PlaceLimitOrder place limit order
+func (spot *Spot) PlaceLimitOrder(symbol goex.Symbol, price string, amount string, side goex.TradeSide, ClientOrderID string) interface{} {
+	return spot.place(&Order{
+		ClientOrderId: ClientOrderID,
+		Type:          goex.LIMIT,
+		Price:         goex.ToFloat(price),
+		Amount:        goex.ToFloat(amount),
+		symbol:        symbol,
+		side:          side,
+	})
+}
+
+// PlaceMarketOrder place market order, amount is base coin amount
+func (spot *Spot) PlaceMarketOrder(symbol goex.Symbol, amount string, side goex.TradeSide, ClientOrderID string) interface{} {
+	return spot.place(&Order{
+		ClientOrderId: ClientOrderID,
+		Type:          goex.MARKET,
+		Amount:        goex.ToFloat(amount),
+		symbol:        symbol,
+		side:          side,
+	})
+}
+
+// BatchPlaceLimitOrder batch place limit order, data is result list in order
+func (spot *Spot) BatchPlaceLimitOrder(orders []goex.LimitOrder) interface{} {
+	results := make([]interface{}, 0, len(orders))
+	for _, item := range orders {
+		results = append(results, spot.place(&Order{
+			ClientOrderId: item.ClientOrderId,
+			Type:          goex.LIMIT,
+			Price:         goex.ToFloat(item.Price),
+			Amount:        goex.ToFloat(item.Amount),
+			symbol:        item.Symbol,
+			side:          item.Side,
+			timeInForce:   item.TimeInForce,
+		}))
+	}
+	return goex.ReturnAPIData(results)
+}
+
+// CancelOrder cancel user trust order
+func (spot *Spot) CancelOrder(symbol goex.Symbol, orderID, clientOrderID string) interface{} {
+	order, err := spot.cancel(orderID, clientOrderID)
+	if err != nil {
+		return exchangeError(err)
+	}
+	return goex.ReturnAPIData(jsonData(order))
+}
+
+// BatchCancelOrder batch cancel trust order, ids are separated by comma
+func (spot *Spot) BatchCancelOrder(symbol goex.Symbol, orderIds, clientOrderIds string) interface{} {
+	results := []interface{}{}
+	if orderIds != "" {
+		for _, orderID := range strings.Split(orderIds, ",") {
+			results = append(results, spot.CancelOrder(symbol, orderID, ""))
+		}
+	} else {
+		for _, clientOrderID := range strings.Split(clientOrderIds, ",") {
+			results = append(results, spot.CancelOrder(symbol, "", clientOrderID))
+		}
+	}
+	return goex.ReturnAPIData(results)
+}
+
+// GetUserOpenTrustOrders user open trust order list
+func (spot *Spot) GetUserOpenTrustOrders(symbol goex.Symbol, size int, options map[string]string) interface{} {
+	spot.sync(symbol)
+	return goex.ReturnAPIData(jsonData(spot.ordersOf(symbol, "", true, size)))
+}
+
+// GetUserOrderInfo user trust order info
+func (spot *Spot) GetUserOrderInfo(symbol goex.Symbol, orderID, clientOrderID string) interface{} {
+	spot.sync(symbol)
+	spot.mutex.Lock()
+	defer spot.mutex.Unlock()
+	order := spot.find(orderID, clientOrderID)
+	if order == nil {
+		return exchangeError(errors.New("order does not exist"))
+	}
+	return goex.ReturnAPIData(jsonData(order))
+}
+
+// GetUserTradeOrders user fill list, newest first
+func (spot *Spot) GetUserTradeOrders(symbol goex.Symbol, size int, options map[string]string) interface{} {
+	spot.sync(symbol)
+	return goex.ReturnAPIData(jsonData(spot.fillsOf(symbol, size)))
+}
+
+// GetUserTrustOrders user trust order list, newest first
+func (spot *Spot) GetUserTrustOrders(symbol goex.Symbol, status string, size int, options map[string]string) interface{} {
+	spot.sync(symbol)
+	return goex.ReturnAPIData(jsonData(spot.ordersOf(symbol, status, false, size)))
+}
+
+// HttpRequest is not available on simulated exchange
+func (spot *Spot) HttpRequest(requestURL, method string, options interface{}, signed bool) interface{} {
+	return goex.ReturnAPIError(goex.MethodNotExistError)
+}
+
+func (spot *Spot) place(order *Order) interface{} {
+	placed, err := spot.submit(order)
+	if err != nil {
+		return exchangeError(err)
+	}
+	spot.mutex.Lock()
+	defer spot.mutex.Unlock()
+	return goex.ReturnAPIData(jsonData(placed))
+}
+
+func (spot *Spot) balance(coin string) *Balance {
+	coin = strings.ToLower(coin)
+	balance, ok := spot.balances[coin]
+	if !ok {
+		balance = &Balance{Asset: coin}
+		spot.balances[coin] = balance
+	}
+	return balance
+}
+
+// reserve lock quote of limit buy and base of sell
+func (spot *Spot) reserve(order *Order) error {
+	if order.side == goex.BUY {
+		if order.Type == goex.MARKET {
+			return nil
+		}
+		balance := spot.balance(order.symbol.CoinTo)
+		need := order.Price * order.Amount
+		if balance.Free < need-amountEpsilon {
+			return errors.New("insufficient balance")
+		}
+		balance.Free -= need
+		balance.Locked += need
+		order.locked = need
+		return nil
+	}
+
+	balance := spot.balance(order.symbol.CoinFrom)
+	if balance.Free < order.Amount-amountEpsilon {
+		return errors.New("insufficient balance")
+	}
+	balance.Free -= order.Amount
+	balance.Locked += order.Amount
+	order.locked = order.Amount
+	return nil
+}
+
+// affordable market buy is limited by free quote
+func (spot *Spot) affordable(order *Order, price, amount float64) float64 {
+	if order.side == goex.BUY && order.Type == goex.MARKET && price > 0 {
+		return minFloat(amount, spot.balance(order.symbol.CoinTo).Free/price)
+	}
+	return amount
+}
+
+// settle buyer pays fee in base coin and seller in quote coin
+func (spot *Spot) settle(order *Order, fill *Fill, rate float64) {
+	base := spot.balance(order.symbol.CoinFrom)
+	quote := spot.balance(order.symbol.CoinTo)
+	cash := fill.Price * fill.Amount
+	if order.side == goex.BUY {
+		if order.Type == goex.MARKET {
+			quote.Free -= cash
+		} else {
+			locked := order.Price * fill.Amount
+			quote.Locked -= locked
+			quote.Free += locked - cash
+			order.locked -= locked
+		}
+		fill.Fee = fill.Amount * rate
+		fill.FeeCoin = base.Asset
+		base.Free += fill.Amount - fill.Fee
+		return
+	}
+
+	base.Locked -= fill.Amount
+	order.locked -= fill.Amount
+	fill.Fee = cash * rate
+	fill.FeeCoin = quote.Asset
+	quote.Free += cash - fill.Fee
+}
+
+// release unlock funds left of closed order
+func (spot *Spot) release(order *Order) {
+	if order.locked <= 0 {
+		order.locked = 0
+		return
+	}
+	coin := order.symbol.CoinFrom
+	if order.side == goex.BUY {
+		coin = order.symbol.CoinTo
+	}
+	balance := spot.balance(coin)
+	balance.Locked -= order.locked
+	balance.Free += order.locked
+	order.locked = 0
+}
+
+// jsonData convert value to json decoded data like adapters return
+func jsonData(value interface{}) interface{} {
+	body, _ := json.Marshal(value)
+	var data interface{}
+	json.Unmarshal(body, &data)
+	return data
+}
+
+// depthData depth in [[price, amount]] format
+func depthData(depth *goex.Depth, size int) interface{} {
+	bids := [][]float64{}
+	asks := [][]float64{}
+	if depth != nil {
+		for i, level := range depth.Bids {
+			if size > 0 && i >= size {
+				break
+			}
+			bids = append(bids, []float64{level.Price, level.Amount})
+		}
+		for i, level := range depth.Asks {
+			if size > 0 && i >= size {
+				break
+			}
+			asks = append(asks, []float64{level.Price, level.Amount})
+		}
+	}
+	return jsonData(map[string]interface{}{"bids": bids, "asks": asks})
+}
+
+// tickerData best prices of depth
+func tickerData(symbol goex.Symbol, depth *goex.Depth) interface{} {
+	return map[string]interface{}{
+		"symbol":   symbolKey(symbol),
+		"bidPrice": depth.BestBid().Price,
+		"askPrice": depth.BestAsk().Price,
+		"last":     depth.MidPrice(),
+	}
+}
+
+func exchangeError(err error) map[string]interface{} {
+	retData := goex.ReturnAPIError(goex.ExchangeError).(map[string]interface{})
+	retData["error"] = err.Error()
+	return retData
+}
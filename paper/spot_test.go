@@ -0,0 +1,207 @@
+package paper
+
+import (
+	"testing"
+	"time"
+
+	goex "github.com/primitivelab/goexchange"
+)
+
+var btcUsdt = goex.NewSymbol("btc", "usdt")
+
+func getSpotInstance() *Spot {
+	spot := NewSpot(nil, &Config{
+		Balances: map[string]float64{"usdt": 10000, "btc": 1},
+		Fees:     &FeeSchedule{Default: Fee{Maker: 0.001, Taker: 0.002}},
+	})
+	spot.SetDepth(btcUsdt, &goex.Depth{
+		Bids: []goex.DepthRecord{{Price: 99, Amount: 1}, {Price: 98, Amount: 2}},
+		Asks: []goex.DepthRecord{{Price: 101, Amount: 1}, {Price: 102, Amount: 2}},
+	})
+	return spot
+}
+
+func TestSpot_PlaceMarketOrder(t *testing.T) {
+	spot := getSpotInstance()
+
+	response := spot.PlaceMarketOrder(btcUsdt, "2", goex.BUY, "")
+	data, err := goex.ParseResponse(response)
+	if err != nil {
+		t.Fatal(err)
+	}
+	order := data.(map[string]interface{})
+	if order["status"] != ORDER_FILLED || goex.ToFloat(order["cummulativeQuoteQty"]) != 203 {
+		t.Fatalf("unexpected order: %v", order)
+	}
+	balances := spot.GetBalances()
+	if balances["usdt"].Free != 10000-203 || balances["btc"].Free != 1+2*(1-0.002) {
+		t.Fatalf("unexpected balances: %v", balances)
+	}
+}
+
+func TestSpot_TimeInForce(t *testing.T) {
+	spot := getSpotInstance()
+
+	poc := spot.PlaceOrder(&goex.PlaceOrder{Symbol: btcUsdt, Price: "101", Amount: "1", Side: goex.BUY, TradeType: goex.LIMIT, TimeInForce: goex.POC})
+	data, _ := goex.ParseResponse(poc)
+	if data.(map[string]interface{})["status"] != ORDER_REJECTED {
+		t.Fatalf("post only order crossing the book must be rejected: %v", data)
+	}
+
+	fok := spot.PlaceOrder(&goex.PlaceOrder{Symbol: btcUsdt, Price: "101", Amount: "2", Side: goex.BUY, TradeType: goex.LIMIT, TimeInForce: goex.FOK})
+	data, _ = goex.ParseResponse(fok)
+	if data.(map[string]interface{})["status"] != ORDER_EXPIRED || goex.ToFloat(data.(map[string]interface{})["executedQty"]) != 0 {
+		t.Fatalf("fok order without enough liquidity must expire unfilled: %v", data)
+	}
+
+	ioc := spot.PlaceOrder(&goex.PlaceOrder{Symbol: btcUsdt, Price: "101", Amount: "2", Side: goex.BUY, TradeType: goex.LIMIT, TimeInForce: goex.IOC})
+	data, _ = goex.ParseResponse(ioc)
+	if data.(map[string]interface{})["status"] != ORDER_EXPIRED || goex.ToFloat(data.(map[string]interface{})["executedQty"]) != 1 {
+		t.Fatalf("ioc order must fill 1 and expire: %v", data)
+	}
+	if spot.GetBalances()["usdt"].Locked != 0 {
+		t.Fatalf("closed orders must not lock balance: %v", spot.GetBalances())
+	}
+}
+
+func TestSpot_RestingOrder(t *testing.T) {
+	spot := getSpotInstance()
+
+	response := spot.PlaceLimitOrder(btcUsdt, "100", "1", goex.BUY, "my-order")
+	if _, err := goex.ParseResponse(response); err != nil {
+		t.Fatal(err)
+	}
+	if spot.GetBalances()["usdt"].Locked != 100 {
+		t.Fatalf("limit buy must lock quote: %v", spot.GetBalances())
+	}
+
+	spot.ApplyTrade(btcUsdt, 99.5, 0.4)
+	spot.SetDepth(btcUsdt, &goex.Depth{
+		Bids: []goex.DepthRecord{{Price: 98, Amount: 1}},
+		Asks: []goex.DepthRecord{{Price: 99, Amount: 5}},
+	})
+	data, _ := goex.ParseResponse(spot.GetUserOrderInfo(btcUsdt, "", "my-order"))
+	order := data.(map[string]interface{})
+	if order["status"] != ORDER_FILLED || goex.ToFloat(order["price"]) != 100 {
+		t.Fatalf("resting order must fill at its price: %v", order)
+	}
+	fills := spot.GetFills()
+	if len(fills) != 2 || !fills[0].Maker || fills[0].Amount != 0.4 {
+		t.Fatalf("unexpected fills: %v", fills)
+	}
+}
+
+func TestSpot_PlaceOrderTradeType(t *testing.T) {
+	spot := getSpotInstance()
+
+	for _, tradeType := range []string{goex.STOP_LIMIT, goex.STOP_MARKET, goex.TAKE_PROFIT_LIMIT, goex.TAKE_PROFIT_MARKET, goex.TRAILING_STOP} {
+		response := spot.PlaceOrder(&goex.PlaceOrder{Symbol: btcUsdt, Price: "90", StopPrice: "95", CallbackRate: "1", Amount: "1", Side: goex.SELL, TradeType: tradeType})
+		if _, err := goex.ParseResponse(response); err == nil {
+			t.Fatalf("%s order must be rejected", tradeType)
+		}
+	}
+	if len(spot.GetFills()) != 0 || spot.GetBalances()["btc"].Locked != 0 {
+		t.Fatalf("rejected orders must not trade: %v", spot.GetBalances())
+	}
+}
+
+func TestSpot_DepthConsumed(t *testing.T) {
+	spot := NewSpot(nil, &Config{Balances: map[string]float64{"usdt": 10000}})
+	spot.SetDepth(btcUsdt, &goex.Depth{Asks: []goex.DepthRecord{{Price: 100, Amount: 3}}})
+
+	spot.PlaceLimitOrder(btcUsdt, "100", "10", goex.BUY, "big")
+	for i := 0; i < 3; i++ {
+		spot.GetUserOpenTrustOrders(btcUsdt, 10, nil)
+	}
+	data, _ := goex.ParseResponse(spot.GetUserOrderInfo(btcUsdt, "", "big"))
+	if order := data.(map[string]interface{}); goex.ToFloat(order["executedQty"]) != 3 || order["status"] != ORDER_PARTIALLY_FILLED {
+		t.Fatalf("filled levels must be used up: %v", order)
+	}
+	if depth, _ := goex.ParseDepth(spot.GetDepth(btcUsdt, 10, nil)); len(depth.Asks) != 0 {
+		t.Fatalf("unexpected depth after fill: %+v", depth)
+	}
+
+	spot.SetDepth(btcUsdt, &goex.Depth{Asks: []goex.DepthRecord{{Price: 100, Amount: 3}}})
+	data, _ = goex.ParseResponse(spot.GetUserOrderInfo(btcUsdt, "", "big"))
+	if order := data.(map[string]interface{}); goex.ToFloat(order["executedQty"]) != 6 {
+		t.Fatalf("new book must fill again: %v", order)
+	}
+}
+
+func TestSpot_Latency(t *testing.T) {
+	now := int64(1000)
+	spot := NewSpot(nil, &Config{
+		Balances: map[string]float64{"usdt": 1000},
+		Latency:  50,
+		Clock:    func() int64 { return now },
+	})
+	spot.SetDepth(btcUsdt, &goex.Depth{Asks: []goex.DepthRecord{{Price: 10, Amount: 10}}})
+
+	spot.PlaceMarketOrder(btcUsdt, "1", goex.BUY, "")
+	if len(spot.GetFills()) != 0 {
+		t.Fatal("order must not fill before latency passes")
+	}
+	now = 1050
+	spot.Sync(btcUsdt)
+	if len(spot.GetFills()) != 1 {
+		t.Fatal("order must fill after latency passes")
+	}
+}
+
+func TestSpot_PlaceOrderMarketMode(t *testing.T) {
+	spot := getSpotInstance()
+
+	response := spot.PlaceOrder(&goex.PlaceOrder{Symbol: btcUsdt, Side: goex.BUY, TradeType: goex.MARKET, Amount: "100", MarketMode: goex.MarketOrderByQuote})
+	if _, err := goex.ParseResponse(response); err == nil {
+		t.Fatal("expected error of market buy by quote")
+	}
+	response = spot.PlaceOrder(&goex.PlaceOrder{Symbol: btcUsdt, Side: goex.BUY, TradeType: goex.MARKET, Amount: "1", MarketMode: goex.MarketOrderByBase})
+	if _, err := goex.ParseResponse(response); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// lockCheckSource book source reporting whether the spot is locked while
+// its book is fetched
+type lockCheckSource struct {
+	spot   *Spot
+	locked bool
+}
+
+func (source *lockCheckSource) GetDepth(symbol goex.Symbol, size int, options map[string]string) map[string]interface{} {
+	if source.spot != nil {
+		done := make(chan struct{})
+		go func() {
+			source.spot.mutex.Lock()
+			source.spot.mutex.Unlock()
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			source.locked = true
+		}
+	}
+	return goex.ReturnAPIData(map[string]interface{}{
+		"bids": []interface{}{[]interface{}{"99", "1"}},
+		"asks": []interface{}{[]interface{}{"101", "1"}},
+	})
+}
+
+func TestSpot_DepthFetchedUnlocked(t *testing.T) {
+	source := &lockCheckSource{}
+	spot := NewSpot(source, &Config{Balances: map[string]float64{"usdt": 10000}})
+	source.spot = spot
+
+	spot.PlaceMarketOrder(btcUsdt, "0.5", goex.BUY, "")
+	placed, _ := goex.ParseResponse(spot.PlaceLimitOrder(btcUsdt, "98", "1", goex.BUY, ""))
+	spot.GetUserOrderInfo(btcUsdt, goex.ToString(placed.(map[string]interface{})["orderId"]), "")
+	spot.GetTicker(btcUsdt)
+	if source.locked {
+		t.Fatal("book of source was fetched while the spot was locked")
+	}
+	orders := spot.GetOrders()
+	if len(orders) != 2 || orders[0].FilledAmount != 0.5 {
+		t.Fatalf("unexpected orders %+v", orders)
+	}
+}
@@ -0,0 +1,448 @@
+package paper
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"strings"
+
+	goex "github.com/primitivelab/goexchange"
+)
+
+// Position simulated one-way position, Amount is negative for short
+type Position struct {
+	Symbol        string  `json:"symbol"`
+	Amount        float64 `json:"positionAmt"`
+	EntryPrice    float64 `json:"entryPrice"`
+	MarkPrice     float64 `json:"markPrice"`
+	UnrealizedPnl float64 `json:"unRealizedProfit"`
+	RealizedPnl   float64 `json:"realizedPnl"`
+	Leverage      int     `json:"leverage"`
+}
+
+// SwapBalance simulated margin balance of a settle coin
+type SwapBalance struct {
+	Asset          string  `json:"asset"`
+	WalletBalance  float64 `json:"walletBalance"`
+	OrderMargin    float64 `json:"orderMargin"`
+	PositionMargin float64 `json:"positionMargin"`
+	Available      float64 `json:"availableBalance"`
+}
+
+// Swap simulated linear (quote margined) perpetual swap exchange with positions,
+// implements goex.SwapAPI, liquidation is not simulated
+type Swap struct {
+	*engine
+	wallets   map[string]float64
+	margins   map[string]float64
+	positions map[string]*Position
+	leverages map[string]int
+}
+
+// NewSwap new instance, orders are matched against books of source, which can
+// be any swap adapter, or nil when books are fed by SetDepth
+func NewSwap(source DepthSource, config *Config) *Swap {
+	instance := new(Swap)
+	instance.engine = newEngine(source, config)
+	instance.engine.account = instance
+	instance.wallets = map[string]float64{}
+	instance.margins = map[string]float64{}
+	instance.positions = map[string]*Position{}
+	instance.leverages = map[string]int{}
+	for coin, amount := range instance.config.Balances {
+		instance.wallets[strings.ToLower(coin)] = amount
+	}
+	return instance
+}
+
+// SetDepth feed order book of symbol, it replaces the source book of symbol,
+// filled levels are used up until the next SetDepth
+func (swap *Swap) SetDepth(symbol goex.Symbol, depth *goex.Depth) {
+	swap.setDepth(symbol, depth)
+}
+
+// ApplyTrade feed a market trade, resting orders crossed by it are filled
+func (swap *Swap) ApplyTrade(symbol goex.Symbol, price, amount float64) {
+	swap.applyTrade(symbol, price, amount)
+}
+
+// ReplayTrade replace the book of symbol by depth and feed a market trade of
+// amount at price, orders reaching the book take from depth and resting
+// orders fill against the trade only, together they fill at most amount
+func (swap *Swap) ReplayTrade(symbol goex.Symbol, depth *goex.Depth, price, amount float64) {
+	swap.replayTrade(symbol, depth, price, amount)
+}
+
+// Sync match open orders of symbol against the current book
+func (swap *Swap) Sync(symbol goex.Symbol) {
+	swap.sync(symbol)
+}
+
+// SetLeverage leverage of symbol used by new orders
+func (swap *Swap) SetLeverage(symbol goex.Symbol, leverage int) error {
+	if leverage < 1 {
+		return errors.New("leverage must be at least 1")
+	}
+	swap.mutex.Lock()
+	defer swap.mutex.Unlock()
+	swap.leverages[symbolKey(symbol)] = leverage
+	return nil
+}
+
+// GetExchangeName get exchange name
+func (swap *Swap) GetExchangeName() string {
+	return swap.config.Exchange
+}
+
+// GetContractList contract list of source
+func (swap *Swap) GetContractList() interface{} {
+	if source, ok := swap.source.(goex.SwapAPI); ok {
+		return source.GetContractList()
+	}
+	return goex.ReturnAPIError(goex.MethodNotExistError)
+}
+
+// GetDepth depth fed by SetDepth, or of source
+func (swap *Swap) GetDepth(symbol goex.Symbol, size int, options map[string]string) map[string]interface{} {
+	swap.mutex.Lock()
+	depth, ok := swap.depths[symbolKey(symbol)]
+	data := depthData(depth, size)
+	swap.mutex.Unlock()
+	if !ok && swap.source != nil {
+		return swap.source.GetDepth(symbol, size, options)
+	}
+	return goex.ReturnAPIData(data)
+}
+
+// GetTicker ticker of source, or best prices of depth fed by SetDepth
+func (swap *Swap) GetTicker(symbol goex.Symbol) interface{} {
+	if source, ok := swap.source.(goex.SwapAPI); ok {
+		return source.GetTicker(symbol)
+	}
+	fetched := swap.fetchDepth(symbol)
+	swap.mutex.Lock()
+	defer swap.mutex.Unlock()
+	return goex.ReturnAPIData(tickerData(symbol, swap.depth(symbol, fetched)))
+}
+
+// GetKline kline of source
+func (swap *Swap) GetKline(symbol goex.Symbol, period, size int, options map[string]string) interface{} {
+	if source, ok := swap.source.(goex.SwapAPI); ok {
+		return source.GetKline(symbol, period, size, options)
+	}
+	return goex.ReturnAPIError(goex.MethodNotExistError)
+}
+
+// GetTrade trade of source
+func (swap *Swap) GetTrade(symbol goex.Symbol, size int, options map[string]string) interface{} {
+	if source, ok := swap.source.(goex.SwapAPI); ok {
+		return source.GetTrade(symbol, size, options)
+	}
+	return goex.ReturnAPIError(goex.MethodNotExistError)
+}
+
+// GetPremiumIndex premium index of source
+func (swap *Swap) GetPremiumIndex(symbol goex.Symbol) interface{} {
+	if source, ok := swap.source.(goex.SwapAPI); ok {
+		return source.GetPremiumIndex(symbol)
+	}
+	return goex.ReturnAPIError(goex.MethodNotExistError)
+}
+
+// HTTPRequest is not available on simulated exchange
+func (swap *Swap) HTTPRequest(requestURL, method string, options interface{}, signed bool) interface{} {
+	return goex.ReturnAPIError(goex.MethodNotExistError)
+}
+
+// GetUserBalance simulated margin balances
+func (swap *Swap) GetUserBalance() interface{} {
+	swap.mutex.Lock()
+	defer swap.mutex.Unlock()
+	list := []SwapBalance{}
+	for coin, wallet := range swap.wallets {
+		list = append(list, SwapBalance{
+			Asset:          coin,
+			WalletBalance:  wallet,
+			OrderMargin:    swap.margins[coin],
+			PositionMargin: swap.positionMargin(coin),
+			Available:      swap.available(coin),
+		})
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Asset < list[j].Asset })
+	return goex.ReturnAPIData(jsonData(list))
+}
+
+// GetUserPositions open positions, of symbol when symbol is given, data: []goex.Position
+func (swap *Swap) GetUserPositions(symbol goex.Symbol) interface{} {
+	positions := []goex.Position{}
+	for _, position := range swap.GetPositions(symbol) {
+		record := goex.Position{
+			Symbol:        position.Symbol,
+			Side:          goex.POSITION_LONG,
+			Amount:        math.Abs(position.Amount),
+			EntryPrice:    position.EntryPrice,
+			MarkPrice:     position.MarkPrice,
+			UnrealizedPnl: position.UnrealizedPnl,
+			Leverage:      float64(position.Leverage),
+			MarginType:    goex.MARGIN_CROSSED,
+		}
+		if position.Amount < 0 {
+			record.Side = goex.POSITION_SHORT
+		}
+		positions = append(positions, record)
+	}
+	return goex.ReturnAPIData(positions)
+}
+
+// ClosePosition close position of symbol at market when price is empty, or
+// with a limit order at price
+func (swap *Swap) ClosePosition(symbol goex.Symbol, price string) interface{} {
+	return goex.ClosePositions(swap.GetUserPositions(symbol), symbol, price, swap.PlaceOrder)
+}
+
+// GetPositions copy of open positions marked to current mid price, books of
+// source are fetched before the positions are read
+func (swap *Swap) GetPositions(symbol goex.Symbol) []Position {
+	swap.mutex.Lock()
+	keys := make([]string, 0, len(swap.positions))
+	for key, position := range swap.positions {
+		if position.Amount == 0 || (symbol.CoinFrom != "" && key != symbolKey(symbol)) {
+			continue
+		}
+		keys = append(keys, key)
+	}
+	swap.mutex.Unlock()
+	sort.Strings(keys)
+	fetched := make(map[string]*goex.Depth, len(keys))
+	for _, key := range keys {
+		parts := strings.Split(key, "_")
+		fetched[key] = swap.fetchDepth(goex.NewSymbol(parts[0], parts[len(parts)-1]))
+	}
+
+	swap.mutex.Lock()
+	defer swap.mutex.Unlock()
+	positions := make([]Position, 0, len(keys))
+	for _, key := range keys {
+		current, ok := swap.positions[key]
+		if !ok || current.Amount == 0 {
+			continue
+		}
+		position := *current
+		parts := strings.Split(key, "_")
+		if mark := swap.depth(goex.NewSymbol(parts[0], parts[len(parts)-1]), fetched[key]).MidPrice(); mark > 0 {
+			position.MarkPrice = mark
+			position.UnrealizedPnl = (mark - position.EntryPrice) * position.Amount
+		}
+		positions = append(positions, position)
+	}
+	return positions
+}
+
+// PlaceOrder place order
+func (swap *Swap) PlaceOrder(order *goex.PlaceOrder) interface{} {
+	// simulated amounts are in base coin
+	if err := goex.CheckMarketMode(order, goex.MarketOrderByBase); err != nil {
+		return goex.InvalidOrder(err)
+	}
+	tradeType, err := orderType(order)
+	if err != nil {
+		return goex.InvalidOrder(err)
+	}
+	return swap.place(&Order{
+		ClientOrderId: order.ClientOrderId,
+		Type:          tradeType,
+		Price:         goex.ToFloat(order.Price),
+		Amount:        goex.ToFloat(order.Amount),
+		symbol:        order.Symbol,
+		side:          order.Side,
+		timeInForce:   order.TimeInForce,
+	})
+}
+
+// PlaceLimitOrder place limit order
+func (swap *Swap) PlaceLimitOrder(symbol goex.Symbol, price string, amount string, side goex.TradeSide, ClientOrderID string) interface{} {
+	return swap.place(&Order{
+		ClientOrderId: ClientOrderID,
+		Type:          goex.LIMIT,
+		Price:         goex.ToFloat(price),
+		Amount:        goex.ToFloat(amount),
+		symbol:        symbol,
+		side:          side,
+	})
+}
+
+// PlaceMarketOrder place market order
+func (swap *Swap) PlaceMarketOrder(symbol goex.Symbol, amount string, side goex.TradeSide, ClientOrderID string) interface{} {
+	return swap.place(&Order{
+		ClientOrderId: ClientOrderID,
+		Type:          goex.MARKET,
+		Amount:        goex.ToFloat(amount),
+		symbol:        symbol,
+		side:          side,
+	})
+}
+
+// CancelOrder cancel user trust order
+func (swap *Swap) CancelOrder(symbol goex.Symbol, orderID, clientOrderID string) interface{} {
+	order, err := swap.cancel(orderID, clientOrderID)
+	if err != nil {
+		return exchangeError(err)
+	}
+	return goex.ReturnAPIData(jsonData(order))
+}
+
+// GetUserOpenTrustOrders user open trust order list
+func (swap *Swap) GetUserOpenTrustOrders(symbol goex.Symbol, size int, options map[string]string) interface{} {
+	swap.sync(symbol)
+	return goex.ReturnAPIData(jsonData(swap.ordersOf(symbol, "", true, size)))
+}
+
+// GetUserOrderInfo user trust order info
+func (swap *Swap) GetUserOrderInfo(symbol goex.Symbol, orderID, clientOrderID string) interface{} {
+	swap.sync(symbol)
+	swap.mutex.Lock()
+	defer swap.mutex.Unlock()
+	order := swap.find(orderID, clientOrderID)
+	if order == nil {
+		return exchangeError(errors.New("order does not exist"))
+	}
+	return goex.ReturnAPIData(jsonData(order))
+}
+
+// GetUserTradeOrders user fill list, newest first
+func (swap *Swap) GetUserTradeOrders(symbol goex.Symbol, size int, options map[string]string) interface{} {
+	swap.sync(symbol)
+	return goex.ReturnAPIData(jsonData(swap.fillsOf(symbol, size)))
+}
+
+// GetUserTrustOrders user trust order list, newest first
+func (swap *Swap) GetUserTrustOrders(symbol goex.Symbol, status string, size int, options map[string]string) interface{} {
+	swap.sync(symbol)
+	return goex.ReturnAPIData(jsonData(swap.ordersOf(symbol, status, false, size)))
+}
+
+func (swap *Swap) place(order *Order) interface{} {
+	placed, err := swap.submit(order)
+	if err != nil {
+		return exchangeError(err)
+	}
+	swap.mutex.Lock()
+	defer swap.mutex.Unlock()
+	return goex.ReturnAPIData(jsonData(placed))
+}
+
+func (swap *Swap) position(symbol goex.Symbol) *Position {
+	key := symbolKey(symbol)
+	position, ok := swap.positions[key]
+	if !ok {
+		position = &Position{Symbol: key, Leverage: swap.leverage(symbol)}
+		swap.positions[key] = position
+	}
+	return position
+}
+
+func (swap *Swap) leverage(symbol goex.Symbol) int {
+	if leverage, ok := swap.leverages[symbolKey(symbol)]; ok {
+		return leverage
+	}
+	return swap.config.Leverage
+}
+
+// positionMargin margin held by positions settled in coin
+func (swap *Swap) positionMargin(coin string) float64 {
+	margin := 0.0
+	for key, position := range swap.positions {
+		if strings.HasSuffix(key, "_"+coin) && position.Leverage > 0 {
+			margin += math.Abs(position.Amount) * position.EntryPrice / float64(position.Leverage)
+		}
+	}
+	return margin
+}
+
+func (swap *Swap) available(coin string) float64 {
+	return swap.wallets[coin] - swap.margins[coin] - swap.positionMargin(coin)
+}
+
+// increasing amount of order which opens position, the rest closes it
+func (swap *Swap) increasing(order *Order, amount float64) float64 {
+	position := swap.position(order.symbol)
+	if position.Amount == 0 || (position.Amount > 0) == (order.side == goex.BUY) {
+		return amount
+	}
+	return math.Max(0, amount-math.Abs(position.Amount))
+}
+
+// reserve lock initial margin of the position increasing part of a limit order
+func (swap *Swap) reserve(order *Order) error {
+	if order.Type == goex.MARKET {
+		return nil
+	}
+	coin := strings.ToLower(order.symbol.CoinTo)
+	need := order.Price * swap.increasing(order, order.Amount) / float64(swap.leverage(order.symbol))
+	if swap.available(coin) < need-amountEpsilon {
+		return errors.New("insufficient margin")
+	}
+	swap.margins[coin] += need
+	order.locked = need
+	return nil
+}
+
+// affordable market order increasing part is limited by available margin
+func (swap *Swap) affordable(order *Order, price, amount float64) float64 {
+	if order.Type != goex.MARKET || price <= 0 {
+		return amount
+	}
+	closing := amount - swap.increasing(order, amount)
+	coin := strings.ToLower(order.symbol.CoinTo)
+	opening := math.Max(0, swap.available(coin)) * float64(swap.leverage(order.symbol)) / price
+	return closing + math.Min(amount-closing, opening)
+}
+
+// settle update position, realize pnl of closed part and pay fee in settle coin
+func (swap *Swap) settle(order *Order, fill *Fill, rate float64) {
+	coin := strings.ToLower(order.symbol.CoinTo)
+	position := swap.position(order.symbol)
+	leverage := swap.leverage(order.symbol)
+
+	if order.locked > 0 {
+		unlock := math.Min(order.locked, order.Price*swap.increasing(order, fill.Amount)/float64(leverage))
+		swap.margins[coin] -= unlock
+		order.locked -= unlock
+	}
+
+	signed := fill.Amount * float64(order.side)
+	if position.Amount == 0 || (position.Amount > 0) == (signed > 0) {
+		total := math.Abs(position.Amount) + fill.Amount
+		position.EntryPrice = (math.Abs(position.Amount)*position.EntryPrice + fill.Amount*fill.Price) / total
+		position.Amount += signed
+		position.Leverage = leverage
+	} else {
+		closing := math.Min(math.Abs(position.Amount), fill.Amount)
+		pnl := closing * (fill.Price - position.EntryPrice)
+		if position.Amount < 0 {
+			pnl = -pnl
+		}
+		swap.wallets[coin] += pnl
+		position.RealizedPnl += pnl
+		position.Amount += signed
+		if math.Abs(position.Amount) < amountEpsilon {
+			position.Amount = 0
+			position.EntryPrice = 0
+		} else if (position.Amount > 0) == (signed > 0) {
+			position.EntryPrice = fill.Price
+			position.Leverage = leverage
+		}
+	}
+
+	fill.Fee = fill.Price * fill.Amount * rate
+	fill.FeeCoin = coin
+	swap.wallets[coin] -= fill.Fee
+}
+
+// release unlock margin left of closed order
+func (swap *Swap) release(order *Order) {
+	if order.locked > 0 {
+		swap.margins[strings.ToLower(order.symbol.CoinTo)] -= order.locked
+	}
+	order.locked = 0
+}
@@ -0,0 +1,72 @@
+package paper
+
+import (
+	"testing"
+
+	goex "github.com/primitivelab/goexchange"
+)
+
+func TestSwap_Positions(t *testing.T) {
+	swap := NewSwap(nil, &Config{Balances: map[string]float64{"usdt": 1000}, Leverage: 10})
+	swap.SetDepth(btcUsdt, &goex.Depth{
+		Bids: []goex.DepthRecord{{Price: 99, Amount: 10}},
+		Asks: []goex.DepthRecord{{Price: 101, Amount: 10}},
+	})
+
+	if _, err := goex.ParseResponse(swap.PlaceMarketOrder(btcUsdt, "5", goex.BUY, "")); err != nil {
+		t.Fatal(err)
+	}
+	positions := swap.GetPositions(btcUsdt)
+	if len(positions) != 1 || positions[0].Amount != 5 || positions[0].EntryPrice != 101 {
+		t.Fatalf("unexpected positions: %v", positions)
+	}
+
+	swap.SetDepth(btcUsdt, &goex.Depth{
+		Bids: []goex.DepthRecord{{Price: 111, Amount: 10}},
+		Asks: []goex.DepthRecord{{Price: 112, Amount: 10}},
+	})
+	if _, err := goex.ParseResponse(swap.PlaceMarketOrder(btcUsdt, "5", goex.SELL, "")); err != nil {
+		t.Fatal(err)
+	}
+	if len(swap.GetPositions(btcUsdt)) != 0 {
+		t.Fatal("position must be closed")
+	}
+	if swap.wallets["usdt"] != 1050 {
+		t.Fatalf("unexpected wallet balance: %v", swap.wallets["usdt"])
+	}
+}
+
+func TestSwap_InsufficientMargin(t *testing.T) {
+	swap := NewSwap(nil, &Config{Balances: map[string]float64{"usdt": 100}, Leverage: 2})
+	response := swap.PlaceLimitOrder(btcUsdt, "100", "3", goex.BUY, "")
+	if _, err := goex.ParseResponse(response); err == nil {
+		t.Fatal("order over available margin must fail")
+	}
+}
+
+func TestSwap_ClosePosition(t *testing.T) {
+	swap := NewSwap(nil, &Config{Balances: map[string]float64{"usdt": 1000}, Leverage: 10})
+	swap.SetDepth(btcUsdt, &goex.Depth{
+		Bids: []goex.DepthRecord{{Price: 99, Amount: 10}},
+		Asks: []goex.DepthRecord{{Price: 101, Amount: 10}},
+	})
+	if _, err := goex.ParseResponse(swap.PlaceMarketOrder(btcUsdt, "3", goex.SELL, "")); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := goex.ParseResponse(swap.GetUserPositions(btcUsdt))
+	if err != nil {
+		t.Fatal(err)
+	}
+	positions := data.([]goex.Position)
+	if len(positions) != 1 || positions[0].Side != goex.POSITION_SHORT || positions[0].Amount != 3 || positions[0].Leverage != 10 {
+		t.Fatalf("unexpected positions: %+v", positions)
+	}
+
+	if _, err := goex.ParseResponse(swap.ClosePosition(btcUsdt, "")); err != nil {
+		t.Fatal(err)
+	}
+	if len(swap.GetPositions(btcUsdt)) != 0 {
+		t.Fatal("position must be closed")
+	}
+}
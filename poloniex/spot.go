@@ -142,6 +142,10 @@ func (spot *PoloniexSpot) GetUserBalance() interface{} {
 
 // PlaceOrder place order
 func (spot *PoloniexSpot) PlaceOrder(order *PlaceOrder) interface{} {
+	// poloniex has no market orders, market modes can not be honored
+	if err := CheckMarketMode(order); err != nil {
+		return InvalidOrder(err)
+	}
 	params := &url.Values{}
 	params.Set("currencyPair", spot.getSymbol(order.Symbol))
 	params.Set("rate", order.Price)
@@ -156,7 +160,7 @@ func (spot *PoloniexSpot) PlaceOrder(order *PlaceOrder) interface{} {
 		params.Set("immediateOrCancel", "1")
 	case FOK:
 		params.Set("fillOrKill", "1")
-	case POC:
+	case POC, GTX:
 		params.Set("postOnly", "1")
 	}
 	if order.ClientOrderId != "" {
@@ -165,3 +165,27 @@ func TestPoloniexSpot_BatchCancelOrder(t *testing.T) {
 	b, _ := json.Marshal(response)
 	t.Log(string(b))
 }
+
+func TestPoloniexSpot_GetDepositAddresses(t *testing.T) {
+	market := getInstance()
+
+	response := market.GetDepositAddresses("usdt", "")
+	b, _ := json.Marshal(response)
+	t.Log(string(b))
+}
+
+func TestPoloniexSpot_GetDepositHistory(t *testing.T) {
+	market := getInstance()
+
+	response := market.GetDepositHistory("usdt", 10, nil)
+	b, _ := json.Marshal(response)
+	t.Log(string(b))
+}
+
+func TestPoloniexSpot_GetWithdrawHistory(t *testing.T) {
+	market := getInstance()
+
+	response := market.GetWithdrawHistory("usdt", 10, nil)
+	b, _ := json.Marshal(response)
+	t.Log(string(b))
+}
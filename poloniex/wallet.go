@@ -0,0 +1,129 @@
+package poloniex
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	. "github.com/primitivelab/goexchange"
+)
+
+// GetDepositAddresses deposit address of coin, chain is the poloniex currency
+// code of the chain, eg: USDTTRON, empty for coin itself
+func (spot *PoloniexSpot) GetDepositAddresses(coin, chain string) interface{} {
+	params := &url.Values{}
+	params.Set("command", "returnDepositAddresses")
+	result := spot.httpPost("/tradingApi", params)
+	if result["code"] != 0 {
+		return result
+	}
+
+	currency := strings.ToUpper(coin)
+	if chain != "" {
+		currency = strings.ToUpper(chain)
+	}
+	data, _ := result["data"].(map[string]interface{})
+	addresses := []DepositAddress{}
+	if address, ok := data[currency].(string); ok {
+		addresses = append(addresses, DepositAddress{Coin: coin, Chain: chain, Address: address})
+	}
+	result["data"] = addresses
+	return result
+}
+
+// ApplyWithdraw user withdraw, poloniex charges a fixed fee so request fee is not used
+func (spot *PoloniexSpot) ApplyWithdraw(request *WithdrawRequest) interface{} {
+	params := &url.Values{}
+	// typed fields are set last so that options can not override them
+	for key, value := range request.Options {
+		params.Set(key, value)
+	}
+	params.Set("command", "withdraw")
+	params.Set("currency", strings.ToUpper(request.Coin))
+	params.Set("amount", request.Amount)
+	params.Set("address", request.Address)
+	if request.Tag != "" {
+		params.Set("paymentId", request.Tag)
+	}
+	if request.Chain != "" {
+		params.Set("currencyToWithdrawAs", strings.ToUpper(request.Chain))
+	}
+	result := spot.httpPost("/tradingApi", params)
+	if result["code"] != 0 {
+		return result
+	}
+	data, _ := result["data"].(map[string]interface{})
+	result["data"] = ToString(data["withdrawalNumber"])
+	return result
+}
+
+// GetDepositHistory user deposit record list of options start and end seconds, default last 30 days
+func (spot *PoloniexSpot) GetDepositHistory(coin string, size int, options map[string]string) interface{} {
+	return spot.transferHistory("deposits", coin, size, options, TRANSFER_DEPOSIT)
+}
+
+// GetWithdrawHistory user withdraw record list of options start and end seconds, default last 30 days
+func (spot *PoloniexSpot) GetWithdrawHistory(coin string, size int, options map[string]string) interface{} {
+	return spot.transferHistory("withdrawals", coin, size, options, TRANSFER_WITHDRAW)
+}
+
+func (spot *PoloniexSpot) transferHistory(key, coin string, size int, options map[string]string, transferType string) interface{} {
+	now := time.Now().Unix()
+	params := &url.Values{}
+	params.Set("command", "returnDepositsWithdrawals")
+	params.Set("start", strconv.FormatInt(now-30*24*3600, 10))
+	params.Set("end", strconv.FormatInt(now, 10))
+	if start, ok := options["start"]; ok {
+		params.Set("start", start)
+	}
+	if end, ok := options["end"]; ok {
+		params.Set("end", end)
+	}
+
+	result := spot.httpPost("/tradingApi", params)
+	if result["code"] != 0 {
+		return result
+	}
+
+	data, _ := result["data"].(map[string]interface{})
+	list, _ := data[key].([]interface{})
+	transfers := make([]Transfer, 0, len(list))
+	for _, item := range list {
+		record, ok := item.(map[string]interface{})
+		if !ok || (coin != "" && !strings.EqualFold(ToString(record["currency"]), coin)) {
+			continue
+		}
+		transfer := Transfer{
+			Id:      ToString(record["depositNumber"]),
+			TxId:    ToString(record["txid"]),
+			Type:    transferType,
+			Coin:    ToString(record["currency"]),
+			Address: ToString(record["address"]),
+			Tag:     ToString(record["paymentID"]),
+			Amount:  ToFloat(record["amount"]),
+			Fee:     ToFloat(record["fee"]),
+			Status:  TRANSFER_STATUS_PENDING,
+			Time:    ParseTimestamp(record["timestamp"]),
+		}
+		if transferType == TRANSFER_WITHDRAW {
+			transfer.Id = ToString(record["withdrawalNumber"])
+		}
+		// withdraw status is like "COMPLETE: txid"
+		status := strings.ToUpper(ToString(record["status"]))
+		if strings.HasPrefix(status, "COMPLETE") {
+			transfer.Status = TRANSFER_STATUS_SUCCESS
+			if parts := strings.SplitN(status, ":", 2); len(parts) == 2 && transfer.TxId == "" {
+				transfer.TxId = strings.TrimSpace(ToString(record["status"])[len(parts[0])+1:])
+			}
+		} else if strings.HasPrefix(status, "CANCEL") {
+			transfer.Status = TRANSFER_STATUS_CANCELED
+		}
+		transfers = append(transfers, transfer)
+		if size > 0 && len(transfers) >= size {
+			break
+		}
+	}
+	result["data"] = transfers
+	return result
+}
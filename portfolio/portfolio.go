@@ -0,0 +1,353 @@
+package portfolio
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	goex "github.com/primitivelab/goexchange"
+)
+
+// BalanceAPI balance method of spot, margin, swap and futures adapters
+type BalanceAPI interface {
+	GetExchangeName() string
+	GetUserBalance() interface{}
+}
+
+// TickerAPI ticker method used to price coins
+type TickerAPI interface {
+	GetTicker(symbol goex.Symbol) interface{}
+}
+
+// Account one balance source of the portfolio
+type Account struct {
+	// name in snapshots, default exchange name, accounts of one exchange like
+	// spot and swap need distinct names
+	Name string
+	API  BalanceAPI
+}
+
+// name account name
+func (account *Account) name() string {
+	if account.Name != "" {
+		return account.Name
+	}
+	return account.API.GetExchangeName()
+}
+
+// Config portfolio config
+type Config struct {
+	// lower case coin balances are valued in, default usdt
+	Quote string
+	// tickers pricing coins, tried in order, accounts implementing TickerAPI
+	// are tried after them
+	Pricers []TickerAPI
+	// coins priced through a bridge coin when they have no market in quote,
+	// default usdt and btc
+	Bridges []string
+	// fixed prices in quote by lower case coin, like stable coins
+	Prices map[string]float64
+	// snapshot interval of Run, default 1 minute
+	Interval time.Duration
+	// snapshots kept by Snapshots, default 1440
+	History int
+	// snapshot log, one json Snapshot per line
+	Journal io.Writer
+	// current time, default time.Now
+	Clock func() time.Time
+}
+
+// Balance balance of one coin in one account, Price and Value are in quote
+// coin and 0 when the coin can not be priced
+type Balance struct {
+	Account string  `json:"account"`
+	Coin    string  `json:"coin"`
+	Free    float64 `json:"free"`
+	Locked  float64 `json:"locked"`
+	Price   float64 `json:"price"`
+	Value   float64 `json:"value"`
+}
+
+// Asset balance of one coin summed over accounts
+type Asset struct {
+	Coin   string  `json:"coin"`
+	Free   float64 `json:"free"`
+	Locked float64 `json:"locked"`
+	Price  float64 `json:"price"`
+	Value  float64 `json:"value"`
+}
+
+// Total free and locked amount
+func (asset *Asset) Total() float64 {
+	return asset.Free + asset.Locked
+}
+
+// Snapshot balances of all accounts at Time, Assets are sorted by value
+// descending and Total is their value in Quote, Unpriced lists coins left out
+// of Total and Errors the accounts whose balance failed
+type Snapshot struct {
+	Time     int64             `json:"time"`
+	Quote    string            `json:"quote"`
+	Total    float64           `json:"total"`
+	Balances []Balance         `json:"balances"`
+	Assets   []Asset           `json:"assets"`
+	Unpriced []string          `json:"unpriced"`
+	Errors   map[string]string `json:"errors"`
+}
+
+// Asset asset of coin, false when no account holds it
+func (snapshot *Snapshot) Asset(coin string) (Asset, bool) {
+	for _, asset := range snapshot.Assets {
+		if asset.Coin == strings.ToLower(coin) {
+			return asset, true
+		}
+	}
+	return Asset{}, false
+}
+
+// AccountTotal value of account in Quote
+func (snapshot *Snapshot) AccountTotal(account string) float64 {
+	total := 0.0
+	for _, balance := range snapshot.Balances {
+		if balance.Account == account {
+			total += balance.Value
+		}
+	}
+	return total
+}
+
+// Portfolio balances of several accounts valued in one quote coin
+type Portfolio struct {
+	accounts  []*Account
+	config    Config
+	mutex     sync.Mutex
+	snapshots []*Snapshot
+	handlers  []func(snapshot *Snapshot)
+}
+
+// New new instance
+func New(config *Config, accounts ...*Account) *Portfolio {
+	portfolio := &Portfolio{accounts: accounts}
+	if config != nil {
+		portfolio.config = *config
+	}
+	portfolio.config.Quote = strings.ToLower(portfolio.config.Quote)
+	if portfolio.config.Quote == "" {
+		portfolio.config.Quote = "usdt"
+	}
+	if portfolio.config.Bridges == nil {
+		portfolio.config.Bridges = []string{"usdt", "btc"}
+	}
+	if portfolio.config.Interval == 0 {
+		portfolio.config.Interval = time.Minute
+	}
+	if portfolio.config.History == 0 {
+		portfolio.config.History = 1440
+	}
+	if portfolio.config.Clock == nil {
+		portfolio.config.Clock = time.Now
+	}
+	return portfolio
+}
+
+// Accounts accounts of portfolio
+func (portfolio *Portfolio) Accounts() []*Account {
+	return portfolio.accounts
+}
+
+// OnSnapshot add handler called with every snapshot taken
+func (portfolio *Portfolio) OnSnapshot(handler func(snapshot *Snapshot)) {
+	portfolio.mutex.Lock()
+	defer portfolio.mutex.Unlock()
+	portfolio.handlers = append(portfolio.handlers, handler)
+}
+
+// Snapshot fetch balances of all accounts concurrently and value them, failed
+// accounts are left out and listed in Snapshot.Errors, error only when every
+// account failed
+func (portfolio *Portfolio) Snapshot() (*Snapshot, error) {
+	lists := make([][]goex.AssetBalance, len(portfolio.accounts))
+	errs := make([]error, len(portfolio.accounts))
+	var wait sync.WaitGroup
+	for i, account := range portfolio.accounts {
+		wait.Add(1)
+		go func(i int, account *Account) {
+			defer wait.Done()
+			lists[i], errs[i] = goex.ParseBalance(account.API.GetUserBalance())
+		}(i, account)
+	}
+	wait.Wait()
+
+	snapshot := &Snapshot{
+		Time:     portfolio.config.Clock().UnixNano() / int64(time.Millisecond),
+		Quote:    portfolio.config.Quote,
+		Balances: []Balance{},
+		Assets:   []Asset{},
+		Unpriced: []string{},
+		Errors:   map[string]string{},
+	}
+	assets := map[string]*Asset{}
+	prices := map[string]float64{}
+	for i, account := range portfolio.accounts {
+		if errs[i] != nil {
+			snapshot.Errors[account.name()] = errs[i].Error()
+			continue
+		}
+		for _, item := range lists[i] {
+			price, ok := prices[item.Coin]
+			if !ok {
+				price = portfolio.price(item.Coin, prices)
+				prices[item.Coin] = price
+			}
+			balance := Balance{
+				Account: account.name(),
+				Coin:    item.Coin,
+				Free:    item.Free,
+				Locked:  item.Frozen,
+				Price:   price,
+				Value:   item.Total() * price,
+			}
+			snapshot.Balances = append(snapshot.Balances, balance)
+			asset, ok := assets[item.Coin]
+			if !ok {
+				asset = &Asset{Coin: item.Coin, Price: price}
+				assets[item.Coin] = asset
+			}
+			asset.Free += balance.Free
+			asset.Locked += balance.Locked
+			asset.Value += balance.Value
+		}
+	}
+	if len(portfolio.accounts) > 0 && len(snapshot.Errors) == len(portfolio.accounts) {
+		return nil, errors.New("balance of all accounts failed")
+	}
+	for _, asset := range assets {
+		snapshot.Assets = append(snapshot.Assets, *asset)
+		snapshot.Total += asset.Value
+		if asset.Price == 0 {
+			snapshot.Unpriced = append(snapshot.Unpriced, asset.Coin)
+		}
+	}
+	sort.Slice(snapshot.Assets, func(i, j int) bool {
+		if snapshot.Assets[i].Value != snapshot.Assets[j].Value {
+			return snapshot.Assets[i].Value > snapshot.Assets[j].Value
+		}
+		return snapshot.Assets[i].Coin < snapshot.Assets[j].Coin
+	})
+	sort.Strings(snapshot.Unpriced)
+	return snapshot, portfolio.record(snapshot)
+}
+
+// Snapshots snapshots taken, oldest first
+func (portfolio *Portfolio) Snapshots() []*Snapshot {
+	portfolio.mutex.Lock()
+	defer portfolio.mutex.Unlock()
+	return append([]*Snapshot{}, portfolio.snapshots...)
+}
+
+// Latest last snapshot taken, nil before the first one
+func (portfolio *Portfolio) Latest() *Snapshot {
+	portfolio.mutex.Lock()
+	defer portfolio.mutex.Unlock()
+	if len(portfolio.snapshots) == 0 {
+		return nil
+	}
+	return portfolio.snapshots[len(portfolio.snapshots)-1]
+}
+
+// Run take a snapshot every interval until stop is closed
+func (portfolio *Portfolio) Run(stop <-chan struct{}) {
+	ticker := time.NewTicker(portfolio.config.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			portfolio.Snapshot()
+		}
+	}
+}
+
+// record keep snapshot, write it to journal and notify handlers
+func (portfolio *Portfolio) record(snapshot *Snapshot) error {
+	portfolio.mutex.Lock()
+	portfolio.snapshots = append(portfolio.snapshots, snapshot)
+	if len(portfolio.snapshots) > portfolio.config.History {
+		portfolio.snapshots = portfolio.snapshots[len(portfolio.snapshots)-portfolio.config.History:]
+	}
+	var err error
+	if portfolio.config.Journal != nil {
+		var data []byte
+		if data, err = json.Marshal(snapshot); err == nil {
+			_, err = portfolio.config.Journal.Write(append(data, '\n'))
+		}
+	}
+	handlers := portfolio.handlers
+	portfolio.mutex.Unlock()
+	for _, handler := range handlers {
+		handler(snapshot)
+	}
+	return err
+}
+
+// price price of coin in quote, directly, inverted or through a bridge coin,
+// 0 when it can not be priced, prices caches prices of this snapshot
+func (portfolio *Portfolio) price(coin string, prices map[string]float64) float64 {
+	quote := portfolio.config.Quote
+	if coin == quote {
+		return 1
+	}
+	if price, ok := portfolio.config.Prices[coin]; ok {
+		return price
+	}
+	if price := portfolio.pairPrice(coin, quote); price > 0 {
+		return price
+	}
+	for _, bridge := range portfolio.config.Bridges {
+		if bridge == coin || bridge == quote {
+			continue
+		}
+		bridgePrice, ok := prices[bridge]
+		if !ok {
+			bridgePrice = portfolio.pairPrice(bridge, quote)
+			prices[bridge] = bridgePrice
+		}
+		if bridgePrice <= 0 {
+			continue
+		}
+		if price := portfolio.pairPrice(coin, bridge); price > 0 {
+			return price * bridgePrice
+		}
+	}
+	return 0
+}
+
+// pairPrice last price of coin in quote from the first pricer listing the
+// pair or its inverse, 0 when none does
+func (portfolio *Portfolio) pairPrice(coin, quote string) float64 {
+	for _, pricer := range portfolio.pricers() {
+		if ticker, err := goex.ParseTicker(pricer.GetTicker(goex.NewSymbol(coin, quote))); err == nil && ticker.Last > 0 {
+			return ticker.Last
+		}
+		if ticker, err := goex.ParseTicker(pricer.GetTicker(goex.NewSymbol(quote, coin))); err == nil && ticker.Last > 0 {
+			return 1 / ticker.Last
+		}
+	}
+	return 0
+}
+
+// pricers of config followed by accounts implementing TickerAPI
+func (portfolio *Portfolio) pricers() []TickerAPI {
+	pricers := append([]TickerAPI{}, portfolio.config.Pricers...)
+	for _, account := range portfolio.accounts {
+		if pricer, ok := account.API.(TickerAPI); ok {
+			pricers = append(pricers, pricer)
+		}
+	}
+	return pricers
+}
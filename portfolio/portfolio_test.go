@@ -0,0 +1,99 @@
+package portfolio
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"testing"
+
+	goex "github.com/primitivelab/goexchange"
+	"github.com/primitivelab/goexchange/paper"
+)
+
+type failedAPI struct{}
+
+func (api *failedAPI) GetExchangeName() string {
+	return goex.EXCHANGE_GATE
+}
+
+func (api *failedAPI) GetUserBalance() interface{} {
+	return goex.ReturnAPIError(goex.HttpClientInternalError)
+}
+
+func getSpot(exchange string, balances map[string]float64) *paper.Spot {
+	return paper.NewSpot(nil, &paper.Config{Exchange: exchange, Balances: balances})
+}
+
+func setPrice(spot *paper.Spot, symbol goex.Symbol, price float64) {
+	spot.SetDepth(symbol, &goex.Depth{
+		Bids: []goex.DepthRecord{{Price: price * 0.99, Amount: 10}},
+		Asks: []goex.DepthRecord{{Price: price * 1.01, Amount: 10}},
+	})
+}
+
+func TestPortfolio_Snapshot(t *testing.T) {
+	var _ BalanceAPI = &paper.Swap{}
+	binance := getSpot(goex.EXCHANGE_BINANCE, map[string]float64{"usdt": 1000, "btc": 1, "eth": 10, "xyz": 5})
+	setPrice(binance, goex.NewSymbol("btc", "usdt"), 100)
+	setPrice(binance, goex.NewSymbol("eth", "btc"), 0.05)
+	huobi := getSpot(goex.EXCHANGE_HUOBI, map[string]float64{"usdt": 500, "btc": 0.5, "usdc": 100})
+	journal := &bytes.Buffer{}
+	portfolio := New(&Config{Prices: map[string]float64{"usdc": 1}, Journal: journal, History: 1},
+		&Account{API: binance}, &Account{API: huobi}, &Account{API: &failedAPI{}})
+
+	snapshot, err := portfolio.Snapshot()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(snapshot.Total-1800) > 1e-9 || snapshot.Quote != "usdt" {
+		t.Fatalf("unexpected total %v", snapshot.Total)
+	}
+	if len(snapshot.Unpriced) != 1 || snapshot.Unpriced[0] != "xyz" || snapshot.Errors[goex.EXCHANGE_GATE] == "" {
+		t.Fatalf("unexpected unpriced coins %v or errors %v", snapshot.Unpriced, snapshot.Errors)
+	}
+	btc, _ := snapshot.Asset("BTC")
+	if btc.Total() != 1.5 || btc.Price != 100 || btc.Value != 150 {
+		t.Fatalf("unexpected btc asset %+v", btc)
+	}
+	if eth, _ := snapshot.Asset("eth"); math.Abs(eth.Value-50) > 1e-9 {
+		t.Fatalf("unexpected bridged eth asset %+v", eth)
+	}
+	if snapshot.Assets[0].Coin != "usdt" || snapshot.Assets[0].Value != 1500 {
+		t.Fatalf("assets are not sorted by value %+v", snapshot.Assets)
+	}
+	if total := snapshot.AccountTotal(goex.EXCHANGE_HUOBI); total != 650 {
+		t.Fatalf("unexpected huobi total %v", total)
+	}
+
+	binance.PlaceOrder(&goex.PlaceOrder{Symbol: goex.NewSymbol("btc", "usdt"), Side: goex.BUY, TradeType: goex.LIMIT, Price: "90", Amount: "2"})
+	snapshot, _ = portfolio.Snapshot()
+	if usdt, _ := snapshot.Asset("usdt"); usdt.Locked != 180 || usdt.Free != 1320 {
+		t.Fatalf("unexpected usdt asset after order %+v", usdt)
+	}
+	if snapshots := portfolio.Snapshots(); len(snapshots) != 1 || snapshots[0] != portfolio.Latest() {
+		t.Fatalf("unexpected history %v", snapshots)
+	}
+	var logged Snapshot
+	lines := bytes.Split(bytes.TrimSpace(journal.Bytes()), []byte("\n"))
+	if len(lines) != 2 || json.Unmarshal(lines[1], &logged) != nil || logged.Time != snapshot.Time || len(logged.Assets) != 5 {
+		t.Fatalf("unexpected journal %s", journal.String())
+	}
+}
+
+func TestPortfolio_Quote(t *testing.T) {
+	binance := getSpot(goex.EXCHANGE_BINANCE, map[string]float64{"usdt": 1000, "btc": 1})
+	setPrice(binance, goex.NewSymbol("btc", "usdt"), 100)
+	swap := paper.NewSwap(nil, &paper.Config{Exchange: goex.EXCHANGE_OKEX, Balances: map[string]float64{"usdt": 200}})
+	portfolio := New(&Config{Quote: "BTC", Pricers: []TickerAPI{binance}},
+		&Account{API: binance}, &Account{Name: "okex-swap", API: swap})
+	snapshot, err := portfolio.Snapshot()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(snapshot.Total-13) > 1e-9 || math.Abs(snapshot.AccountTotal("okex-swap")-2) > 1e-9 {
+		t.Fatalf("unexpected btc valued snapshot %+v", snapshot)
+	}
+	if _, err := New(nil, &Account{API: &failedAPI{}}).Snapshot(); err == nil {
+		t.Fatal("expected error when every account failed")
+	}
+}
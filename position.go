@@ -0,0 +1,81 @@
+package goexchange
+
+import "strings"
+
+// 持仓方向
+const (
+	POSITION_LONG  = "long"
+	POSITION_SHORT = "short"
+)
+
+// 保证金模式
+const (
+	MARGIN_CROSSED  = "crossed"
+	MARGIN_ISOLATED = "isolated"
+)
+
+// Position typed derivatives position of any adapter, Amount is positive and
+// counted as the exchange does, contracts on huobi and okex, coins on binance
+type Position struct {
+	Symbol string
+	// POSITION_LONG or POSITION_SHORT
+	Side          string
+	Amount        float64
+	EntryPrice    float64
+	MarkPrice     float64
+	UnrealizedPnl float64
+	Leverage      float64
+	// MARGIN_CROSSED or MARGIN_ISOLATED
+	MarginType       string
+	LiquidationPrice float64
+}
+
+// ParseMarginType normalize exchange margin mode into MARGIN_*, empty when unknown
+func ParseMarginType(value interface{}) string {
+	switch strings.ToLower(ToString(value)) {
+	case "cross", "crossed":
+		return MARGIN_CROSSED
+	case "isolated", "fixed":
+		return MARGIN_ISOLATED
+	}
+	return ""
+}
+
+// ClosePositionOrder order closing position of symbol, a market order when
+// price is empty and a limit order at price otherwise
+func ClosePositionOrder(symbol Symbol, position Position, price string) *PlaceOrder {
+	order := &PlaceOrder{
+		Symbol:    symbol,
+		Amount:    FloatToString(position.Amount),
+		Side:      SELL,
+		TradeType: MARKET,
+		Offset:    OFFSET_CLOSE,
+	}
+	if position.Side == POSITION_SHORT {
+		order.Side = BUY
+	}
+	if price != "" {
+		order.Price = price
+		order.TradeType = LIMIT
+	}
+	return order
+}
+
+// ClosePositions place ClosePositionOrder of every position in a
+// GetUserPositions response, data: []interface{} of order responses data
+func ClosePositions(positions interface{}, symbol Symbol, price string, place func(order *PlaceOrder) interface{}) interface{} {
+	data, err := ParseResponse(positions)
+	if err != nil {
+		return positions
+	}
+	list, _ := data.([]Position)
+	orders := []interface{}{}
+	for _, position := range list {
+		result, _ := place(ClosePositionOrder(symbol, position, price)).(map[string]interface{})
+		if result["code"] != 0 {
+			return result
+		}
+		orders = append(orders, result["data"])
+	}
+	return ReturnAPIData(orders)
+}
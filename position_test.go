@@ -0,0 +1,59 @@
+package goexchange
+
+import "testing"
+
+func TestParseMarginType(t *testing.T) {
+	tests := map[string]string{
+		"cross":    MARGIN_CROSSED,
+		"crossed":  MARGIN_CROSSED,
+		"ISOLATED": MARGIN_ISOLATED,
+		"fixed":    MARGIN_ISOLATED,
+		"":         "",
+	}
+	for value, expected := range tests {
+		if marginType := ParseMarginType(value); marginType != expected {
+			t.Errorf("%s: expected %s, got %s", value, expected, marginType)
+		}
+	}
+}
+
+func TestClosePositionOrder(t *testing.T) {
+	symbol := NewSymbol("btc", "usdt")
+	order := ClosePositionOrder(symbol, Position{Side: POSITION_LONG, Amount: 1.5}, "")
+	if order.Side != SELL || order.TradeType != MARKET || order.Amount != "1.5" || order.Offset != OFFSET_CLOSE {
+		t.Fatalf("unexpected order %+v", order)
+	}
+	order = ClosePositionOrder(symbol, Position{Side: POSITION_SHORT, Amount: 2}, "30000")
+	if order.Side != BUY || order.TradeType != LIMIT || order.Price != "30000" || order.Amount != "2" {
+		t.Fatalf("unexpected order %+v", order)
+	}
+}
+
+func TestClosePositions(t *testing.T) {
+	positions := ReturnAPIData([]Position{
+		{Side: POSITION_LONG, Amount: 1},
+		{Side: POSITION_SHORT, Amount: 2},
+	})
+	var orders []*PlaceOrder
+	place := func(order *PlaceOrder) interface{} {
+		orders = append(orders, order)
+		return ReturnAPIData(len(orders))
+	}
+	data, err := ParseResponse(ClosePositions(positions, NewSymbol("btc", "usdt"), "", place))
+	if err != nil || len(data.([]interface{})) != 2 || len(orders) != 2 {
+		t.Fatalf("unexpected result %v %v", data, err)
+	}
+	if orders[0].Side != SELL || orders[1].Side != BUY {
+		t.Fatalf("unexpected orders %+v %+v", orders[0], orders[1])
+	}
+
+	failed := func(order *PlaceOrder) interface{} {
+		return ReturnAPIError(ExchangeError)
+	}
+	if _, err := ParseResponse(ClosePositions(positions, NewSymbol("btc", "usdt"), "", failed)); err == nil {
+		t.Fatal("order error should be returned")
+	}
+	if _, err := ParseResponse(ClosePositions(ReturnAPIError(ExchangeError), NewSymbol("btc", "usdt"), "", place)); err == nil {
+		t.Fatal("positions error should be returned")
+	}
+}
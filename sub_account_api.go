@@ -0,0 +1,72 @@
+package goexchange
+
+// SubAccount sub-account of master account, Id is what the exchange uses
+// to address it: binance email, huobi uid or okex sub-account name
+type SubAccount struct {
+	Id      string
+	Name    string
+	Enabled bool
+	Time    int64
+}
+
+// AssetBalance balance of one coin
+type AssetBalance struct {
+	Coin   string
+	Free   float64
+	Frozen float64
+}
+
+// SubTransferRequest move asset between master and sub-accounts, empty From
+// or To is the master account
+type SubTransferRequest struct {
+	Coin    string
+	Amount  string
+	From    string
+	To      string
+	Options map[string]string
+}
+
+// SubAPIKeyRequest create api key of sub-account, Passphrase is needed by okex
+type SubAPIKeyRequest struct {
+	SubAccount string
+	Label      string
+	Passphrase string
+	Trade      bool
+	Withdraw   bool
+	IPs        []string
+	Options    map[string]string
+}
+
+// SubAPIKey api key of sub-account, SecretKey is only returned on creation
+type SubAPIKey struct {
+	SubAccount  string
+	Label       string
+	ApiKey      string
+	SecretKey   string
+	Passphrase  string
+	Permissions []string
+	IPs         []string
+	Time        int64
+}
+
+// SubAccountAPI sub-account api interface, called with master account key
+type SubAccountAPI interface {
+
+	// 子账户列表, data: []SubAccount
+	GetSubAccounts() interface{}
+
+	// 子账户资产, data: []AssetBalance
+	GetSubAccountBalances(subAccount string) interface{}
+
+	// 母子账户划转, data: transfer id
+	SubAccountTransfer(request *SubTransferRequest) interface{}
+
+	// 创建子账户 api key, data: SubAPIKey
+	CreateSubAccountAPIKey(request *SubAPIKeyRequest) interface{}
+
+	// 子账户 api key 列表, data: []SubAPIKey
+	GetSubAccountAPIKeys(subAccount string) interface{}
+
+	// 删除子账户 api key, data: api key
+	DeleteSubAccountAPIKey(subAccount, apiKey string) interface{}
+}
@@ -0,0 +1,92 @@
+package goexchange
+
+import (
+	"errors"
+)
+
+// Ticker typed ticker, amounts are 0 when exchange ticker has no best sizes
+type Ticker struct {
+	Last      float64
+	Bid       float64
+	BidAmount float64
+	Ask       float64
+	AskAmount float64
+	High      float64
+	Low       float64
+	Volume    float64
+	Time      int64
+}
+
+// ParseTicker parse GetTicker response of any adapter into typed ticker
+func ParseTicker(result interface{}) (*Ticker, error) {
+	data := result
+	if retData, ok := result.(map[string]interface{}); ok {
+		if _, isResponse := retData["code"]; isResponse {
+			var err error
+			data, err = ParseResponse(result)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	var object map[string]interface{}
+	for level := 0; level < 3; level++ {
+		if list, ok := data.([]interface{}); ok && len(list) > 0 {
+			data = list[0]
+		}
+		var ok bool
+		if object, ok = data.(map[string]interface{}); !ok {
+			return nil, errors.New("ticker data is not an object")
+		}
+		nested, ok := firstValue(object, "tick", "ticker", "data", "result")
+		if !ok {
+			break
+		}
+		data = nested
+	}
+	if object == nil {
+		return nil, errors.New("ticker data is not an object")
+	}
+
+	ticker := &Ticker{}
+	ticker.Bid, ticker.BidAmount = tickerLevel(object, []string{"bidPrice", "best_bid", "highest_bid", "buy", "bid"}, []string{"bidQty", "best_bid_size", "bidSize"})
+	ticker.Ask, ticker.AskAmount = tickerLevel(object, []string{"askPrice", "best_ask", "lowest_ask", "sell", "ask"}, []string{"askQty", "best_ask_size", "askSize"})
+	if value, ok := firstValue(object, "lastPrice", "last", "close", "c"); ok {
+		ticker.Last = ToFloat(value)
+	}
+	if value, ok := firstValue(object, "highPrice", "high_24h", "high", "h"); ok {
+		ticker.High = ToFloat(value)
+	}
+	if value, ok := firstValue(object, "lowPrice", "low_24h", "low", "l"); ok {
+		ticker.Low = ToFloat(value)
+	}
+	if value, ok := firstValue(object, "volume", "base_volume", "amount", "base_volume_24h", "vol"); ok {
+		ticker.Volume = ToFloat(value)
+	}
+	if value, ok := firstValue(object, "closeTime", "timestamp", "ts", "time", "date"); ok {
+		ticker.Time = ParseTimestamp(value)
+	}
+	if ticker.Last == 0 && ticker.Bid > 0 && ticker.Ask > 0 {
+		ticker.Last = (ticker.Bid + ticker.Ask) / 2
+	}
+	return ticker, nil
+}
+
+// tickerLevel best price and size, price value can be a [price, amount] array
+func tickerLevel(object map[string]interface{}, priceKeys, amountKeys []string) (float64, float64) {
+	value, ok := firstValue(object, priceKeys...)
+	if !ok {
+		return 0, 0
+	}
+	if level, ok := value.([]interface{}); ok {
+		if len(level) < 2 {
+			return 0, 0
+		}
+		return ToFloat(level[0]), ToFloat(level[1])
+	}
+	amount := 0.0
+	if size, ok := firstValue(object, amountKeys...); ok {
+		amount = ToFloat(size)
+	}
+	return ToFloat(value), amount
+}
@@ -0,0 +1,25 @@
+package goexchange
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseTicker(t *testing.T) {
+	bodies := []string{
+		`{"symbol":"BTCUSDT","lastPrice":"100","bidPrice":"99","bidQty":"2","askPrice":"101","askQty":"3","highPrice":"110","lowPrice":"90","volume":"5","closeTime":1600000000000}`,
+		`{"ch":"market.btcusdt.detail.merged","ts":1600000000000,"tick":{"close":100,"high":110,"low":90,"amount":5,"bid":[99,2],"ask":[101,3]}}`,
+		`{"instrument_id":"BTC-USDT","last":"100","best_bid":"99","best_bid_size":"2","best_ask":"101","best_ask_size":"3","high_24h":"110","low_24h":"90","base_volume_24h":"5","timestamp":"2020-09-13T12:26:40.000Z"}`,
+	}
+	for _, body := range bodies {
+		var data interface{}
+		json.Unmarshal([]byte(body), &data)
+		ticker, err := ParseTicker(map[string]interface{}{"code": 0, "data": data})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ticker.Last != 100 || ticker.Bid != 99 || ticker.BidAmount != 2 || ticker.Ask != 101 || ticker.AskAmount != 3 || ticker.High != 110 || ticker.Low != 90 || ticker.Volume != 5 {
+			t.Fatalf("unexpected ticker of %s: %+v", body, ticker)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package goexchange
+
+import (
+	"errors"
+	"sort"
+)
+
+// Trade typed market trade, Side is the taker side
+type Trade struct {
+	Id     string
+	Time   int64
+	Price  float64
+	Amount float64
+	Side   TradeSide
+}
+
+// ParseTrade parse GetTrade response into typed trades sorted by time, nested
+// trade lists such as huobi's {"data": [...]} groups are flattened
+func ParseTrade(result interface{}) ([]Trade, error) {
+	data := result
+	if retData, ok := result.(map[string]interface{}); ok {
+		if _, isResponse := retData["code"]; isResponse {
+			var err error
+			data, err = ParseResponse(result)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	if object, ok := data.(map[string]interface{}); ok {
+		for _, value := range object {
+			if list, ok := value.([]interface{}); ok {
+				data = list
+				break
+			}
+		}
+	}
+	list, ok := data.([]interface{})
+	if !ok {
+		return nil, errors.New("trade data is not a list")
+	}
+
+	trades := make([]Trade, 0, len(list))
+	for _, item := range list {
+		object, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if group, ok := object["data"].([]interface{}); ok {
+			groupTrades, _ := ParseTrade(group)
+			trades = append(trades, groupTrades...)
+			continue
+		}
+		if trade, ok := parseTradeObject(object); ok {
+			trades = append(trades, trade)
+		}
+	}
+	sort.SliceStable(trades, func(i, j int) bool { return trades[i].Time < trades[j].Time })
+	return trades, nil
+}
+
+// parseTradeObject parse one trade object
+func parseTradeObject(object map[string]interface{}) (Trade, bool) {
+	trade := Trade{}
+	for _, key := range []string{"trade-id", "trade_id", "tradeID", "id"} {
+		if value, ok := object[key]; ok {
+			trade.Id = idString(value)
+			break
+		}
+	}
+	for _, key := range []string{"create_time_ms", "timestamp", "time", "ts", "date", "create_time", "T"} {
+		if value, ok := object[key]; ok {
+			trade.Time = ParseTimestamp(value)
+			break
+		}
+	}
+	for _, key := range []string{"price", "rate", "p"} {
+		if value, ok := object[key]; ok {
+			trade.Price = ToFloat(value)
+			break
+		}
+	}
+	for _, key := range []string{"qty", "quantity", "amount", "size", "q"} {
+		if value, ok := object[key]; ok {
+			trade.Amount = ToFloat(value)
+			break
+		}
+	}
+
+	trade.Side = BUY
+	if buyerMaker, ok := object["isBuyerMaker"].(bool); ok {
+		if buyerMaker {
+			trade.Side = SELL
+		}
+	} else {
+		for _, key := range []string{"side", "direction", "type"} {
+			if value, ok := object[key].(string); ok {
+				if value == "sell" || value == "SELL" || value == "ask" {
+					trade.Side = SELL
+				}
+				break
+			}
+		}
+	}
+	return trade, trade.Price > 0 && trade.Amount > 0
+}
@@ -0,0 +1,25 @@
+package goexchange
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseTrade(t *testing.T) {
+	bodies := []string{
+		`[{"id":2,"price":"101","qty":"1","time":1600000001000,"isBuyerMaker":true},{"id":1,"price":"100","qty":"2","time":1600000000000,"isBuyerMaker":false}]`,
+		`[{"id":9,"ts":1600000001000,"data":[{"trade-id":2,"ts":1600000001000,"amount":1,"price":101,"direction":"sell"}]},{"id":8,"ts":1600000000000,"data":[{"trade-id":1,"ts":1600000000000,"amount":2,"price":100,"direction":"buy"}]}]`,
+		`[{"trade_id":"2","timestamp":"2020-09-13T12:26:41.000Z","price":"101","size":"1","side":"sell"},{"trade_id":"1","timestamp":"2020-09-13T12:26:40.000Z","price":"100","size":"2","side":"buy"}]`,
+	}
+	for _, body := range bodies {
+		var data interface{}
+		json.Unmarshal([]byte(body), &data)
+		trades, err := ParseTrade(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(trades) != 2 || trades[0].Id != "1" || trades[0].Side != BUY || trades[1].Side != SELL || trades[1].Price != 101 || trades[0].Amount != 2 {
+			t.Fatalf("unexpected trades of %s: %v", body, trades)
+		}
+	}
+}
@@ -134,3 +134,42 @@ func LoadConfig(exchange string) (map[string]interface{}, error) {
 	}
 	return result[exchange].(map[string]interface{}), nil
 }
+
+// ToFloat convert json number or numeric string to float64, 0 when invalid
+func ToFloat(value interface{}) float64 {
+	switch val := value.(type) {
+	case float64:
+		return val
+	case float32:
+		return float64(val)
+	case int:
+		return float64(val)
+	case int64:
+		return float64(val)
+	case json.Number:
+		f, _ := val.Float64()
+		return f
+	case string:
+		f, _ := strconv.ParseFloat(val, 64)
+		return f
+	}
+	return 0
+}
+
+// FloatToString format float64 without exponent and trailing zeros
+func FloatToString(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+// ToString string of string or number value, empty for nil
+func ToString(value interface{}) string {
+	switch val := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return val
+	case json.Number:
+		return val.String()
+	}
+	return FloatToString(ToFloat(value))
+}
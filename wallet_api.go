@@ -0,0 +1,110 @@
+package goexchange
+
+// 充提类型
+const (
+	TRANSFER_DEPOSIT  = "deposit"
+	TRANSFER_WITHDRAW = "withdraw"
+)
+
+// 充提状态
+const (
+	TRANSFER_STATUS_PENDING  = "pending"
+	TRANSFER_STATUS_SUCCESS  = "success"
+	TRANSFER_STATUS_FAILED   = "failed"
+	TRANSFER_STATUS_CANCELED = "canceled"
+)
+
+// DepositAddress deposit address of coin on chain
+type DepositAddress struct {
+	Coin    string
+	Chain   string
+	Address string
+	// tag or memo, empty when chain does not use it
+	Tag string
+}
+
+// WithdrawRequest withdraw to an external address, Chain, Tag, Fee and
+// ClientId are optional, Options are exchange specific params
+type WithdrawRequest struct {
+	Coin     string
+	Chain    string
+	Address  string
+	Tag      string
+	Amount   string
+	Fee      string
+	ClientId string
+	Options  map[string]string
+}
+
+// Transfer deposit or withdraw record
+type Transfer struct {
+	Id      string
+	TxId    string
+	Type    string
+	Coin    string
+	Chain   string
+	Address string
+	Tag     string
+	Amount  float64
+	Fee     float64
+	Status  string
+	Time    int64
+}
+
+// WalletAPI wallet api interface, adapters whose exchange has no wallet api
+// return MethodNotExistError
+type WalletAPI interface {
+
+	// 充值地址, data: []DepositAddress
+	GetDepositAddresses(coin, chain string) interface{}
+
+	// 提现, data: withdraw id
+	ApplyWithdraw(request *WithdrawRequest) interface{}
+
+	// 充值记录, data: []Transfer
+	GetDepositHistory(coin string, size int, options map[string]string) interface{}
+
+	// 提现记录, data: []Transfer
+	GetWithdrawHistory(coin string, size int, options map[string]string) interface{}
+}
+
+// 账户类型
+const (
+	ACCOUNT_SPOT      = "spot"
+	ACCOUNT_MARGIN    = "margin"
+	ACCOUNT_SWAP_USDT = "swap_usdt"
+	ACCOUNT_SWAP_COIN = "swap_coin"
+	ACCOUNT_FUTURES   = "futures"
+)
+
+// TransferRequest move asset between accounts of the same user, Symbol is
+// the isolated margin symbol or margin account when the exchange needs it
+type TransferRequest struct {
+	Coin    string
+	From    string
+	To      string
+	Amount  string
+	Symbol  string
+	Options map[string]string
+}
+
+// AccountTransfer internal transfer record
+type AccountTransfer struct {
+	Id     string
+	Coin   string
+	From   string
+	To     string
+	Amount float64
+	Status string
+	Time   int64
+}
+
+// TransferAPI internal transfer api interface, accounts are ACCOUNT_* constants
+type TransferAPI interface {
+
+	// 账户划转, data: transfer id
+	TransferAsset(request *TransferRequest) interface{}
+
+	// 划转记录, data: []AccountTransfer
+	GetTransferHistory(coin, from, to string, size int, options map[string]string) interface{}
+}